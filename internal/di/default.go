@@ -1,14 +1,26 @@
-//go:build !in_memory
+//go:build !in_memory && !redis_storage
 
 package di
 
 import (
+	"expvar"
+	"log/slog"
+	"net/http"
+	"os"
 	"sync"
 
 	"github.com/qushot/gin-todo-api/internal/domain/repository"
 	"github.com/qushot/gin-todo-api/internal/infrastructure/db"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/cache"
 	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/postgresql"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/redis"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/sqlite"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/webhook"
+	"github.com/qushot/gin-todo-api/internal/interfaces/collab"
 	"github.com/qushot/gin-todo-api/internal/interfaces/controllers"
+	"github.com/qushot/gin-todo-api/internal/interfaces/graph"
+	"github.com/qushot/gin-todo-api/internal/interfaces/rpc"
 	"github.com/qushot/gin-todo-api/internal/usecase"
 )
 
@@ -19,48 +31,398 @@ var (
 
 type container struct {
 	TodoRepo repository.Todo
+	TagRepo  repository.Tag
 
-	GetAllTodosUseCase usecase.GetAllTodos
-	GetTodoByIDUseCase usecase.GetTodoByID
-	CreateTodoUseCase  usecase.CreateTodo
-	UpdateTodoUseCase  usecase.UpdateTodo
-	DeleteTodoUseCase  usecase.DeleteTodo
+	TodoDependencyRepo  repository.TodoDependency
+	TodoHistoryRepo     repository.TodoHistory
+	ViewRepo            repository.View
+	IdempotencyKeyRepo  repository.IdempotencyKey
+	TxManager           repository.TxManager
+	OutboxRepo          repository.Outbox
+	WebhookRepo         repository.Webhook
+	WebhookDeliveryRepo repository.WebhookDelivery
+	TodoEventStream     repository.TodoEventStream
+	// TodoEventListener は他のプロセスの変更を監視できる場合 (PostgreSQLの場合) のみ設定する
+	TodoEventListener repository.TodoEventListener
 
-	TodoController *controllers.Todo
+	GetAllTodosUseCase      usecase.GetAllTodos
+	GetTodoListStampUseCase usecase.GetTodoListStamp
+	GetTodoByIDUseCase      usecase.GetTodoByID
+	CreateTodoUseCase       usecase.CreateTodo
+	UpdateTodoUseCase       usecase.UpdateTodo
+	PatchTodoUseCase        usecase.PatchTodo
+	DeleteTodoUseCase       usecase.DeleteTodo
+	BatchTodosUseCase       usecase.BatchTodos
+	StreamTodosUseCase      usecase.StreamTodos
+
+	GetTodosByIDsUseCase               usecase.GetTodosByIDs
+	ListTodoChildrenByParentIDsUseCase usecase.ListTodoChildrenByParentIDs
+	ListTodoHistoryByTodoIDsUseCase    usecase.ListTodoHistoryByTodoIDs
+
+	ListTodoChildrenUseCase    usecase.ListTodoChildren
+	ReorderTodoChildrenUseCase usecase.ReorderTodoChildren
+
+	ListTodoBlockersUseCase     usecase.ListTodoBlockers
+	AddTodoDependencyUseCase    usecase.AddTodoDependency
+	RemoveTodoDependencyUseCase usecase.RemoveTodoDependency
+
+	ListTrashedTodosUseCase usecase.ListTrashedTodos
+	RestoreTodoUseCase      usecase.RestoreTodo
+	PurgeTodoUseCase        usecase.PurgeTodo
+	PurgeTrashUseCase       usecase.PurgeTrash
+
+	PurgeExpiredIdempotencyKeysUseCase usecase.PurgeExpiredIdempotencyKeys
+
+	ArchiveTodoUseCase           usecase.ArchiveTodo
+	UnarchiveTodoUseCase         usecase.UnarchiveTodo
+	ArchiveCompletedTodosUseCase usecase.ArchiveCompletedTodos
+
+	ListTodoHistoryUseCase usecase.ListTodoHistory
+	RevertTodoUseCase      usecase.RevertTodo
+
+	ListTagsUseCase  usecase.ListTags
+	RenameTagUseCase usecase.RenameTag
+	MergeTagsUseCase usecase.MergeTags
+	DeleteTagUseCase usecase.DeleteTag
+
+	ListViewsUseCase    usecase.ListViews
+	GetViewByIDUseCase  usecase.GetViewByID
+	CreateViewUseCase   usecase.CreateView
+	UpdateViewUseCase   usecase.UpdateView
+	DeleteViewUseCase   usecase.DeleteView
+	GetViewTodosUseCase usecase.GetViewTodos
+
+	ListWebhooksUseCase             usecase.ListWebhooks
+	GetWebhookByIDUseCase           usecase.GetWebhookByID
+	CreateWebhookUseCase            usecase.CreateWebhook
+	UpdateWebhookUseCase            usecase.UpdateWebhook
+	DeleteWebhookUseCase            usecase.DeleteWebhook
+	ListWebhookDeliveriesUseCase    usecase.ListWebhookDeliveries
+	SendTestWebhookUseCase          usecase.SendTestWebhook
+	EnqueueWebhookDeliveriesUseCase usecase.EnqueueWebhookDeliveries
+	DeliverWebhooksUseCase          usecase.DeliverWebhooks
+
+	GetTodoChangesUseCase  usecase.GetTodoChanges
+	PushTodoChangesUseCase usecase.PushTodoChanges
+
+	TodoController           *controllers.Todo
+	TodoBatchController      *controllers.TodoBatch
+	TodoStreamController     *controllers.TodoStream
+	TodoDependencyController *controllers.TodoDependency
+	TodoTrashController      *controllers.TodoTrash
+	TodoArchiveController    *controllers.TodoArchive
+	TodoHistoryController    *controllers.TodoHistory
+	TagController            *controllers.Tag
+	ViewController           *controllers.View
+	WebhookController        *controllers.Webhook
+	SyncController           *controllers.Sync
+
+	// WebSocket
+	CollaborationHandler *collab.Handler
+
+	// gRPC
+	TodoService *rpc.TodoService
+
+	// GraphQL
+	GraphHandler *graph.Handler
 }
 
 func GetContainer() *container {
 	once.Do(func() {
 		// repositories
-		dbConn := db.GetDBConn()
-		todoRepo := postgresql.NewTodo(dbConn)
+		// SQLiteが設定されている場合はPostgreSQLの代わりにSQLiteに保存する
+		var (
+			baseTodoRepo           repository.Todo
+			baseTagRepo            repository.Tag
+			baseTodoDependencyRepo repository.TodoDependency
+			todoHistoryRepo        repository.TodoHistory
+			viewRepo               repository.View
+			idempotencyKeyRepo     repository.IdempotencyKey
+			baseTxManager          repository.TxManager
+			outboxRepo             repository.Outbox
+			webhookRepo            repository.Webhook
+			webhookDeliveryRepo    repository.WebhookDelivery
+			todoEventListener      repository.TodoEventListener
+		)
+		if sqliteDB := db.GetSQLiteDB(); sqliteDB != nil {
+			slog.Info("NOTE: Use SQLite Database")
+			baseTodoRepo = sqlite.NewTodo(sqliteDB)
+			baseTagRepo = sqlite.NewTag(sqliteDB)
+			baseTodoDependencyRepo = sqlite.NewTodoDependency(sqliteDB)
+			todoHistoryRepo = sqlite.NewTodoHistory(sqliteDB)
+			viewRepo = sqlite.NewView(sqliteDB)
+			idempotencyKeyRepo = sqlite.NewIdempotencyKey(sqliteDB)
+			baseTxManager = sqlite.NewTxManager(sqliteDB)
+			outboxRepo = sqlite.NewOutbox(sqliteDB)
+			webhookRepo = sqlite.NewWebhook(sqliteDB)
+			webhookDeliveryRepo = sqlite.NewWebhookDelivery(sqliteDB)
+		} else {
+			dbConn := db.GetDBConn()
+			baseTodoRepo = postgresql.NewTodo(dbConn)
+			baseTagRepo = postgresql.NewTag(dbConn)
+			baseTodoDependencyRepo = postgresql.NewTodoDependency(dbConn)
+			todoHistoryRepo = postgresql.NewTodoHistory(dbConn)
+			viewRepo = postgresql.NewView(dbConn)
+			idempotencyKeyRepo = postgresql.NewIdempotencyKey(dbConn)
+			baseTxManager = postgresql.NewTxManager(dbConn)
+			outboxRepo = postgresql.NewOutbox(dbConn)
+			webhookRepo = postgresql.NewWebhook(dbConn)
+			webhookDeliveryRepo = postgresql.NewWebhookDelivery(dbConn)
+			todoEventListener = postgresql.NewTodoEventListener(dbConn)
+		}
+		// Redisが設定されている場合はTodoのキャッシュと冪等キーをRedisに保存する
+		todoCacheStore := cache.NewLRU(cache.DefaultLRUCapacity)
+		if redisClient := db.GetRedisClient(); redisClient != nil {
+			todoCacheStore = redis.NewTodoCache(redisClient)
+			idempotencyKeyRepo = redis.NewIdempotencyKey(redisClient)
+		}
+		todoRepo := cache.NewTodo(baseTodoRepo, todoCacheStore, cache.DefaultTTL)
+		expvar.Publish("todo_cache", expvar.Func(func() any { return todoRepo.Stats() }))
+		tagRepo := cache.NewTag(baseTagRepo, todoRepo)
+		todoDependencyRepo := cache.NewTodoDependency(baseTodoDependencyRepo, todoRepo)
+		txManager := cache.NewTxManager(baseTxManager, todoRepo)
+		webhookSender := webhook.NewSender(&http.Client{Timeout: webhook.DefaultTimeout})
+		todoEventStream := stream.NewBroker(stream.DefaultRetention)
 
 		// use cases
 		getAllTodosUseCase := usecase.NewGetAllTodos(todoRepo)
+		getTodoListStampUseCase := usecase.NewGetTodoListStamp(todoRepo)
 		getTodoByIDUseCase := usecase.NewGetTodoByID(todoRepo)
-		createTodoUseCase := usecase.NewCreateTodo(todoRepo)
-		updateTodoUseCase := usecase.NewUpdateTodo(todoRepo)
-		deleteTodoUseCase := usecase.NewDeleteTodo(todoRepo)
+		createTodoUseCase := usecase.NewCreateTodo(txManager, todoRepo, outboxRepo)
+		updateTodoUseCase := usecase.NewUpdateTodo(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		patchTodoUseCase := usecase.NewPatchTodo(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		deleteTodoUseCase := usecase.NewDeleteTodo(txManager, todoRepo, outboxRepo)
+		batchTodosUseCase := usecase.NewBatchTodos(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		// 他のレプリカの変更の直後に一覧を取得し直すため、それを反映していない可能性があるキャッシュを使わない
+		streamTodosUseCase := usecase.NewStreamTodos(baseTodoRepo, todoEventStream)
+		listTodoChildrenUseCase := usecase.NewListTodoChildren(todoRepo)
+		getTodosByIDsUseCase := usecase.NewGetTodosByIDs(todoRepo)
+		listTodoChildrenByParentIDsUseCase := usecase.NewListTodoChildrenByParentIDs(todoRepo)
+		listTodoHistoryByTodoIDsUseCase := usecase.NewListTodoHistoryByTodoIDs(todoHistoryRepo)
+		reorderTodoChildrenUseCase := usecase.NewReorderTodoChildren(todoRepo)
+		listTodoBlockersUseCase := usecase.NewListTodoBlockers(todoRepo, todoDependencyRepo)
+		addTodoDependencyUseCase := usecase.NewAddTodoDependency(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		removeTodoDependencyUseCase := usecase.NewRemoveTodoDependency(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		listTrashedTodosUseCase := usecase.NewListTrashedTodos(todoRepo)
+		restoreTodoUseCase := usecase.NewRestoreTodo(txManager, todoRepo, todoDependencyRepo, outboxRepo)
+		purgeTodoUseCase := usecase.NewPurgeTodo(todoRepo)
+		purgeTrashUseCase := usecase.NewPurgeTrash(todoRepo)
+		purgeExpiredIdempotencyKeysUseCase := usecase.NewPurgeExpiredIdempotencyKeys(idempotencyKeyRepo)
+		archiveTodoUseCase := usecase.NewArchiveTodo(txManager, todoRepo, outboxRepo)
+		unarchiveTodoUseCase := usecase.NewUnarchiveTodo(txManager, todoRepo, outboxRepo)
+		archiveCompletedTodosUseCase := usecase.NewArchiveCompletedTodos(todoRepo)
+		listTodoHistoryUseCase := usecase.NewListTodoHistory(todoRepo, todoHistoryRepo)
+		revertTodoUseCase := usecase.NewRevertTodo(txManager, todoRepo, todoDependencyRepo, todoHistoryRepo, outboxRepo)
+		listTagsUseCase := usecase.NewListTags(tagRepo)
+		renameTagUseCase := usecase.NewRenameTag(txManager, tagRepo, todoRepo, outboxRepo)
+		mergeTagsUseCase := usecase.NewMergeTags(txManager, tagRepo, todoRepo, outboxRepo)
+		deleteTagUseCase := usecase.NewDeleteTag(txManager, tagRepo, todoRepo, outboxRepo)
+		listViewsUseCase := usecase.NewListViews(viewRepo)
+		getViewByIDUseCase := usecase.NewGetViewByID(viewRepo)
+		createViewUseCase := usecase.NewCreateView(viewRepo)
+		updateViewUseCase := usecase.NewUpdateView(viewRepo)
+		deleteViewUseCase := usecase.NewDeleteView(viewRepo)
+		getViewTodosUseCase := usecase.NewGetViewTodos(viewRepo, getAllTodosUseCase)
+		listWebhooksUseCase := usecase.NewListWebhooks(webhookRepo)
+		getWebhookByIDUseCase := usecase.NewGetWebhookByID(webhookRepo)
+		createWebhookUseCase := usecase.NewCreateWebhook(webhookRepo)
+		updateWebhookUseCase := usecase.NewUpdateWebhook(webhookRepo)
+		deleteWebhookUseCase := usecase.NewDeleteWebhook(webhookRepo)
+		listWebhookDeliveriesUseCase := usecase.NewListWebhookDeliveries(webhookRepo, webhookDeliveryRepo)
+		sendTestWebhookUseCase := usecase.NewSendTestWebhook(webhookRepo, webhookDeliveryRepo, webhookSender)
+		enqueueWebhookDeliveriesUseCase := usecase.NewEnqueueWebhookDeliveries(webhookRepo, webhookDeliveryRepo)
+		deliverWebhooksUseCase := usecase.NewDeliverWebhooks(webhookRepo, webhookDeliveryRepo, webhookSender)
+		// 差分同期はキャッシュを介さずに変更を読み取る
+		getTodoChangesUseCase := usecase.NewGetTodoChanges(baseTodoRepo)
+		pushTodoChangesUseCase := usecase.NewPushTodoChanges(txManager, todoRepo, todoDependencyRepo, todoHistoryRepo, outboxRepo)
 
 		// controllers
 		todoController := controllers.NewTodo(
+			getAllTodosUseCase,
+			getTodoListStampUseCase,
+			getTodoByIDUseCase,
+			createTodoUseCase,
+			updateTodoUseCase,
+			patchTodoUseCase,
+			deleteTodoUseCase,
+			listTodoChildrenUseCase,
+			reorderTodoChildrenUseCase,
+		)
+		todoBatchController := controllers.NewTodoBatch(batchTodosUseCase)
+		todoStreamController := controllers.NewTodoStream(streamTodosUseCase)
+		todoDependencyController := controllers.NewTodoDependency(
+			listTodoBlockersUseCase,
+			addTodoDependencyUseCase,
+			removeTodoDependencyUseCase,
+		)
+		todoTrashController := controllers.NewTodoTrash(
+			listTrashedTodosUseCase,
+			restoreTodoUseCase,
+			purgeTodoUseCase,
+		)
+		todoArchiveController := controllers.NewTodoArchive(
+			archiveTodoUseCase,
+			unarchiveTodoUseCase,
+		)
+		todoHistoryController := controllers.NewTodoHistory(
+			listTodoHistoryUseCase,
+			revertTodoUseCase,
+		)
+		tagController := controllers.NewTag(
+			listTagsUseCase,
+			renameTagUseCase,
+			mergeTagsUseCase,
+			deleteTagUseCase,
+		)
+		viewController := controllers.NewView(
+			listViewsUseCase,
+			getViewByIDUseCase,
+			createViewUseCase,
+			updateViewUseCase,
+			deleteViewUseCase,
+			getViewTodosUseCase,
+		)
+		webhookController := controllers.NewWebhook(
+			listWebhooksUseCase,
+			getWebhookByIDUseCase,
+			createWebhookUseCase,
+			updateWebhookUseCase,
+			deleteWebhookUseCase,
+			listWebhookDeliveriesUseCase,
+			sendTestWebhookUseCase,
+		)
+		syncController := controllers.NewSync(getTodoChangesUseCase, pushTodoChangesUseCase)
+
+		// WebSocket
+		// チケットを複数のレプリカで検証するため、秘密鍵を COLLAB_TICKET_SECRET で共有する
+		collaborationHandler := collab.NewHandler(
+			collab.NewTickets([]byte(os.Getenv("COLLAB_TICKET_SECRET")), collab.DefaultTicketTTL),
+			streamTodosUseCase,
+			getAllTodosUseCase,
+			createTodoUseCase,
+			updateTodoUseCase,
+			patchTodoUseCase,
+			deleteTodoUseCase,
+		)
+
+		// gRPC
+		todoService := rpc.NewTodoService(
+			getAllTodosUseCase,
+			getTodoByIDUseCase,
+			createTodoUseCase,
+			updateTodoUseCase,
+			patchTodoUseCase,
+			deleteTodoUseCase,
+			streamTodosUseCase,
+		)
+
+		// GraphQL
+		graphHandler := graph.NewHandler(
 			getAllTodosUseCase,
 			getTodoByIDUseCase,
 			createTodoUseCase,
 			updateTodoUseCase,
+			patchTodoUseCase,
 			deleteTodoUseCase,
+			streamTodosUseCase,
+			listTagsUseCase,
+			getTodosByIDsUseCase,
+			listTodoChildrenByParentIDsUseCase,
+			listTodoHistoryByTodoIDsUseCase,
 		)
 
 		c = &container{
 			TodoRepo: todoRepo,
+			TagRepo:  tagRepo,
+
+			TodoDependencyRepo:  todoDependencyRepo,
+			TodoHistoryRepo:     todoHistoryRepo,
+			ViewRepo:            viewRepo,
+			IdempotencyKeyRepo:  idempotencyKeyRepo,
+			TxManager:           txManager,
+			OutboxRepo:          outboxRepo,
+			WebhookRepo:         webhookRepo,
+			WebhookDeliveryRepo: webhookDeliveryRepo,
+			TodoEventStream:     todoEventStream,
+			TodoEventListener:   todoEventListener,
+
+			GetAllTodosUseCase:      getAllTodosUseCase,
+			GetTodoListStampUseCase: getTodoListStampUseCase,
+			GetTodoByIDUseCase:      getTodoByIDUseCase,
+			CreateTodoUseCase:       createTodoUseCase,
+			UpdateTodoUseCase:       updateTodoUseCase,
+			PatchTodoUseCase:        patchTodoUseCase,
+			DeleteTodoUseCase:       deleteTodoUseCase,
+			BatchTodosUseCase:       batchTodosUseCase,
+			StreamTodosUseCase:      streamTodosUseCase,
+
+			GetTodosByIDsUseCase:               getTodosByIDsUseCase,
+			ListTodoChildrenByParentIDsUseCase: listTodoChildrenByParentIDsUseCase,
+			ListTodoHistoryByTodoIDsUseCase:    listTodoHistoryByTodoIDsUseCase,
+
+			ListTodoChildrenUseCase:    listTodoChildrenUseCase,
+			ReorderTodoChildrenUseCase: reorderTodoChildrenUseCase,
+
+			ListTodoBlockersUseCase:     listTodoBlockersUseCase,
+			AddTodoDependencyUseCase:    addTodoDependencyUseCase,
+			RemoveTodoDependencyUseCase: removeTodoDependencyUseCase,
+
+			ListTrashedTodosUseCase: listTrashedTodosUseCase,
+			RestoreTodoUseCase:      restoreTodoUseCase,
+			PurgeTodoUseCase:        purgeTodoUseCase,
+			PurgeTrashUseCase:       purgeTrashUseCase,
+
+			PurgeExpiredIdempotencyKeysUseCase: purgeExpiredIdempotencyKeysUseCase,
+
+			ArchiveTodoUseCase:           archiveTodoUseCase,
+			UnarchiveTodoUseCase:         unarchiveTodoUseCase,
+			ArchiveCompletedTodosUseCase: archiveCompletedTodosUseCase,
+
+			ListTodoHistoryUseCase: listTodoHistoryUseCase,
+			RevertTodoUseCase:      revertTodoUseCase,
+
+			ListTagsUseCase:  listTagsUseCase,
+			RenameTagUseCase: renameTagUseCase,
+			MergeTagsUseCase: mergeTagsUseCase,
+			DeleteTagUseCase: deleteTagUseCase,
+
+			ListViewsUseCase:    listViewsUseCase,
+			GetViewByIDUseCase:  getViewByIDUseCase,
+			CreateViewUseCase:   createViewUseCase,
+			UpdateViewUseCase:   updateViewUseCase,
+			DeleteViewUseCase:   deleteViewUseCase,
+			GetViewTodosUseCase: getViewTodosUseCase,
+
+			ListWebhooksUseCase:             listWebhooksUseCase,
+			GetWebhookByIDUseCase:           getWebhookByIDUseCase,
+			CreateWebhookUseCase:            createWebhookUseCase,
+			UpdateWebhookUseCase:            updateWebhookUseCase,
+			DeleteWebhookUseCase:            deleteWebhookUseCase,
+			ListWebhookDeliveriesUseCase:    listWebhookDeliveriesUseCase,
+			SendTestWebhookUseCase:          sendTestWebhookUseCase,
+			EnqueueWebhookDeliveriesUseCase: enqueueWebhookDeliveriesUseCase,
+			DeliverWebhooksUseCase:          deliverWebhooksUseCase,
+
+			GetTodoChangesUseCase:  getTodoChangesUseCase,
+			PushTodoChangesUseCase: pushTodoChangesUseCase,
+
+			TodoController:           todoController,
+			TodoBatchController:      todoBatchController,
+			TodoStreamController:     todoStreamController,
+			TodoDependencyController: todoDependencyController,
+			TodoTrashController:      todoTrashController,
+			TodoArchiveController:    todoArchiveController,
+			TodoHistoryController:    todoHistoryController,
+			TagController:            tagController,
+			ViewController:           viewController,
+			WebhookController:        webhookController,
+			SyncController:           syncController,
+
+			CollaborationHandler: collaborationHandler,
 
-			GetAllTodosUseCase: getAllTodosUseCase,
-			GetTodoByIDUseCase: getTodoByIDUseCase,
-			CreateTodoUseCase:  createTodoUseCase,
-			UpdateTodoUseCase:  updateTodoUseCase,
-			DeleteTodoUseCase:  deleteTodoUseCase,
+			TodoService: todoService,
 
-			TodoController: todoController,
+			GraphHandler: graphHandler,
 		}
 	})
 
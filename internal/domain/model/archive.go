@@ -0,0 +1,40 @@
+package model
+
+import "fmt"
+
+// ArchivedFilter はアーカイブ状態による絞り込みの条件を表す
+type ArchivedFilter string
+
+const (
+	// ArchivedFilterFalse はアーカイブされていないTodoに一致する
+	ArchivedFilterFalse ArchivedFilter = "false"
+	// ArchivedFilterTrue はアーカイブされたTodoに一致する
+	ArchivedFilterTrue ArchivedFilter = "true"
+	// ArchivedFilterAny はアーカイブ状態に関わらず全てのTodoに一致する
+	ArchivedFilterAny ArchivedFilter = "any"
+)
+
+// Match はアーカイブ状態が条件に一致するかを返す
+func (f ArchivedFilter) Match(archived bool) bool {
+	switch f {
+	case ArchivedFilterTrue:
+		return archived
+	case ArchivedFilterAny:
+		return true
+	default:
+		return !archived
+	}
+}
+
+// IsArchived はTodoがアーカイブされているかを返す
+func (t *Todo) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
+// CheckWritable はTodoを更新できるかを検証する (アーカイブされたTodoはアーカイブを解除するまで読み取り専用)
+func (t *Todo) CheckWritable() error {
+	if t.IsArchived() {
+		return fmt.Errorf("%w: todo %s is archived, unarchive it first", ErrConflict, t.ID)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package model
+
+import "fmt"
+
+// MaxBatchOperations は一括操作で指定できる操作の最大数
+const MaxBatchOperations = 500
+
+// BatchMode は一括操作の実行方式を表す
+type BatchMode string
+
+const (
+	// BatchModeAtomic は全ての操作を1つのトランザクションで適用し、1つでも失敗した場合は全て適用しない
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort は成功した操作のみを適用し、失敗した操作は結果として報告する
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+// BatchOperationType は一括操作の各操作の種類を表す
+type BatchOperationType string
+
+const (
+	// BatchOperationCreate はTodoを作成する
+	BatchOperationCreate BatchOperationType = "create"
+	// BatchOperationUpdate はTodoを部分更新する
+	BatchOperationUpdate BatchOperationType = "update"
+	// BatchOperationComplete はTodoを完了にする
+	BatchOperationComplete BatchOperationType = "complete"
+	// BatchOperationDelete はTodoをゴミ箱に移動する
+	BatchOperationDelete BatchOperationType = "delete"
+)
+
+// TodoBatchOperation は一括操作の1件の操作を表す
+type TodoBatchOperation struct {
+	Op BatchOperationType `json:"op"`
+	// ID は update、complete、delete の対象のTodoのID
+	ID string `json:"id"`
+	// Todo は create で作成するTodo
+	Todo *Todo `json:"todo"`
+	// Patch は update で適用する部分更新
+	Patch *TodoPatch `json:"patch"`
+	// Cascade が true の場合、delete で子孫のTodoも含めてゴミ箱に移動する
+	Cascade bool `json:"cascade"`
+}
+
+// TodoBatch はTodoの一括操作を表す
+type TodoBatch struct {
+	Mode       BatchMode            `json:"mode"`
+	Operations []TodoBatchOperation `json:"operations"`
+}
+
+// Validate は一括操作の実行方式と操作数を検証し、省略された実行方式を atomic とする
+// 各操作の内容は適用する際に個別に検証する
+func (b *TodoBatch) Validate() error {
+	switch b.Mode {
+	case "":
+		b.Mode = BatchModeAtomic
+	case BatchModeAtomic, BatchModeBestEffort:
+	default:
+		return fmt.Errorf("%w: mode must be %q or %q", ErrInvalidArgument, BatchModeAtomic, BatchModeBestEffort)
+	}
+	if len(b.Operations) == 0 || len(b.Operations) > MaxBatchOperations {
+		return fmt.Errorf("%w: operations must contain between 1 and %d items", ErrInvalidArgument, MaxBatchOperations)
+	}
+	return nil
+}
+
+// TodoBatchResult は一括操作の1件の操作の結果を表す
+type TodoBatchResult struct {
+	Index int                `json:"index"`
+	Op    BatchOperationType `json:"op"`
+	ID    string             `json:"id,omitempty"`
+	// Todo は作成または更新した後のTodo (失敗した場合と delete の場合は nil)
+	Todo *Todo `json:"todo,omitempty"`
+	// Err は失敗した理由 (成功した場合と Skipped の場合は nil)
+	Err error `json:"-"`
+	// Skipped は atomic で他の操作が失敗したために適用しなかったことを表す
+	Skipped bool `json:"-"`
+}
+
+// TodoWriteKind はリポジトリでまとめて適用する書き込みの種類を表す
+type TodoWriteKind string
+
+const (
+	// TodoWriteCreate はTodoを作成する
+	TodoWriteCreate TodoWriteKind = "create"
+	// TodoWriteUpdate はTodoを更新する
+	TodoWriteUpdate TodoWriteKind = "update"
+	// TodoWriteDelete はTodoを子孫のTodoも含めてゴミ箱に移動する
+	TodoWriteDelete TodoWriteKind = "delete"
+)
+
+// TodoWrite はリポジトリでまとめて適用するTodoへの1件の書き込みを表す (検証済みであること)
+type TodoWrite struct {
+	Kind TodoWriteKind
+	// ID は更新またはゴミ箱に移動するTodoのID
+	ID string
+	// Todo は作成または更新する内容
+	Todo Todo
+}
@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// DueFilter は期限による絞り込みの条件を表す
+// 日付は保存した時点ではなく、取得する時点の日時を基準に解決する
+type DueFilter string
+
+const (
+	// DueFilterOverdue は期限を過ぎた完了または中止でないTodoに一致する
+	DueFilterOverdue DueFilter = "overdue"
+	// DueFilterToday は今日が期限のTodoに一致する
+	DueFilterToday DueFilter = "today"
+	// DueFilterTomorrow は明日が期限のTodoに一致する
+	DueFilterTomorrow DueFilter = "tomorrow"
+	// DueFilterNext7Days は今日から7日間 (今日を含む) のいずれかが期限のTodoに一致する
+	DueFilterNext7Days DueFilter = "next_7_days"
+	// DueFilterNext30Days は今日から30日間 (今日を含む) のいずれかが期限のTodoに一致する
+	DueFilterNext30Days DueFilter = "next_30_days"
+	// DueFilterNone は期限のないTodoに一致する
+	DueFilterNone DueFilter = "none"
+)
+
+// IsValid は定義済みの条件かどうかを返す
+func (f DueFilter) IsValid() bool {
+	switch f {
+	case DueFilterOverdue, DueFilterToday, DueFilterTomorrow, DueFilterNext7Days, DueFilterNext30Days, DueFilterNone:
+		return true
+	}
+	return false
+}
+
+// DueRange は期限による絞り込みの条件を日時の範囲に解決したものを表す
+type DueRange struct {
+	// From を指定した場合、期限がこの日時以降のTodoのみに一致する
+	From *time.Time
+	// To を指定した場合、期限がこの日時より前のTodoのみに一致する
+	To *time.Time
+	// None が true の場合、期限のないTodoのみに一致する
+	None bool
+	// OpenOnly が true の場合、完了または中止でないTodoのみに一致する
+	OpenOnly bool
+}
+
+// Resolve は now を基準に loc の日付の境界で条件を解決する
+func (f DueFilter) Resolve(now time.Time, loc *time.Location) *DueRange {
+	y, m, d := now.In(loc).Date()
+	day := func(offset int) *time.Time {
+		t := time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+		return &t
+	}
+
+	switch f {
+	case DueFilterOverdue:
+		return &DueRange{To: &now, OpenOnly: true}
+	case DueFilterToday:
+		return &DueRange{From: day(0), To: day(1)}
+	case DueFilterTomorrow:
+		return &DueRange{From: day(1), To: day(2)}
+	case DueFilterNext7Days:
+		return &DueRange{From: day(0), To: day(7)}
+	case DueFilterNext30Days:
+		return &DueRange{From: day(0), To: day(30)}
+	case DueFilterNone:
+		return &DueRange{None: true}
+	}
+	return nil
+}
+
+// Match はTodoが範囲に一致するかを返す
+func (r *DueRange) Match(t *Todo) bool {
+	if r.OpenOnly && t.Status.IsClosed() {
+		return false
+	}
+	if r.None {
+		return t.DueAt == nil
+	}
+	if (r.From != nil || r.To != nil) && t.DueAt == nil {
+		return false
+	}
+	if r.From != nil && t.DueAt.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.DueAt.Before(*r.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestDueFilter_Resolve(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 東京では 2025-01-02 の 08:00
+	now := time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)
+	at := func(s string) *time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &tm
+	}
+
+	tests := []struct {
+		name   string
+		filter model.DueFilter
+		loc    *time.Location
+		todo   model.Todo
+		want   bool
+	}{
+		{
+			name:   "overdue open todo",
+			filter: model.DueFilterOverdue,
+			loc:    time.UTC,
+			todo:   model.Todo{Status: model.TodoStatusTodo, DueAt: at("2025-01-01T22:00:00Z")},
+			want:   true,
+		},
+		{
+			name:   "overdue done todo",
+			filter: model.DueFilterOverdue,
+			loc:    time.UTC,
+			todo:   model.Todo{Status: model.TodoStatusDone, DueAt: at("2025-01-01T22:00:00Z")},
+			want:   false,
+		},
+		{
+			name:   "today in UTC",
+			filter: model.DueFilterToday,
+			loc:    time.UTC,
+			todo:   model.Todo{DueAt: at("2025-01-01T00:00:00Z")},
+			want:   true,
+		},
+		{
+			name:   "today uses timezone",
+			filter: model.DueFilterToday,
+			loc:    tokyo,
+			todo:   model.Todo{DueAt: at("2025-01-01T00:00:00Z")},
+			want:   false,
+		},
+		{
+			name:   "next 7 days includes the last day",
+			filter: model.DueFilterNext7Days,
+			loc:    time.UTC,
+			todo:   model.Todo{DueAt: at("2025-01-07T23:59:59Z")},
+			want:   true,
+		},
+		{
+			name:   "next 7 days excludes the eighth day",
+			filter: model.DueFilterNext7Days,
+			loc:    time.UTC,
+			todo:   model.Todo{DueAt: at("2025-01-08T00:00:00Z")},
+			want:   false,
+		},
+		{
+			name:   "range excludes todo without due",
+			filter: model.DueFilterTomorrow,
+			loc:    time.UTC,
+			todo:   model.Todo{},
+			want:   false,
+		},
+		{
+			name:   "none",
+			filter: model.DueFilterNone,
+			loc:    time.UTC,
+			todo:   model.Todo{},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Resolve(now, tt.loc).Match(&tt.todo)
+			if got != tt.want {
+				t.Errorf("Resolve().Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+import "errors"
+
+var (
+	// ErrNotFound は対象のデータが存在しないことを表す
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument は入力値が不正であることを表す
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrConflict は既存のデータと競合することを表す
+	ErrConflict = errors.New("conflict")
+	// ErrInvalidStatusTransition は許可されていないステータス遷移であることを表す
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+)
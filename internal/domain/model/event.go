@@ -0,0 +1,83 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TodoEventType はTodoのドメインイベントの種類を表す
+type TodoEventType string
+
+const (
+	// TodoEventCreated は作成
+	TodoEventCreated TodoEventType = "todo.created"
+	// TodoEventUpdated は更新 (ゴミ箱からの復元、アーカイブとその解除を含む)
+	TodoEventUpdated TodoEventType = "todo.updated"
+	// TodoEventCompleted は done への変更 (TodoEventUpdated に続けて発生する)
+	TodoEventCompleted TodoEventType = "todo.completed"
+	// TodoEventDeleted はゴミ箱への移動
+	TodoEventDeleted TodoEventType = "todo.deleted"
+	// TodoEventStreamReset は購読を再開できなかったことを表す (受信側で一覧を取得し直す)
+	TodoEventStreamReset TodoEventType = "stream.reset"
+)
+
+// ErrEventExpired は指定したイベントを保持していないため、その続きから購読を再開できないことを表す
+var ErrEventExpired = errors.New("event expired")
+
+// TodoEvent はTodoのドメインイベントを表す
+// 書き込みと同じトランザクションでアウトボックスに保存し、保存した順にTodoごとに配信する
+type TodoEvent struct {
+	// ID はアウトボックスに保存する際に払い出す (受信側で重複を除くために使う)
+	ID      string        `json:"id"`
+	Type    TodoEventType `json:"type"`
+	TodoID  string        `json:"todo_id"`
+	Actor   string        `json:"actor"`
+	TraceID string        `json:"trace_id"`
+	// Todo はイベントが発生した後のTodoの全体 (ゴミ箱への移動の場合は移動する前のTodo)
+	Todo       Todo      `json:"todo"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewTodoEvent は ctx の操作者の情報を設定した todo のイベントを返す
+func NewTodoEvent(ctx context.Context, eventType TodoEventType, todo *Todo) TodoEvent {
+	info := AuditInfoFromContext(ctx)
+	return TodoEvent{
+		Type:       eventType,
+		TodoID:     todo.ID,
+		Actor:      info.Actor,
+		TraceID:    info.TraceID,
+		Todo:       *todo,
+		OccurredAt: time.Now(),
+	}
+}
+
+// TodoUpdateEvents は before から after への更新で発生するイベントを返す
+// done に変更した場合は TodoEventUpdated に続けて TodoEventCompleted を返す
+func TodoUpdateEvents(ctx context.Context, before, after *Todo) []TodoEvent {
+	events := []TodoEvent{NewTodoEvent(ctx, TodoEventUpdated, after)}
+	if before.Status != TodoStatusDone && after.Status == TodoStatusDone {
+		events = append(events, NewTodoEvent(ctx, TodoEventCompleted, after))
+	}
+	return events
+}
+
+// OutboxMessage はアウトボックスに保存した、配信待ちのイベントを表す
+type OutboxMessage struct {
+	// Seq はアウトボックスに保存した順序
+	Seq   int64
+	Event TodoEvent
+	// Attempts は配信に失敗した回数
+	Attempts int
+	// LastError は最後に配信に失敗した理由
+	LastError string
+	// AvailableAt は次に配信を試みる日時
+	AvailableAt time.Time
+}
+
+// TodoStreamEvent は購読者に配信するTodoのイベントを表す
+type TodoStreamEvent struct {
+	TodoEvent
+	// Match はイベントが発生した後のTodoが購読の検索クエリに一致するか (一致しない場合は受信側の一覧から取り除く)
+	Match bool `json:"match"`
+}
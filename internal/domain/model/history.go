@@ -0,0 +1,139 @@
+package model
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// DefaultActor は操作者が特定できない場合の操作者
+const DefaultActor = "anonymous"
+
+// HistoryAction は変更履歴の操作の種類を表す
+type HistoryAction string
+
+const (
+	// HistoryActionCreate は作成
+	HistoryActionCreate HistoryAction = "create"
+	// HistoryActionUpdate は更新
+	HistoryActionUpdate HistoryAction = "update"
+	// HistoryActionDelete はゴミ箱への移動
+	HistoryActionDelete HistoryAction = "delete"
+	// HistoryActionRestore はゴミ箱からの復元
+	HistoryActionRestore HistoryAction = "restore"
+	// HistoryActionArchive はアーカイブ
+	HistoryActionArchive HistoryAction = "archive"
+	// HistoryActionUnarchive はアーカイブの解除
+	HistoryActionUnarchive HistoryAction = "unarchive"
+)
+
+// FieldChange は1つの項目の変更前後の値を表す
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// TodoHistory はTodoの変更履歴を表す (追記のみで更新・削除はしない)
+type TodoHistory struct {
+	ID     string `json:"id"`
+	TodoID string `json:"todo_id"`
+	// Version は変更後のTodoのバージョン
+	Version int           `json:"version"`
+	Action  HistoryAction `json:"action"`
+	Actor   string        `json:"actor"`
+	TraceID string        `json:"trace_id"`
+	// Changes は変更された項目の一覧 (作成の場合は Before が nil)
+	Changes []FieldChange `json:"changes"`
+	// Snapshot は変更後のTodoの全体 (このバージョンに戻す際に使う)
+	Snapshot  Todo      `json:"snapshot"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// todoHistoryFields は変更履歴で差分を記録するTodoの項目
+var todoHistoryFields = []struct {
+	name  string
+	value func(t *Todo) any
+}{
+	{"title", func(t *Todo) any { return t.Title }},
+	{"content", func(t *Todo) any { return t.Content }},
+	{"status", func(t *Todo) any { return t.Status }},
+	{"priority", func(t *Todo) any { return t.Priority }},
+	{"tags", func(t *Todo) any { return t.Tags }},
+	{"parent_id", func(t *Todo) any { return t.ParentID }},
+	{"auto_complete", func(t *Todo) any { return t.AutoComplete }},
+	{"blocked_by", func(t *Todo) any { return t.BlockedBy }},
+	{"due_at", func(t *Todo) any { return t.DueAt }},
+	{"recurrence", func(t *Todo) any { return t.Recurrence }},
+	{"timezone", func(t *Todo) any { return t.Timezone }},
+	{"series_id", func(t *Todo) any { return t.SeriesID }},
+	{"occurrence_at", func(t *Todo) any { return t.OccurrenceAt }},
+	{"archived_at", func(t *Todo) any { return t.ArchivedAt }},
+	{"deleted_at", func(t *Todo) any { return t.DeletedAt }},
+}
+
+// DiffTodo は before から after への変更を項目ごとに返す (作成の場合は before に nil を指定する)
+func DiffTodo(before, after *Todo) []FieldChange {
+	changes := []FieldChange{}
+	for _, f := range todoHistoryFields {
+		a := f.value(after)
+		if before == nil {
+			if !isZeroValue(a) {
+				changes = append(changes, FieldChange{Field: f.name, After: a})
+			}
+			continue
+		}
+		if b := f.value(before); !equalValue(b, a) {
+			changes = append(changes, FieldChange{Field: f.name, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// equalValue は変更履歴の項目の値が等しいかを返す (日時はタイムゾーンに関わらず同じ時刻であれば等しい)
+func equalValue(a, b any) bool {
+	if at, ok := a.(*time.Time); ok {
+		bt := b.(*time.Time)
+		if at == nil || bt == nil {
+			return at == bt
+		}
+		return at.Equal(*bt)
+	}
+	if as, ok := a.([]string); ok {
+		bs := b.([]string)
+		return len(as) == 0 && len(bs) == 0 || reflect.DeepEqual(as, bs)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// isZeroValue は変更履歴の項目の値がゼロ値 (nil や空のスライスを含む) かを返す
+func isZeroValue(v any) bool {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// AuditInfo は変更履歴に記録する操作者の情報を表す
+type AuditInfo struct {
+	Actor   string
+	TraceID string
+}
+
+// auditInfoKey は context に AuditInfo を格納するためのキー
+type auditInfoKey struct{}
+
+// WithAuditInfo は info を格納した context を返す
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditInfoKey{}, info)
+}
+
+// AuditInfoFromContext は context に格納された AuditInfo を返す (格納されていない場合は DefaultActor とする)
+func AuditInfoFromContext(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditInfoKey{}).(AuditInfo)
+	if info.Actor == "" {
+		info.Actor = DefaultActor
+	}
+	return info
+}
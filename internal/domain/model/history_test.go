@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestDiffTodo(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dueAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueAtInTokyo := dueAt.In(tokyo)
+	parentID := "parent"
+
+	tests := []struct {
+		name   string
+		before *model.Todo
+		after  *model.Todo
+		want   []model.FieldChange
+	}{
+		{
+			name:   "create records non-zero fields",
+			before: nil,
+			after:  &model.Todo{Title: "title", Status: model.TodoStatusTodo, Tags: []string{}},
+			want: []model.FieldChange{
+				{Field: "title", After: "title"},
+				{Field: "status", After: model.TodoStatusTodo},
+			},
+		},
+		{
+			name:   "update records changed fields",
+			before: &model.Todo{Title: "before", Priority: 1, ParentID: &parentID},
+			after:  &model.Todo{Title: "after", Priority: 1},
+			want: []model.FieldChange{
+				{Field: "title", Before: "before", After: "after"},
+				{Field: "parent_id", Before: &parentID, After: (*string)(nil)},
+			},
+		},
+		{
+			name:   "same instant in another timezone and empty tags are unchanged",
+			before: &model.Todo{DueAt: &dueAt, Tags: nil},
+			after:  &model.Todo{DueAt: &dueAtInTokyo, Tags: []string{}},
+			want:   []model.FieldChange{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.DiffTodo(tt.before, tt.after)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DiffTodo() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
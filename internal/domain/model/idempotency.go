@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+const (
+	// MaxIdempotencyKeyLength は冪等キーの最大長
+	MaxIdempotencyKeyLength = 255
+	// DefaultIdempotencyKeyTTL は冪等キーと保存したレスポンスを保持する期間のデフォルト値
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+)
+
+// IdempotencyRecord は冪等キーに対応するリクエストと、保存したレスポンスを表す
+type IdempotencyRecord struct {
+	Key string `json:"key"`
+	// Fingerprint はリクエストのメソッド、パス、ボディから求めた値 (同じキーで異なるリクエストを検出する)
+	Fingerprint string `json:"fingerprint"`
+	// Completed はレスポンスを保存済みかを表す (false の場合は最初のリクエストを処理中)
+	Completed   bool      `json:"completed"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
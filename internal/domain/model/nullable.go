@@ -0,0 +1,41 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable は部分更新で「指定しない」「null を指定する」「値を指定する」を区別する項目を表す
+// ポインタでは JSON の null と省略を区別できないため、null で値を消せる項目に使う
+type Nullable[T any] struct {
+	// Set は項目が指定されたかどうか (null を指定した場合も true)
+	Set bool
+	// Value は指定された値 (null を指定した場合は nil)
+	Value *T
+}
+
+// NullableOf は v を指定した Nullable を返す (v が nil の場合は null を指定したことになる)
+func NullableOf[T any](v *T) Nullable[T] {
+	return Nullable[T]{Set: true, Value: v}
+}
+
+// UnmarshalJSON は項目が指定されたことを記録して値を読み取る
+// (省略された項目では呼び出されないため、Set は false のまま)
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if bytes.Equal(data, []byte("null")) {
+		n.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
+}
+
+// MarshalJSON は値を JSON に変換する (指定されていない場合と null の場合は null)
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"context"
+	"fmt"
+)
+
+// expectedVersionKey は context に更新・削除するTodoに期待するバージョンを格納するためのキー
+type expectedVersionKey struct{}
+
+// WithExpectedVersion は更新・削除するTodoに期待するバージョンを格納した context を返す (楽観的排他制御に使う)
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// CheckExpectedVersion は context に期待するバージョンが格納されていて、todo のバージョンと異なる場合に ErrConflict を返す
+func CheckExpectedVersion(ctx context.Context, todo *Todo) error {
+	version, ok := ctx.Value(expectedVersionKey{}).(int)
+	if ok && version != todo.Version {
+		return fmt.Errorf("%w: version %d does not match the current version %d", ErrConflict, version, todo.Version)
+	}
+	return nil
+}
+
+// CheckBaseVersion は更新の元にしたバージョン base (0 の場合は確認しない) が、保存されている current のバージョンと異なる場合に ErrConflict を返す
+// リポジトリが更新する行をロックした状態で確認し、同時に更新された場合に他の更新を上書きしないようにする
+func CheckBaseVersion(current *Todo, base int) error {
+	if base != 0 && base != current.Version {
+		return fmt.Errorf("%w: todo %s was modified concurrently (version %d, now %d)", ErrConflict, current.ID, base, current.Version)
+	}
+	return nil
+}
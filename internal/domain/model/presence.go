@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// PresenceState は共同編集の参加者がTodoに対して行っていることを表す
+type PresenceState string
+
+const (
+	// PresenceViewing は閲覧中
+	PresenceViewing PresenceState = "viewing"
+	// PresenceEditing は編集中
+	PresenceEditing PresenceState = "editing"
+)
+
+// Presence は共同編集の参加者 (接続ごと) の在席状況を表す
+type Presence struct {
+	// ConnectionID は接続のID (同じ操作者が複数の接続で参加する場合に区別する)
+	ConnectionID string `json:"connection_id"`
+	Actor        string `json:"actor"`
+	// TodoID は閲覧中または編集中のTodoのID (どのTodoも開いていない場合は空)
+	TodoID    string        `json:"todo_id"`
+	State     PresenceState `json:"state"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Validate は在席状況の値が正しいかを検証する
+func (p *Presence) Validate() error {
+	switch {
+	case p.TodoID == "" && p.State == "":
+	case p.TodoID == "":
+		return fmt.Errorf("%w: todo_id is required when state is %q", ErrInvalidArgument, p.State)
+	case p.State != PresenceViewing && p.State != PresenceEditing:
+		return fmt.Errorf("%w: state must be %q or %q", ErrInvalidArgument, PresenceViewing, PresenceEditing)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// RecurrenceScope は繰り返しTodoの変更を適用する範囲を表す
+type RecurrenceScope string
+
+const (
+	// RecurrenceScopeThis はこの発生のみに変更を適用する (期限の変更は次の発生に影響しない)
+	RecurrenceScopeThis RecurrenceScope = "this"
+	// RecurrenceScopeThisAndFuture はこの発生と以降の発生に変更を適用する
+	RecurrenceScopeThisAndFuture RecurrenceScope = "this_and_future"
+)
+
+// DefaultTimezone はタイムゾーンが省略された場合に使うタイムゾーン
+const DefaultTimezone = "UTC"
+
+// ValidateRecurrenceScope は適用範囲を検証し、省略された場合は this_and_future を返す
+func ValidateRecurrenceScope(scope RecurrenceScope) (RecurrenceScope, error) {
+	switch scope {
+	case "":
+		return RecurrenceScopeThisAndFuture, nil
+	case RecurrenceScopeThis, RecurrenceScopeThisAndFuture:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("%w: scope must be %q or %q", ErrInvalidArgument, RecurrenceScopeThis, RecurrenceScopeThisAndFuture)
+	}
+}
+
+// NormalizeRecurrence は RRULE を検証し、"RRULE:" の接頭辞を除いて正規化した文字列を返す
+// 開始日時は due_at で指定するため DTSTART を含めることはできない
+func NormalizeRecurrence(rule string) (string, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	if rule == "" {
+		return "", nil
+	}
+
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid recurrence: %s", ErrInvalidArgument, err.Error())
+	}
+	if !opt.Dtstart.IsZero() {
+		return "", fmt.Errorf("%w: recurrence must not contain DTSTART, use due_at instead", ErrInvalidArgument)
+	}
+	if _, err := rrule.NewRRule(*opt); err != nil {
+		return "", fmt.Errorf("%w: invalid recurrence: %s", ErrInvalidArgument, err.Error())
+	}
+	return opt.RRuleString(), nil
+}
+
+// LoadTimezone はタイムゾーン名 (IANA 形式) からロケーションを取得する
+func LoadTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidArgument, name)
+	}
+	return loc, nil
+}
+
+// NextOccurrence は rule に従った occurrenceAt の次の発生日時と、次の発生に引き継ぐ RRULE を返す
+// 発生日時は timezone の壁時計の時刻で計算するため、夏時間の切り替えを跨いでも同じ時刻になる
+// COUNT は発生ごとに1ずつ減らして引き継ぎ、次の発生がない場合は ok が false となる
+func NextOccurrence(rule, timezone string, occurrenceAt time.Time) (next time.Time, nextRule string, ok bool, err error) {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+	opt, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return time.Time{}, "", false, fmt.Errorf("%w: invalid recurrence: %s", ErrInvalidArgument, err.Error())
+	}
+	if opt.Count == 1 {
+		return time.Time{}, "", false, nil
+	}
+
+	opt.Dtstart = occurrenceAt.In(loc)
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return time.Time{}, "", false, fmt.Errorf("%w: invalid recurrence: %s", ErrInvalidArgument, err.Error())
+	}
+	next = r.After(opt.Dtstart, false)
+	if next.IsZero() {
+		return time.Time{}, "", false, nil
+	}
+
+	if opt.Count > 1 {
+		opt.Count--
+	}
+	opt.Dtstart = time.Time{}
+	return next, opt.RRuleString(), true, nil
+}
+
+// AdvanceRecurrence は rule の発生から n 回後の発生に引き継ぐ RRULE を返す (NextOccurrence を n 回繰り返した場合と同じく COUNT を減らす)
+// COUNT が n 以下の場合は、n 回後の発生を系列の最後の発生とするため COUNT を1とする
+func AdvanceRecurrence(rule string, n int) (string, error) {
+	if rule == "" || n <= 0 {
+		return rule, nil
+	}
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid recurrence: %s", ErrInvalidArgument, err.Error())
+	}
+	if opt.Count == 0 {
+		return rule, nil
+	}
+	opt.Count = max(opt.Count-n, 1)
+	return opt.RRuleString(), nil
+}
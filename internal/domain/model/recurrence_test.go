@@ -0,0 +1,109 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		rule         string
+		timezone     string
+		occurrenceAt time.Time
+		wantNext     time.Time
+		wantRule     string
+		wantOK       bool
+	}{
+		{
+			name:         "weekly",
+			rule:         "FREQ=WEEKLY;BYDAY=MO",
+			timezone:     "Asia/Tokyo",
+			occurrenceAt: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			wantNext:     time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			wantRule:     "FREQ=WEEKLY;BYDAY=MO",
+			wantOK:       true,
+		},
+		{
+			name:         "keeps wall clock time across DST",
+			rule:         "FREQ=WEEKLY",
+			timezone:     "America/New_York",
+			occurrenceAt: time.Date(2025, 3, 3, 9, 0, 0, 0, newYork),
+			wantNext:     time.Date(2025, 3, 10, 9, 0, 0, 0, newYork),
+			wantRule:     "FREQ=WEEKLY",
+			wantOK:       true,
+		},
+		{
+			name:         "decrements count",
+			rule:         "FREQ=DAILY;COUNT=3",
+			timezone:     "UTC",
+			occurrenceAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantNext:     time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantRule:     "FREQ=DAILY;COUNT=2",
+			wantOK:       true,
+		},
+		{
+			name:         "last occurrence by count",
+			rule:         "FREQ=DAILY;COUNT=1",
+			timezone:     "UTC",
+			occurrenceAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOK:       false,
+		},
+		{
+			name:         "last occurrence by until",
+			rule:         "FREQ=DAILY;UNTIL=20250101T120000Z",
+			timezone:     "UTC",
+			occurrenceAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOK:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNext, gotRule, gotOK, err := model.NextOccurrence(tt.rule, tt.timezone, tt.occurrenceAt)
+			if err != nil {
+				t.Fatalf("NextOccurrence() failed: %v", err)
+			}
+			if gotOK != tt.wantOK {
+				t.Fatalf("NextOccurrence() ok = %v, want %v", gotOK, tt.wantOK)
+			}
+			if !gotNext.Equal(tt.wantNext) {
+				t.Errorf("NextOccurrence() next = %v, want %v", gotNext, tt.wantNext)
+			}
+			if gotRule != tt.wantRule {
+				t.Errorf("NextOccurrence() rule = %q, want %q", gotRule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestAdvanceRecurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		n    int
+		want string
+	}{
+		{name: "without count", rule: "FREQ=WEEKLY;BYDAY=MO", n: 2, want: "FREQ=WEEKLY;BYDAY=MO"},
+		{name: "decrements count", rule: "FREQ=DAILY;COUNT=5", n: 2, want: "FREQ=DAILY;COUNT=3"},
+		{name: "keeps the last occurrence", rule: "FREQ=DAILY;COUNT=2", n: 3, want: "FREQ=DAILY;COUNT=1"},
+		{name: "not recurring", rule: "", n: 1, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.AdvanceRecurrence(tt.rule, tt.n)
+			if err != nil {
+				t.Fatalf("AdvanceRecurrence() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("AdvanceRecurrence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
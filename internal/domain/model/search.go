@@ -0,0 +1,152 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSearchQueryLength は検索キーワードの最大の文字数
+const MaxSearchQueryLength = 100
+
+const (
+	// HighlightStart は検索キーワードに一致した箇所の開始を表す文字列
+	HighlightStart = "<mark>"
+	// HighlightEnd は検索キーワードに一致した箇所の終了を表す文字列
+	HighlightEnd = "</mark>"
+)
+
+const (
+	// snippetLength はスニペットの最大の文字数 (省略記号を除く)
+	snippetLength = 80
+	// snippetLeading はスニペットで最初に一致した箇所より前に含める文字数
+	snippetLeading = 20
+	// snippetEllipsis はスニペットで省略した箇所を表す文字列
+	snippetEllipsis = "…"
+)
+
+// TodoSearchMatch は検索キーワードに一致したTodoの関連度とハイライトを表す
+type TodoSearchMatch struct {
+	// Rank は関連度 (大きいほど関連度が高く、値の尺度はデータストアによって異なる)
+	Rank float64 `json:"rank"`
+	// Title は一致した箇所を HighlightStart と HighlightEnd で囲んだタイトル (HTML のエスケープはしない)
+	Title string `json:"title"`
+	// Snippet は最初に一致した箇所の前後を抜き出し、一致した箇所を囲んだ内容
+	Snippet string `json:"snippet"`
+}
+
+// SearchTerms は検索キーワードを文字と数字以外 (空白や記号) で区切った検索語の一覧を返す
+func SearchTerms(q string) []string {
+	return strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsMark(r)
+	})
+}
+
+// SearchRank は全ての検索語がタイトルまたは内容に含まれるかと、その関連度を返す
+// (PostgreSQL 以外のリポジトリで検索する場合に使う)
+// 関連度は PostgreSQL の重みに合わせ、タイトルに含まれる回数を 1.0、内容に含まれる回数を 0.4 として合計する
+func SearchRank(t *Todo, terms []string) (float64, bool) {
+	title := strings.ToLower(t.Title)
+	content := strings.ToLower(t.Content)
+
+	var rank float64
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		n := float64(strings.Count(title, term)) + 0.4*float64(strings.Count(content, term))
+		if n == 0 {
+			return 0, false
+		}
+		rank += n
+	}
+	return rank, true
+}
+
+// NewTodoSearchMatch は terms に一致した箇所をハイライトした model.TodoSearchMatch を作成する
+func NewTodoSearchMatch(t *Todo, terms []string, rank float64) *TodoSearchMatch {
+	title := []rune(t.Title)
+	content := []rune(t.Content)
+
+	titleMatches := matchRanges(title, terms)
+	contentMatches := matchRanges(content, terms)
+
+	start := 0
+	if len(contentMatches) > 0 {
+		start = max(contentMatches[0][0]-snippetLeading, 0)
+	}
+	end := min(start+snippetLength, len(content))
+	// 末尾付近で一致した場合も、できるだけ snippetLength の文字数を含める
+	start = max(end-snippetLength, 0)
+
+	return &TodoSearchMatch{
+		Rank:    rank,
+		Title:   highlight(title, titleMatches, 0, len(title)),
+		Snippet: highlight(content, contentMatches, start, end),
+	}
+}
+
+// matchRanges は text の中で terms のいずれかに大文字と小文字を区別せずに一致する範囲を、
+// 重なりや隣接をまとめて [開始, 終了) の rune の位置で返す
+func matchRanges(text []rune, terms []string) [][2]int {
+	lower := lowerRunes(text)
+	matched := make([]bool, len(text))
+	for _, term := range terms {
+		t := lowerRunes([]rune(term))
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == string(t) {
+				for j := i; j < i+len(t); j++ {
+					matched[j] = true
+				}
+			}
+		}
+	}
+
+	var ranges [][2]int
+	for i := 0; i < len(matched); i++ {
+		if !matched[i] {
+			continue
+		}
+		j := i
+		for j < len(matched) && matched[j] {
+			j++
+		}
+		ranges = append(ranges, [2]int{i, j})
+		i = j
+	}
+	return ranges
+}
+
+// lowerRunes は rune ごとに小文字にした rune の一覧を返す (位置を変えないため strings.ToLower は使わない)
+func lowerRunes(text []rune) []rune {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// highlight は text の [start, end) の範囲を、ranges の範囲を囲んだ文字列で返す (前後を省略した場合は省略記号を付ける)
+func highlight(text []rune, ranges [][2]int, start, end int) string {
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(snippetEllipsis)
+	}
+	pos := start
+	for _, r := range ranges {
+		from, to := max(r[0], start), min(r[1], end)
+		if from >= to {
+			continue
+		}
+		b.WriteString(string(text[pos:from]))
+		b.WriteString(HighlightStart)
+		b.WriteString(string(text[from:to]))
+		b.WriteString(HighlightEnd)
+		pos = to
+	}
+	b.WriteString(string(text[pos:end]))
+	if end < len(text) {
+		b.WriteString(snippetEllipsis)
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []string
+	}{
+		{name: "splits by spaces", q: " 掃除　洗濯 ", want: []string{"掃除", "洗濯"}},
+		{name: "splits by symbols", q: "go-lang, 料理!", want: []string{"go", "lang", "料理"}},
+		{name: "symbols only", q: "!?", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.SearchTerms(tt.q)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SearchTerms() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewTodoSearchMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		todo  model.Todo
+		terms []string
+		want  model.TodoSearchMatch
+	}{
+		{
+			name:  "highlights case-insensitively",
+			todo:  model.Todo{Title: "Buy Milk and milk tea", Content: "牛乳を買う"},
+			terms: []string{"milk"},
+			want: model.TodoSearchMatch{
+				Rank:    1,
+				Title:   "Buy <mark>Milk</mark> and <mark>milk</mark> tea",
+				Snippet: "牛乳を買う",
+			},
+		},
+		{
+			name:  "merges overlapping matches",
+			todo:  model.Todo{Title: "掃除をする", Content: "部屋の掃除"},
+			terms: []string{"掃除", "除を"},
+			want: model.TodoSearchMatch{
+				Rank:    1,
+				Title:   "<mark>掃除を</mark>する",
+				Snippet: "部屋の<mark>掃除</mark>",
+			},
+		},
+		{
+			name:  "snippet around first match in long content",
+			todo:  model.Todo{Title: "title", Content: strings.Repeat("あ", 50) + "洗濯" + strings.Repeat("い", 100)},
+			terms: []string{"洗濯"},
+			want: model.TodoSearchMatch{
+				Rank:    1,
+				Title:   "title",
+				Snippet: "…" + strings.Repeat("あ", 20) + "<mark>洗濯</mark>" + strings.Repeat("い", 58) + "…",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.NewTodoSearchMatch(&tt.todo, tt.terms, 1)
+			if diff := cmp.Diff(tt.want, *got); diff != "" {
+				t.Errorf("NewTodoSearchMatch() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MaxSyncChanges は同期で1回に送信できる変更の最大数
+const MaxSyncChanges = 500
+
+// ParseSyncToken は同期トークンを解析する (空文字列の場合は全ての変更を返す 0 を返す)
+// 同期トークンはリポジトリが払い出す単調増加の値で、クライアントは値を解釈せずに次回の同期に指定する
+func ParseSyncToken(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	token, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || token < 0 {
+		return 0, fmt.Errorf("%w: malformed sync token %q", ErrInvalidArgument, s)
+	}
+	return token, nil
+}
+
+// FormatSyncToken は同期トークンを文字列に変換する
+func FormatSyncToken(token int64) string {
+	return strconv.FormatInt(token, 10)
+}
+
+// TodoTombstone は削除したTodoを表す (同期したクライアントからTodoを削除させるために返す)
+type TodoTombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// Purged は完全に削除したか (false の場合はゴミ箱にあり、復元すると変更されたTodoとして返す)
+	Purged bool `json:"purged"`
+}
+
+// TodoChanges はリポジトリが返す、同期トークン以降に変更されたTodo
+type TodoChanges struct {
+	// Todos は作成または変更されたTodo (ゴミ箱に移動したTodoを含む)
+	Todos []Todo
+	// Purged は完全に削除したTodo
+	Purged []TodoTombstone
+	// Token はこの時点までの変更を含む同期トークン (次回の同期に指定する)
+	Token int64
+}
+
+// TodoSync は同期トークン以降の変更の差分を表す
+type TodoSync struct {
+	// Todos は作成または変更されたTodo (ゴミ箱にあるTodoは含まない)
+	Todos []Todo `json:"todos"`
+	// Tombstones はゴミ箱に移動または完全に削除したTodo (同期トークンを指定せずに最初から同期する場合は空)
+	Tombstones []TodoTombstone `json:"tombstones"`
+	// Token は次回の同期に指定する同期トークン
+	Token string `json:"token"`
+}
+
+// SyncOperationType はクライアントで行った変更の種類を表す
+type SyncOperationType string
+
+const (
+	// SyncOperationCreate はTodoを作成する
+	SyncOperationCreate SyncOperationType = "create"
+	// SyncOperationUpdate はTodoを全体で更新する
+	SyncOperationUpdate SyncOperationType = "update"
+	// SyncOperationPatch はTodoを部分更新する (競合した場合、変更した項目が重ならなければサーバーの変更とマージする)
+	SyncOperationPatch SyncOperationType = "patch"
+	// SyncOperationDelete はTodoをゴミ箱に移動する
+	SyncOperationDelete SyncOperationType = "delete"
+)
+
+// TodoSyncChange はクライアントでオフラインの間に行った1件の変更を表す
+type TodoSyncChange struct {
+	// ClientID はクライアントで変更を識別するID (結果に同じ値を設定する)
+	// 同じ送信の後の変更の ID や親のIDに指定すると、作成したTodoのIDに置き換える
+	ClientID string            `json:"client_id"`
+	Op       SyncOperationType `json:"op"`
+	// ID は update、patch、delete の対象のTodoのID
+	ID string `json:"id"`
+	// Version はクライアントが変更の元にしたTodoのバージョン (update、patch、delete で必須)
+	Version int `json:"version"`
+	// Todo は create と update の内容
+	Todo *Todo `json:"todo"`
+	// Patch は patch で適用する部分更新
+	Patch *TodoPatch `json:"patch"`
+	// Cascade が true の場合、delete で子孫のTodoも含めてゴミ箱に移動する
+	Cascade bool `json:"cascade"`
+}
+
+// TodoSyncPush はクライアントからまとめて送信する変更を表す
+type TodoSyncPush struct {
+	Changes []TodoSyncChange `json:"changes"`
+}
+
+// Validate は変更の数を検証する (各変更の内容は適用する際に個別に検証する)
+func (p *TodoSyncPush) Validate() error {
+	if len(p.Changes) == 0 || len(p.Changes) > MaxSyncChanges {
+		return fmt.Errorf("%w: changes must contain between 1 and %d items", ErrInvalidArgument, MaxSyncChanges)
+	}
+	return nil
+}
+
+// SyncResolution は変更を適用した結果を表す
+type SyncResolution string
+
+const (
+	// SyncResolutionApplied は変更をそのまま適用した
+	SyncResolutionApplied SyncResolution = "applied"
+	// SyncResolutionMerged は元にしたバージョンより後のサーバーの変更と重ならないため、マージして適用した
+	SyncResolutionMerged SyncResolution = "merged"
+	// SyncResolutionConflict はサーバーの変更と競合したため適用しなかった (Todo はサーバーの現在のTodo)
+	SyncResolutionConflict SyncResolution = "conflict"
+	// SyncResolutionRejected は変更の内容が正しくないなどの理由で適用しなかった
+	SyncResolutionRejected SyncResolution = "rejected"
+)
+
+// TodoSyncResult はクライアントの1件の変更を適用した結果を表す
+type TodoSyncResult struct {
+	ClientID   string            `json:"client_id,omitempty"`
+	Op         SyncOperationType `json:"op"`
+	ID         string            `json:"id,omitempty"`
+	Resolution SyncResolution    `json:"resolution"`
+	// Todo は適用した後のTodo (競合した場合はサーバーの現在のTodo、delete とサーバーで削除されていた場合は nil)
+	Todo *Todo `json:"todo,omitempty"`
+	// ConflictingFields は patch で競合した項目 (クライアントとサーバーの両方で変更した項目)
+	ConflictingFields []string `json:"conflicting_fields,omitempty"`
+	// Err は適用しなかった理由 (適用した場合は nil)
+	Err error `json:"-"`
+}
+
+// Fields は部分更新で変更する項目の名前を返す (DiffTodo の項目の名前と同じ)
+func (p *TodoPatch) Fields() []string {
+	var fields []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"title", p.Title != nil},
+		{"content", p.Content != nil},
+		{"status", p.Status != nil || p.Done != nil},
+		{"priority", p.Priority != nil},
+		{"tags", p.Tags != nil},
+		{"parent_id", p.ParentID != nil},
+		{"auto_complete", p.AutoComplete != nil},
+		{"due_at", p.DueAt.Set},
+		{"recurrence", p.Recurrence != nil},
+		{"timezone", p.Timezone != nil},
+	} {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// ConflictingFields は base から current への変更と、部分更新で変更する項目のうち重なる項目を返す
+// 重なる項目がなければ、部分更新を current に適用してもサーバーの変更を上書きしない
+func (p *TodoPatch) ConflictingFields(base, current *Todo) []string {
+	changed := make(map[string]bool)
+	for _, c := range DiffTodo(base, current) {
+		changed[c.Field] = true
+	}
+	var fields []string
+	for _, f := range p.Fields() {
+		if changed[f] {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,83 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestTodoPatch_ConflictingFields(t *testing.T) {
+	base := model.Todo{Title: "a", Content: "c", Status: model.TodoStatusTodo, Tags: []string{}}
+	title := "b"
+	priority := 2
+	done := true
+
+	tests := []struct {
+		name    string
+		current func(t model.Todo) model.Todo
+		patch   model.TodoPatch
+		want    []string
+	}{
+		{
+			name:    "unchanged on the server",
+			current: func(t model.Todo) model.Todo { return t },
+			patch:   model.TodoPatch{Title: &title},
+		},
+		{
+			name:    "different fields changed on the server",
+			current: func(t model.Todo) model.Todo { t.Content = "changed"; return t },
+			patch:   model.TodoPatch{Title: &title, Priority: &priority},
+		},
+		{
+			name:    "same field changed on the server",
+			current: func(t model.Todo) model.Todo { t.Title = "changed"; t.Content = "changed"; return t },
+			patch:   model.TodoPatch{Title: &title, Priority: &priority},
+			want:    []string{"title"},
+		},
+		{
+			name:    "done conflicts with a status change",
+			current: func(t model.Todo) model.Todo { t.Status = model.TodoStatusInProgress; return t },
+			patch:   model.TodoPatch{Done: &done},
+			want:    []string{"status"},
+		},
+		{
+			name:    "empty tags are equal to nil tags",
+			current: func(t model.Todo) model.Todo { t.Tags = nil; return t },
+			patch:   model.TodoPatch{Tags: &[]string{"x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := tt.current(base)
+			got := tt.patch.ConflictingFields(&base, &current)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ConflictingFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSyncToken(t *testing.T) {
+	tests := []struct {
+		token   string
+		want    int64
+		wantErr bool
+	}{
+		{token: "", want: 0},
+		{token: "42", want: 42},
+		{token: model.FormatSyncToken(1 << 40), want: 1 << 40},
+		{token: "-1", wantErr: true},
+		{token: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := model.ParseSyncToken(tt.token)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseSyncToken(%q) error = %v, wantErr %v", tt.token, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSyncToken(%q) = %d, want %d", tt.token, got, tt.want)
+		}
+	}
+}
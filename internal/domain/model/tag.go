@@ -0,0 +1,57 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagNameLength はタグ名の最大文字数
+const MaxTagNameLength = 50
+
+// Tag はTodoに付与するタグを表す
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TagMatch はタグによる絞り込みの一致条件を表す
+type TagMatch string
+
+const (
+	// TagMatchAny は指定したタグのいずれかが付与されているTodoに一致する
+	TagMatchAny TagMatch = "any"
+	// TagMatchAll は指定したタグが全て付与されているTodoに一致する
+	TagMatchAll TagMatch = "all"
+)
+
+// NormalizeTagName はタグ名の前後の空白を除去し、妥当性を検証する
+func NormalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: tag name must not be empty", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", fmt.Errorf("%w: tag name must be at most %d characters", ErrInvalidArgument, MaxTagNameLength)
+	}
+	return name, nil
+}
+
+// NormalizeTags はタグ名の一覧を正規化し、重複を除いて名前順に並べる
+// nil は「指定なし」を表すため nil のまま返す
+func NormalizeTags(names []string) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		n, err := NormalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, n)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
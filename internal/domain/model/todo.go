@@ -1,14 +1,308 @@
 package model
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TodoStatus はTodoの進捗状態を表す
+type TodoStatus string
+
+const (
+	// TodoStatusTodo は未着手
+	TodoStatusTodo TodoStatus = "todo"
+	// TodoStatusInProgress は作業中
+	TodoStatusInProgress TodoStatus = "in_progress"
+	// TodoStatusBlocked は作業が止まっている状態
+	TodoStatusBlocked TodoStatus = "blocked"
+	// TodoStatusDone は完了
+	TodoStatusDone TodoStatus = "done"
+	// TodoStatusCancelled は中止
+	TodoStatusCancelled TodoStatus = "cancelled"
+)
+
+// todoStatusTransitions は各ステータスから遷移可能なステータスの一覧
+var todoStatusTransitions = map[TodoStatus][]TodoStatus{
+	TodoStatusTodo:       {TodoStatusInProgress, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled},
+	TodoStatusInProgress: {TodoStatusTodo, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled},
+	TodoStatusBlocked:    {TodoStatusTodo, TodoStatusInProgress, TodoStatusCancelled},
+	TodoStatusDone:       {TodoStatusTodo},
+	TodoStatusCancelled:  {TodoStatusTodo},
+}
+
+// IsValid は定義済みのステータスかどうかを返す
+func (s TodoStatus) IsValid() bool {
+	_, ok := todoStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo は next への遷移が許可されているかどうかを返す
+func (s TodoStatus) CanTransitionTo(next TodoStatus) bool {
+	if s == next {
+		return true
+	}
+	return slices.Contains(todoStatusTransitions[s], next)
+}
+
+// IsClosed は完了または中止のステータスかどうかを返す
+func (s TodoStatus) IsClosed() bool {
+	return s == TodoStatusDone || s == TodoStatusCancelled
+}
+
+// MaxTodoDepth は親子関係の最大の深さ (ルートのTodoを1とする)
+const MaxTodoDepth = 5
+
+const (
+	// MinPriority は優先度の最小値
+	MinPriority = 0
+	// MaxPriority は優先度の最大値
+	MaxPriority = 4
+)
+
 // Todo はTodoモデルを表す
 type Todo struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Done    bool   `json:"done"`
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Content  string     `json:"content"`
+	Status   TodoStatus `json:"status"`
+	Priority int        `json:"priority"`
+	Tags     []string   `json:"tags"`
+	// ParentID は親のTodoのID (ルートの場合は nil)
+	ParentID *string `json:"parent_id"`
+	// Position は兄弟間での並び順
+	Position int `json:"position"`
+	// AutoComplete が true の場合、子が全て完了または中止になると自動で完了にする
+	AutoComplete bool `json:"auto_complete"`
+	// BlockedBy はこのTodoをブロックしているTodoのIDの一覧 (依存関係のAPIで更新する)
+	BlockedBy []string `json:"blocked_by"`
+	// DueAt は期限日時
+	DueAt *time.Time `json:"due_at"`
+	// Recurrence は繰り返しのルール (iCalendar の RRULE 形式、例: FREQ=WEEKLY;BYDAY=MO)
+	Recurrence string `json:"recurrence"`
+	// Timezone は繰り返しの計算に使うタイムゾーン (IANA 形式)
+	Timezone string `json:"timezone"`
+	// SeriesID は繰り返しTodoの系列のID (同じ系列の発生で共通)
+	SeriesID *string `json:"series_id"`
+	// OccurrenceAt は繰り返しのルール上の発生日時 (期限をこの発生のみ変更しても次の発生はこの日時から計算する)
+	OccurrenceAt *time.Time `json:"occurrence_at"`
+	// CompletedAt は done になった日時 (Status から導出され、done でない場合は nil)
+	CompletedAt *time.Time `json:"completed_at"`
+	// ArchivedAt はアーカイブした日時 (アーカイブされていない場合は nil)
+	ArchivedAt *time.Time `json:"archived_at"`
+	// DeletedAt はゴミ箱に移動した日時 (ゴミ箱にない場合は nil)
+	DeletedAt *time.Time `json:"deleted_at"`
+	// Version は更新のたびに増えるバージョン
+	Version int `json:"version"`
+	// UpdatedAt は最後に更新した日時
+	UpdatedAt time.Time `json:"updated_at"`
+	// Search は検索キーワードを指定して取得した場合の一致の情報 (それ以外の場合は nil)
+	Search *TodoSearchMatch `json:"search,omitempty"`
+	// Done は Status から導出される完了フラグ (後方互換のために残している)
+	Done bool `json:"done"`
+}
+
+// Validate はTodoの値が正しいかを検証し、タグを正規化する
+func (t *Todo) Validate() error {
+	if !t.Status.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidArgument, t.Status)
+	}
+	if t.Priority < MinPriority || t.Priority > MaxPriority {
+		return fmt.Errorf("%w: priority must be between %d and %d", ErrInvalidArgument, MinPriority, MaxPriority)
+	}
+	tags, err := NormalizeTags(t.Tags)
+	if err != nil {
+		return err
+	}
+	t.Tags = tags
+
+	if t.Timezone == "" {
+		t.Timezone = DefaultTimezone
+	}
+	if _, err := LoadTimezone(t.Timezone); err != nil {
+		return err
+	}
+	if t.Recurrence, err = NormalizeRecurrence(t.Recurrence); err != nil {
+		return err
+	}
+	if t.Recurrence != "" && t.DueAt == nil {
+		return fmt.Errorf("%w: due_at is required for recurring todos", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// IsRecurring は繰り返しのTodoかどうかを返す
+func (t *Todo) IsRecurring() bool {
+	return t.Recurrence != ""
 }
 
-// TodoQuery はTodoの検索クエリを表す
+// TodoPatch はTodoの部分更新の内容を表す (nil のフィールドと、指定されていない Nullable のフィールドは更新しない)
+type TodoPatch struct {
+	Title    *string     `json:"title"`
+	Content  *string     `json:"content"`
+	Status   *TodoStatus `json:"status"`
+	Priority *int        `json:"priority"`
+	Done     *bool       `json:"done"`
+	Tags     *[]string   `json:"tags"`
+	// ParentID に空文字を指定した場合はルートに移動する
+	ParentID     *string `json:"parent_id"`
+	AutoComplete *bool   `json:"auto_complete"`
+	// DueAt に null を指定した場合は期限を外す
+	DueAt      Nullable[time.Time] `json:"due_at"`
+	Recurrence *string             `json:"recurrence"`
+	Timezone   *string             `json:"timezone"`
+}
+
+// Apply は t に部分更新の内容を適用したTodoを返す
+func (p *TodoPatch) Apply(t Todo) Todo {
+	if p.Title != nil {
+		t.Title = *p.Title
+	}
+	if p.Content != nil {
+		t.Content = *p.Content
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.Done != nil {
+		// status が指定されていない場合は done から導出させる
+		t.Done = *p.Done
+		t.Status = ""
+	}
+	if p.Status != nil {
+		t.Status = *p.Status
+	}
+	if p.Tags != nil {
+		t.Tags = *p.Tags
+		if t.Tags == nil {
+			t.Tags = []string{}
+		}
+	}
+	if p.ParentID != nil {
+		t.ParentID = p.ParentID
+	}
+	if p.AutoComplete != nil {
+		t.AutoComplete = *p.AutoComplete
+	}
+	if p.DueAt.Set {
+		t.DueAt = p.DueAt.Value
+	}
+	if p.Recurrence != nil {
+		t.Recurrence = *p.Recurrence
+	}
+	if p.Timezone != nil {
+		t.Timezone = *p.Timezone
+	}
+	return t
+}
+
+// TodoQuery はTodoの検索クエリを表す (ビューとして保存する際は JSON で表す)
 type TodoQuery struct {
-	Status string `form:"status"`
+	Status   TodoStatus `form:"status" json:"status,omitempty"`
+	Tags     []string   `form:"tag" json:"tag,omitempty"`
+	TagMatch TagMatch   `form:"tag_match" json:"tag_match,omitempty"`
+	// Actionable が true の場合、未完了かつブロックしているTodoが全て完了または中止のTodoのみを取得する
+	Actionable bool `form:"actionable" json:"actionable,omitempty"`
+	// SeriesID を指定した場合、その繰り返しの系列のTodoのみを取得する
+	SeriesID string `form:"series_id" json:"series_id,omitempty"`
+	// Archived はアーカイブ状態による絞り込み (省略した場合はアーカイブされていないTodoのみを取得する)
+	Archived ArchivedFilter `form:"archived" json:"archived,omitempty"`
+	// Q はタイトルと内容の検索キーワード (空白で区切った全ての語を含むTodoを関連度の高い順に取得する)
+	Q string `form:"q" json:"q,omitempty"`
+	// MinPriority を指定した場合、優先度がその値以上のTodoのみを取得する
+	MinPriority *int `form:"min_priority" json:"min_priority,omitempty"`
+	// Due は期限による絞り込み (取得する時点の日時を基準に ResolveDue で解決する)
+	Due DueFilter `form:"due" json:"due,omitempty"`
+	// Timezone は Due の日付の境界の計算に使うタイムゾーン (IANA 形式)
+	Timezone string `form:"timezone" json:"timezone,omitempty"`
+	// DueRange は ResolveDue で Due を解決した範囲 (リポジトリはこの範囲で絞り込む)
+	DueRange *DueRange `form:"-" json:"-"`
+}
+
+// Validate は検索クエリの値が正しいかを検証し、省略された値を補完する
+func (q *TodoQuery) Validate() error {
+	if q.Status != "" && !q.Status.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidArgument, q.Status)
+	}
+
+	switch q.TagMatch {
+	case "":
+		q.TagMatch = TagMatchAny
+	case TagMatchAny, TagMatchAll:
+	default:
+		return fmt.Errorf("%w: tag_match must be %q or %q", ErrInvalidArgument, TagMatchAny, TagMatchAll)
+	}
+	tags, err := NormalizeTags(q.Tags)
+	if err != nil {
+		return err
+	}
+	q.Tags = tags
+
+	switch q.Archived {
+	case "":
+		q.Archived = ArchivedFilterFalse
+	case ArchivedFilterFalse, ArchivedFilterTrue, ArchivedFilterAny:
+	default:
+		return fmt.Errorf("%w: archived must be %q, %q or %q", ErrInvalidArgument, ArchivedFilterTrue, ArchivedFilterFalse, ArchivedFilterAny)
+	}
+
+	q.Q = strings.TrimSpace(q.Q)
+	if utf8.RuneCountInString(q.Q) > MaxSearchQueryLength {
+		return fmt.Errorf("%w: q must be at most %d characters", ErrInvalidArgument, MaxSearchQueryLength)
+	}
+	if q.Q != "" && len(SearchTerms(q.Q)) == 0 {
+		return fmt.Errorf("%w: q must contain at least one letter or number", ErrInvalidArgument)
+	}
+
+	if q.MinPriority != nil && (*q.MinPriority < MinPriority || *q.MinPriority > MaxPriority) {
+		return fmt.Errorf("%w: min_priority must be between %d and %d", ErrInvalidArgument, MinPriority, MaxPriority)
+	}
+	if q.Due != "" && !q.Due.IsValid() {
+		return fmt.Errorf("%w: unknown due %q", ErrInvalidArgument, q.Due)
+	}
+	if q.Timezone == "" {
+		q.Timezone = DefaultTimezone
+	}
+	if _, err := LoadTimezone(q.Timezone); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MatchTags はTodoのタグ (tags) が検索条件のタグに一致するかを返す (検索条件のタグがない場合は true)
+func (q *TodoQuery) MatchTags(tags []string) bool {
+	if len(q.Tags) == 0 {
+		return true
+	}
+	if q.TagMatch == TagMatchAll {
+		return !slices.ContainsFunc(q.Tags, func(w string) bool { return !slices.Contains(tags, w) })
+	}
+	return slices.ContainsFunc(q.Tags, func(w string) bool { return slices.Contains(tags, w) })
+}
+
+// ResolveDue は now を基準に Due を解決して DueRange を設定する (Validate の後に呼び出すこと)
+func (q *TodoQuery) ResolveDue(now time.Time) {
+	q.DueRange = nil
+	if q.Due == "" {
+		return
+	}
+	loc, err := LoadTimezone(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	q.DueRange = q.Due.Resolve(now, loc)
+}
+
+// TodoListStamp はTodoの一覧が変わったかを判定するための値を表す
+type TodoListStamp struct {
+	// Count は検索クエリに一致するTodoの件数
+	Count int
+	// LastModified は全てのTodo (ゴミ箱にあるTodoを含む) の最終更新日時 (Todoが存在しない場合はゼロ値)
+	// 更新により検索クエリに一致しなくなったTodoも反映するため、検索クエリによらず全てのTodoから求める
+	LastModified time.Time
+	// DueRange は期限による絞り込みを解決した範囲 (同じ検索クエリでも取得する日時により変わる)
+	DueRange *DueRange
 }
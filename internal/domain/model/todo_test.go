@@ -0,0 +1,57 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestTodoPatch_Apply_DueAt(t *testing.T) {
+	current := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	next := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		body       string
+		want       *time.Time
+		wantFields []string
+	}{
+		{
+			name:       "omitted keeps the due date",
+			body:       `{"title": "a"}`,
+			want:       &current,
+			wantFields: []string{"title"},
+		},
+		{
+			name:       "null clears the due date",
+			body:       `{"due_at": null}`,
+			want:       nil,
+			wantFields: []string{"due_at"},
+		},
+		{
+			name:       "value replaces the due date",
+			body:       `{"due_at": "2025-02-01T09:00:00Z"}`,
+			want:       &next,
+			wantFields: []string{"due_at"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch model.TodoPatch
+			if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			got := patch.Apply(model.Todo{Title: "a", DueAt: &current})
+			if diff := cmp.Diff(tt.want, got.DueAt); diff != "" {
+				t.Errorf("Apply() due_at mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantFields, patch.Fields()); diff != "" {
+				t.Errorf("Fields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxViewNameLength はビュー名の最大文字数
+const MaxViewNameLength = 100
+
+// View は名前を付けて保存したTodoの検索クエリ (スマートビュー) を表す
+type View struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Query は保存した検索クエリ (期限による絞り込みはビューのTodoを取得する時点で解決する)
+	Query     TodoQuery `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate はビューの値が正しいかを検証し、名前と検索クエリを正規化する
+func (v *View) Validate() error {
+	v.Name = strings.TrimSpace(v.Name)
+	if v.Name == "" {
+		return fmt.Errorf("%w: view name must not be empty", ErrInvalidArgument)
+	}
+	if utf8.RuneCountInString(v.Name) > MaxViewNameLength {
+		return fmt.Errorf("%w: view name must be at most %d characters", ErrInvalidArgument, MaxViewNameLength)
+	}
+	return v.Query.Validate()
+}
@@ -0,0 +1,215 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookEventTest は「テストイベントを送信」で配信するイベントの種類 (イベントの種類による絞り込みの対象外)
+	WebhookEventTest TodoEventType = "webhook.test"
+
+	// MaxWebhookAttempts は配信を試みる回数の上限 (超えた場合は dead にする)
+	MaxWebhookAttempts = 8
+	// WebhookMinBackoff は配信に失敗した場合に再試行するまでの最初の間隔
+	WebhookMinBackoff = 30 * time.Second
+	// WebhookMaxBackoff は配信に失敗した場合に再試行するまでの間隔の上限
+	WebhookMaxBackoff = time.Hour
+	// WebhookSignatureTolerance は受信側で署名のタイムスタンプを許容する現在日時との差 (リプレイ攻撃の対策)
+	WebhookSignatureTolerance = 5 * time.Minute
+)
+
+// 配信のリクエストに設定するヘッダー
+const (
+	// WebhookIDHeader は配信のID (再試行しても変わらないため、受信側で重複を除くために使う)
+	WebhookIDHeader = "X-Webhook-ID"
+	// WebhookEventHeader はイベントの種類
+	WebhookEventHeader = "X-Webhook-Event"
+	// WebhookSignatureHeader は署名 ("t=<UNIX 時間>,v1=<HMAC-SHA256 の16進数>" の形式)
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// ErrInvalidWebhookSignature はWebhookの署名が正しくないことを表す
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// webhookEventTypes はWebhookで絞り込めるイベントの種類
+var webhookEventTypes = []TodoEventType{TodoEventCreated, TodoEventUpdated, TodoEventCompleted, TodoEventDeleted}
+
+// Webhook はTodoのイベントを配信するWebhookの購読を表す
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events は配信するイベントの種類 (空の場合は全ての種類を配信する)
+	Events []TodoEventType `json:"events"`
+	// Secret は署名に使う秘密鍵 (作成した時のみ返す)
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate はWebhookの値が正しいかを検証し、イベントの種類を正規化する
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an absolute http or https URL", ErrInvalidArgument)
+	}
+	events := []TodoEventType{}
+	for _, e := range w.Events {
+		if !slices.Contains(webhookEventTypes, e) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidArgument, e)
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	w.Events = events
+	return nil
+}
+
+// Matches はイベントの種類がWebhookの配信の対象かを返す
+func (w *Webhook) Matches(eventType TodoEventType) bool {
+	return eventType == WebhookEventTest || len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// NewWebhookSecret は署名に使うランダムな秘密鍵を返す
+func NewWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// SignWebhook は秘密鍵で timestamp と本文に署名し、WebhookSignatureHeader の値を返す
+// 署名は "<UNIX 時間>.<本文>" の HMAC-SHA256
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + webhookMAC(secret, t, body)
+}
+
+// VerifyWebhookSignature は受信側で WebhookSignatureHeader の値を検証する
+// タイムスタンプが now から tolerance より離れている場合は、再送された古いリクエストとして拒否する
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t, sig string
+	for part := range strings.SplitSeq(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			t = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || sig == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidWebhookSignature)
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("%w: timestamp is outside the tolerance", ErrInvalidWebhookSignature)
+	}
+	if !hmac.Equal([]byte(sig), []byte(webhookMAC(secret, t, body))) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidWebhookSignature)
+	}
+	return nil
+}
+
+// webhookMAC は "<t>.<本文>" の HMAC-SHA256 を16進数で返す
+func webhookMAC(secret, t string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliveryStatus はWebhookの配信の状態を表す
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending は配信待ち (再試行待ちを含む)
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded は配信に成功した
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryDead は配信を試みる回数の上限に達したため、配信を諦めた
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery はイベントをWebhookに配信する1件の配信とその結果を表す
+type WebhookDelivery struct {
+	ID        string        `json:"id"`
+	WebhookID string        `json:"webhook_id"`
+	EventID   string        `json:"event_id"`
+	EventType TodoEventType `json:"event_type"`
+	// Payload は配信するリクエストの本文 (イベントの JSON)
+	Payload  json.RawMessage       `json:"payload"`
+	Status   WebhookDeliveryStatus `json:"status"`
+	Attempts int                   `json:"attempts"`
+	// ResponseStatus は最後に配信を試みた際の応答のステータスコード (応答がなかった場合は 0)
+	ResponseStatus int    `json:"response_status"`
+	LastError      string `json:"last_error"`
+	// NextAttemptAt は次に配信を試みる日時 (配信待ちでない場合は nil)
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// NewWebhookDelivery はイベントを webhookID のWebhookに配信する、すぐに配信を試みる配信を返す
+func NewWebhookDelivery(webhookID string, event TodoEvent, now time.Time) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDelivery{
+		WebhookID:     webhookID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}, nil
+}
+
+// RecordAttempt は now に配信を試みた結果 (応答のステータスコードとエラー) を記録する
+// 2xx の応答の場合は成功とし、失敗した場合は回数に応じて間隔を空けて再試行する (上限に達した場合は dead にする)
+func (d *WebhookDelivery) RecordAttempt(now time.Time, status int, err error) {
+	d.Attempts++
+	d.ResponseStatus = status
+	d.LastError = ""
+	if err == nil && (status < 200 || status >= 300) {
+		err = fmt.Errorf("unexpected status: %d", status)
+	}
+	switch {
+	case err == nil:
+		d.Status = WebhookDeliverySucceeded
+		d.NextAttemptAt = nil
+	case d.Attempts >= MaxWebhookAttempts:
+		d.Status = WebhookDeliveryDead
+		d.LastError = err.Error()
+		d.NextAttemptAt = nil
+	default:
+		d.Status = WebhookDeliveryPending
+		d.LastError = err.Error()
+		next := now.Add(WebhookBackoff(d.Attempts))
+		d.NextAttemptAt = &next
+	}
+}
+
+// WebhookBackoff は attempts 回目の失敗の後に再試行するまでの間隔を返す (失敗するたびに倍にし、WebhookMaxBackoff を上限とする)
+func WebhookBackoff(attempts int) time.Duration {
+	d := WebhookMinBackoff
+	for range attempts - 1 {
+		d *= 2
+		if d >= WebhookMaxBackoff {
+			return WebhookMaxBackoff
+		}
+	}
+	return d
+}
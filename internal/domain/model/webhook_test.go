@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"event"}`)
+	signedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	header := model.SignWebhook(secret, signedAt, body)
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: secret, header: header, body: body, now: signedAt.Add(time.Minute)},
+		{name: "wrong secret", secret: "whsec_other", header: header, body: body, now: signedAt, wantErr: true},
+		{name: "tampered body", secret: secret, header: header, body: []byte(`{"id":"other"}`), now: signedAt, wantErr: true},
+		{name: "replayed after tolerance", secret: secret, header: header, body: body, now: signedAt.Add(model.WebhookSignatureTolerance + time.Second), wantErr: true},
+		{name: "timestamp in the future", secret: secret, header: header, body: body, now: signedAt.Add(-model.WebhookSignatureTolerance - time.Second), wantErr: true},
+		{name: "malformed header", secret: secret, header: "v1=abc", body: body, now: signedAt, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := model.VerifyWebhookSignature(tt.secret, tt.header, tt.body, tt.now, model.WebhookSignatureTolerance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyWebhookSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, model.ErrInvalidWebhookSignature) {
+				t.Errorf("VerifyWebhookSignature() error = %v, want %v", err, model.ErrInvalidWebhookSignature)
+			}
+		})
+	}
+}
+
+func TestWebhookDelivery_RecordAttempt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	errTimeout := errors.New("timeout")
+
+	tests := []struct {
+		name          string
+		attempts      int
+		status        int
+		err           error
+		wantStatus    model.WebhookDeliveryStatus
+		wantNext      time.Duration
+		wantLastError string
+	}{
+		{name: "2xx succeeds", status: 204, wantStatus: model.WebhookDeliverySucceeded},
+		{name: "non-2xx is retried", status: 500, wantStatus: model.WebhookDeliveryPending, wantNext: model.WebhookMinBackoff, wantLastError: "unexpected status: 500"},
+		{name: "backoff doubles", attempts: 2, err: errTimeout, wantStatus: model.WebhookDeliveryPending, wantNext: 4 * model.WebhookMinBackoff, wantLastError: "timeout"},
+		{name: "dead after max attempts", attempts: model.MaxWebhookAttempts - 1, err: errTimeout, wantStatus: model.WebhookDeliveryDead, wantLastError: "timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := model.WebhookDelivery{Status: model.WebhookDeliveryPending, Attempts: tt.attempts}
+			d.RecordAttempt(now, tt.status, tt.err)
+
+			if d.Attempts != tt.attempts+1 {
+				t.Errorf("Attempts = %d, want %d", d.Attempts, tt.attempts+1)
+			}
+			if d.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", d.Status, tt.wantStatus)
+			}
+			if d.LastError != tt.wantLastError {
+				t.Errorf("LastError = %q, want %q", d.LastError, tt.wantLastError)
+			}
+			switch {
+			case tt.wantNext == 0 && d.NextAttemptAt != nil:
+				t.Errorf("NextAttemptAt = %v, want nil", *d.NextAttemptAt)
+			case tt.wantNext != 0 && (d.NextAttemptAt == nil || !d.NextAttemptAt.Equal(now.Add(tt.wantNext))):
+				t.Errorf("NextAttemptAt = %v, want %v", d.NextAttemptAt, now.Add(tt.wantNext))
+			}
+		})
+	}
+}
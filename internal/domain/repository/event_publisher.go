@@ -0,0 +1,15 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// EventPublisher はドメインイベントを外部に配信するインターフェース
+// 配信は少なくとも1回 (at-least-once) のため、受信側はイベントのIDで重複を除くこと
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.TodoEvent) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// IdempotencyKey は冪等キーとレスポンスの保存を担当するインターフェース
+// 有効期限を過ぎた冪等キーは存在しないものとして扱う
+type IdempotencyKey interface {
+	// Reserve は冪等キーが存在しない場合に処理中として ttl の間保存し、true を返す
+	// 既に存在する場合は保存せずに既存の内容と false を返す
+	Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error)
+	// Complete は処理中の冪等キーにレスポンスを保存する (有効期限は Reserve の時点から変えない)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// Release は処理中の冪等キーを削除し、同じキーで再試行できるようにする
+	Release(ctx context.Context, key string) error
+	// DeleteExpired は before の時点で有効期限を過ぎた冪等キーを削除し、削除した件数を返す
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
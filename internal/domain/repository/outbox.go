@@ -0,0 +1,24 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// Outbox はドメインイベントのアウトボックスのデータ操作を担当するインターフェース
+// Add は ctx のトランザクションで書き込むため、Todoの書き込みと同じ TxManager.Do の中で呼び出す
+type Outbox interface {
+	// Add はイベントにIDを払い出し、配信待ちとして引数の順に保存する
+	Add(ctx context.Context, events []model.TodoEvent) error
+	// FindPending は now までに配信を試みる日時になった配信待ちのイベントを、保存した順に最大 limit 件返す
+	// 配信を待っている (以前に失敗した) イベントがあるTodoの、それより後のイベントは返さない
+	FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error)
+	// Delete は配信したイベントを削除する
+	Delete(ctx context.Context, seq int64) error
+	// MarkFailed は配信に失敗したイベントの失敗回数を増やし、次に配信を試みる日時を設定する
+	MarkFailed(ctx context.Context, seq int64, lastError string, nextAttemptAt time.Time) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// Tag はタグのデータ操作を担当するインターフェース
+// Todoへのタグの付与は repository.Todo が担当する
+type Tag interface {
+	FindAll(ctx context.Context) ([]model.Tag, error)
+	FindByID(ctx context.Context, id string) (*model.Tag, error)
+	Rename(ctx context.Context, id string, name string) (*model.Tag, error)
+	// Merge は source が付与されたTodoを target に付け替え、source を削除する
+	Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error)
+	Delete(ctx context.Context, id string) error
+}
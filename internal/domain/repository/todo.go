@@ -4,15 +4,56 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/qushot/gin-todo-api/internal/domain/model"
 )
 
 // Todo はTodoのデータ操作を担当するインターフェース
+// FindTrash 以外の取得・更新の操作はゴミ箱にあるTodoを対象としない
+// 作成、更新、ゴミ箱への移動と復元、アーカイブとその解除は同じトランザクションで変更履歴を記録する
 type Todo interface {
 	FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error)
+	// Stamp は query に一致するTodoの件数と、全てのTodoの最終更新日時を返す (DueRange は query の値を設定する)
+	Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error)
 	FindByID(ctx context.Context, id string) (*model.Todo, error)
+	// FindByIDs は ids のTodoをまとめて取得する (見つからないIDは無視し、順番は保証しない)
+	FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error)
 	Create(ctx context.Context, todo model.Todo) (*model.Todo, error)
+	// Update はTodoを更新する
+	// todo.Version には更新の元にしたバージョンを指定し、保存されているバージョンと異なる場合は model.ErrConflict を返す (0 の場合は確認しない)
 	Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error)
+	// Delete はTodoを子孫のTodoも含めてゴミ箱に移動する
 	Delete(ctx context.Context, id string) error
+	// FindChildren は子のTodoを並び順で取得する
+	FindChildren(ctx context.Context, parentID string) ([]model.Todo, error)
+	// FindChildrenByParentIDs は parentIDs の各親の子のTodoをまとめて取得する (同じ親の子は並び順で並べる)
+	FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error)
+	// ReorderChildren は子のTodoを ids の順に並び替える
+	ReorderChildren(ctx context.Context, parentID string, ids []string) error
+	// FindTrash はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+	FindTrash(ctx context.Context) ([]model.Todo, error)
+	// Restore はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+	// 親のTodoがゴミ箱にある場合は model.ErrConflict を返す
+	Restore(ctx context.Context, id string) (*model.Todo, error)
+	// Purge はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する
+	Purge(ctx context.Context, id string) error
+	// PurgeDeletedBefore は before より前にゴミ箱に移動したTodoを完全に削除し、削除した件数を返す
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error)
+	// Archive はTodoをアーカイブする (アーカイブ済みの場合はアーカイブした日時を維持する)
+	Archive(ctx context.Context, id string) (*model.Todo, error)
+	// Unarchive はTodoのアーカイブを解除する
+	Unarchive(ctx context.Context, id string) (*model.Todo, error)
+	// ArchiveCompletedBefore は before より前に done になったアーカイブされていないTodoをアーカイブし、アーカイブした件数を返す
+	ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error)
+	// ApplyBatch は writes を1つのトランザクションで適用し、書き込みごとに作成または更新した後のTodoを返す
+	// (ゴミ箱に移動した場合は nil)。作成を先に適用し、続けて更新とゴミ箱への移動を順に適用する
+	// 1つでも失敗した場合は全て適用せずにエラーを返す (更新のバージョンは Update と同様に確認する)
+	ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error)
+	// Touch はタグや依存関係などTodo以外の変更で内容が変わったTodoのバージョンと更新日時を更新し、befores からの変更を変更履歴に記録する
+	// befores には変更する前に同じトランザクションで取得したTodoを指定し、更新後のTodoを返す (ゴミ箱にあるTodoと存在しないTodoは含めない)
+	Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error)
+	// FindChanges は同期トークン since より後に作成、変更、ゴミ箱への移動、完全な削除をしたTodoと、次回の同期トークンを返す
+	// since が 0 の場合は全てのTodoを返す。前回の同期で返した変更を再び返す場合がある (クライアントはバージョンで判断する)
+	FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error)
 }
@@ -0,0 +1,22 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+)
+
+// TodoDependency はTodo間の依存関係 (blocked-by) のデータ操作を担当するインターフェース
+type TodoDependency interface {
+	// FindBlockerIDs は todoID のTodoをブロックしているTodoのIDを返す (ゴミ箱にあるTodoは除く)
+	FindBlockerIDs(ctx context.Context, todoID string) ([]string, error)
+	// FindAllBlockerIDs は todoID のTodoをブロックしている全てのTodoのIDを返す (ゴミ箱にあるTodoも含む)
+	// ゴミ箱にあるTodoは元に戻すと再び依存関係が有効になるため、循環の検証に使う
+	FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error)
+	// Lock は依存関係の循環の検証と追加を直列化するため、トランザクションが終了するまで他の依存関係の変更を待たせる
+	// (トランザクションの外で呼び出した場合は何もしない)
+	Lock(ctx context.Context) error
+	// Add は todoID のTodoが blockerID のTodoにブロックされる依存関係を追加する (既に存在する場合は何もしない)
+	Add(ctx context.Context, todoID string, blockerID string) error
+	Remove(ctx context.Context, todoID string, blockerID string) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// TodoEventStream はTodoのイベントを購読者に配信するインターフェース
+// 購読を再開できるように、配信したイベントを直近の一定件数だけ保持する
+type TodoEventStream interface {
+	EventPublisher
+	// Subscribe は ctx がキャンセルされるまで配信したイベントを受け取る購読を開始する
+	// lastEventID を指定した場合は、保持しているイベントのうちそれより後に配信したイベントを backlog として返す
+	// lastEventID のイベントを保持していない場合は model.ErrEventExpired を返す
+	// 受け取りが遅れて配信を待つイベントが溜まった場合は、イベントを取りこぼさないように events を閉じて購読を終了する
+	Subscribe(ctx context.Context, lastEventID string) (backlog []model.TodoEvent, events <-chan model.TodoEvent, err error)
+	// Reset は保持しているイベントを破棄し、全ての購読を終了する (イベントを取りこぼした可能性がある場合に呼び出す)
+	Reset()
+}
+
+// TodoEventListener は他のプロセスによる変更も含めてTodoの変更を監視し、イベントとして配信するインターフェース
+type TodoEventListener interface {
+	// Listen は ctx がキャンセルされるまでTodoの変更を監視し、stream に配信する
+	// 監視が中断した場合 (データベースとの接続が切れた場合など) はエラーを返す
+	Listen(ctx context.Context, stream TodoEventStream) error
+}
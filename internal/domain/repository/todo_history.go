@@ -0,0 +1,20 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// TodoHistory はTodoの変更履歴のデータ操作を担当するインターフェース
+// 変更履歴は repository.Todo の各操作と同じトランザクションで記録される
+type TodoHistory interface {
+	// FindByTodoID はTodoの変更履歴を古い順に取得する
+	FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error)
+	// FindByTodoIDs は todoIDs の各Todoの変更履歴をまとめて取得する (同じTodoの変更履歴は古い順に並べる)
+	FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error)
+	// FindByVersion はTodoの指定したバージョンの変更履歴を取得する
+	FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+)
+
+// TxManager は複数のリポジトリの操作を1つのトランザクションで実行するインターフェース
+type TxManager interface {
+	// Do は fn を1つのトランザクションで実行し、fn がエラーを返した場合は全ての変更を取り消す
+	// fn に渡した ctx を使ったリポジトリの操作は、同じトランザクションで実行される
+	// トランザクションの中で呼び出した場合は、外側のトランザクションの一部として実行する
+	// (fn がエラーを返した場合は、この呼び出しでの変更のみを取り消す)
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
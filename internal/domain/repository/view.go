@@ -0,0 +1,20 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// View はビューのデータ操作を担当するインターフェース
+// 作成と更新でビュー名が重複する場合は model.ErrConflict を返す
+type View interface {
+	// FindAll は全てのビューを名前順で取得する
+	FindAll(ctx context.Context) ([]model.View, error)
+	FindByID(ctx context.Context, id string) (*model.View, error)
+	Create(ctx context.Context, view model.View) (*model.View, error)
+	Update(ctx context.Context, id string, view model.View) (*model.View, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// Webhook はWebhookの購読のデータ操作を担当するインターフェース
+type Webhook interface {
+	// FindAll は全てのWebhookを作成した順に取得する
+	FindAll(ctx context.Context) ([]model.Webhook, error)
+	FindByID(ctx context.Context, id string) (*model.Webhook, error)
+	Create(ctx context.Context, webhook model.Webhook) (*model.Webhook, error)
+	// Update はWebhookの URL、イベントの種類と秘密鍵を更新する
+	Update(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error)
+	// Delete はWebhookとその配信を削除する
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// WebhookDelivery はWebhookの配信のデータ操作を担当するインターフェース
+type WebhookDelivery interface {
+	// Create は配信を作成する
+	// 同じWebhookに同じイベントの配信が既にある場合は model.ErrConflict を返す (イベントを再度配信しても重複しない)
+	// Webhookが存在しない場合は model.ErrNotFound を返す
+	Create(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error)
+	// FindByWebhookID はWebhookの配信を新しい順に limit 件まで取得する
+	FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error)
+	// FindDue は now までに配信を試みる日時になった配信待ちの配信を、配信を試みる日時の順に limit 件まで取得する
+	FindDue(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	// Update は配信を試みた結果 (状態、回数、応答、次に配信を試みる日時) を保存する
+	Update(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+//go:generate go tool mockgen -source=$GOFILE -destination=../../mocks/$GOPACKAGE/mock_$GOFILE
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// WebhookSender はWebhookの配信を送信するインターフェース
+type WebhookSender interface {
+	// Send は配信の本文を秘密鍵で署名してWebhookの URL に送信し、応答のステータスコードを返す
+	// 応答がなかった場合はエラーを返す (2xx 以外の応答はエラーとしない)
+	Send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (int, error)
+}
@@ -4,33 +4,38 @@ import (
 	"context"
 	"log/slog"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// DBConn はデータベース接続を表す
-var conn *pgx.Conn
+// pool はデータベースのコネクションプールを表す
+// リクエストとバックグラウンドジョブから並行して利用するため、単一の接続ではなくプールを使う
+var pool *pgxpool.Pool
 
 // Initialize はデータベース接続を初期化する
-func Initialize(connectionString string) (*pgx.Conn, error) {
+func Initialize(connectionString string) (*pgxpool.Pool, error) {
 	var err error
-	conn, err = pgx.Connect(context.Background(), connectionString)
+	pool, err = pgxpool.New(context.Background(), connectionString)
 	if err != nil {
 		return nil, err
 	}
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		return nil, err
+	}
 
 	slog.Info("Database connected")
-	return conn, nil
+	return pool, nil
 }
 
 // GetDBConn はデータベース接続を取得する
-func GetDBConn() *pgx.Conn {
-	return conn
+func GetDBConn() *pgxpool.Pool {
+	return pool
 }
 
 // CloseDB はデータベース接続を閉じる
 func CloseDB(ctx context.Context) error {
-	if conn != nil {
-		return conn.Close(ctx)
+	if pool != nil {
+		pool.Close()
 	}
 	return nil
 }
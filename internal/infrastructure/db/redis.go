@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisClient はRedisのクライアントを表す (未設定の場合は nil)
+var redisClient *redis.Client
+
+// InitializeRedis はRedisへの接続を初期化する (url は redis://host:port/db の形式)
+func InitializeRedis(url string) (*redis.Client, error) {
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opt)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	redisClient = client
+	slog.Info("Redis connected")
+	return client, nil
+}
+
+// GetRedisClient はRedisのクライアントを取得する (InitializeRedis を呼び出していない場合は nil)
+func GetRedisClient() *redis.Client {
+	return redisClient
+}
+
+// CloseRedis はRedisへの接続を閉じる
+func CloseRedis() error {
+	if redisClient != nil {
+		return redisClient.Close()
+	}
+	return nil
+}
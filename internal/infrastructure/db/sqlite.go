@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/url"
+
+	// SQLiteのドライバ (cgo を使わない実装)
+	_ "modernc.org/sqlite"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/sqlite"
+)
+
+// sqliteDB はSQLiteのデータベースを表す (未設定の場合は nil)
+var sqliteDB *sql.DB
+
+// sqlitePragmas はSQLiteへの接続ごとに設定する PRAGMA
+// 外部キー制約を有効にし、WAL で読み取りと書き込みを並行して行えるようにする
+// ロックを待つ時間を設定し、トランザクションは開始時に書き込みのロックを取得する (_txlock=immediate)
+var sqlitePragmas = url.Values{
+	"_pragma": {"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"},
+	"_txlock": {"immediate"},
+}
+
+// InitializeSQLite はSQLiteのデータベースを開き、未適用のマイグレーションを適用する (path はデータベースのファイルのパス)
+func InitializeSQLite(path string) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite", "file:"+path+"?"+sqlitePragmas.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlite.Migrate(context.Background(), conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	sqliteDB = conn
+	slog.Info("SQLite database opened", slog.String("path", path))
+	return conn, nil
+}
+
+// GetSQLiteDB はSQLiteのデータベースを取得する (InitializeSQLite を呼び出していない場合は nil)
+func GetSQLiteDB() *sql.DB {
+	return sqliteDB
+}
+
+// CloseSQLite はSQLiteのデータベースを閉じる
+func CloseSQLite() error {
+	if sqliteDB != nil {
+		return sqliteDB.Close()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store はキャッシュの保存先を表すインターフェース
+// 保存した値は世代ごとに区別し、世代を更新することで全ての値を無効にする
+type Store interface {
+	// Get はキーに対応する値を返す (存在しない場合と期限切れの場合は false を返す)
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set は値を ttl の間保存する
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Generation は現在の世代を返す
+	Generation(ctx context.Context) (string, error)
+	// NewGeneration は世代を更新する
+	NewGeneration(ctx context.Context) error
+}
+
+// DefaultLRUCapacity は LRU に保存する値の件数の上限のデフォルト値
+const DefaultLRUCapacity = 1000
+
+// LRU はプロセス内のメモリに保存する Store の実装
+// 件数が上限を超えた場合は最も長く使われていない値から削除する
+// 他のプロセスの更新では無効にならないため、複数のプロセスで動かす場合はTTLの間古い値を返すことがある
+type LRU struct {
+	mu         sync.Mutex
+	capacity   int
+	entries    *list.List
+	index      map[string]*list.Element
+	generation string
+}
+
+// lruEntry は LRU に保存した値
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU は Store のコンストラクタ (capacity は保存する値の件数の上限)
+func NewLRU(capacity int) Store {
+	return &LRU{
+		capacity:   capacity,
+		entries:    list.New(),
+		index:      make(map[string]*list.Element),
+		generation: uuid.NewString(),
+	}
+}
+
+// Get はキーに対応する値を返す
+func (s *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := e.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		s.remove(e)
+		return nil, false, nil
+	}
+	s.entries.MoveToFront(e)
+	return entry.value, true, nil
+}
+
+// Set は値を ttl の間保存する
+func (s *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if e, ok := s.index[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.entries.MoveToFront(e)
+		return nil
+	}
+
+	s.index[key] = s.entries.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for s.entries.Len() > s.capacity {
+		s.remove(s.entries.Back())
+	}
+	return nil
+}
+
+// Generation は現在の世代を返す
+func (s *LRU) Generation(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.generation, nil
+}
+
+// NewGeneration は世代を更新し、保存した値を全て削除する
+func (s *LRU) NewGeneration(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation = uuid.NewString()
+	s.entries.Init()
+	clear(s.index)
+	return nil
+}
+
+// remove は値を削除する (呼び出し元でロックを取得していること)
+func (s *LRU) remove(e *list.Element) {
+	s.entries.Remove(e)
+	delete(s.index, e.Value.(*lruEntry).key)
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Tag はタグを変更した場合にTodoのキャッシュを無効にする repository.Tag の実装
+// (Todoにはタグ名が含まれるため) 読み取りはそのまま next に委譲する
+type Tag struct {
+	repository.Tag
+	todos *Todo
+}
+
+// NewTag は repository.Tag のコンストラクタ
+func NewTag(next repository.Tag, todos *Todo) repository.Tag {
+	return &Tag{
+		Tag:   next,
+		todos: todos,
+	}
+}
+
+// Rename はタグ名を変更し、Todoのキャッシュを無効にする
+func (r *Tag) Rename(ctx context.Context, id string, name string) (*model.Tag, error) {
+	defer r.todos.Invalidate(ctx)
+	return r.Tag.Rename(ctx, id, name)
+}
+
+// Merge はタグを統合し、Todoのキャッシュを無効にする
+func (r *Tag) Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	defer r.todos.Invalidate(ctx)
+	return r.Tag.Merge(ctx, sourceID, targetID)
+}
+
+// Delete はタグを削除し、Todoのキャッシュを無効にする
+func (r *Tag) Delete(ctx context.Context, id string) error {
+	defer r.todos.Invalidate(ctx)
+	return r.Tag.Delete(ctx, id)
+}
@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DefaultTTL はキャッシュした値を保持する期間のデフォルト値
+const DefaultTTL = time.Minute
+
+// Todo は FindByID と FindAll の結果をキャッシュする repository.Todo の実装
+// 書き込みのたびにキャッシュの世代を更新し、それまでにキャッシュした値を全て無効にする
+// (取得を始める前に世代を取得するため、取得中に書き込まれた場合も古い値が新しい世代で使われることはない)
+// 同じキーの取得が同時に行われた場合は、1つの取得の結果を共有する
+// キャッシュの保存先でエラーが発生した場合は、キャッシュを使わずに next から取得する
+type Todo struct {
+	next  repository.Todo
+	store Store
+	ttl   time.Duration
+	group singleflight.Group
+	stats stats
+}
+
+// stats はキャッシュの利用状況の集計
+type stats struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	sharedLoads   atomic.Int64
+	errors        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// Stats はキャッシュの利用状況
+type Stats struct {
+	// Hits はキャッシュした値を返した回数
+	Hits int64 `json:"hits"`
+	// Misses はキャッシュした値がなく next から取得した回数 (SharedLoads を含む)
+	Misses int64 `json:"misses"`
+	// SharedLoads は同時に行われた取得の結果を共有した回数
+	SharedLoads int64 `json:"shared_loads"`
+	// Errors はキャッシュの保存先でエラーが発生した回数
+	Errors int64 `json:"errors"`
+	// Invalidations は書き込みによりキャッシュを無効にした回数
+	Invalidations int64 `json:"invalidations"`
+}
+
+// NewTodo は next の結果を store にキャッシュする Todo のコンストラクタ (ttl はキャッシュした値を保持する期間)
+func NewTodo(next repository.Todo, store Store, ttl time.Duration) *Todo {
+	return &Todo{
+		next:  next,
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Stats はキャッシュの利用状況を返す
+func (r *Todo) Stats() Stats {
+	return Stats{
+		Hits:          r.stats.hits.Load(),
+		Misses:        r.stats.misses.Load(),
+		SharedLoads:   r.stats.sharedLoads.Load(),
+		Errors:        r.stats.errors.Load(),
+		Invalidations: r.stats.invalidations.Load(),
+	}
+}
+
+// FindAll は検索クエリごとにキャッシュした一覧を返す
+// 期限による絞り込みは取得する日時により範囲が変わるため、キャッシュしない
+func (r *Todo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	if query.DueRange != nil {
+		return r.next.FindAll(ctx, query)
+	}
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+
+	return readThrough(ctx, r, "todos:"+hex.EncodeToString(sum[:]), func(ctx context.Context) ([]model.Todo, error) {
+		return r.next.FindAll(ctx, query)
+	})
+}
+
+// Stamp はキャッシュせずに next の結果を返す
+func (r *Todo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	return r.next.Stamp(ctx, query)
+}
+
+// FindByID はIDごとにキャッシュしたTodoを返す (存在しない場合はキャッシュしない)
+func (r *Todo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
+	return readThrough(ctx, r, "todo:"+id, func(ctx context.Context) (*model.Todo, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+// FindByIDs はキャッシュせずに next の結果を返す
+func (r *Todo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	return r.next.FindByIDs(ctx, ids)
+}
+
+// Create はTodoを作成し、キャッシュを無効にする
+func (r *Todo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Create(ctx, todo)
+}
+
+// Update はTodoを更新し、キャッシュを無効にする
+func (r *Todo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Update(ctx, id, todo)
+}
+
+// Delete はTodoをゴミ箱に移動し、キャッシュを無効にする
+func (r *Todo) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx)
+	return r.next.Delete(ctx, id)
+}
+
+// FindChildren はキャッシュせずに next の結果を返す
+func (r *Todo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	return r.next.FindChildren(ctx, parentID)
+}
+
+// FindChildrenByParentIDs はキャッシュせずに next の結果を返す
+func (r *Todo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	return r.next.FindChildrenByParentIDs(ctx, parentIDs)
+}
+
+// ReorderChildren は子のTodoを並び替え、キャッシュを無効にする
+func (r *Todo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	defer r.invalidate(ctx)
+	return r.next.ReorderChildren(ctx, parentID, ids)
+}
+
+// FindTrash はキャッシュせずに next の結果を返す
+func (r *Todo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	return r.next.FindTrash(ctx)
+}
+
+// FindChanges はキャッシュせずに next の結果を返す
+func (r *Todo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	return r.next.FindChanges(ctx, since)
+}
+
+// Restore はTodoをゴミ箱から元に戻し、キャッシュを無効にする
+func (r *Todo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Restore(ctx, id)
+}
+
+// Purge はゴミ箱にあるTodoを完全に削除し、キャッシュを無効にする
+func (r *Todo) Purge(ctx context.Context, id string) error {
+	defer r.invalidate(ctx)
+	return r.next.Purge(ctx, id)
+}
+
+// PurgeDeletedBefore はゴミ箱にあるTodoを完全に削除し、キャッシュを無効にする
+func (r *Todo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	defer r.invalidate(ctx)
+	return r.next.PurgeDeletedBefore(ctx, before)
+}
+
+// Archive はTodoをアーカイブし、キャッシュを無効にする
+func (r *Todo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Archive(ctx, id)
+}
+
+// Unarchive はTodoのアーカイブを解除し、キャッシュを無効にする
+func (r *Todo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Unarchive(ctx, id)
+}
+
+// ArchiveCompletedBefore は完了したTodoをアーカイブし、キャッシュを無効にする
+func (r *Todo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	defer r.invalidate(ctx)
+	return r.next.ArchiveCompletedBefore(ctx, before)
+}
+
+// ApplyBatch は writes を適用し、キャッシュを無効にする
+func (r *Todo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.ApplyBatch(ctx, writes)
+}
+
+// Touch はTodoのバージョンと更新日時を更新し、キャッシュを無効にする
+func (r *Todo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	defer r.invalidate(ctx)
+	return r.next.Touch(ctx, befores)
+}
+
+// Invalidate はキャッシュした値を全て無効にする
+// Todoに含まれるタグや依存関係を他のリポジトリで変更した場合に呼び出す
+func (r *Todo) Invalidate(ctx context.Context) {
+	r.invalidate(ctx)
+}
+
+// invalidate はキャッシュの世代を更新する
+// 書き込みが失敗した場合も、一部が適用されている可能性があるため無効にする
+// 世代の更新に失敗した場合は書き込み自体を失敗にはせず、TTLが経過するまで古い値を返すことを許容する
+func (r *Todo) invalidate(ctx context.Context) {
+	r.stats.invalidations.Add(1)
+	if err := r.store.NewGeneration(context.WithoutCancel(ctx)); err != nil {
+		r.stats.errors.Add(1)
+		slog.ErrorContext(ctx, "Failed to invalidate todo cache", slog.Any("error", err))
+	}
+}
+
+// readThrough はキャッシュした値を返し、ない場合は load で取得した値をキャッシュして返す
+// トランザクションの中ではキャッシュを使わずに load で取得する
+func readThrough[T any](ctx context.Context, r *Todo, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if inTx(ctx) {
+		return load(ctx)
+	}
+	generation, err := r.store.Generation(ctx)
+	if err != nil {
+		r.stats.errors.Add(1)
+		slog.WarnContext(ctx, "Failed to get todo cache generation", slog.Any("error", err))
+		return load(ctx)
+	}
+	key = generation + ":" + key
+
+	var v T
+	b, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		r.stats.errors.Add(1)
+		slog.WarnContext(ctx, "Failed to get todo cache", slog.Any("error", err))
+	}
+	if ok && json.Unmarshal(b, &v) == nil {
+		r.stats.hits.Add(1)
+		return v, nil
+	}
+	r.stats.misses.Add(1)
+
+	// 結果を共有する他の呼び出し元に影響しないよう、取得はキャンセルしない
+	// 呼び出し元ごとに別の値を返すため、共有するのはエンコードした値とする
+	res, err, shared := r.group.Do(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		v, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.store.Set(loadCtx, key, b, r.jitteredTTL()); err != nil {
+			r.stats.errors.Add(1)
+			slog.WarnContext(ctx, "Failed to set todo cache", slog.Any("error", err))
+		}
+		return b, nil
+	})
+	if shared {
+		r.stats.sharedLoads.Add(1)
+	}
+	if err != nil {
+		return v, err
+	}
+	err = json.Unmarshal(res.([]byte), &v)
+	return v, err
+}
+
+// jitteredTTL は同時にキャッシュした値が一斉に期限切れにならないよう、TTLに最大1割の揺らぎを加えた値を返す
+func (r *Todo) jitteredTTL() time.Duration {
+	return r.ttl + rand.N(r.ttl/10+1)
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoDependency は依存関係を変更した場合にTodoのキャッシュを無効にする repository.TodoDependency の実装
+// (TodoにはブロックしているTodoのIDの一覧が含まれるため) 読み取りはそのまま next に委譲する
+type TodoDependency struct {
+	repository.TodoDependency
+	todos *Todo
+}
+
+// NewTodoDependency は repository.TodoDependency のコンストラクタ
+func NewTodoDependency(next repository.TodoDependency, todos *Todo) repository.TodoDependency {
+	return &TodoDependency{
+		TodoDependency: next,
+		todos:          todos,
+	}
+}
+
+// Add は依存関係を追加し、Todoのキャッシュを無効にする
+func (r *TodoDependency) Add(ctx context.Context, todoID string, blockerID string) error {
+	defer r.todos.Invalidate(ctx)
+	return r.TodoDependency.Add(ctx, todoID, blockerID)
+}
+
+// Remove は依存関係を削除し、Todoのキャッシュを無効にする
+func (r *TodoDependency) Remove(ctx context.Context, todoID string, blockerID string) error {
+	defer r.todos.Invalidate(ctx)
+	return r.TodoDependency.Remove(ctx, todoID, blockerID)
+}
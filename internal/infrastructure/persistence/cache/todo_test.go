@@ -0,0 +1,199 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/cache"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/redis"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+)
+
+const (
+	todoID    = "00000000-0000-4000-a000-000000000002"
+	blockerID = "00000000-0000-4000-a000-000000000003"
+)
+
+// stores はテスト対象のキャッシュの保存先を作成する関数の一覧
+func stores(t *testing.T) map[string]func() cache.Store {
+	return map[string]func() cache.Store{
+		"lru": func() cache.Store {
+			return cache.NewLRU(cache.DefaultLRUCapacity)
+		},
+		"redis": func() cache.Store {
+			mr := miniredis.RunT(t)
+			client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+			return redis.NewTodoCache(client)
+		},
+	}
+}
+
+func TestTodo_FindByID(t *testing.T) {
+	for name, newStore := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			db := inmemory.NewDB()
+			next := &repositorytest.CountingTodo{Todo: inmemory.NewTodo(db)}
+			repo := cache.NewTodo(next, newStore(), time.Minute)
+			dependencyRepo := cache.NewTodoDependency(inmemory.NewTodoDependency(db), repo)
+
+			for range 2 {
+				if _, err := repo.FindByID(ctx, todoID); err != nil {
+					t.Fatalf("FindByID() error = %v", err)
+				}
+			}
+			if got := next.Loads(); got != 1 {
+				t.Errorf("loads after cached reads = %d, want 1", got)
+			}
+
+			current, _ := repo.FindByID(ctx, todoID)
+			current.Title = "Renamed"
+			if _, err := repo.Update(ctx, todoID, *current); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if got, _ := repo.FindByID(ctx, todoID); got.Title != "Renamed" {
+				t.Errorf("FindByID() after Update title = %q, want %q", got.Title, "Renamed")
+			}
+
+			if err := dependencyRepo.Add(ctx, todoID, blockerID); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if got, _ := repo.FindByID(ctx, todoID); len(got.BlockedBy) != 1 {
+				t.Errorf("FindByID() after adding a dependency blocked_by = %v, want [%s]", got.BlockedBy, blockerID)
+			}
+
+			want := cache.Stats{Hits: 2, Misses: 3, Invalidations: 2}
+			if got := repo.Stats(); got != want {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTodo_FindAll(t *testing.T) {
+	for name, newStore := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			next := &repositorytest.CountingTodo{Todo: inmemory.NewTodo(inmemory.NewDB())}
+			repo := cache.NewTodo(next, newStore(), time.Minute)
+
+			queries := []model.TodoQuery{{}, {Status: model.TodoStatusTodo}, {}, {Status: model.TodoStatusTodo}}
+			for _, q := range queries {
+				if _, err := repo.FindAll(ctx, q); err != nil {
+					t.Fatalf("FindAll() error = %v", err)
+				}
+			}
+			if got := next.Loads(); got != 2 {
+				t.Errorf("loads for two distinct queries = %d, want 2", got)
+			}
+
+			if _, err := repo.Create(ctx, model.Todo{Title: "New", Status: model.TodoStatusTodo, Timezone: model.DefaultTimezone}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			todos, err := repo.FindAll(ctx, model.TodoQuery{})
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+			if len(todos) != 4 {
+				t.Errorf("FindAll() after Create returned %d todos, want 4", len(todos))
+			}
+
+			// 期限による絞り込みはキャッシュしない
+			q := model.TodoQuery{Due: model.DueFilterToday}
+			q.ResolveDue(time.Now())
+			for range 2 {
+				if _, err := repo.FindAll(ctx, q); err != nil {
+					t.Fatalf("FindAll() error = %v", err)
+				}
+			}
+			if got := next.Loads(); got != 5 {
+				t.Errorf("loads = %d, want 5", got)
+			}
+		})
+	}
+}
+
+func TestTodo_Stampede(t *testing.T) {
+	for name, newStore := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			const callers = 10
+			next := &repositorytest.CountingTodo{Todo: inmemory.NewTodo(inmemory.NewDB()), Gate: make(chan struct{})}
+			repo := cache.NewTodo(next, newStore(), time.Minute)
+
+			var wg sync.WaitGroup
+			for range callers {
+				wg.Go(func() {
+					if _, err := repo.FindByID(context.Background(), todoID); err != nil {
+						t.Errorf("FindByID() error = %v", err)
+					}
+				})
+			}
+			// 全ての呼び出し元がキャッシュを確認して取得を待つまで待機する
+			for repo.Stats().Misses < callers {
+				time.Sleep(time.Millisecond)
+			}
+			time.Sleep(10 * time.Millisecond)
+			close(next.Gate)
+			wg.Wait()
+
+			if got := next.Loads(); got != 1 {
+				t.Errorf("loads for concurrent misses = %d, want 1", got)
+			}
+			if got := repo.Stats().SharedLoads; got != callers {
+				t.Errorf("Stats().SharedLoads = %d, want %d", got, callers)
+			}
+		})
+	}
+}
+
+func TestTodo_RedisUnavailable(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	next := &repositorytest.CountingTodo{Todo: inmemory.NewTodo(inmemory.NewDB())}
+	repo := cache.NewTodo(next, redis.NewTodoCache(client), time.Minute)
+	mr.Close()
+
+	// Redisに接続できない場合もキャッシュを使わずに取得できる
+	for range 2 {
+		if _, err := repo.FindByID(ctx, todoID); err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+	}
+	if got := next.Loads(); got != 2 {
+		t.Errorf("loads = %d, want 2", got)
+	}
+	if got := repo.Stats().Errors; got != 2 {
+		t.Errorf("Stats().Errors = %d, want 2", got)
+	}
+}
+
+func TestLRU_Eviction(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRU(2)
+	for _, key := range []string{"a", "b"} {
+		_ = store.Set(ctx, key, []byte(key), time.Minute)
+	}
+	// a を使用したため、c を保存すると最も長く使われていない b が削除される
+	_, _, _ = store.Get(ctx, "a")
+	_ = store.Set(ctx, "c", []byte("c"), time.Minute)
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, got, _ := store.Get(ctx, key); got != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, got, want)
+		}
+	}
+
+	_ = store.Set(ctx, "a", []byte("a"), -time.Second)
+	if _, got, _ := store.Get(ctx, "a"); got {
+		t.Errorf("Get(%q) found an expired value", "a")
+	}
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// txKey はトランザクションの中であることを context に保存するキー
+type txKey struct{}
+
+// TxManager はトランザクションの中ではTodoのキャッシュを使わない repository.TxManager の実装
+// トランザクションの中で読み取った値はコミットされていない可能性があるためキャッシュせず、
+// トランザクションの中の書き込みはコミットされるまで他から見えないため、終了した時点でもう一度キャッシュを無効にする
+type TxManager struct {
+	next  repository.TxManager
+	todos *Todo
+}
+
+// NewTxManager は repository.TxManager のコンストラクタ
+func NewTxManager(next repository.TxManager, todos *Todo) repository.TxManager {
+	return &TxManager{
+		next:  next,
+		todos: todos,
+	}
+}
+
+// Do は fn を next のトランザクションで実行し、終了した後にTodoのキャッシュを無効にする
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return m.next.Do(ctx, fn)
+	}
+	defer m.todos.Invalidate(ctx)
+	return m.next.Do(context.WithValue(ctx, txKey{}, true), fn)
+}
+
+// inTx は ctx がトランザクションの中かを返す
+func inTx(ctx context.Context) bool {
+	v, _ := ctx.Value(txKey{}).(bool)
+	return v
+}
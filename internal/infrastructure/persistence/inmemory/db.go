@@ -0,0 +1,242 @@
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// DB はインメモリの各リポジトリで共有するデータストア
+type DB struct {
+	mu    sync.RWMutex
+	todos []model.Todo
+	tags  []model.Tag
+	// todoTags はTodoのIDをキーとした、付与されているタグのIDの一覧 (todo_tag テーブル相当)
+	todoTags map[string][]string
+	// dependencies はTodoのIDをキーとした、ブロックしているTodoのIDの一覧 (todo_dependency テーブル相当)
+	dependencies map[string][]string
+	// histories はTodoの変更履歴 (追記のみ)
+	histories []model.TodoHistory
+	views     []model.View
+	// idempotencyKeys は冪等キーをキーとした、リクエストと保存したレスポンス
+	idempotencyKeys map[string]model.IdempotencyRecord
+	// outbox は配信待ちのイベント (保存した順)
+	outbox []model.OutboxMessage
+	// outboxSeq は最後に払い出したイベントの順序
+	outboxSeq int64
+	webhooks  []model.Webhook
+	// webhookDeliveries はWebhookの配信 (作成した順)
+	webhookDeliveries []model.WebhookDelivery
+	// todoChanges はTodoのIDをキーとした最後の変更 (todo_change テーブル相当、完全に削除したTodoも残す)
+	todoChanges map[string]todoChange
+	// changeSeq は最後に払い出した変更の順序 (同期トークン)
+	changeSeq int64
+}
+
+// todoChange はTodoの最後の変更の順序と日時
+type todoChange struct {
+	seq       int64
+	changedAt time.Time
+}
+
+// NewDB は初期データを投入した DB を作成する
+func NewDB() *DB {
+	now := time.Now()
+	db := NewEmptyDB()
+	db.todos = []model.Todo{
+		{ID: "00000000-0000-4000-a000-000000000001", Title: "掃除", Content: "掃除をする", Status: model.TodoStatusDone, Position: 0, Timezone: model.DefaultTimezone, Version: 1, UpdatedAt: now, Done: true},
+		{ID: "00000000-0000-4000-a000-000000000002", Title: "洗濯", Content: "洗濯をする", Status: model.TodoStatusTodo, Position: 1, Timezone: model.DefaultTimezone, Version: 1, UpdatedAt: now, Done: false},
+		{ID: "00000000-0000-4000-a000-000000000003", Title: "料理", Content: "料理をする", Status: model.TodoStatusTodo, Position: 2, Timezone: model.DefaultTimezone, Version: 1, UpdatedAt: now, Done: false},
+	}
+	for _, t := range db.todos {
+		db.recordChange(t.ID)
+	}
+	return db
+}
+
+// NewEmptyDB は初期データのない DB を作成する
+func NewEmptyDB() *DB {
+	return &DB{
+		todoTags:        make(map[string][]string),
+		dependencies:    make(map[string][]string),
+		idempotencyKeys: make(map[string]model.IdempotencyRecord),
+		todoChanges:     make(map[string]todoChange),
+	}
+}
+
+// txKey は実行中のトランザクションの DB を context に保存するキー
+type txKey struct{}
+
+// inTx は ctx がこの DB のトランザクションの中か (ロックを取得済みか) を返す
+func (db *DB) inTx(ctx context.Context) bool {
+	tx, _ := ctx.Value(txKey{}).(*DB)
+	return tx == db
+}
+
+// lock は書き込みのロックを取得し、解放する関数を返す
+// トランザクションの中ではロックを取得済みのため、何もしない
+func (db *DB) lock(ctx context.Context) func() {
+	if db.inTx(ctx) {
+		return func() {}
+	}
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// rlock は読み取りのロックを取得し、解放する関数を返す
+// トランザクションの中ではロックを取得済みのため、何もしない
+func (db *DB) rlock(ctx context.Context) func() {
+	if db.inTx(ctx) {
+		return func() {}
+	}
+	db.mu.RLock()
+	return db.mu.RUnlock
+}
+
+// 以下のメソッドは呼び出し元でロックを取得していること
+
+// savedState は restore で元に戻すための DB の状態
+type savedState struct {
+	todos             []model.Todo
+	tags              []model.Tag
+	todoTags          map[string][]string
+	dependencies      map[string][]string
+	histories         int
+	views             []model.View
+	idempotencyKeys   map[string]model.IdempotencyRecord
+	outbox            []model.OutboxMessage
+	outboxSeq         int64
+	webhooks          []model.Webhook
+	webhookDeliveries []model.WebhookDelivery
+	todoChanges       map[string]todoChange
+	changeSeq         int64
+}
+
+// save は DB の現在の状態を返す
+// Todo のポインタのフィールドや付与されたタグの一覧は書き換えずに置き換えるため浅いコピーで十分だが、
+// 依存関係の一覧はその場で書き換えるため複製する
+func (db *DB) save() savedState {
+	dependencies := make(map[string][]string, len(db.dependencies))
+	for id, blockerIDs := range db.dependencies {
+		dependencies[id] = slices.Clone(blockerIDs)
+	}
+	return savedState{
+		todos:             slices.Clone(db.todos),
+		tags:              slices.Clone(db.tags),
+		todoTags:          maps.Clone(db.todoTags),
+		dependencies:      dependencies,
+		histories:         len(db.histories),
+		views:             slices.Clone(db.views),
+		idempotencyKeys:   maps.Clone(db.idempotencyKeys),
+		outbox:            slices.Clone(db.outbox),
+		outboxSeq:         db.outboxSeq,
+		webhooks:          slices.Clone(db.webhooks),
+		webhookDeliveries: slices.Clone(db.webhookDeliveries),
+		todoChanges:       maps.Clone(db.todoChanges),
+		changeSeq:         db.changeSeq,
+	}
+}
+
+// restore は save で保存した状態に戻す
+func (db *DB) restore(s savedState) {
+	db.todos = s.todos
+	db.tags = s.tags
+	db.todoTags = s.todoTags
+	db.dependencies = s.dependencies
+	db.histories = db.histories[:s.histories]
+	db.views = s.views
+	db.idempotencyKeys = s.idempotencyKeys
+	db.outbox = s.outbox
+	db.outboxSeq = s.outboxSeq
+	db.webhooks = s.webhooks
+	db.webhookDeliveries = s.webhookDeliveries
+	db.todoChanges = s.todoChanges
+	db.changeSeq = s.changeSeq
+}
+
+// recordChange はTodoを変更したことを同期のために記録する
+func (db *DB) recordChange(todoID string) {
+	db.changeSeq++
+	db.todoChanges[todoID] = todoChange{seq: db.changeSeq, changedAt: time.Now()}
+}
+
+// hydrate は関連するデータ (タグ、依存関係) を設定したTodoを返す
+func (db *DB) hydrate(t model.Todo) model.Todo {
+	t.Tags = db.tagNames(t.ID)
+	t.BlockedBy = db.blockerIDs(t.ID)
+	return t
+}
+
+// blockerIDs はTodoをブロックしているTodoのうち、ゴミ箱にないもののIDを返す
+func (db *DB) blockerIDs(todoID string) []string {
+	ids := make([]string, 0, len(db.dependencies[todoID]))
+	for _, blockerID := range db.dependencies[todoID] {
+		if db.activeTodoIndex(blockerID) != -1 {
+			ids = append(ids, blockerID)
+		}
+	}
+	return ids
+}
+
+// tagNames はTodoに付与されているタグ名を名前順で返す
+func (db *DB) tagNames(todoID string) []string {
+	names := make([]string, 0, len(db.todoTags[todoID]))
+	for _, tagID := range db.todoTags[todoID] {
+		if i := db.tagIndex(tagID); i != -1 {
+			names = append(names, db.tags[i].Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// todoIndex はIDに一致するTodoの位置を返す (存在しない場合は -1)
+func (db *DB) todoIndex(id string) int {
+	return slices.IndexFunc(db.todos, func(t model.Todo) bool {
+		return t.ID == id
+	})
+}
+
+// activeTodoIndex はIDに一致するゴミ箱にないTodoの位置を返す (存在しない場合は -1)
+func (db *DB) activeTodoIndex(id string) int {
+	i := db.todoIndex(id)
+	if i == -1 || db.todos[i].DeletedAt != nil {
+		return -1
+	}
+	return i
+}
+
+// hasOpenBlocker はTodoをブロックしているTodoのうち、完了または中止になっていないものがあるかを返す
+func (db *DB) hasOpenBlocker(todoID string) bool {
+	return slices.ContainsFunc(db.dependencies[todoID], func(blockerID string) bool {
+		i := db.activeTodoIndex(blockerID)
+		return i != -1 && !db.todos[i].Status.IsClosed()
+	})
+}
+
+// tagIndex はIDに一致するタグの位置を返す (存在しない場合は -1)
+func (db *DB) tagIndex(id string) int {
+	return slices.IndexFunc(db.tags, func(t model.Tag) bool {
+		return t.ID == id
+	})
+}
+
+// ensureTags は名前に一致するタグのIDを返す (存在しないタグは作成する)
+func (db *DB) ensureTags(names []string) []string {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(db.tags, func(t model.Tag) bool { return t.Name == name })
+		if i == -1 {
+			db.tags = append(db.tags, model.Tag{ID: uuid.New().String(), Name: name})
+			i = len(db.tags) - 1
+		}
+		ids = append(ids, db.tags[i].ID)
+	}
+	return ids
+}
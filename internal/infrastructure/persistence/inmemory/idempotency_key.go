@@ -0,0 +1,80 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// IdempotencyKey はインメモリの冪等キーの実装
+type IdempotencyKey struct {
+	db *DB
+}
+
+// NewIdempotencyKey は repository.IdempotencyKey のコンストラクタ
+func NewIdempotencyKey(db *DB) repository.IdempotencyKey {
+	return &IdempotencyKey{
+		db: db,
+	}
+}
+
+// Reserve は冪等キーが存在しない場合に処理中として ttl の間保存し、true を返す
+// 有効期限を過ぎた冪等キーは新しいリクエストで上書きする
+func (r *IdempotencyKey) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	if rec, ok := r.db.idempotencyKeys[key]; ok && rec.ExpiresAt.After(now) {
+		rec.Body = slices.Clone(rec.Body)
+		return &rec, false, nil
+	}
+	r.db.idempotencyKeys[key] = model.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	return nil, true, nil
+}
+
+// Complete は処理中の冪等キーにレスポンスを保存する
+func (r *IdempotencyKey) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	defer r.db.lock(ctx)()
+
+	rec, ok := r.db.idempotencyKeys[key]
+	if !ok || rec.Completed {
+		return model.ErrNotFound
+	}
+	rec.Completed = true
+	rec.StatusCode = statusCode
+	rec.ContentType = contentType
+	rec.Body = slices.Clone(body)
+	r.db.idempotencyKeys[key] = rec
+	return nil
+}
+
+// Release は処理中の冪等キーを削除する
+func (r *IdempotencyKey) Release(ctx context.Context, key string) error {
+	defer r.db.lock(ctx)()
+
+	if rec, ok := r.db.idempotencyKeys[key]; ok && !rec.Completed {
+		delete(r.db.idempotencyKeys, key)
+	}
+	return nil
+}
+
+// DeleteExpired は before の時点で有効期限を過ぎた冪等キーを削除し、削除した件数を返す
+func (r *IdempotencyKey) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	defer r.db.lock(ctx)()
+
+	n := 0
+	for key, rec := range r.db.idempotencyKeys {
+		if !rec.ExpiresAt.After(before) {
+			delete(r.db.idempotencyKeys, key)
+			n++
+		}
+	}
+	return n, nil
+}
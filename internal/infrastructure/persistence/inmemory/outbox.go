@@ -0,0 +1,84 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Outbox はインメモリのアウトボックスの実装
+type Outbox struct {
+	db *DB
+}
+
+// NewOutbox は repository.Outbox のコンストラクタ
+func NewOutbox(db *DB) repository.Outbox {
+	return &Outbox{
+		db: db,
+	}
+}
+
+// Add はイベントを配信待ちとして保存する
+func (r *Outbox) Add(ctx context.Context, events []model.TodoEvent) error {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	for _, e := range events {
+		r.db.outboxSeq++
+		e.ID = uuid.New().String()
+		r.db.outbox = append(r.db.outbox, model.OutboxMessage{Seq: r.db.outboxSeq, Event: e, AvailableAt: now})
+	}
+	return nil
+}
+
+// FindPending は配信を試みる日時になった配信待ちのイベントを保存した順に返す
+func (r *Outbox) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	defer r.db.rlock(ctx)()
+
+	messages := []model.OutboxMessage{}
+	waiting := make(map[string]bool)
+	for _, m := range r.db.outbox {
+		if len(messages) == limit {
+			break
+		}
+		if m.AvailableAt.After(now) {
+			waiting[m.Event.TodoID] = true
+			continue
+		}
+		if !waiting[m.Event.TodoID] {
+			messages = append(messages, m)
+		}
+	}
+	return messages, nil
+}
+
+// Delete は配信したイベントを削除する
+func (r *Outbox) Delete(ctx context.Context, seq int64) error {
+	defer r.db.lock(ctx)()
+
+	r.db.outbox = slices.DeleteFunc(r.db.outbox, func(m model.OutboxMessage) bool {
+		return m.Seq == seq
+	})
+	return nil
+}
+
+// MarkFailed は配信に失敗したイベントの失敗回数を増やし、次に配信を試みる日時を設定する
+func (r *Outbox) MarkFailed(ctx context.Context, seq int64, lastError string, nextAttemptAt time.Time) error {
+	defer r.db.lock(ctx)()
+
+	i := slices.IndexFunc(r.db.outbox, func(m model.OutboxMessage) bool {
+		return m.Seq == seq
+	})
+	if i == -1 {
+		return model.ErrNotFound
+	}
+	r.db.outbox[i].Attempts++
+	r.db.outbox[i].LastError = lastError
+	r.db.outbox[i].AvailableAt = nextAttemptAt
+	return nil
+}
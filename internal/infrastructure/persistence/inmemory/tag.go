@@ -0,0 +1,102 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Tag はインメモリのタグの実装
+type Tag struct {
+	db *DB
+}
+
+// NewTag は repository.Tag のコンストラクタ
+func NewTag(db *DB) repository.Tag {
+	return &Tag{
+		db: db,
+	}
+}
+
+// FindAll は全てのタグを名前順で取得する
+func (r *Tag) FindAll(ctx context.Context) ([]model.Tag, error) {
+	defer r.db.rlock(ctx)()
+
+	tags := slices.Clone(r.db.tags)
+	slices.SortFunc(tags, func(a, b model.Tag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tags, nil
+}
+
+// FindByID はIDによるタグの取得
+func (r *Tag) FindByID(ctx context.Context, id string) (*model.Tag, error) {
+	defer r.db.rlock(ctx)()
+
+	i := r.db.tagIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	t := r.db.tags[i]
+	return &t, nil
+}
+
+// Rename はタグ名を変更する
+func (r *Tag) Rename(ctx context.Context, id string, name string) (*model.Tag, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.db.tagIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	if slices.ContainsFunc(r.db.tags, func(t model.Tag) bool { return t.Name == name && t.ID != id }) {
+		return nil, model.ErrConflict
+	}
+	r.db.tags[i].Name = name
+	t := r.db.tags[i]
+	return &t, nil
+}
+
+// Merge は source が付与されたTodoを target に付け替え、source を削除する
+func (r *Tag) Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	defer r.db.lock(ctx)()
+
+	if r.db.tagIndex(sourceID) == -1 {
+		return nil, model.ErrNotFound
+	}
+	ti := r.db.tagIndex(targetID)
+	if ti == -1 {
+		return nil, model.ErrNotFound
+	}
+
+	for todoID, tagIDs := range r.db.todoTags {
+		if !slices.Contains(tagIDs, sourceID) {
+			continue
+		}
+		tagIDs = slices.DeleteFunc(tagIDs, func(id string) bool { return id == sourceID })
+		if !slices.Contains(tagIDs, targetID) {
+			tagIDs = append(tagIDs, targetID)
+		}
+		r.db.todoTags[todoID] = tagIDs
+	}
+	target := r.db.tags[ti]
+	r.db.tags = slices.DeleteFunc(r.db.tags, func(t model.Tag) bool { return t.ID == sourceID })
+	return &target, nil
+}
+
+// Delete はタグを削除し、付与されていたTodoからも外す
+func (r *Tag) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	if r.db.tagIndex(id) == -1 {
+		return model.ErrNotFound
+	}
+	for todoID, tagIDs := range r.db.todoTags {
+		r.db.todoTags[todoID] = slices.DeleteFunc(tagIDs, func(tagID string) bool { return tagID == id })
+	}
+	r.db.tags = slices.DeleteFunc(r.db.tags, func(t model.Tag) bool { return t.ID == id })
+	return nil
+}
@@ -1,9 +1,12 @@
 package inmemory
 
 import (
+	"cmp"
 	"context"
-	"errors"
+	"fmt"
+	"maps"
 	"slices"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -11,75 +14,527 @@ import (
 	"github.com/qushot/gin-todo-api/internal/domain/repository"
 )
 
-// Todo はPostgreSQLを使ったTodoの実装
+// Todo はインメモリのTodoの実装
 type Todo struct {
-	todos []model.Todo
+	db *DB
 }
 
 // NewTodo は repository.Todo のコンストラクタ
-func NewTodo() repository.Todo {
+func NewTodo(db *DB) repository.Todo {
 	return &Todo{
-		todos: []model.Todo{
-			{ID: "00000000-0000-4000-a000-000000000001", Title: "掃除", Content: "掃除をする", Done: true},
-			{ID: "00000000-0000-4000-a000-000000000002", Title: "洗濯", Content: "洗濯をする", Done: false},
-			{ID: "00000000-0000-4000-a000-000000000003", Title: "料理", Content: "料理をする", Done: false},
-		},
+		db: db,
 	}
 }
 
 // FindAll は全てのTodoを取得する
-func (r *Todo) FindAll(ctx context.Context, _ model.TodoQuery) ([]model.Todo, error) {
-	return r.todos, nil
+func (r *Todo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	defer r.db.rlock(ctx)()
+
+	terms := model.SearchTerms(query.Q)
+	todos := make([]model.Todo, 0, len(r.db.todos))
+	for _, t := range r.db.todos {
+		if t.DeletedAt != nil || !query.Archived.Match(t.IsArchived()) {
+			continue
+		}
+		if query.Status != "" && t.Status != query.Status {
+			continue
+		}
+		if query.SeriesID != "" && (t.SeriesID == nil || *t.SeriesID != query.SeriesID) {
+			continue
+		}
+		if query.MinPriority != nil && t.Priority < *query.MinPriority {
+			continue
+		}
+		if query.DueRange != nil && !query.DueRange.Match(&t) {
+			continue
+		}
+		if query.Actionable && (t.Status.IsClosed() || r.db.hasOpenBlocker(t.ID)) {
+			continue
+		}
+		t = r.db.hydrate(t)
+		if !query.MatchTags(t.Tags) {
+			continue
+		}
+		if len(terms) > 0 {
+			rank, ok := model.SearchRank(&t, terms)
+			if !ok {
+				continue
+			}
+			t.Search = model.NewTodoSearchMatch(&t, terms, rank)
+		}
+		todos = append(todos, t)
+	}
+	if len(terms) > 0 {
+		slices.SortStableFunc(todos, func(a, b model.Todo) int {
+			return cmp.Compare(b.Search.Rank, a.Search.Rank)
+		})
+	}
+	return todos, nil
+}
+
+// Stamp は query に一致するTodoの件数と、全てのTodoの最終更新日時を返す
+func (r *Todo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	todos, err := r.FindAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.db.rlock(ctx)()
+
+	stamp := model.TodoListStamp{Count: len(todos), DueRange: query.DueRange}
+	for _, t := range r.db.todos {
+		if t.UpdatedAt.After(stamp.LastModified) {
+			stamp.LastModified = t.UpdatedAt
+		}
+	}
+	return &stamp, nil
 }
 
 // FindByID はIDによるTodoの取得
 func (r *Todo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
-	i := slices.IndexFunc(r.todos, func(t model.Todo) bool {
-		return t.ID == id
-	})
+	defer r.db.rlock(ctx)()
+
+	i := r.db.activeTodoIndex(id)
 	if i == -1 {
-		return nil, errors.New("not found")
+		return nil, model.ErrNotFound
+	}
+	t := r.db.hydrate(r.db.todos[i])
+	return &t, nil
+}
+
+// FindByIDs は ids のTodoをまとめて取得する
+func (r *Todo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	defer r.db.rlock(ctx)()
+
+	todos := []model.Todo{}
+	for _, id := range ids {
+		if i := r.db.activeTodoIndex(id); i != -1 {
+			todos = append(todos, r.db.hydrate(r.db.todos[i]))
+		}
 	}
-	return &r.todos[i], nil
+	return todos, nil
 }
 
 // Create は新しいTodoを作成する
 func (r *Todo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	t := r.create(ctx, todo)
+	return &t, nil
+}
+
+// create は新しいTodoを作成する (呼び出し元でロックを取得していること)
+func (r *Todo) create(ctx context.Context, todo model.Todo) model.Todo {
 	t := model.Todo{
-		ID:      uuid.New().String(),
-		Title:   todo.Title,
-		Content: todo.Content,
-		Done:    todo.Done,
+		ID:           uuid.New().String(),
+		Title:        todo.Title,
+		Content:      todo.Content,
+		Status:       todo.Status,
+		Priority:     todo.Priority,
+		ParentID:     todo.ParentID,
+		Position:     r.nextPosition(todo.ParentID),
+		AutoComplete: todo.AutoComplete,
+		DueAt:        todo.DueAt,
+		Recurrence:   todo.Recurrence,
+		Timezone:     todo.Timezone,
+		SeriesID:     todo.SeriesID,
+		OccurrenceAt: todo.OccurrenceAt,
+		CompletedAt:  completedAt(nil, todo.Status),
+		Version:      1,
+		UpdatedAt:    time.Now(),
+		Done:         todo.Status == model.TodoStatusDone,
 	}
-	r.todos = append(r.todos, t)
-	return &t, nil
+	r.db.todos = append(r.db.todos, t)
+	r.db.todoTags[t.ID] = r.db.ensureTags(todo.Tags)
+	r.db.recordChange(t.ID)
+	t = r.db.hydrate(t)
+	r.db.recordHistory(ctx, model.HistoryActionCreate, nil, &t)
+	return t
 }
 
 // Update はTodoを更新する
 func (r *Todo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
-	i := slices.IndexFunc(r.todos, func(t model.Todo) bool {
-		return t.ID == id
-	})
+	defer r.db.lock(ctx)()
+
+	return r.update(ctx, id, todo)
+}
+
+// update はTodoを更新する (呼び出し元でロックを取得していること)
+func (r *Todo) update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+	i := r.db.activeTodoIndex(id)
 	if i == -1 {
-		return nil, errors.New("not found")
+		return nil, model.ErrNotFound
+	}
+	if err := model.CheckBaseVersion(&r.db.todos[i], todo.Version); err != nil {
+		return nil, err
 	}
-	r.todos[i] = model.Todo{
-		ID:      id,
-		Title:   todo.Title,
-		Content: todo.Content,
-		Done:    todo.Done,
+	// 親が変わった場合は新しい兄弟の末尾に移動する
+	position := r.db.todos[i].Position
+	if !sameParent(r.db.todos[i].ParentID, todo.ParentID) {
+		position = r.nextPosition(todo.ParentID)
 	}
-	return &r.todos[i], nil
+	t := r.modify(ctx, i, model.HistoryActionUpdate, func(t *model.Todo) {
+		*t = model.Todo{
+			ID:           id,
+			Title:        todo.Title,
+			Content:      todo.Content,
+			Status:       todo.Status,
+			Priority:     todo.Priority,
+			ParentID:     todo.ParentID,
+			Position:     position,
+			AutoComplete: todo.AutoComplete,
+			DueAt:        todo.DueAt,
+			Recurrence:   todo.Recurrence,
+			Timezone:     todo.Timezone,
+			SeriesID:     todo.SeriesID,
+			OccurrenceAt: todo.OccurrenceAt,
+			CompletedAt:  completedAt(t.CompletedAt, todo.Status),
+			ArchivedAt:   t.ArchivedAt,
+			Done:         todo.Status == model.TodoStatusDone,
+		}
+		r.db.todoTags[id] = r.db.ensureTags(todo.Tags)
+	})
+	return &t, nil
 }
 
-// Delete はTodoを削除する
+// Delete はTodoを子孫のTodoも含めてゴミ箱に移動する
+// 子孫のTodoには同じ日時を設定し、Restore で一緒に元に戻せるようにする
 func (r *Todo) Delete(ctx context.Context, id string) error {
-	i := slices.IndexFunc(r.todos, func(t model.Todo) bool {
-		return t.ID == id
+	defer r.db.lock(ctx)()
+
+	return r.delete(ctx, id)
+}
+
+// delete はTodoを子孫のTodoも含めてゴミ箱に移動する (呼び出し元でロックを取得していること)
+func (r *Todo) delete(ctx context.Context, id string) error {
+	if r.db.activeTodoIndex(id) == -1 {
+		return model.ErrNotFound
+	}
+
+	now := time.Now()
+	trashed := r.subtree(id, func(t model.Todo) bool { return t.DeletedAt == nil })
+	for i, t := range r.db.todos {
+		if trashed[t.ID] {
+			r.modify(ctx, i, model.HistoryActionDelete, func(t *model.Todo) { t.DeletedAt = &now })
+		}
+	}
+	return nil
+}
+
+// FindChildren は子のTodoを並び順で取得する
+func (r *Todo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	defer r.db.rlock(ctx)()
+
+	return r.children(parentID), nil
+}
+
+// FindChildrenByParentIDs は parentIDs の各親の子のTodoをまとめて取得する
+func (r *Todo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	defer r.db.rlock(ctx)()
+
+	children := []model.Todo{}
+	for _, parentID := range parentIDs {
+		children = append(children, r.children(parentID)...)
+	}
+	return children, nil
+}
+
+// ReorderChildren は子のTodoを ids の順に並び替える
+func (r *Todo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	defer r.db.lock(ctx)()
+
+	for position, id := range ids {
+		i := r.db.activeTodoIndex(id)
+		if i == -1 || !sameParent(r.db.todos[i].ParentID, &parentID) {
+			return model.ErrNotFound
+		}
+		r.db.todos[i].Position = position
+		r.db.todos[i].Version++
+		r.db.todos[i].UpdatedAt = time.Now()
+		r.db.recordChange(id)
+	}
+	return nil
+}
+
+// FindTrash はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+func (r *Todo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	defer r.db.rlock(ctx)()
+
+	todos := []model.Todo{}
+	for _, t := range r.db.todos {
+		if t.DeletedAt != nil {
+			todos = append(todos, r.db.hydrate(t))
+		}
+	}
+	slices.SortStableFunc(todos, func(a, b model.Todo) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(a.Position, b.Position))
 	})
-	if i == -1 {
-		return errors.New("not found")
+	return todos, nil
+}
+
+// Restore はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+func (r *Todo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.db.todoIndex(id)
+	if i == -1 || r.db.todos[i].DeletedAt == nil {
+		return nil, model.ErrNotFound
+	}
+	if parentID := r.db.todos[i].ParentID; parentID != nil && r.db.activeTodoIndex(*parentID) == -1 {
+		return nil, fmt.Errorf("%w: parent todo is in the trash, restore it first", model.ErrConflict)
+	}
+
+	deletedAt := *r.db.todos[i].DeletedAt
+	restored := r.subtree(id, func(t model.Todo) bool { return t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) })
+	for j, t := range r.db.todos {
+		if restored[t.ID] {
+			r.modify(ctx, j, model.HistoryActionRestore, func(t *model.Todo) { t.DeletedAt = nil })
+		}
+	}
+	t := r.db.hydrate(r.db.todos[i])
+	return &t, nil
+}
+
+// Purge はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する
+func (r *Todo) Purge(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.db.todoIndex(id)
+	if i == -1 || r.db.todos[i].DeletedAt == nil {
+		return model.ErrNotFound
 	}
-	r.todos = append(r.todos[:i], r.todos[i+1:]...)
+
+	r.purge(r.subtree(id, func(model.Todo) bool { return true }))
 	return nil
 }
+
+// PurgeDeletedBefore は before より前にゴミ箱に移動したTodoを完全に削除し、削除した件数を返す
+func (r *Todo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	defer r.db.lock(ctx)()
+
+	purged := map[string]bool{}
+	for _, t := range r.db.todos {
+		if t.DeletedAt != nil && t.DeletedAt.Before(before) {
+			maps.Copy(purged, r.subtree(t.ID, func(model.Todo) bool { return true }))
+		}
+	}
+	r.purge(purged)
+	return len(purged), nil
+}
+
+// Archive はTodoをアーカイブする (アーカイブ済みの場合はアーカイブした日時を維持する)
+func (r *Todo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.db.activeTodoIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	t := r.modify(ctx, i, model.HistoryActionArchive, func(t *model.Todo) {
+		if t.ArchivedAt == nil {
+			now := time.Now()
+			t.ArchivedAt = &now
+		}
+	})
+	return &t, nil
+}
+
+// Unarchive はTodoのアーカイブを解除する
+func (r *Todo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.db.activeTodoIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	t := r.modify(ctx, i, model.HistoryActionUnarchive, func(t *model.Todo) { t.ArchivedAt = nil })
+	return &t, nil
+}
+
+// ArchiveCompletedBefore は before より前に done になったアーカイブされていないTodoをアーカイブし、アーカイブした件数を返す
+func (r *Todo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	n := 0
+	for i, t := range r.db.todos {
+		if t.DeletedAt == nil && t.ArchivedAt == nil && t.Status == model.TodoStatusDone &&
+			t.CompletedAt != nil && t.CompletedAt.Before(before) {
+			r.modify(ctx, i, model.HistoryActionArchive, func(t *model.Todo) { t.ArchivedAt = &now })
+			n++
+		}
+	}
+	return n, nil
+}
+
+// modify は i 番目のTodoに fn を適用してバージョンと更新日時を更新し、action として変更履歴を記録する
+// (PostgreSQL ではバージョンと更新日時はトリガーで更新される)
+func (r *Todo) modify(ctx context.Context, i int, action model.HistoryAction, fn func(t *model.Todo)) model.Todo {
+	before := r.db.hydrate(r.db.todos[i])
+	fn(&r.db.todos[i])
+	r.db.todos[i].Version = before.Version + 1
+	r.db.todos[i].UpdatedAt = time.Now()
+	r.db.recordChange(before.ID)
+	after := r.db.hydrate(r.db.todos[i])
+	r.db.recordHistory(ctx, action, &before, &after)
+	return after
+}
+
+// subtree は id のTodoと、match に一致する子を辿って到達できる子孫のTodoのIDを返す
+func (r *Todo) subtree(id string, match func(t model.Todo) bool) map[string]bool {
+	ids := map[string]bool{id: true}
+	// 親が対象のTodoを、追加されなくなるまで繰り返し対象にする
+	for added := true; added; {
+		added = false
+		for _, t := range r.db.todos {
+			if t.ParentID != nil && ids[*t.ParentID] && !ids[t.ID] && match(t) {
+				ids[t.ID] = true
+				added = true
+			}
+		}
+	}
+	return ids
+}
+
+// purge は ids のTodoと関連するデータを完全に削除する
+func (r *Todo) purge(ids map[string]bool) {
+	r.db.todos = slices.DeleteFunc(r.db.todos, func(t model.Todo) bool {
+		return ids[t.ID]
+	})
+	for todoID := range ids {
+		delete(r.db.todoTags, todoID)
+		delete(r.db.dependencies, todoID)
+		r.db.recordChange(todoID)
+	}
+	for todoID, blockerIDs := range r.db.dependencies {
+		r.db.dependencies[todoID] = slices.DeleteFunc(blockerIDs, func(blockerID string) bool {
+			return ids[blockerID]
+		})
+	}
+}
+
+// children は子のTodoを並び順で返す
+func (r *Todo) children(parentID string) []model.Todo {
+	children := []model.Todo{}
+	for _, t := range r.db.todos {
+		if t.DeletedAt == nil && t.ParentID != nil && *t.ParentID == parentID {
+			children = append(children, r.db.hydrate(t))
+		}
+	}
+	slices.SortStableFunc(children, func(a, b model.Todo) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+	return children
+}
+
+// nextPosition は parentID の子の末尾の並び順を返す
+func (r *Todo) nextPosition(parentID *string) int {
+	position := 0
+	for _, t := range r.db.todos {
+		if sameParent(t.ParentID, parentID) {
+			position = max(position, t.Position+1)
+		}
+	}
+	return position
+}
+
+// completedAt は status に応じた done になった日時を返す (done のままの場合は current を維持する)
+func completedAt(current *time.Time, status model.TodoStatus) *time.Time {
+	if status != model.TodoStatusDone {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	now := time.Now()
+	return &now
+}
+
+// sameParent は親のIDが等しいかを返す (ルート同士も等しいとみなす)
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Touch は befores のTodoのバージョンと更新日時を更新し、befores からの変更を変更履歴に記録する
+func (r *Todo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	todos := make([]model.Todo, 0, len(befores))
+	for _, before := range befores {
+		i := r.db.activeTodoIndex(before.ID)
+		if i == -1 {
+			continue
+		}
+		r.db.todos[i].Version++
+		r.db.todos[i].UpdatedAt = time.Now()
+		r.db.recordChange(before.ID)
+		after := r.db.hydrate(r.db.todos[i])
+		r.db.recordHistory(ctx, model.HistoryActionUpdate, &before, &after)
+		todos = append(todos, after)
+	}
+	return todos, nil
+}
+
+// ApplyBatch は作成する書き込みを先に適用し、続けて更新とゴミ箱への移動を順に適用する
+// 書き込みごとに作成または更新した後のTodoを返し (ゴミ箱に移動した場合は nil)、
+// 1つでも失敗した場合は適用する前の状態に戻してエラーを返す
+func (r *Todo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	defer r.db.lock(ctx)()
+
+	saved := r.db.save()
+	todos := make([]*model.Todo, len(writes))
+	for i, w := range writes {
+		if w.Kind == model.TodoWriteCreate {
+			t := r.create(ctx, w.Todo)
+			todos[i] = &t
+		}
+	}
+	for i, w := range writes {
+		var err error
+		switch w.Kind {
+		case model.TodoWriteCreate:
+		case model.TodoWriteUpdate:
+			todos[i], err = r.update(ctx, w.ID, w.Todo)
+		case model.TodoWriteDelete:
+			err = r.delete(ctx, w.ID)
+		default:
+			err = fmt.Errorf("unknown write kind %q", w.Kind)
+		}
+		if err != nil {
+			r.db.restore(saved)
+			return nil, err
+		}
+	}
+	return todos, nil
+}
+
+// FindChanges は同期トークン since より後に変更したTodoを変更した順に返す
+// 同期トークンは変更を記録するたびに払い出す連番 (変更はロックの中で記録するため、コミットした順と一致する)
+func (r *Todo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	defer r.db.rlock(ctx)()
+
+	type change struct {
+		id string
+		todoChange
+	}
+	var changed []change
+	for id, c := range r.db.todoChanges {
+		if c.seq > since {
+			changed = append(changed, change{id, c})
+		}
+	}
+	slices.SortFunc(changed, func(a, b change) int { return cmp.Compare(a.seq, b.seq) })
+
+	changes := &model.TodoChanges{Todos: []model.Todo{}, Purged: []model.TodoTombstone{}, Token: max(since, r.db.changeSeq)}
+	for _, c := range changed {
+		if i := r.db.todoIndex(c.id); i != -1 {
+			changes.Todos = append(changes.Todos, r.db.hydrate(r.db.todos[i]))
+		} else {
+			changes.Purged = append(changes.Purged, model.TodoTombstone{ID: c.id, DeletedAt: c.changedAt, Purged: true})
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,66 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoDependency はインメモリのTodoの依存関係の実装
+type TodoDependency struct {
+	db *DB
+}
+
+// NewTodoDependency は repository.TodoDependency のコンストラクタ
+func NewTodoDependency(db *DB) repository.TodoDependency {
+	return &TodoDependency{
+		db: db,
+	}
+}
+
+// FindBlockerIDs は todoID のTodoをブロックしているTodoのIDを返す (ゴミ箱にあるTodoは除く)
+func (r *TodoDependency) FindBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	defer r.db.rlock(ctx)()
+
+	return r.db.blockerIDs(todoID), nil
+}
+
+// FindAllBlockerIDs は todoID のTodoをブロックしている全てのTodoのIDを返す (ゴミ箱にあるTodoも含む)
+func (r *TodoDependency) FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	defer r.db.rlock(ctx)()
+
+	return append([]string{}, r.db.dependencies[todoID]...), nil
+}
+
+// Lock は何もしない (トランザクションは DB の書き込みのロックを保持するため、依存関係の変更は既に直列化されている)
+func (r *TodoDependency) Lock(_ context.Context) error {
+	return nil
+}
+
+// Add は todoID のTodoが blockerID のTodoにブロックされる依存関係を追加する
+func (r *TodoDependency) Add(ctx context.Context, todoID string, blockerID string) error {
+	defer r.db.lock(ctx)()
+
+	if r.db.activeTodoIndex(todoID) == -1 || r.db.activeTodoIndex(blockerID) == -1 {
+		return model.ErrNotFound
+	}
+	if !slices.Contains(r.db.dependencies[todoID], blockerID) {
+		r.db.dependencies[todoID] = append(r.db.dependencies[todoID], blockerID)
+	}
+	return nil
+}
+
+// Remove は todoID のTodoが blockerID のTodoにブロックされる依存関係を削除する
+func (r *TodoDependency) Remove(ctx context.Context, todoID string, blockerID string) error {
+	defer r.db.lock(ctx)()
+
+	if !slices.Contains(r.db.dependencies[todoID], blockerID) {
+		return model.ErrNotFound
+	}
+	r.db.dependencies[todoID] = slices.DeleteFunc(r.db.dependencies[todoID], func(id string) bool {
+		return id == blockerID
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoHistory はインメモリのTodoの変更履歴の実装
+type TodoHistory struct {
+	db *DB
+}
+
+// NewTodoHistory は repository.TodoHistory のコンストラクタ
+func NewTodoHistory(db *DB) repository.TodoHistory {
+	return &TodoHistory{
+		db: db,
+	}
+}
+
+// FindByTodoID はTodoの変更履歴を古い順に取得する
+func (r *TodoHistory) FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error) {
+	defer r.db.rlock(ctx)()
+
+	histories := []model.TodoHistory{}
+	for _, h := range r.db.histories {
+		if h.TodoID == todoID {
+			histories = append(histories, h)
+		}
+	}
+	slices.SortStableFunc(histories, func(a, b model.TodoHistory) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return histories, nil
+}
+
+// FindByTodoIDs は todoIDs の各Todoの変更履歴をまとめて取得する
+func (r *TodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	defer r.db.rlock(ctx)()
+
+	histories := []model.TodoHistory{}
+	for _, h := range r.db.histories {
+		if slices.Contains(todoIDs, h.TodoID) {
+			histories = append(histories, h)
+		}
+	}
+	slices.SortStableFunc(histories, func(a, b model.TodoHistory) int {
+		return cmp.Or(cmp.Compare(a.TodoID, b.TodoID), cmp.Compare(a.Version, b.Version))
+	})
+	return histories, nil
+}
+
+// FindByVersion はTodoの指定したバージョンの変更履歴を取得する
+func (r *TodoHistory) FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error) {
+	defer r.db.rlock(ctx)()
+
+	i := slices.IndexFunc(r.db.histories, func(h model.TodoHistory) bool {
+		return h.TodoID == todoID && h.Version == version
+	})
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	h := r.db.histories[i]
+	return &h, nil
+}
+
+// recordHistory は before から after への変更履歴を記録する (呼び出し元でロックを取得していること)
+// 更新で変更がない場合は記録しない
+func (db *DB) recordHistory(ctx context.Context, action model.HistoryAction, before, after *model.Todo) {
+	changes := model.DiffTodo(before, after)
+	if before != nil && len(changes) == 0 {
+		return
+	}
+
+	info := model.AuditInfoFromContext(ctx)
+	db.histories = append(db.histories, model.TodoHistory{
+		ID:        uuid.New().String(),
+		TodoID:    after.ID,
+		Version:   after.Version,
+		Action:    action,
+		Actor:     info.Actor,
+		TraceID:   info.TraceID,
+		Changes:   changes,
+		Snapshot:  *after,
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,24 @@
+package inmemory_test
+
+import (
+	"testing"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+)
+
+func TestTodo(t *testing.T) {
+	repositorytest.TestTodo(t, func(t *testing.T) repositorytest.Backend {
+		db := inmemory.NewEmptyDB()
+		return repositorytest.Backend{
+			Todo:            inmemory.NewTodo(db),
+			TodoDependency:  inmemory.NewTodoDependency(db),
+			TodoHistory:     inmemory.NewTodoHistory(db),
+			Tag:             inmemory.NewTag(db),
+			TxManager:       inmemory.NewTxManager(db),
+			Outbox:          inmemory.NewOutbox(db),
+			Webhook:         inmemory.NewWebhook(db),
+			WebhookDelivery: inmemory.NewWebhookDelivery(db),
+		}
+	})
+}
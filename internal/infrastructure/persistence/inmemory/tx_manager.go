@@ -0,0 +1,33 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TxManager は repository.TxManager の実装
+// トランザクションの間は DB の書き込みのロックを保持して他の操作を待たせ、
+// fn がエラーを返した場合は開始した時点の状態に戻す
+// (fn に渡した ctx を他のゴルーチンで使うことはできない)
+type TxManager struct {
+	db *DB
+}
+
+// NewTxManager は repository.TxManager のコンストラクタ
+func NewTxManager(db *DB) repository.TxManager {
+	return &TxManager{
+		db: db,
+	}
+}
+
+// Do は fn を1つのトランザクションで実行する
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	defer m.db.lock(ctx)()
+	saved := m.db.save()
+	if err := fn(context.WithValue(ctx, txKey{}, m.db)); err != nil {
+		m.db.restore(saved)
+		return err
+	}
+	return nil
+}
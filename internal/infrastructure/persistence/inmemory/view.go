@@ -0,0 +1,111 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// View はインメモリのビューの実装
+type View struct {
+	db *DB
+}
+
+// NewView は repository.View のコンストラクタ
+func NewView(db *DB) repository.View {
+	return &View{
+		db: db,
+	}
+}
+
+// FindAll は全てのビューを名前順で取得する
+func (r *View) FindAll(ctx context.Context) ([]model.View, error) {
+	defer r.db.rlock(ctx)()
+
+	views := slices.Clone(r.db.views)
+	slices.SortFunc(views, func(a, b model.View) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return views, nil
+}
+
+// FindByID はIDによるビューの取得
+func (r *View) FindByID(ctx context.Context, id string) (*model.View, error) {
+	defer r.db.rlock(ctx)()
+
+	i := r.viewIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	v := r.db.views[i]
+	return &v, nil
+}
+
+// Create は新しいビューを作成する
+func (r *View) Create(ctx context.Context, view model.View) (*model.View, error) {
+	defer r.db.lock(ctx)()
+
+	if r.nameExists(view.Name, "") {
+		return nil, model.ErrConflict
+	}
+	now := time.Now()
+	v := model.View{
+		ID:        uuid.New().String(),
+		Name:      view.Name,
+		Query:     view.Query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.db.views = append(r.db.views, v)
+	return &v, nil
+}
+
+// Update はビューの名前と検索クエリを更新する
+func (r *View) Update(ctx context.Context, id string, view model.View) (*model.View, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.viewIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	if r.nameExists(view.Name, id) {
+		return nil, model.ErrConflict
+	}
+	r.db.views[i].Name = view.Name
+	r.db.views[i].Query = view.Query
+	r.db.views[i].UpdatedAt = time.Now()
+	v := r.db.views[i]
+	return &v, nil
+}
+
+// Delete はビューを削除する
+func (r *View) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.viewIndex(id)
+	if i == -1 {
+		return model.ErrNotFound
+	}
+	r.db.views = slices.Delete(r.db.views, i, i+1)
+	return nil
+}
+
+// viewIndex はIDに一致するビューの位置を返す (存在しない場合は -1)
+func (r *View) viewIndex(id string) int {
+	return slices.IndexFunc(r.db.views, func(v model.View) bool {
+		return v.ID == id
+	})
+}
+
+// nameExists は exceptID 以外に name と同じ名前のビューがあるかを返す
+func (r *View) nameExists(name string, exceptID string) bool {
+	return slices.ContainsFunc(r.db.views, func(v model.View) bool {
+		return v.Name == name && v.ID != exceptID
+	})
+}
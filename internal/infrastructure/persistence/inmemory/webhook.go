@@ -0,0 +1,98 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Webhook はインメモリのWebhookの購読の実装
+type Webhook struct {
+	db *DB
+}
+
+// NewWebhook は repository.Webhook のコンストラクタ
+func NewWebhook(db *DB) repository.Webhook {
+	return &Webhook{
+		db: db,
+	}
+}
+
+// FindAll は全てのWebhookを作成した順に取得する
+func (r *Webhook) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	defer r.db.rlock(ctx)()
+
+	return slices.Clone(r.db.webhooks), nil
+}
+
+// FindByID はIDによるWebhookの取得
+func (r *Webhook) FindByID(ctx context.Context, id string) (*model.Webhook, error) {
+	defer r.db.rlock(ctx)()
+
+	i := r.db.webhookIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	w := r.db.webhooks[i]
+	return &w, nil
+}
+
+// Create は新しいWebhookを作成する
+func (r *Webhook) Create(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	defer r.db.lock(ctx)()
+
+	now := time.Now()
+	w := model.Webhook{
+		ID:        uuid.New().String(),
+		URL:       webhook.URL,
+		Events:    slices.Clone(webhook.Events),
+		Secret:    webhook.Secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.db.webhooks = append(r.db.webhooks, w)
+	return &w, nil
+}
+
+// Update はWebhookの URL、イベントの種類と秘密鍵を更新する
+func (r *Webhook) Update(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.db.webhookIndex(id)
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	r.db.webhooks[i].URL = webhook.URL
+	r.db.webhooks[i].Events = slices.Clone(webhook.Events)
+	r.db.webhooks[i].Secret = webhook.Secret
+	r.db.webhooks[i].UpdatedAt = time.Now()
+	w := r.db.webhooks[i]
+	return &w, nil
+}
+
+// Delete はWebhookとその配信を削除する
+func (r *Webhook) Delete(ctx context.Context, id string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.db.webhookIndex(id)
+	if i == -1 {
+		return model.ErrNotFound
+	}
+	r.db.webhooks = slices.Delete(r.db.webhooks, i, i+1)
+	r.db.webhookDeliveries = slices.DeleteFunc(r.db.webhookDeliveries, func(d model.WebhookDelivery) bool {
+		return d.WebhookID == id
+	})
+	return nil
+}
+
+// webhookIndex はIDに一致するWebhookの位置を返す (存在しない場合は -1、呼び出し元でロックを取得していること)
+func (db *DB) webhookIndex(id string) int {
+	return slices.IndexFunc(db.webhooks, func(w model.Webhook) bool {
+		return w.ID == id
+	})
+}
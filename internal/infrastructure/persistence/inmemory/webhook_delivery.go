@@ -0,0 +1,105 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// WebhookDelivery はインメモリのWebhookの配信の実装
+type WebhookDelivery struct {
+	db *DB
+}
+
+// NewWebhookDelivery は repository.WebhookDelivery のコンストラクタ
+func NewWebhookDelivery(db *DB) repository.WebhookDelivery {
+	return &WebhookDelivery{
+		db: db,
+	}
+}
+
+// Create は配信を作成する
+func (r *WebhookDelivery) Create(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	defer r.db.lock(ctx)()
+
+	if r.db.webhookIndex(delivery.WebhookID) == -1 {
+		return nil, model.ErrNotFound
+	}
+	if slices.ContainsFunc(r.db.webhookDeliveries, func(d model.WebhookDelivery) bool {
+		return d.WebhookID == delivery.WebhookID && d.EventID == delivery.EventID
+	}) {
+		return nil, model.ErrConflict
+	}
+	now := time.Now()
+	d := model.WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        delivery.Status,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	r.db.webhookDeliveries = append(r.db.webhookDeliveries, d)
+	return &d, nil
+}
+
+// FindByWebhookID はWebhookの配信を新しい順に limit 件まで取得する
+func (r *WebhookDelivery) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	defer r.db.rlock(ctx)()
+
+	deliveries := []model.WebhookDelivery{}
+	for _, d := range slices.Backward(r.db.webhookDeliveries) {
+		if len(deliveries) == limit {
+			break
+		}
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// FindDue は now までに配信を試みる日時になった配信待ちの配信を、配信を試みる日時の順に limit 件まで取得する
+func (r *WebhookDelivery) FindDue(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	defer r.db.rlock(ctx)()
+
+	deliveries := []model.WebhookDelivery{}
+	for _, d := range r.db.webhookDeliveries {
+		if d.Status == model.WebhookDeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	slices.SortStableFunc(deliveries, func(a, b model.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(*b.NextAttemptAt)
+	})
+	return deliveries[:min(len(deliveries), limit)], nil
+}
+
+// Update は配信を試みた結果を保存する
+func (r *WebhookDelivery) Update(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	defer r.db.lock(ctx)()
+
+	i := slices.IndexFunc(r.db.webhookDeliveries, func(d model.WebhookDelivery) bool {
+		return d.ID == delivery.ID
+	})
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	d := &r.db.webhookDeliveries[i]
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.ResponseStatus = delivery.ResponseStatus
+	d.LastError = delivery.LastError
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.UpdatedAt = time.Now()
+	updated := *d
+	return &updated, nil
+}
@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// IdempotencyKey はPostgreSQLを使った冪等キーの実装
+type IdempotencyKey struct {
+	conn *pgxpool.Pool
+}
+
+// NewIdempotencyKey は repository.IdempotencyKey のコンストラクタ
+func NewIdempotencyKey(conn *pgxpool.Pool) repository.IdempotencyKey {
+	return &IdempotencyKey{
+		conn: conn,
+	}
+}
+
+// Reserve は冪等キーが存在しない場合に処理中として ttl の間保存し、true を返す
+// 有効期限を過ぎた冪等キーは新しいリクエストで上書きする
+func (r *IdempotencyKey) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx,
+		`INSERT INTO idempotency_key (request_key, fingerprint, expires_at) VALUES ($1, $2, NOW() + $3::INTERVAL)
+		ON CONFLICT (request_key) DO UPDATE SET
+			fingerprint = excluded.fingerprint, status_code = NULL, content_type = '', body = NULL,
+			created_at = NOW(), expires_at = excluded.expires_at
+		WHERE idempotency_key.expires_at <= NOW()`,
+		key, fingerprint, ttl)
+	if err != nil {
+		return nil, false, err
+	}
+	if cmdTag.RowsAffected() > 0 {
+		return nil, true, nil
+	}
+
+	rec := model.IdempotencyRecord{Key: key}
+	var statusCode *int
+	if err := connFrom(ctx, r.conn).QueryRow(ctx,
+		"SELECT fingerprint, status_code, content_type, body, expires_at FROM idempotency_key WHERE request_key = $1",
+		key).Scan(&rec.Fingerprint, &statusCode, &rec.ContentType, &rec.Body, &rec.ExpiresAt); err != nil {
+		return nil, false, err
+	}
+	if statusCode != nil {
+		rec.Completed = true
+		rec.StatusCode = *statusCode
+	}
+	return &rec, false, nil
+}
+
+// Complete は処理中の冪等キーにレスポンスを保存する
+func (r *IdempotencyKey) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx,
+		"UPDATE idempotency_key SET status_code = $2, content_type = $3, body = $4 WHERE request_key = $1 AND status_code IS NULL",
+		key, statusCode, contentType, body)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
+
+// Release は処理中の冪等キーを削除する
+func (r *IdempotencyKey) Release(ctx context.Context, key string) error {
+	_, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM idempotency_key WHERE request_key = $1 AND status_code IS NULL", key)
+	return err
+}
+
+// DeleteExpired は before の時点で有効期限を過ぎた冪等キーを削除し、削除した件数を返す
+func (r *IdempotencyKey) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM idempotency_key WHERE expires_at <= $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(cmdTag.RowsAffected()), nil
+}
@@ -0,0 +1,88 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Outbox はPostgreSQLを使ったアウトボックスの実装
+type Outbox struct {
+	conn *pgxpool.Pool
+}
+
+// NewOutbox は repository.Outbox のコンストラクタ
+func NewOutbox(conn *pgxpool.Pool) repository.Outbox {
+	return &Outbox{
+		conn: conn,
+	}
+}
+
+// Add はイベントを配信待ちとして保存する
+func (r *Outbox) Add(ctx context.Context, events []model.TodoEvent) error {
+	return pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		for _, e := range events {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO outbox_event (event_type, todo_id, actor, trace_id, todo, occurred_at)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				e.Type, e.TodoID, e.Actor, e.TraceID, e.Todo, e.OccurredAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPending は配信を試みる日時になった配信待ちのイベントを保存した順に返す
+func (r *Outbox) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		`SELECT o.seq, o.id, o.event_type, o.todo_id, o.actor, o.trace_id, o.todo, o.occurred_at, o.attempts, o.last_error, o.available_at
+		FROM outbox_event o
+		WHERE o.available_at <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_event w WHERE w.todo_id = o.todo_id AND w.seq < o.seq AND w.available_at > $1
+			)
+		ORDER BY o.seq
+		LIMIT $2`,
+		now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []model.OutboxMessage{}
+	for rows.Next() {
+		var m model.OutboxMessage
+		if err := rows.Scan(&m.Seq, &m.Event.ID, &m.Event.Type, &m.Event.TodoID, &m.Event.Actor, &m.Event.TraceID,
+			&m.Event.Todo, &m.Event.OccurredAt, &m.Attempts, &m.LastError, &m.AvailableAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Delete は配信したイベントを削除する
+func (r *Outbox) Delete(ctx context.Context, seq int64) error {
+	_, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM outbox_event WHERE seq = $1", seq)
+	return err
+}
+
+// MarkFailed は配信に失敗したイベントの失敗回数を増やし、次に配信を試みる日時を設定する
+func (r *Outbox) MarkFailed(ctx context.Context, seq int64, lastError string, nextAttemptAt time.Time) error {
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx,
+		"UPDATE outbox_event SET attempts = attempts + 1, last_error = $2, available_at = $3 WHERE seq = $1",
+		seq, lastError, nextAttemptAt)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
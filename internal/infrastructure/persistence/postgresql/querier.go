@@ -0,0 +1,31 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier は pgxpool.Pool と pgx.Tx の共通のクエリ実行インターフェース
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn はトランザクションを開始できる querier
+// (pgx.Tx の Begin はセーブポイントを作成するため、pgx.BeginFunc はトランザクションの中でも使える)
+type conn interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// connFrom は ctx に TxManager のトランザクションがあればそれを、なければ pool を返す
+func connFrom(ctx context.Context, pool *pgxpool.Pool) conn {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
@@ -0,0 +1,140 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// uniqueViolation は一意制約違反のエラーコード
+const uniqueViolation = "23505"
+
+// Tag はPostgreSQLを使ったタグの実装
+type Tag struct {
+	conn *pgxpool.Pool
+}
+
+// NewTag は repository.Tag のコンストラクタ
+func NewTag(conn *pgxpool.Pool) repository.Tag {
+	return &Tag{
+		conn: conn,
+	}
+}
+
+// scanTag は id, name の順で取得した行をタグに変換する
+func scanTag(row pgx.Row) (*model.Tag, error) {
+	var t model.Tag
+	if err := row.Scan(&t.ID, &t.Name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindAll は全てのタグを名前順で取得する
+func (r *Tag) FindAll(ctx context.Context) ([]model.Tag, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx, "SELECT id, name FROM tag ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// FindByID はIDによるタグの取得
+func (r *Tag) FindByID(ctx context.Context, id string) (*model.Tag, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return scanTag(connFrom(ctx, r.conn).QueryRow(ctx, "SELECT id, name FROM tag WHERE id = $1", id))
+}
+
+// Rename はタグ名を変更する
+func (r *Tag) Rename(ctx context.Context, id string, name string) (*model.Tag, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	t, err := scanTag(connFrom(ctx, r.conn).QueryRow(ctx, "UPDATE tag SET name = $2 WHERE id = $1 RETURNING id, name", id, name))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return nil, model.ErrConflict
+	}
+	return t, err
+}
+
+// Merge は source が付与されたTodoを target に付け替え、source を削除する
+func (r *Tag) Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	if err := checkUUIDs(sourceID, targetID); err != nil {
+		return nil, err
+	}
+	var target *model.Tag
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		var err error
+		target, err = scanTag(tx.QueryRow(ctx, "SELECT id, name FROM tag WHERE id = $1", targetID))
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO todo_tag (todo_id, tag_id)
+			SELECT todo_id, $2 FROM todo_tag WHERE tag_id = $1
+			ON CONFLICT DO NOTHING`,
+			sourceID, targetID); err != nil {
+			return err
+		}
+
+		// todo_tag は ON DELETE CASCADE で削除される
+		cmdTag, err := tx.Exec(ctx, "DELETE FROM tag WHERE id = $1", sourceID)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return model.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// Delete はタグを削除し、付与されていたTodoからも外す
+func (r *Tag) Delete(ctx context.Context, id string) error {
+	if err := checkUUIDs(id); err != nil {
+		return err
+	}
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM tag WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
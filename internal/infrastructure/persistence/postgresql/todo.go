@@ -3,40 +3,216 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/qushot/gin-todo-api/internal/domain/model"
 	"github.com/qushot/gin-todo-api/internal/domain/repository"
 )
 
+// todoColumns はTodoを取得する際のカラム一覧 (scanTodo と順番を合わせること)
+const todoColumns = `id, title, content, status, priority, parent_id, position, auto_complete,
+	due_at, recurrence, timezone, series_id, occurrence_at, completed_at, archived_at, deleted_at, version,
+	updated_at::TIMESTAMPTZ AS updated_at, done,
+	ARRAY(
+		SELECT tag.name FROM todo_tag INNER JOIN tag ON todo_tag.tag_id = tag.id
+		WHERE todo_tag.todo_id = todo.id ORDER BY tag.name
+	) AS tags,
+	ARRAY(
+		SELECT todo_dependency.blocker_id::TEXT FROM todo_dependency
+		INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+		WHERE todo_dependency.todo_id = todo.id AND blocker.deleted_at IS NULL
+		ORDER BY todo_dependency.created_at
+	) AS blocked_by`
+
+// nextPositionQuery は parentIDParam で指定した親の子の末尾の並び順を求めるサブクエリを返す
+func nextPositionQuery(parentIDParam string) string {
+	return "SELECT COALESCE(MAX(position) + 1, 0) FROM todo WHERE parent_id IS NOT DISTINCT FROM " + parentIDParam + "::UUID"
+}
+
+// updateTodoQuery はTodoを更新するクエリ (引数は updateTodoArgs で組み立てる)
+// 親が変わった場合は新しい兄弟の末尾に移動する
+var updateTodoQuery = `UPDATE todo SET title = $2, content = $3, status = $4, priority = $6, parent_id = $5, auto_complete = $7,
+		due_at = $8, recurrence = $9, timezone = $10, series_id = $11, occurrence_at = $12,
+		completed_at = CASE WHEN $4 = 'done' THEN COALESCE(completed_at, NOW()) END,
+		position = CASE WHEN parent_id IS DISTINCT FROM $5 THEN (` + nextPositionQuery("$5") + `) ELSE position END
+	WHERE id = $1 AND deleted_at IS NULL`
+
+// updateTodoArgs は updateTodoQuery の引数を返す
+func updateTodoArgs(id string, todo model.Todo) []any {
+	return []any{
+		id, todo.Title, todo.Content, todo.Status, todo.ParentID, todo.Priority, todo.AutoComplete,
+		todo.DueAt, todo.Recurrence, todo.Timezone, todo.SeriesID, todo.OccurrenceAt,
+	}
+}
+
+// trashTodoQuery は $1 のTodoを子孫のTodoも含めてゴミ箱に移動し、移動したTodoのIDを返すクエリ
+const trashTodoQuery = `WITH RECURSIVE subtree AS (
+		SELECT id FROM todo WHERE id = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT todo.id FROM todo INNER JOIN subtree ON todo.parent_id = subtree.id
+		WHERE todo.deleted_at IS NULL
+	)
+	UPDATE todo SET deleted_at = NOW() FROM subtree WHERE todo.id = subtree.id
+	RETURNING todo.id::TEXT`
+
 // Todo はPostgreSQLを使ったTodoの実装
 type Todo struct {
-	conn *pgx.Conn
+	conn *pgxpool.Pool
 }
 
 // NewTodo は repository.Todo のコンストラクタ
-func NewTodo(conn *pgx.Conn) repository.Todo {
+func NewTodo(conn *pgxpool.Pool) repository.Todo {
 	return &Todo{
 		conn: conn,
 	}
 }
 
-// FindAll は全てのTodoを取得する
-func (r *Todo) FindAll(ctx context.Context, _ model.TodoQuery) ([]model.Todo, error) {
-	var todos []model.Todo
-	rows, err := r.conn.Query(ctx, "SELECT id, title, content, done FROM todo")
+// scanTodo は todoColumns の順で取得した行をTodoに変換する (todoColumns に続くカラムは extra に格納する)
+func scanTodo(row pgx.Row, extra ...any) (*model.Todo, error) {
+	var t model.Todo
+	dest := []any{
+		&t.ID, &t.Title, &t.Content, &t.Status, &t.Priority, &t.ParentID, &t.Position, &t.AutoComplete,
+		&t.DueAt, &t.Recurrence, &t.Timezone, &t.SeriesID, &t.OccurrenceAt, &t.CompletedAt, &t.ArchivedAt, &t.DeletedAt,
+		&t.Version, &t.UpdatedAt, &t.Done, &t.Tags, &t.BlockedBy,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// queryTodos は todoColumns を取得するクエリを実行し、行をTodoに変換する
+func queryTodos(ctx context.Context, q querier, query string, args ...any) ([]model.Todo, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	todos := []model.Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// buildTodoFilter は検索クエリからWHERE句と引数を組み立てる (ゴミ箱にあるTodoは常に除く)
+func buildTodoFilter(query model.TodoQuery) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.Status != "" {
+		conds = append(conds, "status = "+arg(query.Status))
+	}
+	if len(query.Tags) > 0 {
+		tagged := "SELECT COUNT(DISTINCT tag.id) FROM todo_tag INNER JOIN tag ON todo_tag.tag_id = tag.id " +
+			"WHERE todo_tag.todo_id = todo.id AND tag.name = ANY(" + arg(query.Tags) + ")"
+		if query.TagMatch == model.TagMatchAll {
+			conds = append(conds, fmt.Sprintf("(%s) = %d", tagged, len(query.Tags)))
+		} else {
+			conds = append(conds, fmt.Sprintf("(%s) > 0", tagged))
+		}
+	}
+
+	switch query.Archived {
+	case model.ArchivedFilterAny:
+	case model.ArchivedFilterTrue:
+		conds = append(conds, "archived_at IS NOT NULL")
+	default:
+		conds = append(conds, "archived_at IS NULL")
+	}
+	if query.SeriesID != "" {
+		conds = append(conds, "series_id = "+arg(query.SeriesID))
+	}
+	if query.MinPriority != nil {
+		conds = append(conds, "priority >= "+arg(*query.MinPriority))
+	}
+	if due := query.DueRange; due != nil {
+		if due.None {
+			conds = append(conds, "due_at IS NULL")
+		}
+		if due.From != nil {
+			conds = append(conds, "due_at >= "+arg(*due.From))
+		}
+		if due.To != nil {
+			conds = append(conds, "due_at < "+arg(*due.To))
+		}
+		if due.OpenOnly {
+			conds = append(conds, "status NOT IN ('done', 'cancelled')")
+		}
+	}
+
+	if terms := model.SearchTerms(query.Q); len(terms) > 0 {
+		conds = append(conds, "search_vector @@ todo_search_query("+arg(terms)+")")
+	}
+
+	if query.Actionable {
+		conds = append(conds, `status NOT IN ('done', 'cancelled') AND NOT EXISTS (
+			SELECT 1 FROM todo_dependency INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+			WHERE todo_dependency.todo_id = todo.id AND blocker.deleted_at IS NULL
+				AND blocker.status NOT IN ('done', 'cancelled')
+		)`)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// FindAll は全てのTodoを作成した順に取得する (同時に作成したTodoは並び順で並べる)
+// 検索キーワードを指定した場合は関連度の高い順に並べ、一致の情報を設定する
+func (r *Todo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	todos := []model.Todo{}
+	where, args := buildTodoFilter(query)
+	terms := model.SearchTerms(query.Q)
+	stmt := "SELECT " + todoColumns + " FROM todo" + where + " ORDER BY created_at, position"
+	var rank float64
+	var extra []any
+	if len(terms) > 0 {
+		// タイトル (重み A) に一致したTodoを内容 (重み B) のみに一致したTodoより上位にする
+		args = append(args, terms)
+		stmt = fmt.Sprintf("SELECT %s, ts_rank(search_vector, todo_search_query($%d))::FLOAT8 AS rank FROM todo%s ORDER BY rank DESC, created_at, position",
+			todoColumns, len(args), where)
+		extra = append(extra, &rank)
+	}
+	rows, err := connFrom(ctx, r.conn).Query(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var t model.Todo
-		if err := rows.Scan(&t.ID, &t.Title, &t.Content, &t.Done); err != nil {
+		t, err := scanTodo(rows, extra...)
+		if err != nil {
 			return nil, err
 		}
-		todos = append(todos, t)
+		if len(terms) > 0 {
+			t.Search = model.NewTodoSearchMatch(t, terms, rank)
+		}
+		todos = append(todos, *t)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -46,62 +222,797 @@ func (r *Todo) FindAll(ctx context.Context, _ model.TodoQuery) ([]model.Todo, er
 	return todos, nil
 }
 
+// Stamp は query に一致するTodoの件数と、全てのTodoの最終更新日時を返す
+func (r *Todo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	where, args := buildTodoFilter(query)
+	stamp := model.TodoListStamp{DueRange: query.DueRange}
+	var lastModified *time.Time
+	if err := connFrom(ctx, r.conn).QueryRow(ctx,
+		"SELECT (SELECT COUNT(*) FROM todo"+where+"), (SELECT MAX(updated_at)::TIMESTAMPTZ FROM todo)",
+		args...).Scan(&stamp.Count, &lastModified); err != nil {
+		return nil, err
+	}
+	if lastModified != nil {
+		stamp.LastModified = *lastModified
+	}
+
+	return &stamp, nil
+}
+
 // FindByID はIDによるTodoの取得
 func (r *Todo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
-	var t model.Todo
-	err := r.conn.QueryRow(ctx, "SELECT id, title, content, done FROM todo WHERE id = $1", id).
-		Scan(&t.ID, &t.Title, &t.Content, &t.Done)
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return findTodoByID(ctx, connFrom(ctx, r.conn), id)
+}
+
+// findTodoByID はIDによるTodoの取得 (トランザクション内からも利用する)
+func findTodoByID(ctx context.Context, q querier, id string) (*model.Todo, error) {
+	return scanTodo(q.QueryRow(ctx, "SELECT "+todoColumns+" FROM todo WHERE id = $1 AND deleted_at IS NULL", id))
+}
+
+// FindByIDs は ids のTodoをまとめて取得する (UUID でないIDは存在しないため問い合わせない)
+func (r *Todo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	return queryTodos(ctx, connFrom(ctx, r.conn),
+		"SELECT "+todoColumns+" FROM todo WHERE id = ANY($1::UUID []) AND deleted_at IS NULL",
+		validUUIDs(ids))
+}
+
+// lockTodoByID はIDによるTodoの取得を行い、トランザクションの終了まで行をロックする
+func lockTodoByID(ctx context.Context, tx pgx.Tx, id string) (*model.Todo, error) {
+	return scanTodo(tx.QueryRow(ctx, "SELECT "+todoColumns+" FROM todo WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id))
+}
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, errors.New("not found")
-	} else if err != nil {
+// findTodosByIDs は複数のIDによるTodoの取得 (ゴミ箱にあるTodoも含む)
+func findTodosByIDs(ctx context.Context, q querier, ids []string) ([]model.Todo, error) {
+	rows, err := q.Query(ctx, "SELECT "+todoColumns+" FROM todo WHERE id = ANY($1::UUID [])", ids)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &t, nil
+	var todos []model.Todo
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
 }
 
 // Create は新しいTodoを作成する
 func (r *Todo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
-	var t model.Todo
-	err := r.conn.QueryRow(ctx,
-		"INSERT INTO todo (title, content, done) VALUES ($1, $2, $3) RETURNING id, title, content, done",
-		todo.Title, todo.Content, todo.Done).
-		Scan(&t.ID, &t.Title, &t.Content, &t.Done)
+	var t *model.Todo
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		var id string
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO todo (
+				title, content, status, priority, parent_id, position, auto_complete,
+				due_at, recurrence, timezone, series_id, occurrence_at, completed_at
+			)
+			VALUES (
+				$1, $2, $3, $4, $5, (`+nextPositionQuery("$5")+`), $6, $7, $8, $9, $10, $11,
+				CASE WHEN $3 = 'done' THEN NOW() END
+			) RETURNING id`,
+			todo.Title, todo.Content, todo.Status, todo.Priority, todo.ParentID, todo.AutoComplete,
+			todo.DueAt, todo.Recurrence, todo.Timezone, todo.SeriesID, todo.OccurrenceAt).Scan(&id); err != nil {
+			return err
+		}
+		if err := setTodoTags(ctx, tx, id, todo.Tags); err != nil {
+			return err
+		}
+
+		var err error
+		if t, err = findTodoByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return recordTodoHistory(ctx, tx, model.HistoryActionCreate, nil, t)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &t, nil
+	return t, nil
 }
 
 // Update はTodoを更新する
 func (r *Todo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
-	var t model.Todo
-	err := r.conn.QueryRow(ctx,
-		"UPDATE todo SET title = $2, content = $3, done = $4 WHERE id = $1 RETURNING id, title, content, done",
-		id, todo.Title, todo.Content, todo.Done).
-		Scan(&t.ID, &t.Title, &t.Content, &t.Done)
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	var t *model.Todo
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		before, err := lockTodoByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := model.CheckBaseVersion(before, todo.Version); err != nil {
+			return err
+		}
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, errors.New("not found")
-	} else if err != nil {
+		cmdTag, err := tx.Exec(ctx, updateTodoQuery, updateTodoArgs(id, todo)...)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return model.ErrNotFound
+		}
+		if err := setTodoTags(ctx, tx, id, todo.Tags); err != nil {
+			return err
+		}
+
+		if t, err = findTodoByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return recordTodoHistory(ctx, tx, model.HistoryActionUpdate, before, t)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &t, nil
+	return t, nil
+}
+
+// setTodoTags はTodoに付与するタグを names で置き換える (存在しないタグは作成する)
+func setTodoTags(ctx context.Context, q querier, todoID string, names []string) error {
+	if _, err := q.Exec(ctx, "DELETE FROM todo_tag WHERE todo_id = $1", todoID); err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if _, err := q.Exec(ctx,
+		"INSERT INTO tag (name) SELECT UNNEST($1::TEXT []) ON CONFLICT (name) DO NOTHING",
+		names); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx,
+		"INSERT INTO todo_tag (todo_id, tag_id) SELECT $1, id FROM tag WHERE name = ANY($2)",
+		todoID, names)
+	return err
 }
 
-// Delete はTodoを削除する
+// Delete はTodoを子孫のTodoも含めてゴミ箱に移動する
+// 子孫のTodoには同じ日時を設定し、Restore で一緒に元に戻せるようにする
 func (r *Todo) Delete(ctx context.Context, id string) error {
-	cmdTag, err := r.conn.Exec(ctx, "DELETE FROM todo WHERE id = $1", id)
+	if err := checkUUIDs(id); err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, trashTodoQuery, id)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return model.ErrNotFound
+		}
+
+		return recordTodoHistories(ctx, tx, model.HistoryActionDelete, ids, func(before *model.Todo) {
+			before.DeletedAt = nil
+		})
+	})
+}
+
+// FindChildren は子のTodoを並び順で取得する
+func (r *Todo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	if checkUUIDs(parentID) != nil {
+		return []model.Todo{}, nil
+	}
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		"SELECT "+todoColumns+" FROM todo WHERE parent_id = $1 AND deleted_at IS NULL ORDER BY position, created_at",
+		parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := []model.Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return children, nil
+}
+
+// FindChildrenByParentIDs は parentIDs の各親の子のTodoをまとめて取得する
+func (r *Todo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	return queryTodos(ctx, connFrom(ctx, r.conn),
+		"SELECT "+todoColumns+" FROM todo WHERE parent_id = ANY($1::UUID []) AND deleted_at IS NULL ORDER BY parent_id, position, created_at",
+		validUUIDs(parentIDs))
+}
+
+// ReorderChildren は子のTodoを ids の順に並び替える
+func (r *Todo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	if err := checkUUIDs(append([]string{parentID}, ids...)...); err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		cmdTag, err := tx.Exec(ctx,
+			`UPDATE todo SET position = ordered.position - 1
+			FROM UNNEST($2::UUID []) WITH ORDINALITY AS ordered (id, position)
+			WHERE todo.id = ordered.id AND todo.parent_id = $1 AND todo.deleted_at IS NULL`,
+			parentID, ids)
+		if err != nil {
+			return err
+		}
+		if cmdTag.RowsAffected() != int64(len(ids)) {
+			return model.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// FindTrash はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+func (r *Todo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		"SELECT "+todoColumns+" FROM todo WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, position")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	todos := []model.Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// Restore はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+func (r *Todo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	var t *model.Todo
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		var deletedAt time.Time
+		var parentTrashed bool
+		if err := tx.QueryRow(ctx,
+			`SELECT todo.deleted_at, COALESCE(parent.deleted_at IS NOT NULL, FALSE) FROM todo
+			LEFT JOIN todo AS parent ON todo.parent_id = parent.id
+			WHERE todo.id = $1 AND todo.deleted_at IS NOT NULL
+			FOR UPDATE OF todo`,
+			id).Scan(&deletedAt, &parentTrashed); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.ErrNotFound
+			}
+			return err
+		}
+		if parentTrashed {
+			return fmt.Errorf("%w: parent todo is in the trash, restore it first", model.ErrConflict)
+		}
+
+		rows, err := tx.Query(ctx,
+			`WITH RECURSIVE subtree AS (
+				SELECT id, deleted_at FROM todo WHERE id = $1
+				UNION ALL
+				SELECT todo.id, todo.deleted_at FROM todo INNER JOIN subtree ON todo.parent_id = subtree.id
+				WHERE todo.deleted_at = subtree.deleted_at
+			)
+			UPDATE todo SET deleted_at = NULL FROM subtree WHERE todo.id = subtree.id
+			RETURNING todo.id::TEXT`,
+			id)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		if err := recordTodoHistories(ctx, tx, model.HistoryActionRestore, ids, func(before *model.Todo) {
+			before.DeletedAt = &deletedAt
+		}); err != nil {
+			return err
+		}
+
+		t, err = findTodoByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Purge はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する (子孫は ON DELETE CASCADE で削除される)
+func (r *Todo) Purge(ctx context.Context, id string) error {
+	if err := checkUUIDs(id); err != nil {
+		return err
+	}
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM todo WHERE id = $1 AND deleted_at IS NOT NULL", id)
 	if err != nil {
 		return err
 	}
 
 	if cmdTag.RowsAffected() == 0 {
-		return errors.New("not found")
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+// PurgeDeletedBefore は before より前にゴミ箱に移動したTodoを完全に削除し、削除した件数を返す
+func (r *Todo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM todo WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(cmdTag.RowsAffected()), nil
+}
+
+// Archive はTodoをアーカイブする (アーカイブ済みの場合はアーカイブした日時を維持する)
+func (r *Todo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	return r.setArchivedAt(ctx, id, "COALESCE(archived_at, NOW())", model.HistoryActionArchive)
+}
+
+// Unarchive はTodoのアーカイブを解除する
+func (r *Todo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	return r.setArchivedAt(ctx, id, "NULL", model.HistoryActionUnarchive)
+}
+
+// setArchivedAt はTodoの archived_at を expr の値に更新し、action として変更履歴を記録する
+func (r *Todo) setArchivedAt(ctx context.Context, id string, expr string, action model.HistoryAction) (*model.Todo, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	var t *model.Todo
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		before, err := lockTodoByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "UPDATE todo SET archived_at = "+expr+" WHERE id = $1", id); err != nil {
+			return err
+		}
+
+		if t, err = findTodoByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return recordTodoHistory(ctx, tx, action, before, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ArchiveCompletedBefore は before より前に done になったアーカイブされていないTodoをアーカイブし、アーカイブした件数を返す
+func (r *Todo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			`UPDATE todo SET archived_at = NOW()
+			WHERE status = 'done' AND completed_at < $1 AND archived_at IS NULL AND deleted_at IS NULL
+			RETURNING id::TEXT`,
+			before)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		n = len(ids)
+
+		return recordTodoHistories(ctx, tx, model.HistoryActionArchive, ids, func(before *model.Todo) {
+			before.ArchivedAt = nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Touch は befores のTodoのバージョンと更新日時を更新し、befores からの変更を変更履歴に記録する
+// UPDATE でトリガーを実行してバージョンと更新日時を更新する
+func (r *Todo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	ids := make([]string, len(befores))
+	beforeByID := make(map[string]*model.Todo, len(befores))
+	for i := range befores {
+		ids[i] = befores[i].ID
+		beforeByID[befores[i].ID] = &befores[i]
+	}
+
+	var todos []model.Todo
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			"UPDATE todo SET updated_at = NOW() WHERE id = ANY($1::UUID []) AND deleted_at IS NULL",
+			validUUIDs(ids)); err != nil {
+			return err
+		}
+
+		var err error
+		if todos, err = queryTodos(ctx, tx,
+			"SELECT "+todoColumns+" FROM todo WHERE id = ANY($1::UUID []) AND deleted_at IS NULL",
+			validUUIDs(ids)); err != nil {
+			return err
+		}
+		entries := make([]todoHistoryEntry, 0, len(todos))
+		for i := range todos {
+			entries = append(entries, todoHistoryEntry{model.HistoryActionUpdate, beforeByID[todos[i].ID], &todos[i]})
+		}
+		return copyTodoHistories(ctx, tx, entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// ApplyBatch は writes を1つのトランザクションで適用し、書き込みごとに作成または更新した後のTodoを返す (ゴミ箱に移動した場合は nil)
+// 作成するTodoは COPY でまとめて挿入し、タグの付け替えと更新、ゴミ箱への移動は pgx.Batch で1往復にまとめて送信する
+func (r *Todo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	todos := make([]*model.Todo, len(writes))
+	err := pgx.BeginFunc(ctx, connFrom(ctx, r.conn), func(tx pgx.Tx) error {
+		ids := make([]string, len(writes))
+		var lockIDs []string
+		for i, w := range writes {
+			if w.Kind == model.TodoWriteCreate {
+				ids[i] = uuid.NewString()
+				continue
+			}
+			ids[i] = w.ID
+			lockIDs = append(lockIDs, w.ID)
+		}
+		befores, err := lockTodosByIDs(ctx, tx, lockIDs)
+		if err != nil {
+			return err
+		}
+		for _, w := range writes {
+			if w.Kind != model.TodoWriteUpdate {
+				continue
+			}
+			if err := model.CheckBaseVersion(befores[w.ID], w.Todo.Version); err != nil {
+				return err
+			}
+		}
+
+		if err := copyNewTodos(ctx, tx, writes, ids); err != nil {
+			return err
+		}
+		trashedIDs, err := sendTodoWrites(ctx, tx, writes, ids)
+		if err != nil {
+			return err
+		}
+
+		afters, err := findTodosByIDs(ctx, tx, append(slices.Clone(ids), trashedIDs...))
+		if err != nil {
+			return err
+		}
+		afterByID := make(map[string]*model.Todo, len(afters))
+		for i := range afters {
+			afterByID[afters[i].ID] = &afters[i]
+		}
+
+		var histories []todoHistoryEntry
+		for i, w := range writes {
+			switch w.Kind {
+			case model.TodoWriteCreate:
+				todos[i] = afterByID[ids[i]]
+				histories = append(histories, todoHistoryEntry{model.HistoryActionCreate, nil, todos[i]})
+			case model.TodoWriteUpdate:
+				todos[i] = afterByID[ids[i]]
+				histories = append(histories, todoHistoryEntry{model.HistoryActionUpdate, befores[w.ID], todos[i]})
+			}
+		}
+		for _, id := range trashedIDs {
+			after := afterByID[id]
+			before := *after
+			before.DeletedAt = nil
+			histories = append(histories, todoHistoryEntry{model.HistoryActionDelete, &before, after})
+		}
+		return copyTodoHistories(ctx, tx, histories)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// lockTodosByIDs は複数のIDによるTodoの取得を行い、トランザクションの終了まで行をロックする
+// デッドロックを避けるためIDの順にロックし、1つでも存在しない場合は model.ErrNotFound を返す
+func lockTodosByIDs(ctx context.Context, tx pgx.Tx, ids []string) (map[string]*model.Todo, error) {
+	todos := make(map[string]*model.Todo, len(ids))
+	if len(ids) == 0 {
+		return todos, nil
 	}
+	if err := checkUUIDs(ids...); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx,
+		"SELECT "+todoColumns+" FROM todo WHERE id = ANY($1::UUID []) AND deleted_at IS NULL ORDER BY id FOR UPDATE",
+		ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos[t.ID] = t
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, ok := todos[id]; !ok {
+			return nil, model.ErrNotFound
+		}
+	}
+	return todos, nil
+}
+
+// copyNewTodos は writes のうち作成する書き込みのTodoを ids のIDで COPY を使ってまとめて挿入する
+// 並び順は親ごとに現在の末尾から書き込みの順に割り当てる
+func copyNewTodos(ctx context.Context, tx pgx.Tx, writes []model.TodoWrite, ids []string) error {
+	batch := &pgx.Batch{}
+	var now time.Time
+	batch.Queue("SELECT NOW()").QueryRow(func(row pgx.Row) error {
+		return row.Scan(&now)
+	})
+	positions := make(map[string]int)
+	for _, w := range writes {
+		key := parentKey(w.Todo.ParentID)
+		if _, ok := positions[key]; ok || w.Kind != model.TodoWriteCreate {
+			continue
+		}
+		positions[key] = 0
+		batch.Queue(nextPositionQuery("$1"), w.Todo.ParentID).QueryRow(func(row pgx.Row) error {
+			var position int
+			if err := row.Scan(&position); err != nil {
+				return err
+			}
+			positions[key] = position
+			return nil
+		})
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	var rows [][]any
+	for i, w := range writes {
+		if w.Kind != model.TodoWriteCreate {
+			continue
+		}
+		t := w.Todo
+		// COPY のバイナリ形式では文字列をUUIDに変換できないため、UUIDのカラムは uuid.UUID で渡す
+		id, err := uuid.Parse(ids[i])
+		if err != nil {
+			return err
+		}
+		parentID, err := parseNullableUUID(t.ParentID)
+		if err != nil {
+			return err
+		}
+		seriesID, err := parseNullableUUID(t.SeriesID)
+		if err != nil {
+			return err
+		}
+		var completedAt *time.Time
+		if t.Status == model.TodoStatusDone {
+			completedAt = &now
+		}
+		key := parentKey(t.ParentID)
+		rows = append(rows, []any{
+			id, t.Title, t.Content, t.Status, t.Priority, parentID, positions[key], t.AutoComplete,
+			t.DueAt, t.Recurrence, t.Timezone, seriesID, t.OccurrenceAt, completedAt,
+		})
+		positions[key]++
+	}
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"todo"}, []string{
+		"id", "title", "content", "status", "priority", "parent_id", "position", "auto_complete",
+		"due_at", "recurrence", "timezone", "series_id", "occurrence_at", "completed_at",
+	}, pgx.CopyFromRows(rows))
+	return err
+}
+
+// sendTodoWrites は作成または更新するTodoのタグの付け替えと、更新、ゴミ箱への移動を pgx.Batch でまとめて実行し、
+// ゴミ箱に移動したTodoのIDを返す
+func sendTodoWrites(ctx context.Context, tx pgx.Tx, writes []model.TodoWrite, ids []string) ([]string, error) {
+	batch := &pgx.Batch{}
+	var updatedIDs, taggedIDs, tagNames []string
+	for i, w := range writes {
+		if w.Kind == model.TodoWriteDelete {
+			continue
+		}
+		if w.Kind == model.TodoWriteUpdate {
+			updatedIDs = append(updatedIDs, ids[i])
+		}
+		for _, name := range w.Todo.Tags {
+			taggedIDs = append(taggedIDs, ids[i])
+			tagNames = append(tagNames, name)
+		}
+	}
+	if len(updatedIDs) > 0 {
+		batch.Queue("DELETE FROM todo_tag WHERE todo_id = ANY($1::UUID [])", updatedIDs)
+	}
+	if len(tagNames) > 0 {
+		batch.Queue("INSERT INTO tag (name) SELECT UNNEST($1::TEXT []) ON CONFLICT (name) DO NOTHING", tagNames)
+		batch.Queue(
+			`INSERT INTO todo_tag (todo_id, tag_id)
+			SELECT tagged.todo_id, tag.id FROM UNNEST($1::UUID [], $2::TEXT []) AS tagged (todo_id, name)
+			INNER JOIN tag ON tagged.name = tag.name
+			ON CONFLICT DO NOTHING`,
+			taggedIDs, tagNames)
+	}
+
+	var trashedIDs []string
+	for _, w := range writes {
+		switch w.Kind {
+		case model.TodoWriteUpdate:
+			batch.Queue(updateTodoQuery, updateTodoArgs(w.ID, w.Todo)...).Exec(func(cmdTag pgconn.CommandTag) error {
+				if cmdTag.RowsAffected() == 0 {
+					return model.ErrNotFound
+				}
+				return nil
+			})
+		case model.TodoWriteDelete:
+			batch.Queue(trashTodoQuery, w.ID).Query(func(rows pgx.Rows) error {
+				ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+				if err != nil {
+					return err
+				}
+				if len(ids) == 0 {
+					return model.ErrNotFound
+				}
+				trashedIDs = append(trashedIDs, ids...)
+				return nil
+			})
+		}
+	}
+	if batch.Len() == 0 {
+		return nil, nil
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, err
+	}
+
+	return trashedIDs, nil
+}
+
+// parentKey は親のIDを並び順を割り当てる単位のキーに変換する (ルートは空文字列)
+func parentKey(parentID *string) string {
+	if parentID == nil {
+		return ""
+	}
+	return *parentID
+}
+
+// validUUIDs は ids のうち UUID として正しいものを返す
+func validUUIDs(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if uuid.Validate(id) == nil {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
+
+// checkUUIDs は ids に UUID として正しくないものがある場合に model.ErrNotFound を返す
+// UUID のカラムと比較するとキャストのエラーになるため、問い合わせる前に存在しないIDとして扱う
+func checkUUIDs(ids ...string) error {
+	for _, id := range ids {
+		if uuid.Validate(id) != nil {
+			return fmt.Errorf("%w: invalid id %q", model.ErrNotFound, id)
+		}
+	}
 	return nil
 }
+
+// parseNullableUUID はUUIDの文字列を uuid.UUID に変換する (nil または空文字列の場合は nil)
+func parseNullableUUID(s *string) (*uuid.UUID, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// FindChanges は同期トークン since 以降に変更したTodoを変更した順に返す
+// 同期トークンは読み取ったスナップショットの xmin で、次回はそれ以降のトランザクションIDで記録した変更を返す
+// (スナップショットの時点で実行中だったトランザクションの変更を、後からコミットされても取りこぼさない)
+func (r *Todo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	changes := &model.TodoChanges{Todos: []model.Todo{}, Purged: []model.TodoTombstone{}}
+	sinceXID := strconv.FormatInt(since, 10)
+	opts := pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+	err := pgx.BeginTxFunc(ctx, r.conn, opts, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx,
+			"SELECT GREATEST(pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT, $1)", since,
+		).Scan(&changes.Token); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx,
+			"SELECT "+todoColumns+" FROM todo INNER JOIN todo_change ON todo.id = todo_change.todo_id "+
+				"WHERE todo_change.change_xid >= $1::TEXT::XID8 ORDER BY todo_change.change_xid",
+			sinceXID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			t, err := scanTodo(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			changes.Todos = append(changes.Todos, *t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query(ctx,
+			`SELECT todo_change.todo_id::TEXT, todo_change.changed_at FROM todo_change
+			WHERE todo_change.change_xid >= $1::TEXT::XID8
+				AND NOT EXISTS (SELECT 1 FROM todo WHERE todo.id = todo_change.todo_id)
+			ORDER BY todo_change.change_xid`,
+			sinceXID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			tombstone := model.TodoTombstone{Purged: true}
+			if err := rows.Scan(&tombstone.ID, &tombstone.DeletedAt); err != nil {
+				return err
+			}
+			changes.Purged = append(changes.Purged, tombstone)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// foreignKeyViolation は外部キー制約違反のエラーコード
+const foreignKeyViolation = "23503"
+
+// dependencyLockKey は依存関係の変更を直列化するアドバイザリロックのキー
+const dependencyLockKey = 0x746f646f646570 // "tododep"
+
+// TodoDependency はPostgreSQLを使ったTodoの依存関係の実装
+type TodoDependency struct {
+	conn *pgxpool.Pool
+}
+
+// NewTodoDependency は repository.TodoDependency のコンストラクタ
+func NewTodoDependency(conn *pgxpool.Pool) repository.TodoDependency {
+	return &TodoDependency{
+		conn: conn,
+	}
+}
+
+// FindBlockerIDs は todoID のTodoをブロックしているTodoのIDを返す (ゴミ箱にあるTodoは除く)
+func (r *TodoDependency) FindBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	if checkUUIDs(todoID) != nil {
+		return []string{}, nil
+	}
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		`SELECT todo_dependency.blocker_id FROM todo_dependency
+		INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+		WHERE todo_dependency.todo_id = $1 AND blocker.deleted_at IS NULL
+		ORDER BY todo_dependency.created_at`,
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// FindAllBlockerIDs は todoID のTodoをブロックしている全てのTodoのIDを返す (ゴミ箱にあるTodoも含む)
+func (r *TodoDependency) FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	if checkUUIDs(todoID) != nil {
+		return []string{}, nil
+	}
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		"SELECT blocker_id FROM todo_dependency WHERE todo_id = $1 ORDER BY created_at",
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// Lock はトランザクションが終了するまでのアドバイザリロックを取得し、他の依存関係の変更を待たせる
+// 循環の検証は複数のTodoの依存関係を辿るため、行のロックでは A→B と B→A の同時の追加を防げない
+func (r *TodoDependency) Lock(ctx context.Context) error {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	if !ok {
+		return nil
+	}
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", dependencyLockKey)
+	return err
+}
+
+// Add は todoID のTodoが blockerID のTodoにブロックされる依存関係を追加する
+func (r *TodoDependency) Add(ctx context.Context, todoID string, blockerID string) error {
+	if err := checkUUIDs(todoID, blockerID); err != nil {
+		return err
+	}
+	_, err := connFrom(ctx, r.conn).Exec(ctx,
+		"INSERT INTO todo_dependency (todo_id, blocker_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+		todoID, blockerID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// Remove は todoID のTodoが blockerID のTodoにブロックされる依存関係を削除する
+func (r *TodoDependency) Remove(ctx context.Context, todoID string, blockerID string) error {
+	if err := checkUUIDs(todoID, blockerID); err != nil {
+		return err
+	}
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx,
+		"DELETE FROM todo_dependency WHERE todo_id = $1 AND blocker_id = $2",
+		todoID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
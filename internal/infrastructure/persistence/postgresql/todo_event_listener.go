@@ -0,0 +1,96 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// todoEventChannel はTodoの変更を通知するチャンネル (postgres/init/10_todo_event_notify.sql のトリガーが通知する)
+const todoEventChannel = "todo_events"
+
+// todoNotification はTodoの変更の通知のペイロード
+type todoNotification struct {
+	ID         string              `json:"id"`
+	Type       model.TodoEventType `json:"type"`
+	TodoID     string              `json:"todo_id"`
+	OccurredAt time.Time           `json:"occurred_at"`
+}
+
+// TodoEventListener はPostgreSQLの LISTEN/NOTIFY を使ったTodoの変更の監視の実装
+// 他のレプリカの変更も含め、全てのTodoの変更をコミットした順に受け取る
+type TodoEventListener struct {
+	conn *pgxpool.Pool
+}
+
+// NewTodoEventListener は repository.TodoEventListener のコンストラクタ
+func NewTodoEventListener(conn *pgxpool.Pool) repository.TodoEventListener {
+	return &TodoEventListener{
+		conn: conn,
+	}
+}
+
+// Listen は ctx がキャンセルされるまでTodoの変更の通知を受け取り、通知を受け取った時点のTodoをイベントとして stream に配信する
+// 通知には操作者とトレース ID が含まれないため、イベントのそれらの値は空にする
+func (l *TodoEventListener) Listen(ctx context.Context, stream repository.TodoEventStream) error {
+	pooled, err := l.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// LISTEN した接続をプールに戻さないように、プールから切り離して使い終わったら閉じる
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{todoEventChannel}.Sanitize()); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		event, err := l.event(ctx, n.Payload)
+		if err != nil {
+			return err
+		}
+		if event == nil {
+			continue
+		}
+		if err := stream.Publish(ctx, *event); err != nil {
+			return err
+		}
+	}
+}
+
+// event は通知のペイロードからイベントを作成する (通知を受け取る前に完全に削除されたTodoの場合は nil を返す)
+func (l *TodoEventListener) event(ctx context.Context, payload string) (*model.TodoEvent, error) {
+	var n todoNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return nil, fmt.Errorf("invalid notification %q: %w", payload, err)
+	}
+	// ゴミ箱への移動のイベントにも移動したTodoを含めるため、ゴミ箱にあるTodoも取得する
+	todos, err := findTodosByIDs(ctx, connFrom(ctx, l.conn), []string{n.TodoID})
+	if err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return nil, nil
+	}
+	return &model.TodoEvent{
+		ID:         n.ID,
+		Type:       n.Type,
+		TodoID:     n.TodoID,
+		Todo:       todos[0],
+		OccurredAt: n.OccurredAt,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/postgresql"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+)
+
+// nextEvent は events から todoID のイベントを受け取る (他のテストのTodoのイベントは読み飛ばす)
+func nextEvent(t *testing.T, events <-chan model.TodoEvent, todoID string, timeout time.Duration) (model.TodoEvent, bool) {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				t.Fatal("events closed")
+			}
+			if e.TodoID == todoID {
+				return e, true
+			}
+		case <-deadline:
+			return model.TodoEvent{}, false
+		}
+	}
+}
+
+func TestTodoEventListener_Listen(t *testing.T) {
+	pool := newPool(t)
+	todoRepo := postgresql.NewTodo(pool)
+	broker := stream.NewBroker(stream.DefaultRetention)
+	_, events, err := broker.Subscribe(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- postgresql.NewTodoEventListener(pool).Listen(ctx, broker) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Listen() error = %v", err)
+		}
+	})
+
+	// LISTEN が完了するまでに作成したTodoの通知は届かないため、通知が届くまで作成する
+	var todo *model.Todo
+	for range 50 {
+		todo, err = todoRepo.Create(t.Context(), model.Todo{Title: "Notify", Status: model.TodoStatusTodo, Tags: []string{"home"}})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if e, ok := nextEvent(t, events, todo.ID, 100*time.Millisecond); ok {
+			if e.Type != model.TodoEventCreated || e.Todo.Title != "Notify" || len(e.Todo.Tags) != 1 {
+				t.Errorf("created event = %+v, want todo.created with the created todo", e)
+			}
+			break
+		}
+		todo = nil
+	}
+	if todo == nil {
+		t.Fatal("no notification received")
+	}
+
+	if err := todoRepo.Delete(t.Context(), todo.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	e, ok := nextEvent(t, events, todo.ID, 5*time.Second)
+	if !ok {
+		t.Fatal("no notification received for delete")
+	}
+	if e.Type != model.TodoEventDeleted || e.Todo.DeletedAt == nil || e.ID == "" {
+		t.Errorf("deleted event = %+v, want todo.deleted with the trashed todo", e)
+	}
+}
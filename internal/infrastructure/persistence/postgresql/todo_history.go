@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// todoHistoryColumns はTodoの変更履歴を取得する際のカラム一覧 (scanTodoHistory と順番を合わせること)
+const todoHistoryColumns = "id, todo_id, version, action, actor, trace_id, changes, snapshot, created_at"
+
+// TodoHistory はPostgreSQLを使ったTodoの変更履歴の実装
+type TodoHistory struct {
+	conn *pgxpool.Pool
+}
+
+// NewTodoHistory は repository.TodoHistory のコンストラクタ
+func NewTodoHistory(conn *pgxpool.Pool) repository.TodoHistory {
+	return &TodoHistory{
+		conn: conn,
+	}
+}
+
+// scanTodoHistory は todoHistoryColumns の順で取得した行をTodoの変更履歴に変換する
+func scanTodoHistory(row pgx.Row) (*model.TodoHistory, error) {
+	var h model.TodoHistory
+	if err := row.Scan(
+		&h.ID, &h.TodoID, &h.Version, &h.Action, &h.Actor, &h.TraceID, &h.Changes, &h.Snapshot, &h.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+// FindByTodoID はTodoの変更履歴を古い順に取得する
+func (r *TodoHistory) FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error) {
+	if checkUUIDs(todoID) != nil {
+		return []model.TodoHistory{}, nil
+	}
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id = $1 ORDER BY version, created_at",
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histories := []model.TodoHistory{}
+	for rows.Next() {
+		h, err := scanTodoHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, *h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// FindByTodoIDs は todoIDs の各Todoの変更履歴をまとめて取得する (UUID でないIDは存在しないため問い合わせない)
+func (r *TodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id = ANY($1::UUID []) ORDER BY todo_id, version, created_at",
+		validUUIDs(todoIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histories := []model.TodoHistory{}
+	for rows.Next() {
+		h, err := scanTodoHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, *h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// FindByVersion はTodoの指定したバージョンの変更履歴を取得する
+func (r *TodoHistory) FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error) {
+	if err := checkUUIDs(todoID); err != nil {
+		return nil, err
+	}
+	return scanTodoHistory(connFrom(ctx, r.conn).QueryRow(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id = $1 AND version = $2",
+		todoID, version))
+}
+
+// recordTodoHistory は before から after への変更履歴を記録する (更新で変更がない場合は記録しない)
+// 操作者とトレースIDは ctx の model.AuditInfo から取得する
+func recordTodoHistory(ctx context.Context, q querier, action model.HistoryAction, before, after *model.Todo) error {
+	changes := model.DiffTodo(before, after)
+	if before != nil && len(changes) == 0 {
+		return nil
+	}
+
+	info := model.AuditInfoFromContext(ctx)
+	_, err := q.Exec(ctx,
+		`INSERT INTO todo_history (todo_id, version, action, actor, trace_id, changes, snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		after.ID, after.Version, action, info.Actor, info.TraceID, changes, after)
+	return err
+}
+
+// todoHistoryEntry は copyTodoHistories でまとめて記録する変更履歴の1件
+type todoHistoryEntry struct {
+	action        model.HistoryAction
+	before, after *model.Todo
+}
+
+// copyTodoHistories は entries の変更履歴を COPY でまとめて記録する (更新で変更がない場合は記録しない)
+func copyTodoHistories(ctx context.Context, tx pgx.Tx, entries []todoHistoryEntry) error {
+	info := model.AuditInfoFromContext(ctx)
+	var rows [][]any
+	for _, e := range entries {
+		changes := model.DiffTodo(e.before, e.after)
+		if e.before != nil && len(changes) == 0 {
+			continue
+		}
+		todoID, err := uuid.Parse(e.after.ID)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []any{todoID, e.after.Version, e.action, info.Actor, info.TraceID, changes, e.after})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	_, err := tx.CopyFrom(ctx, pgx.Identifier{"todo_history"},
+		[]string{"todo_id", "version", "action", "actor", "trace_id", "changes", "snapshot"},
+		pgx.CopyFromRows(rows))
+	return err
+}
+
+// recordTodoHistories は ids のTodoの変更履歴をまとめて記録する
+// 変更前のTodoは変更後のTodoに undo を適用して求める
+func recordTodoHistories(ctx context.Context, q querier, action model.HistoryAction, ids []string, undo func(before *model.Todo)) error {
+	todos, err := findTodosByIDs(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	for _, after := range todos {
+		before := after
+		undo(&before)
+		if err := recordTodoHistory(ctx, q, action, &before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
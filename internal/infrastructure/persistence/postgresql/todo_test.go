@@ -0,0 +1,86 @@
+package postgresql_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/postgresql"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+)
+
+// initDir はスキーマを作成するSQLのディレクトリ
+const initDir = "../../../../postgres/init"
+
+// newPool は TEST_POSTGRES_URL のデータベースにテストごとのスキーマを作成し、そのスキーマを使う接続を返す
+// TEST_POSTGRES_URL が設定されていない場合はテストをスキップする (make test-postgres で起動したPostgreSQLに対して実行する)
+func newPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+	ctx := context.Background()
+	schema := "test_" + uuid.New().String()[:8]
+
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, fmt.Sprintf("CREATE SCHEMA %s", schema)); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	files, err := filepath.Glob(filepath.Join(initDir, "*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no schema files in %s: %v", initDir, err)
+	}
+	slices.Sort(files)
+	for _, file := range files {
+		stmt, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if _, err := pool.Exec(ctx, string(stmt)); err != nil {
+			t.Fatalf("apply %s: %v", file, err)
+		}
+	}
+	return pool
+}
+
+func TestTodo(t *testing.T) {
+	repositorytest.TestTodo(t, func(t *testing.T) repositorytest.Backend {
+		pool := newPool(t)
+		return repositorytest.Backend{
+			Todo:            postgresql.NewTodo(pool),
+			TodoDependency:  postgresql.NewTodoDependency(pool),
+			TodoHistory:     postgresql.NewTodoHistory(pool),
+			Tag:             postgresql.NewTag(pool),
+			TxManager:       postgresql.NewTxManager(pool),
+			Outbox:          postgresql.NewOutbox(pool),
+			Webhook:         postgresql.NewWebhook(pool),
+			WebhookDelivery: postgresql.NewWebhookDelivery(pool),
+		}
+	})
+}
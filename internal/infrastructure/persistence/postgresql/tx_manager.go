@@ -0,0 +1,32 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// txKey は実行中のトランザクションを context に保存するキー
+type txKey struct{}
+
+// TxManager は repository.TxManager の実装
+type TxManager struct {
+	conn *pgxpool.Pool
+}
+
+// NewTxManager は repository.TxManager のコンストラクタ
+func NewTxManager(conn *pgxpool.Pool) repository.TxManager {
+	return &TxManager{
+		conn: conn,
+	}
+}
+
+// Do は fn を1つのトランザクションで実行する (トランザクションの中ではセーブポイントを使う)
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return pgx.BeginFunc(ctx, connFrom(ctx, m.conn), func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
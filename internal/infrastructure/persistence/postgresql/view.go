@@ -0,0 +1,110 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// viewColumns はビューを取得する際のカラム一覧 (scanView と順番を合わせること)
+const viewColumns = "id, name, query, created_at, updated_at"
+
+// View はPostgreSQLを使ったビューの実装
+type View struct {
+	conn *pgxpool.Pool
+}
+
+// NewView は repository.View のコンストラクタ
+func NewView(conn *pgxpool.Pool) repository.View {
+	return &View{
+		conn: conn,
+	}
+}
+
+// scanView は viewColumns の順で取得した行をビューに変換する
+func scanView(row pgx.Row) (*model.View, error) {
+	var v model.View
+	if err := row.Scan(&v.ID, &v.Name, &v.Query, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return nil, model.ErrConflict
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindAll は全てのビューを名前順で取得する
+func (r *View) FindAll(ctx context.Context) ([]model.View, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx, "SELECT "+viewColumns+" FROM todo_view ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := []model.View{}
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+// FindByID はIDによるビューの取得
+func (r *View) FindByID(ctx context.Context, id string) (*model.View, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return scanView(connFrom(ctx, r.conn).QueryRow(ctx, "SELECT "+viewColumns+" FROM todo_view WHERE id = $1", id))
+}
+
+// Create は新しいビューを作成する
+func (r *View) Create(ctx context.Context, view model.View) (*model.View, error) {
+	return scanView(connFrom(ctx, r.conn).QueryRow(ctx,
+		"INSERT INTO todo_view (name, query) VALUES ($1, $2) RETURNING "+viewColumns,
+		view.Name, view.Query))
+}
+
+// Update はビューの名前と検索クエリを更新する
+func (r *View) Update(ctx context.Context, id string, view model.View) (*model.View, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return scanView(connFrom(ctx, r.conn).QueryRow(ctx,
+		"UPDATE todo_view SET name = $2, query = $3, updated_at = NOW() WHERE id = $1 RETURNING "+viewColumns,
+		id, view.Name, view.Query))
+}
+
+// Delete はビューを削除する
+func (r *View) Delete(ctx context.Context, id string) error {
+	if err := checkUUIDs(id); err != nil {
+		return err
+	}
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM todo_view WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
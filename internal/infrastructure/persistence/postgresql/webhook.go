@@ -0,0 +1,127 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookColumns はWebhookを取得する際のカラム一覧 (scanWebhook と順番を合わせること)
+const webhookColumns = "id, url, events, secret, created_at, updated_at"
+
+// Webhook はPostgreSQLを使ったWebhookの購読の実装
+type Webhook struct {
+	conn *pgxpool.Pool
+}
+
+// NewWebhook は repository.Webhook のコンストラクタ
+func NewWebhook(conn *pgxpool.Pool) repository.Webhook {
+	return &Webhook{
+		conn: conn,
+	}
+}
+
+// scanWebhook は webhookColumns の順で取得した行をWebhookに変換する
+func scanWebhook(row pgx.Row) (*model.Webhook, error) {
+	var (
+		w      model.Webhook
+		events []string
+	)
+	if err := row.Scan(&w.ID, &w.URL, &events, &w.Secret, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	w.Events = webhookEvents(events)
+	return &w, nil
+}
+
+// webhookEvents は events カラムの値をイベントの種類の一覧に変換する
+func webhookEvents(events []string) []model.TodoEventType {
+	types := make([]model.TodoEventType, len(events))
+	for i, e := range events {
+		types[i] = model.TodoEventType(e)
+	}
+	return types
+}
+
+// webhookEventNames はイベントの種類の一覧を events カラムの値に変換する
+func webhookEventNames(types []model.TodoEventType) []string {
+	events := make([]string, len(types))
+	for i, e := range types {
+		events[i] = string(e)
+	}
+	return events
+}
+
+// FindAll は全てのWebhookを作成した順に取得する
+func (r *Webhook) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx, "SELECT "+webhookColumns+" FROM webhook ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []model.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// FindByID はIDによるWebhookの取得
+func (r *Webhook) FindByID(ctx context.Context, id string) (*model.Webhook, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return scanWebhook(connFrom(ctx, r.conn).QueryRow(ctx, "SELECT "+webhookColumns+" FROM webhook WHERE id = $1", id))
+}
+
+// Create は新しいWebhookを作成する
+func (r *Webhook) Create(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	return scanWebhook(connFrom(ctx, r.conn).QueryRow(ctx,
+		"INSERT INTO webhook (url, events, secret) VALUES ($1, $2, $3) RETURNING "+webhookColumns,
+		webhook.URL, webhookEventNames(webhook.Events), webhook.Secret))
+}
+
+// Update はWebhookの URL、イベントの種類と秘密鍵を更新する
+func (r *Webhook) Update(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error) {
+	if err := checkUUIDs(id); err != nil {
+		return nil, err
+	}
+	return scanWebhook(connFrom(ctx, r.conn).QueryRow(ctx,
+		"UPDATE webhook SET url = $2, events = $3, secret = $4, updated_at = NOW() WHERE id = $1 RETURNING "+webhookColumns,
+		id, webhook.URL, webhookEventNames(webhook.Events), webhook.Secret))
+}
+
+// Delete はWebhookを削除する (配信は外部キーの ON DELETE CASCADE で削除する)
+func (r *Webhook) Delete(ctx context.Context, id string) error {
+	if err := checkUUIDs(id); err != nil {
+		return err
+	}
+	cmdTag, err := connFrom(ctx, r.conn).Exec(ctx, "DELETE FROM webhook WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookDeliveryColumns はWebhookの配信を取得する際のカラム一覧 (scanWebhookDelivery と順番を合わせること)
+const webhookDeliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, updated_at"
+
+// WebhookDelivery はPostgreSQLを使ったWebhookの配信の実装
+type WebhookDelivery struct {
+	conn *pgxpool.Pool
+}
+
+// NewWebhookDelivery は repository.WebhookDelivery のコンストラクタ
+func NewWebhookDelivery(conn *pgxpool.Pool) repository.WebhookDelivery {
+	return &WebhookDelivery{
+		conn: conn,
+	}
+}
+
+// scanWebhookDelivery は webhookDeliveryColumns の順で取得した行をWebhookの配信に変換する
+func scanWebhookDelivery(row pgx.Row) (*model.WebhookDelivery, error) {
+	var (
+		d       model.WebhookDelivery
+		payload []byte
+	)
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case uniqueViolation:
+				return nil, model.ErrConflict
+			case foreignKeyViolation:
+				return nil, model.ErrNotFound
+			}
+		}
+		return nil, err
+	}
+	d.Payload = payload
+	return &d, nil
+}
+
+// queryWebhookDeliveries は query で取得したWebhookの配信の一覧を返す
+func (r *WebhookDelivery) queryWebhookDeliveries(ctx context.Context, query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := connFrom(ctx, r.conn).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Create は配信を作成する
+func (r *WebhookDelivery) Create(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return scanWebhookDelivery(connFrom(ctx, r.conn).QueryRow(ctx,
+		`INSERT INTO webhook_delivery (webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING `+webhookDeliveryColumns,
+		delivery.WebhookID, delivery.EventID, delivery.EventType, []byte(delivery.Payload), delivery.Status, delivery.NextAttemptAt))
+}
+
+// FindByWebhookID はWebhookの配信を新しい順に limit 件まで取得する
+func (r *WebhookDelivery) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	if checkUUIDs(webhookID) != nil {
+		return []model.WebhookDelivery{}, nil
+	}
+	return r.queryWebhookDeliveries(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_delivery WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2",
+		webhookID, limit)
+}
+
+// FindDue は now までに配信を試みる日時になった配信待ちの配信を、配信を試みる日時の順に limit 件まで取得する
+func (r *WebhookDelivery) FindDue(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_delivery WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at, created_at LIMIT $3",
+		model.WebhookDeliveryPending, now, limit)
+}
+
+// Update は配信を試みた結果を保存する
+func (r *WebhookDelivery) Update(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return scanWebhookDelivery(connFrom(ctx, r.conn).QueryRow(ctx,
+		`UPDATE webhook_delivery SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6, updated_at = NOW()
+		WHERE id = $1 RETURNING `+webhookDeliveryColumns,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError, delivery.NextAttemptAt))
+}
@@ -0,0 +1,240 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// Redisをデータストアとして使う場合のキー
+// Todoはハッシュに保存し、並び順と絞り込みにはソート済みセットとセットの索引を使う
+const (
+	// revisionKey はデータを変更するたびに更新するキー
+	// 全ての書き込みはこのキーを WATCH して楽観的に直列化する
+	revisionKey = "todos:revision"
+	// todoSeqKey はTodoを作成した順序の連番を払い出すキー
+	todoSeqKey = "todos:seq"
+	// allTodosKey はゴミ箱にあるものを含む全てのTodoのIDを作成した順序で保持するソート済みセット
+	allTodosKey = "todos:all"
+	// trashKey はゴミ箱にあるTodoのIDをゴミ箱に移動した日時 (マイクロ秒) で保持するソート済みセット
+	trashKey = "todos:trash"
+	// changesKey はTodoのIDを最後に変更した時のリビジョン (revisionKey の値) で保持するソート済みセット
+	// 差分同期で使い、完全に削除したTodoのIDも残す
+	changesKey = "todos:changes"
+	// purgedKey は完全に削除したTodoのIDをキーとした削除した日時のハッシュ
+	purgedKey = "todos:purged"
+	// updatedKey はTodoのIDを更新日時 (マイクロ秒) で保持するソート済みセット
+	updatedKey = "todos:updated"
+	// tagsKey はタグのIDをキーとしたタグ名のハッシュ
+	tagsKey = "tags"
+	// tagNamesKey はタグ名をキーとしたタグのIDのハッシュ
+	tagNamesKey = "tags:names"
+	// viewsKey はビューのIDをキーとしたビューの JSON のハッシュ
+	viewsKey = "views"
+	// viewNamesKey はビュー名をキーとしたビューのIDのハッシュ
+	viewNamesKey = "views:names"
+	// outboxSeqKey はアウトボックスに保存した順序の連番を払い出すキー
+	outboxSeqKey = "outbox:seq"
+	// outboxKey は配信待ちのイベントの順序を保持するソート済みセット
+	outboxKey = "outbox"
+	// outboxMessagesKey は順序をキーとした配信待ちのイベントの JSON のハッシュ
+	outboxMessagesKey = "outbox:messages"
+	// webhooksKey はWebhookのIDをキーとしたWebhookの JSON のハッシュ
+	webhooksKey = "webhooks"
+	// webhookDeliveriesKey は配信のIDをキーとしたWebhookの配信の JSON のハッシュ
+	webhookDeliveriesKey = "webhook_deliveries"
+	// webhookDeliveryEventsKey は "WebhookのID:イベントのID" をキーとした配信のIDのハッシュ (同じイベントの配信の重複を防ぐ)
+	webhookDeliveryEventsKey = "webhook_deliveries:events"
+	// dueWebhookDeliveriesKey は配信待ちの配信のIDを次に配信を試みる日時 (マイクロ秒) で保持するソート済みセット
+	dueWebhookDeliveriesKey = "webhook_deliveries:due"
+)
+
+// maxWriteRetries は書き込みが他の書き込みと競合した場合に再試行する回数の上限
+const maxWriteRetries = 20
+
+// todoKey はTodoを保存するハッシュのキー
+func todoKey(id string) string { return "todo:" + id }
+
+// todoTagsKey はTodoに付与されているタグのIDのセットのキー
+func todoTagsKey(id string) string { return "todo:" + id + ":tags" }
+
+// todoBlockersKey はTodoをブロックしているTodoのIDを追加した日時で保持するソート済みセットのキー
+func todoBlockersKey(id string) string { return "todo:" + id + ":blockers" }
+
+// todoBlockingKey はTodoがブロックしているTodoのIDのセットのキー (完全に削除する際に依存関係を削除するための逆引き)
+func todoBlockingKey(id string) string { return "todo:" + id + ":blocking" }
+
+// todoHistoryKey はTodoの変更履歴の JSON を古い順に保持するリストのキー
+func todoHistoryKey(id string) string { return "todo:" + id + ":history" }
+
+// webhookDeliveriesByWebhookKey はWebhookの配信のIDを作成した日時 (マイクロ秒) で保持するソート済みセットのキー
+func webhookDeliveriesByWebhookKey(webhookID string) string {
+	return "webhook:" + webhookID + ":deliveries"
+}
+
+// statusKey はステータスごとのTodoのIDのセットのキー (ゴミ箱にあるTodoを含む)
+func statusKey(status model.TodoStatus) string { return "todos:status:" + string(status) }
+
+// childrenKey は親ごとの子のTodoのIDを並び順で保持するソート済みセットのキー (ルートは空文字列、ゴミ箱にあるTodoを含む)
+func childrenKey(parentID *string) string {
+	if parentID == nil {
+		return "todos:children:"
+	}
+	return "todos:children:" + *parentID
+}
+
+// tagTodosKey はタグが付与されているTodoのIDのセットのキー
+func tagTodosKey(tagID string) string { return "tag:" + tagID + ":todos" }
+
+// write は fn を1つのトランザクションで実行する
+// fn は tx で読み取り、pipe に書き込みを追加する。他の書き込みと競合した場合は fn から再試行する
+func write(ctx context.Context, client *goredis.Client, fn func(tx *goredis.Tx, pipe goredis.Pipeliner) error) error {
+	for range maxWriteRetries {
+		err := client.Watch(ctx, func(tx *goredis.Tx) error {
+			pipe := tx.TxPipeline()
+			if err := fn(tx, pipe); err != nil {
+				pipe.Discard()
+				return err
+			}
+			pipe.Incr(ctx, revisionKey)
+			_, err := pipe.Exec(ctx)
+			return err
+		}, revisionKey)
+		if !errors.Is(err, goredis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: too many concurrent writes", model.ErrConflict)
+}
+
+// micros は日時をソート済みセットのスコアに使うマイクロ秒に変換する
+// (float64 で誤差なく表せる精度に合わせ、PostgreSQL の TIMESTAMP の精度とも一致する)
+func micros(t time.Time) float64 {
+	return float64(t.UnixMicro())
+}
+
+// now は保存する精度に丸めた現在日時を返す
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// formatTime は日時をハッシュのフィールドの値に変換する (nil の場合は空文字列)
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseTime はハッシュのフィールドの値を日時に変換する (空文字列の場合は nil)
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// formatID は nil になりうるIDをハッシュのフィールドの値に変換する (nil の場合は空文字列)
+func formatID(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
+// parseID はハッシュのフィールドの値を nil になりうるIDに変換する (空文字列の場合は nil)
+func parseID(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// todoFields はTodoをハッシュのフィールドに変換する (タグと依存関係は別のキーに保存する)
+func todoFields(t *model.Todo) map[string]any {
+	return map[string]any{
+		"id":            t.ID,
+		"title":         t.Title,
+		"content":       t.Content,
+		"status":        string(t.Status),
+		"priority":      t.Priority,
+		"parent_id":     formatID(t.ParentID),
+		"position":      t.Position,
+		"auto_complete": strconv.FormatBool(t.AutoComplete),
+		"due_at":        formatTime(t.DueAt),
+		"recurrence":    t.Recurrence,
+		"timezone":      t.Timezone,
+		"series_id":     formatID(t.SeriesID),
+		"occurrence_at": formatTime(t.OccurrenceAt),
+		"completed_at":  formatTime(t.CompletedAt),
+		"archived_at":   formatTime(t.ArchivedAt),
+		"deleted_at":    formatTime(t.DeletedAt),
+		"version":       t.Version,
+		"updated_at":    formatTime(&t.UpdatedAt),
+	}
+}
+
+// parseTodo はハッシュのフィールドをTodoに変換する (存在しない場合は model.ErrNotFound を返す)
+func parseTodo(fields map[string]string) (*model.Todo, error) {
+	if len(fields) == 0 {
+		return nil, model.ErrNotFound
+	}
+
+	t := model.Todo{
+		ID:         fields["id"],
+		Title:      fields["title"],
+		Content:    fields["content"],
+		Status:     model.TodoStatus(fields["status"]),
+		ParentID:   parseID(fields["parent_id"]),
+		Recurrence: fields["recurrence"],
+		Timezone:   fields["timezone"],
+		SeriesID:   parseID(fields["series_id"]),
+	}
+	var err error
+	if t.Priority, err = strconv.Atoi(fields["priority"]); err != nil {
+		return nil, fmt.Errorf("todo %s: priority: %w", t.ID, err)
+	}
+	if t.Position, err = strconv.Atoi(fields["position"]); err != nil {
+		return nil, fmt.Errorf("todo %s: position: %w", t.ID, err)
+	}
+	if t.Version, err = strconv.Atoi(fields["version"]); err != nil {
+		return nil, fmt.Errorf("todo %s: version: %w", t.ID, err)
+	}
+	if t.AutoComplete, err = strconv.ParseBool(fields["auto_complete"]); err != nil {
+		return nil, fmt.Errorf("todo %s: auto_complete: %w", t.ID, err)
+	}
+	for _, f := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"due_at", &t.DueAt},
+		{"occurrence_at", &t.OccurrenceAt},
+		{"completed_at", &t.CompletedAt},
+		{"archived_at", &t.ArchivedAt},
+		{"deleted_at", &t.DeletedAt},
+	} {
+		if *f.dst, err = parseTime(fields[f.name]); err != nil {
+			return nil, fmt.Errorf("todo %s: %s: %w", t.ID, f.name, err)
+		}
+	}
+	updatedAt, err := parseTime(fields["updated_at"])
+	if err != nil {
+		return nil, fmt.Errorf("todo %s: updated_at: %w", t.ID, err)
+	}
+	if updatedAt == nil {
+		return nil, fmt.Errorf("todo %s: updated_at is empty", t.ID)
+	}
+	t.UpdatedAt = *updatedAt
+	t.Done = t.Status == model.TodoStatusDone
+	return &t, nil
+}
@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// idempotencyKeyPrefix は冪等キーを保存するRedisのキーの接頭辞
+const idempotencyKeyPrefix = "idempotency:"
+
+// IdempotencyKey はRedisを使った冪等キーの実装
+// 有効期限はRedisのキーのTTLで管理するため、期限切れの冪等キーは自動で削除される
+type IdempotencyKey struct {
+	client *goredis.Client
+}
+
+// NewIdempotencyKey は repository.IdempotencyKey のコンストラクタ
+func NewIdempotencyKey(client *goredis.Client) repository.IdempotencyKey {
+	return &IdempotencyKey{
+		client: client,
+	}
+}
+
+// Reserve は冪等キーが存在しない場合に処理中として ttl の間保存し、true を返す
+func (r *IdempotencyKey) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	rec := model.IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: time.Now().Add(ttl)}
+	value, err := json.Marshal(rec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 既存の冪等キーを取得する前に期限切れになった場合は、もう一度保存を試みる
+	for {
+		ok, err := r.client.SetNX(ctx, idempotencyKeyPrefix+key, value, ttl).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		existing, err := r.get(ctx, key)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+}
+
+// Complete は処理中の冪等キーにレスポンスを保存する (TTLは維持する)
+func (r *IdempotencyKey) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	rec, err := r.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if rec.Completed {
+		return model.ErrNotFound
+	}
+	rec.Completed = true
+	rec.StatusCode = statusCode
+	rec.ContentType = contentType
+	rec.Body = body
+	value, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	err = r.client.SetArgs(ctx, idempotencyKeyPrefix+key, value, goredis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if errors.Is(err, goredis.Nil) {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// Release は処理中の冪等キーを削除する
+func (r *IdempotencyKey) Release(ctx context.Context, key string) error {
+	rec, err := r.get(ctx, key)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if rec.Completed {
+		return nil
+	}
+	return r.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
+
+// DeleteExpired は何もしない (期限切れの冪等キーはRedisが削除する)
+func (r *IdempotencyKey) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+// get は冪等キーの内容を取得する
+func (r *IdempotencyKey) get(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	value, err := r.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec model.IdempotencyRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
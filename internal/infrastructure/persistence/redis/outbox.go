@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Outbox はRedisをデータストアとして使うアウトボックスの実装
+// 配信待ちのイベントは順序をスコアとしたソート済みセットと、順序をキーとした JSON のハッシュに保存する
+// Redisでは複数の操作にまたがるトランザクションに対応しないため (TxManager を参照)、
+// イベントはTodoの書き込みとは別のトランザクションで保存する
+type Outbox struct {
+	client *goredis.Client
+}
+
+// NewOutbox は repository.Outbox のコンストラクタ
+func NewOutbox(client *goredis.Client) repository.Outbox {
+	return &Outbox{
+		client: client,
+	}
+}
+
+// Add はイベントを配信待ちとして保存する
+func (r *Outbox) Add(ctx context.Context, events []model.TodoEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	last, err := r.client.IncrBy(ctx, outboxSeqKey, int64(len(events))).Result()
+	if err != nil {
+		return err
+	}
+	now := now()
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, e := range events {
+			e.ID = uuid.New().String()
+			m := model.OutboxMessage{Seq: last - int64(len(events)) + int64(i) + 1, Event: e, AvailableAt: now}
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			pipe.HSet(ctx, outboxMessagesKey, strconv.FormatInt(m.Seq, 10), b)
+			pipe.ZAdd(ctx, outboxKey, goredis.Z{Score: float64(m.Seq), Member: m.Seq})
+		}
+		return nil
+	})
+	return err
+}
+
+// FindPending は配信を試みる日時になった配信待ちのイベントを保存した順に返す
+func (r *Outbox) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	seqs, err := r.client.ZRange(ctx, outboxKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(seqs) == 0 {
+		return []model.OutboxMessage{}, nil
+	}
+	values, err := r.client.HMGet(ctx, outboxMessagesKey, seqs...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []model.OutboxMessage{}
+	waiting := make(map[string]bool)
+	for _, v := range values {
+		if len(messages) == limit {
+			break
+		}
+		s, ok := v.(string)
+		if !ok {
+			// 順序を読み取った後に削除された
+			continue
+		}
+		var m model.OutboxMessage
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			return nil, err
+		}
+		if m.AvailableAt.After(now) {
+			waiting[m.Event.TodoID] = true
+			continue
+		}
+		if !waiting[m.Event.TodoID] {
+			messages = append(messages, m)
+		}
+	}
+	return messages, nil
+}
+
+// Delete は配信したイベントを削除する
+func (r *Outbox) Delete(ctx context.Context, seq int64) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, outboxKey, seq)
+		pipe.HDel(ctx, outboxMessagesKey, strconv.FormatInt(seq, 10))
+		return nil
+	})
+	return err
+}
+
+// MarkFailed は配信に失敗したイベントの失敗回数を増やし、次に配信を試みる日時を設定する
+func (r *Outbox) MarkFailed(ctx context.Context, seq int64, lastError string, nextAttemptAt time.Time) error {
+	field := strconv.FormatInt(seq, 10)
+	for range maxWriteRetries {
+		err := r.client.Watch(ctx, func(tx *goredis.Tx) error {
+			v, err := tx.HGet(ctx, outboxMessagesKey, field).Result()
+			if errors.Is(err, goredis.Nil) {
+				return model.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+			var m model.OutboxMessage
+			if err := json.Unmarshal([]byte(v), &m); err != nil {
+				return err
+			}
+			m.Attempts++
+			m.LastError = lastError
+			m.AvailableAt = nextAttemptAt
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				pipe.HSet(ctx, outboxMessagesKey, field, b)
+				return nil
+			})
+			return err
+		}, outboxMessagesKey)
+		if !errors.Is(err, goredis.TxFailedErr) {
+			return err
+		}
+	}
+	return model.ErrConflict
+}
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Tag はRedisをデータストアとして使うタグの実装
+type Tag struct {
+	client *goredis.Client
+}
+
+// NewTag は repository.Tag のコンストラクタ
+func NewTag(client *goredis.Client) repository.Tag {
+	return &Tag{
+		client: client,
+	}
+}
+
+// FindAll は全てのタグを名前順で取得する
+func (r *Tag) FindAll(ctx context.Context) ([]model.Tag, error) {
+	names, err := r.client.HGetAll(ctx, tagsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]model.Tag, 0, len(names))
+	for id, name := range names {
+		tags = append(tags, model.Tag{ID: id, Name: name})
+	}
+	slices.SortFunc(tags, func(a, b model.Tag) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return tags, nil
+}
+
+// FindByID はIDによるタグの取得
+func (r *Tag) FindByID(ctx context.Context, id string) (*model.Tag, error) {
+	return findTag(ctx, r.client, id)
+}
+
+// Rename はタグ名を変更する
+func (r *Tag) Rename(ctx context.Context, id string, name string) (*model.Tag, error) {
+	var renamed *model.Tag
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		tag, err := findTag(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		existingID, err := tx.HGet(ctx, tagNamesKey, name).Result()
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return err
+		}
+		if err == nil && existingID != id {
+			return model.ErrConflict
+		}
+
+		pipe.HDel(ctx, tagNamesKey, tag.Name)
+		pipe.HSet(ctx, tagNamesKey, name, id)
+		pipe.HSet(ctx, tagsKey, id, name)
+		renamed = &model.Tag{ID: id, Name: name}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renamed, nil
+}
+
+// Merge は source が付与されたTodoを target に付け替え、source を削除する
+func (r *Tag) Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	var target *model.Tag
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		source, err := findTag(ctx, tx, sourceID)
+		if err != nil {
+			return err
+		}
+		if target, err = findTag(ctx, tx, targetID); err != nil {
+			return err
+		}
+		todoIDs, err := tx.SMembers(ctx, tagTodosKey(sourceID)).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, todoID := range todoIDs {
+			pipe.SRem(ctx, todoTagsKey(todoID), sourceID)
+			pipe.SAdd(ctx, todoTagsKey(todoID), targetID)
+			pipe.SAdd(ctx, tagTodosKey(targetID), todoID)
+		}
+		deleteTag(ctx, pipe, source)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Delete はタグを削除し、付与されていたTodoからも外す
+func (r *Tag) Delete(ctx context.Context, id string) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		tag, err := findTag(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		todoIDs, err := tx.SMembers(ctx, tagTodosKey(id)).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, todoID := range todoIDs {
+			pipe.SRem(ctx, todoTagsKey(todoID), id)
+		}
+		deleteTag(ctx, pipe, tag)
+		return nil
+	})
+}
+
+// findTag はIDによるタグの取得
+func findTag(ctx context.Context, c goredis.Cmdable, id string) (*model.Tag, error) {
+	name, err := c.HGet(ctx, tagsKey, id).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.Tag{ID: id, Name: name}, nil
+}
+
+// deleteTag はタグと、タグが付与されたTodoの索引を削除する書き込みを追加する
+func deleteTag(ctx context.Context, pipe goredis.Pipeliner, tag *model.Tag) {
+	pipe.Del(ctx, tagTodosKey(tag.ID))
+	pipe.HDel(ctx, tagsKey, tag.ID)
+	pipe.HDel(ctx, tagNamesKey, tag.Name)
+}
@@ -0,0 +1,567 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Todo はRedisをデータストアとして使うTodoの実装
+// 書き込みは楽観的ロックのトランザクション (WATCH / MULTI) で行い、競合した場合は再試行する
+type Todo struct {
+	client *goredis.Client
+}
+
+// NewTodo は repository.Todo のコンストラクタ
+func NewTodo(client *goredis.Client) repository.Todo {
+	return &Todo{
+		client: client,
+	}
+}
+
+// FindAll は全てのTodoを作成した順に取得する (検索キーワードを指定した場合は関連度の高い順)
+// ステータスによる絞り込みは索引を使い、それ以外の条件は読み取ったTodoに対して判定する
+func (r *Todo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	ids, err := r.client.ZRange(ctx, allTodosKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if query.Status != "" {
+		members, err := r.client.SMembersMap(ctx, statusKey(query.Status)).Result()
+		if err != nil {
+			return nil, err
+		}
+		ids = slices.DeleteFunc(ids, func(id string) bool {
+			_, ok := members[id]
+			return !ok
+		})
+	}
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := model.SearchTerms(query.Q)
+	todos := make([]model.Todo, 0, len(records))
+	for _, rec := range orderedRecords(ids, records) {
+		t := rec.todo
+		if t.DeletedAt != nil || !query.Archived.Match(t.IsArchived()) {
+			continue
+		}
+		if query.Status != "" && t.Status != query.Status {
+			continue
+		}
+		if query.SeriesID != "" && (t.SeriesID == nil || *t.SeriesID != query.SeriesID) {
+			continue
+		}
+		if query.MinPriority != nil && t.Priority < *query.MinPriority {
+			continue
+		}
+		if query.DueRange != nil && !query.DueRange.Match(&t) {
+			continue
+		}
+		if query.Actionable && (t.Status.IsClosed() || rec.hasOpenBlocker) {
+			continue
+		}
+		if !query.MatchTags(t.Tags) {
+			continue
+		}
+		if len(terms) > 0 {
+			rank, ok := model.SearchRank(&t, terms)
+			if !ok {
+				continue
+			}
+			t.Search = model.NewTodoSearchMatch(&t, terms, rank)
+		}
+		todos = append(todos, t)
+	}
+	if len(terms) > 0 {
+		slices.SortStableFunc(todos, func(a, b model.Todo) int {
+			return cmp.Compare(b.Search.Rank, a.Search.Rank)
+		})
+	}
+	return todos, nil
+}
+
+// Stamp は query に一致するTodoの件数と、全てのTodoの最終更新日時を返す
+func (r *Todo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	todos, err := r.FindAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	last, err := r.client.ZRevRangeWithScores(ctx, updatedKey, 0, 0).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := model.TodoListStamp{Count: len(todos), DueRange: query.DueRange}
+	if len(last) > 0 {
+		stamp.LastModified = time.UnixMicro(int64(last[0].Score))
+	}
+	return &stamp, nil
+}
+
+// FindByID はIDによるTodoの取得
+func (r *Todo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
+	records, err := loadTodos(ctx, r.client, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := records[id]
+	if !ok || rec.todo.DeletedAt != nil {
+		return nil, model.ErrNotFound
+	}
+	return &rec.todo, nil
+}
+
+// FindByIDs は ids のTodoをまとめて取得する
+func (r *Todo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := []model.Todo{}
+	for _, rec := range orderedRecords(ids, records) {
+		if rec.todo.DeletedAt == nil {
+			todos = append(todos, rec.todo)
+		}
+	}
+	return todos, nil
+}
+
+// Create は新しいTodoを作成する
+func (r *Todo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
+	var created model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		var err error
+		created, err = t.create(todo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Update はTodoを更新する
+func (r *Todo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+	var updated *model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		var err error
+		updated, err = t.update(id, todo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete はTodoを子孫のTodoも含めてゴミ箱に移動する
+func (r *Todo) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *todoTx) error {
+		return t.delete(id)
+	})
+}
+
+// FindChildren は子のTodoを並び順で取得する
+func (r *Todo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	ids, err := r.client.ZRange(ctx, childrenKey(&parentID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []model.Todo{}
+	for _, rec := range orderedRecords(ids, records) {
+		if rec.todo.DeletedAt == nil && sameParent(rec.todo.ParentID, &parentID) {
+			children = append(children, rec.todo)
+		}
+	}
+	return children, nil
+}
+
+// FindChildrenByParentIDs は parentIDs の各親の子のTodoをまとめて取得する
+func (r *Todo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	children := []model.Todo{}
+	if len(parentIDs) == 0 {
+		return children, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.StringSliceCmd, len(parentIDs))
+	for i, parentID := range parentIDs {
+		cmds[i] = pipe.ZRange(ctx, childrenKey(&parentID), 0, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, cmd := range cmds {
+		ids = append(ids, cmd.Val()...)
+	}
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range orderedRecords(ids, records) {
+		if rec.todo.DeletedAt == nil && rec.todo.ParentID != nil && slices.Contains(parentIDs, *rec.todo.ParentID) {
+			children = append(children, rec.todo)
+		}
+	}
+	return children, nil
+}
+
+// ReorderChildren は子のTodoを ids の順に並び替える
+func (r *Todo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	return r.write(ctx, func(t *todoTx) error {
+		for position, id := range ids {
+			rec, err := t.findActive(id)
+			if err != nil {
+				return err
+			}
+			if !sameParent(rec.todo.ParentID, &parentID) {
+				return model.ErrNotFound
+			}
+			rec.todo.Position = position
+			rec.todo.Version++
+			rec.todo.UpdatedAt = t.now
+			t.markDirty(id)
+		}
+		return nil
+	})
+}
+
+// FindTrash はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+func (r *Todo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	ids, err := r.client.ZRevRange(ctx, trashKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := []model.Todo{}
+	for _, rec := range orderedRecords(ids, records) {
+		if rec.todo.DeletedAt != nil {
+			todos = append(todos, rec.todo)
+		}
+	}
+	slices.SortStableFunc(todos, func(a, b model.Todo) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(a.Position, b.Position))
+	})
+	return todos, nil
+}
+
+// Restore はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+func (r *Todo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	var restored model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		rec, err := t.find(id)
+		if err != nil {
+			return err
+		}
+		if rec.todo.DeletedAt == nil {
+			return model.ErrNotFound
+		}
+		if parentID := rec.todo.ParentID; parentID != nil {
+			if _, err := t.findActive(*parentID); err != nil {
+				return fmt.Errorf("%w: parent todo is in the trash, restore it first", model.ErrConflict)
+			}
+		}
+
+		deletedAt := *rec.todo.DeletedAt
+		ids, err := t.subtree(id, func(todo *model.Todo) bool {
+			return todo.DeletedAt != nil && todo.DeletedAt.Equal(deletedAt)
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, err := t.modify(t.records[id], model.HistoryActionRestore, func(r *todoRecord) error {
+				r.todo.DeletedAt = nil
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		restored = rec.todo
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// Purge はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する
+func (r *Todo) Purge(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *todoTx) error {
+		rec, err := t.find(id)
+		if err != nil {
+			return err
+		}
+		if rec.todo.DeletedAt == nil {
+			return model.ErrNotFound
+		}
+		ids, err := t.subtree(id, func(*model.Todo) bool { return true })
+		if err != nil {
+			return err
+		}
+		return t.purge(ids)
+	})
+}
+
+// PurgeDeletedBefore は before より前にゴミ箱に移動したTodoを完全に削除し、削除した件数を返す
+func (r *Todo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := r.write(ctx, func(t *todoTx) error {
+		ids, err := t.tx.ZRangeByScore(ctx, trashKey, &goredis.ZRangeBy{
+			Min: "-inf",
+			Max: fmt.Sprintf("(%d", before.UnixMicro()),
+		}).Result()
+		if err != nil {
+			return err
+		}
+
+		var purged []string
+		for _, id := range ids {
+			subtree, err := t.subtree(id, func(*model.Todo) bool { return true })
+			if err != nil {
+				return err
+			}
+			for _, id := range subtree {
+				if !slices.Contains(purged, id) {
+					purged = append(purged, id)
+				}
+			}
+		}
+		n = len(purged)
+		return t.purge(purged)
+	})
+	return n, err
+}
+
+// Archive はTodoをアーカイブする (アーカイブ済みの場合はアーカイブした日時を維持する)
+func (r *Todo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	var archived model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		rec, err := t.findActive(id)
+		if err != nil {
+			return err
+		}
+		archived, err = t.modify(rec, model.HistoryActionArchive, func(r *todoRecord) error {
+			if r.todo.ArchivedAt == nil {
+				now := t.now
+				r.todo.ArchivedAt = &now
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &archived, nil
+}
+
+// Unarchive はTodoのアーカイブを解除する
+func (r *Todo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	var unarchived model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		rec, err := t.findActive(id)
+		if err != nil {
+			return err
+		}
+		unarchived, err = t.modify(rec, model.HistoryActionUnarchive, func(r *todoRecord) error {
+			r.todo.ArchivedAt = nil
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &unarchived, nil
+}
+
+// ArchiveCompletedBefore は before より前に done になったアーカイブされていないTodoをアーカイブし、アーカイブした件数を返す
+// done のTodoはステータスの索引から読み取る
+func (r *Todo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := r.write(ctx, func(t *todoTx) error {
+		n = 0
+		ids, err := t.tx.SMembers(ctx, statusKey(model.TodoStatusDone)).Result()
+		if err != nil {
+			return err
+		}
+		if err := t.load(ids); err != nil {
+			return err
+		}
+		archivedAt := t.now
+		for _, id := range ids {
+			rec, ok := t.records[id]
+			if !ok {
+				continue
+			}
+			todo := rec.todo
+			if todo.DeletedAt != nil || todo.ArchivedAt != nil || todo.Status != model.TodoStatusDone ||
+				todo.CompletedAt == nil || !todo.CompletedAt.Before(before) {
+				continue
+			}
+			if _, err := t.modify(rec, model.HistoryActionArchive, func(r *todoRecord) error {
+				r.todo.ArchivedAt = &archivedAt
+				return nil
+			}); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Touch は befores のTodoのバージョンと更新日時を更新し、befores からの変更を変更履歴に記録する
+func (r *Todo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		todos = make([]model.Todo, 0, len(befores))
+		for _, before := range befores {
+			rec, err := t.findActive(before.ID)
+			if errors.Is(err, model.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := t.touch(rec, &before); err != nil {
+				return err
+			}
+			todos = append(todos, rec.todo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// ApplyBatch は作成する書き込みを先に適用し、続けて更新とゴミ箱への移動を順に適用する
+// 全ての書き込みを1つのトランザクションで行うため、1つでも失敗した場合は何も書き込まない
+func (r *Todo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	var todos []*model.Todo
+	err := r.write(ctx, func(t *todoTx) error {
+		todos = make([]*model.Todo, len(writes))
+		for i, w := range writes {
+			if w.Kind == model.TodoWriteCreate {
+				created, err := t.create(w.Todo)
+				if err != nil {
+					return err
+				}
+				todos[i] = &created
+			}
+		}
+		for i, w := range writes {
+			var err error
+			switch w.Kind {
+			case model.TodoWriteCreate:
+			case model.TodoWriteUpdate:
+				todos[i], err = t.update(w.ID, w.Todo)
+			case model.TodoWriteDelete:
+				err = t.delete(w.ID)
+			default:
+				err = fmt.Errorf("unknown write kind %q", w.Kind)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// FindChanges は同期トークン since より後に変更したTodoを変更した順に返す
+// 同期トークンはリビジョンで、読み取りの途中で書き込まれた変更は次回の同期でも返す
+func (r *Todo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	revision, err := r.client.Get(ctx, revisionKey).Int64()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+	ids, err := r.client.ZRangeByScore(ctx, changesKey, &goredis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	records, err := loadTodos(ctx, r.client, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &model.TodoChanges{
+		Todos:  []model.Todo{},
+		Purged: []model.TodoTombstone{},
+		Token:  max(since, revision),
+	}
+	var purged []string
+	for _, id := range ids {
+		if rec, ok := records[id]; ok {
+			changes.Todos = append(changes.Todos, rec.todo)
+		} else {
+			purged = append(purged, id)
+		}
+	}
+	if len(purged) == 0 {
+		return changes, nil
+	}
+	deletedAt, err := r.client.HMGet(ctx, purgedKey, purged...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range purged {
+		s, _ := deletedAt[i].(string)
+		t, err := parseTime(s)
+		if err != nil {
+			return nil, err
+		}
+		tombstone := model.TodoTombstone{ID: id, Purged: true}
+		if t != nil {
+			tombstone.DeletedAt = *t
+		}
+		changes.Purged = append(changes.Purged, tombstone)
+	}
+	return changes, nil
+}
+
+// write は fn を1つのトランザクションで実行し、変更したTodoを書き込む
+func (r *Todo) write(ctx context.Context, fn func(t *todoTx) error) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		t := newTodoTx(ctx, tx, pipe)
+		if err := fn(t); err != nil {
+			return err
+		}
+		return t.flush()
+	})
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/cache"
+)
+
+const (
+	// todoCachePrefix はTodoのキャッシュを保存するRedisのキーの接頭辞
+	todoCachePrefix = "todo-cache:"
+	// todoCacheGenerationKey はTodoのキャッシュの世代を保存するRedisのキー
+	todoCacheGenerationKey = todoCachePrefix + "generation"
+)
+
+// TodoCache はRedisを使ったTodoのキャッシュの保存先の実装
+// 世代は複数のプロセスで共有するため、どのプロセスで更新しても全てのプロセスのキャッシュが無効になる
+type TodoCache struct {
+	client *goredis.Client
+}
+
+// NewTodoCache は cache.Store のコンストラクタ
+func NewTodoCache(client *goredis.Client) cache.Store {
+	return &TodoCache{
+		client: client,
+	}
+}
+
+// Get はキーに対応する値を返す
+func (s *TodoCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, todoCachePrefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set は値を ttl の間保存する
+func (s *TodoCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, todoCachePrefix+key, value, ttl).Err()
+}
+
+// Generation は現在の世代を返す
+// 世代が存在しない場合 (Redisが削除した場合を含む) は新しい世代を保存する
+// 世代は連番ではなくランダムな値のため、以前の世代の値が再び使われることはない
+func (s *TodoCache) Generation(ctx context.Context) (string, error) {
+	for {
+		generation, err := s.client.Get(ctx, todoCacheGenerationKey).Result()
+		if err == nil {
+			return generation, nil
+		}
+		if !errors.Is(err, goredis.Nil) {
+			return "", err
+		}
+		if err := s.client.SetNX(ctx, todoCacheGenerationKey, uuid.NewString(), 0).Err(); err != nil {
+			return "", err
+		}
+	}
+}
+
+// NewGeneration は世代を更新する (以前の世代の値はTTLが経過するとRedisが削除する)
+func (s *TodoCache) NewGeneration(ctx context.Context) error {
+	return s.client.Set(ctx, todoCacheGenerationKey, uuid.NewString(), 0).Err()
+}
@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoDependency はRedisをデータストアとして使うTodoの依存関係の実装
+type TodoDependency struct {
+	client *goredis.Client
+}
+
+// NewTodoDependency は repository.TodoDependency のコンストラクタ
+func NewTodoDependency(client *goredis.Client) repository.TodoDependency {
+	return &TodoDependency{
+		client: client,
+	}
+}
+
+// FindBlockerIDs は todoID のTodoをブロックしているTodoのIDを追加した順に返す (ゴミ箱にあるTodoは除く)
+func (r *TodoDependency) FindBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	records, err := loadTodos(ctx, r.client, []string{todoID})
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := records[todoID]
+	if !ok {
+		return []string{}, nil
+	}
+	return rec.todo.BlockedBy, nil
+}
+
+// FindAllBlockerIDs は todoID のTodoをブロックしている全てのTodoのIDを追加した順に返す (ゴミ箱にあるTodoも含む)
+func (r *TodoDependency) FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	return r.client.ZRange(ctx, todoBlockersKey(todoID), 0, -1).Result()
+}
+
+// Lock は何もしない (複数の操作にまたがるトランザクションに対応しないため、循環は Add の中で改めて検証する)
+func (r *TodoDependency) Lock(_ context.Context) error {
+	return nil
+}
+
+// Add は todoID のTodoが blockerID のTodoにブロックされる依存関係を追加する
+// 同時に追加された依存関係で循環しないように、書き込みと同じトランザクションで循環を検証する
+func (r *TodoDependency) Add(ctx context.Context, todoID string, blockerID string) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		for _, id := range []string{todoID, blockerID} {
+			if err := checkActive(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		if err := checkNoCycle(ctx, tx, todoID, blockerID); err != nil {
+			return err
+		}
+
+		pipe.ZAddNX(ctx, todoBlockersKey(todoID), goredis.Z{Score: micros(now()), Member: blockerID})
+		pipe.SAdd(ctx, todoBlockingKey(blockerID), todoID)
+		return nil
+	})
+}
+
+// Remove は todoID のTodoが blockerID のTodoにブロックされる依存関係を削除する
+func (r *TodoDependency) Remove(ctx context.Context, todoID string, blockerID string) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		err := tx.ZScore(ctx, todoBlockersKey(todoID), blockerID).Err()
+		if errors.Is(err, goredis.Nil) {
+			return model.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		pipe.ZRem(ctx, todoBlockersKey(todoID), blockerID)
+		pipe.SRem(ctx, todoBlockingKey(blockerID), todoID)
+		return nil
+	})
+}
+
+// checkNoCycle は blockerID から blocked-by を辿って todoID に到達しないことを確認する
+// (ゴミ箱にあるTodoも元に戻すと依存関係が有効になるため、usecase の検証と同じく全ての依存関係を辿る)
+func checkNoCycle(ctx context.Context, c goredis.Cmdable, todoID, blockerID string) error {
+	visited := map[string]bool{}
+	queue := []string{blockerID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == todoID {
+			return fmt.Errorf("%w: dependency on %s would create a cycle", model.ErrInvalidArgument, blockerID)
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		next, err := c.ZRange(ctx, todoBlockersKey(id), 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		queue = append(queue, next...)
+	}
+	return nil
+}
+
+// checkActive はTodoが存在し、ゴミ箱にないことを確認する
+func checkActive(ctx context.Context, c goredis.Cmdable, id string) error {
+	v, err := c.HMGet(ctx, todoKey(id), "id", "deleted_at").Result()
+	if err != nil {
+		return err
+	}
+	if v[0] == nil || v[1] != "" {
+		return model.ErrNotFound
+	}
+	return nil
+}
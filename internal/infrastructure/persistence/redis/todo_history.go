@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoHistory はRedisをデータストアとして使うTodoの変更履歴の実装
+// 変更履歴はTodoごとのリストに追記し、Todoを完全に削除しても残す
+type TodoHistory struct {
+	client *goredis.Client
+}
+
+// NewTodoHistory は repository.TodoHistory のコンストラクタ
+func NewTodoHistory(client *goredis.Client) repository.TodoHistory {
+	return &TodoHistory{
+		client: client,
+	}
+}
+
+// FindByTodoID はTodoの変更履歴を古い順に取得する
+func (r *TodoHistory) FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error) {
+	values, err := r.client.LRange(ctx, todoHistoryKey(todoID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]model.TodoHistory, len(values))
+	for i, v := range values {
+		if err := json.Unmarshal([]byte(v), &histories[i]); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortStableFunc(histories, func(a, b model.TodoHistory) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return histories, nil
+}
+
+// FindByTodoIDs は todoIDs の各Todoの変更履歴をまとめて取得する
+func (r *TodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	histories := []model.TodoHistory{}
+	if len(todoIDs) == 0 {
+		return histories, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*goredis.StringSliceCmd, len(todoIDs))
+	for i, todoID := range todoIDs {
+		cmds[i] = pipe.LRange(ctx, todoHistoryKey(todoID), 0, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		start := len(histories)
+		for _, v := range cmd.Val() {
+			var h model.TodoHistory
+			if err := json.Unmarshal([]byte(v), &h); err != nil {
+				return nil, err
+			}
+			histories = append(histories, h)
+		}
+		slices.SortStableFunc(histories[start:], func(a, b model.TodoHistory) int {
+			return cmp.Compare(a.Version, b.Version)
+		})
+	}
+	return histories, nil
+}
+
+// FindByVersion はTodoの指定したバージョンの変更履歴を取得する
+func (r *TodoHistory) FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error) {
+	histories, err := r.FindByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(histories, func(h model.TodoHistory) bool {
+		return h.Version == version
+	})
+	if i == -1 {
+		return nil, model.ErrNotFound
+	}
+	return &histories[i], nil
+}
@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// todoRecord は読み取ったTodoと、関連するデータの索引を表す
+type todoRecord struct {
+	// todo はタグと依存関係を設定したTodo
+	todo model.Todo
+	// tagIDs は付与されているタグのID
+	tagIDs []string
+	// hasOpenBlocker はブロックしているTodoのうち、完了または中止になっていないものがあるか
+	hasOpenBlocker bool
+}
+
+// loadTodos は ids のTodoをタグと依存関係とともに読み取る (存在しないTodoは結果に含めない)
+func loadTodos(ctx context.Context, c goredis.Cmdable, ids []string) (map[string]*todoRecord, error) {
+	records := make(map[string]*todoRecord, len(ids))
+	if len(ids) == 0 {
+		return records, nil
+	}
+
+	pipe := c.Pipeline()
+	hashes := make([]*goredis.MapStringStringCmd, len(ids))
+	tags := make([]*goredis.StringSliceCmd, len(ids))
+	blockers := make([]*goredis.StringSliceCmd, len(ids))
+	for i, id := range ids {
+		hashes[i] = pipe.HGetAll(ctx, todoKey(id))
+		tags[i] = pipe.SMembers(ctx, todoTagsKey(id))
+		blockers[i] = pipe.ZRange(ctx, todoBlockersKey(id), 0, -1)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	blockerIDs := make(map[string][]string, len(ids))
+	for i, id := range ids {
+		t, err := parseTodo(hashes[i].Val())
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records[id] = &todoRecord{todo: *t, tagIDs: tags[i].Val()}
+		blockerIDs[id] = blockers[i].Val()
+	}
+	if err := hydrateTodos(ctx, c, records, blockerIDs); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// hydrateTodos はTodoにタグ名と、ブロックしているTodoのうちゴミ箱にないもののIDを設定する
+func hydrateTodos(ctx context.Context, c goredis.Cmdable, records map[string]*todoRecord, blockerIDs map[string][]string) error {
+	var tagIDs []string
+	for _, rec := range records {
+		tagIDs = append(tagIDs, rec.tagIDs...)
+	}
+	slices.Sort(tagIDs)
+	tagIDs = slices.Compact(tagIDs)
+
+	// ブロックしているTodoの状態は、読み取ったTodoに含まれないものだけ追加で読み取る
+	type blockerState struct {
+		status  model.TodoStatus
+		deleted bool
+	}
+	states := make(map[string]blockerState)
+	for _, rec := range records {
+		states[rec.todo.ID] = blockerState{status: rec.todo.Status, deleted: rec.todo.DeletedAt != nil}
+	}
+	var missing []string
+	for _, ids := range blockerIDs {
+		for _, id := range ids {
+			if _, ok := states[id]; !ok {
+				states[id] = blockerState{}
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	pipe := c.Pipeline()
+	var names *goredis.SliceCmd
+	if len(tagIDs) > 0 {
+		names = pipe.HMGet(ctx, tagsKey, tagIDs...)
+	}
+	blockerFields := make([]*goredis.SliceCmd, len(missing))
+	for i, id := range missing {
+		blockerFields[i] = pipe.HMGet(ctx, todoKey(id), "status", "deleted_at")
+	}
+	if len(tagIDs) > 0 || len(missing) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	tagNames := make(map[string]string, len(tagIDs))
+	if names != nil {
+		for i, name := range names.Val() {
+			if s, ok := name.(string); ok {
+				tagNames[tagIDs[i]] = s
+			}
+		}
+	}
+	for i, id := range missing {
+		v := blockerFields[i].Val()
+		status, ok := v[0].(string)
+		if !ok {
+			// 完全に削除されたTodoは、ゴミ箱にあるTodoと同様に扱う
+			states[id] = blockerState{deleted: true}
+			continue
+		}
+		deletedAt, _ := v[1].(string)
+		states[id] = blockerState{status: model.TodoStatus(status), deleted: deletedAt != ""}
+	}
+
+	for id, rec := range records {
+		rec.todo.Tags = make([]string, 0, len(rec.tagIDs))
+		for _, tagID := range rec.tagIDs {
+			if name, ok := tagNames[tagID]; ok {
+				rec.todo.Tags = append(rec.todo.Tags, name)
+			}
+		}
+		slices.Sort(rec.todo.Tags)
+
+		rec.todo.BlockedBy = make([]string, 0, len(blockerIDs[id]))
+		for _, blockerID := range blockerIDs[id] {
+			state := states[blockerID]
+			if state.deleted {
+				continue
+			}
+			rec.todo.BlockedBy = append(rec.todo.BlockedBy, blockerID)
+			if !state.status.IsClosed() {
+				rec.hasOpenBlocker = true
+			}
+		}
+	}
+	return nil
+}
+
+// orderedRecords は ids の順に、読み取ったTodoを返す (存在しないTodoは含めない)
+func orderedRecords(ids []string, records map[string]*todoRecord) []*todoRecord {
+	ordered := make([]*todoRecord, 0, len(records))
+	for _, id := range ids {
+		if rec, ok := records[id]; ok {
+			ordered = append(ordered, rec)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,34 @@
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/redis"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+)
+
+func newClient(t *testing.T) *goredis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestTodo(t *testing.T) {
+	repositorytest.TestTodo(t, func(t *testing.T) repositorytest.Backend {
+		client := newClient(t)
+		return repositorytest.Backend{
+			Todo:            redis.NewTodo(client),
+			TodoDependency:  redis.NewTodoDependency(client),
+			TodoHistory:     redis.NewTodoHistory(client),
+			Tag:             redis.NewTag(client),
+			Outbox:          redis.NewOutbox(client),
+			Webhook:         redis.NewWebhook(client),
+			WebhookDelivery: redis.NewWebhookDelivery(client),
+		}
+	})
+}
@@ -0,0 +1,489 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// todoTx はTodoを書き込むトランザクションを表す
+// 読み取ったTodoを保持し、変更は保持したTodoに反映してから flush でまとめて書き込む
+// (トランザクションの中で書き込みを追加しても読み取りには反映されないため、変更後の状態は保持したTodoから読み取る)
+type todoTx struct {
+	ctx  context.Context
+	tx   *goredis.Tx
+	pipe goredis.Pipeliner
+	now  time.Time
+	// records は読み取ったTodo (変更を反映した状態)
+	records map[string]*todoRecord
+	// original は変更する前のTodo (このトランザクションで作成したTodoは含まない)
+	original map[string]todoRecord
+	// dirty は変更したTodoのID (変更した順)
+	dirty []string
+	// positions は親ごとの次の並び順
+	positions map[string]int
+	// newTags はこのトランザクションで作成したタグの名前をキーとしたID
+	newTags map[string]string
+	// revision はこのトランザクションで書き込んだ後のリビジョン (0 の場合は未取得)
+	revision int64
+}
+
+// newTodoTx は todoTx のコンストラクタ
+func newTodoTx(ctx context.Context, tx *goredis.Tx, pipe goredis.Pipeliner) *todoTx {
+	return &todoTx{
+		ctx:       ctx,
+		tx:        tx,
+		pipe:      pipe,
+		now:       now(),
+		records:   make(map[string]*todoRecord),
+		original:  make(map[string]todoRecord),
+		positions: make(map[string]int),
+		newTags:   make(map[string]string),
+	}
+}
+
+// load は ids のTodoを読み取る (読み取り済みのTodoは読み取らない)
+func (t *todoTx) load(ids []string) error {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := t.records[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	records, err := loadTodos(t.ctx, t.tx, missing)
+	if err != nil {
+		return err
+	}
+	for id, rec := range records {
+		t.records[id] = rec
+		t.original[id] = *rec
+	}
+	return nil
+}
+
+// find はIDによるTodoの取得 (ゴミ箱にあるTodoも含む)
+func (t *todoTx) find(id string) (*todoRecord, error) {
+	if err := t.load([]string{id}); err != nil {
+		return nil, err
+	}
+	rec, ok := t.records[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return rec, nil
+}
+
+// findActive はIDによるゴミ箱にないTodoの取得
+func (t *todoTx) findActive(id string) (*todoRecord, error) {
+	rec, err := t.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.todo.DeletedAt != nil {
+		return nil, model.ErrNotFound
+	}
+	return rec, nil
+}
+
+// children は parentID の子のTodoを並び順で返す (ゴミ箱にあるTodoも含む)
+func (t *todoTx) children(parentID string) ([]*todoRecord, error) {
+	ids, err := t.tx.ZRange(t.ctx, childrenKey(&parentID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.load(ids); err != nil {
+		return nil, err
+	}
+	// このトランザクションで親を変更したTodoは、変更後の親の子とする
+	var children []*todoRecord
+	for _, rec := range t.records {
+		if rec.todo.ParentID != nil && *rec.todo.ParentID == parentID {
+			children = append(children, rec)
+		}
+	}
+	slices.SortStableFunc(children, func(a, b *todoRecord) int {
+		return cmp.Or(cmp.Compare(a.todo.Position, b.todo.Position), cmp.Compare(a.todo.ID, b.todo.ID))
+	})
+	return children, nil
+}
+
+// subtree は id のTodoと、match に一致する子を辿って到達できる子孫のTodoのIDを、親から順に返す
+func (t *todoTx) subtree(id string, match func(t *model.Todo) bool) ([]string, error) {
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		children, err := t.children(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if match(&child.todo) && !slices.Contains(ids, child.todo.ID) {
+				ids = append(ids, child.todo.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// nextPosition は parentID の子の末尾の並び順を返す (ゴミ箱にあるTodoも含めて数える)
+func (t *todoTx) nextPosition(parentID *string) (int, error) {
+	key := childrenKey(parentID)
+	position, ok := t.positions[key]
+	if !ok {
+		last, err := t.tx.ZRevRangeWithScores(t.ctx, key, 0, 0).Result()
+		if err != nil {
+			return 0, err
+		}
+		if len(last) > 0 {
+			position = int(last[0].Score) + 1
+		}
+		for _, rec := range t.records {
+			if sameParent(rec.todo.ParentID, parentID) {
+				position = max(position, rec.todo.Position+1)
+			}
+		}
+	}
+	t.positions[key] = position + 1
+	return position, nil
+}
+
+// ensureTags は名前に一致するタグのIDを返す (存在しないタグは作成する)
+func (t *todoTx) ensureTags(names []string) ([]string, error) {
+	ids := make([]string, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+	existing, err := t.tx.HMGet(t.ctx, tagNamesKey, names...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		if id, ok := existing[i].(string); ok {
+			ids[i] = id
+			continue
+		}
+		id, ok := t.newTags[name]
+		if !ok {
+			id = uuid.New().String()
+			t.newTags[name] = id
+			t.pipe.HSet(t.ctx, tagsKey, id, name)
+			t.pipe.HSet(t.ctx, tagNamesKey, name, id)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// setTags はTodoに付与するタグを names に置き換える
+func (t *todoTx) setTags(rec *todoRecord, names []string) error {
+	ids, err := t.ensureTags(names)
+	if err != nil {
+		return err
+	}
+	rec.tagIDs = ids
+	rec.todo.Tags = slices.Clone(names)
+	if rec.todo.Tags == nil {
+		rec.todo.Tags = []string{}
+	}
+	slices.Sort(rec.todo.Tags)
+	return nil
+}
+
+// create は新しいTodoを作成する
+func (t *todoTx) create(todo model.Todo) (model.Todo, error) {
+	position, err := t.nextPosition(todo.ParentID)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	rec := &todoRecord{
+		todo: model.Todo{
+			ID:           uuid.New().String(),
+			Title:        todo.Title,
+			Content:      todo.Content,
+			Status:       todo.Status,
+			Priority:     todo.Priority,
+			ParentID:     todo.ParentID,
+			Position:     position,
+			AutoComplete: todo.AutoComplete,
+			BlockedBy:    []string{},
+			DueAt:        todo.DueAt,
+			Recurrence:   todo.Recurrence,
+			Timezone:     todo.Timezone,
+			SeriesID:     todo.SeriesID,
+			OccurrenceAt: todo.OccurrenceAt,
+			CompletedAt:  completedAt(nil, todo.Status, t.now),
+			Version:      1,
+			UpdatedAt:    t.now,
+			Done:         todo.Status == model.TodoStatusDone,
+		},
+	}
+	if err := t.setTags(rec, todo.Tags); err != nil {
+		return model.Todo{}, err
+	}
+	t.records[rec.todo.ID] = rec
+	t.markDirty(rec.todo.ID)
+	if err := t.recordHistory(model.HistoryActionCreate, nil, &rec.todo); err != nil {
+		return model.Todo{}, err
+	}
+	return rec.todo, nil
+}
+
+// update はTodoを更新する
+func (t *todoTx) update(id string, todo model.Todo) (*model.Todo, error) {
+	rec, err := t.findActive(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.CheckBaseVersion(&rec.todo, todo.Version); err != nil {
+		return nil, err
+	}
+	// 親が変わった場合は新しい兄弟の末尾に移動する
+	position := rec.todo.Position
+	if !sameParent(rec.todo.ParentID, todo.ParentID) {
+		if position, err = t.nextPosition(todo.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := t.modify(rec, model.HistoryActionUpdate, func(r *todoRecord) error {
+		r.todo = model.Todo{
+			ID:           id,
+			Title:        todo.Title,
+			Content:      todo.Content,
+			Status:       todo.Status,
+			Priority:     todo.Priority,
+			ParentID:     todo.ParentID,
+			Position:     position,
+			AutoComplete: todo.AutoComplete,
+			BlockedBy:    r.todo.BlockedBy,
+			DueAt:        todo.DueAt,
+			Recurrence:   todo.Recurrence,
+			Timezone:     todo.Timezone,
+			SeriesID:     todo.SeriesID,
+			OccurrenceAt: todo.OccurrenceAt,
+			CompletedAt:  completedAt(r.todo.CompletedAt, todo.Status, t.now),
+			ArchivedAt:   r.todo.ArchivedAt,
+			Done:         todo.Status == model.TodoStatusDone,
+		}
+		return t.setTags(r, todo.Tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// delete はTodoを子孫のTodoも含めてゴミ箱に移動する
+// 子孫のTodoには同じ日時を設定し、Restore で一緒に元に戻せるようにする
+func (t *todoTx) delete(id string) error {
+	if _, err := t.findActive(id); err != nil {
+		return err
+	}
+	ids, err := t.subtree(id, func(todo *model.Todo) bool { return todo.DeletedAt == nil })
+	if err != nil {
+		return err
+	}
+	deletedAt := t.now
+	for _, id := range ids {
+		if _, err := t.modify(t.records[id], model.HistoryActionDelete, func(r *todoRecord) error {
+			r.todo.DeletedAt = &deletedAt
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modify は rec に fn を適用してバージョンと更新日時を更新し、action として変更履歴を記録する
+func (t *todoTx) modify(rec *todoRecord, action model.HistoryAction, fn func(r *todoRecord) error) (model.Todo, error) {
+	before := rec.todo
+	if err := fn(rec); err != nil {
+		return model.Todo{}, err
+	}
+	rec.todo.Version = before.Version + 1
+	rec.todo.UpdatedAt = t.now
+	t.markDirty(rec.todo.ID)
+	if err := t.recordHistory(action, &before, &rec.todo); err != nil {
+		return model.Todo{}, err
+	}
+	return rec.todo, nil
+}
+
+// touch は rec のバージョンと更新日時を更新し、before からの変更を変更履歴に記録する
+// (タグや依存関係は読み取った時点で変更後の状態のため、変更前のTodoは呼び出し元から受け取る)
+func (t *todoTx) touch(rec *todoRecord, before *model.Todo) error {
+	rec.todo.Version++
+	rec.todo.UpdatedAt = t.now
+	t.markDirty(rec.todo.ID)
+	return t.recordHistory(model.HistoryActionUpdate, before, &rec.todo)
+}
+
+// markDirty はTodoを変更したTodoとして flush で書き込む対象にする
+func (t *todoTx) markDirty(id string) {
+	if !slices.Contains(t.dirty, id) {
+		t.dirty = append(t.dirty, id)
+	}
+}
+
+// recordHistory は before から after への変更履歴を記録する (更新で変更がない場合は記録しない)
+func (t *todoTx) recordHistory(action model.HistoryAction, before, after *model.Todo) error {
+	changes := model.DiffTodo(before, after)
+	if before != nil && len(changes) == 0 {
+		return nil
+	}
+
+	info := model.AuditInfoFromContext(t.ctx)
+	b, err := json.Marshal(model.TodoHistory{
+		ID:        uuid.New().String(),
+		TodoID:    after.ID,
+		Version:   after.Version,
+		Action:    action,
+		Actor:     info.Actor,
+		TraceID:   info.TraceID,
+		Changes:   changes,
+		Snapshot:  *after,
+		CreatedAt: t.now,
+	})
+	if err != nil {
+		return err
+	}
+	t.pipe.RPush(t.ctx, todoHistoryKey(after.ID), b)
+	return nil
+}
+
+// purge は ids のTodoと関連するデータを完全に削除する (変更履歴は残す)
+func (t *todoTx) purge(ids []string) error {
+	if err := t.load(ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		rec, ok := t.records[id]
+		if !ok {
+			continue
+		}
+		original := t.original[id]
+		blockers, err := t.tx.ZRange(t.ctx, todoBlockersKey(id), 0, -1).Result()
+		if err != nil {
+			return err
+		}
+		blocking, err := t.tx.SMembers(t.ctx, todoBlockingKey(id)).Result()
+		if err != nil {
+			return err
+		}
+		for _, blockerID := range blockers {
+			t.pipe.SRem(t.ctx, todoBlockingKey(blockerID), id)
+		}
+		for _, blockedID := range blocking {
+			t.pipe.ZRem(t.ctx, todoBlockersKey(blockedID), id)
+		}
+		for _, tagID := range original.tagIDs {
+			t.pipe.SRem(t.ctx, tagTodosKey(tagID), id)
+		}
+		t.pipe.Del(t.ctx, todoKey(id), todoTagsKey(id), todoBlockersKey(id), todoBlockingKey(id))
+		t.pipe.ZRem(t.ctx, allTodosKey, id)
+		t.pipe.ZRem(t.ctx, trashKey, id)
+		t.pipe.ZRem(t.ctx, updatedKey, id)
+		t.pipe.ZRem(t.ctx, childrenKey(original.todo.ParentID), id)
+		t.pipe.SRem(t.ctx, statusKey(original.todo.Status), id)
+		t.pipe.HSet(t.ctx, purgedKey, id, formatTime(&t.now))
+		if err := t.recordChange(id); err != nil {
+			return err
+		}
+		delete(t.records, rec.todo.ID)
+	}
+	return nil
+}
+
+// flush は変更したTodoを書き込みに追加する
+func (t *todoTx) flush() error {
+	for _, id := range t.dirty {
+		rec, ok := t.records[id]
+		if !ok {
+			continue
+		}
+		todo := &rec.todo
+		original, exists := t.original[id]
+
+		t.pipe.HSet(t.ctx, todoKey(id), todoFields(todo))
+		if err := t.recordChange(id); err != nil {
+			return err
+		}
+		t.pipe.ZAdd(t.ctx, updatedKey, goredis.Z{Score: micros(todo.UpdatedAt), Member: id})
+		if !exists {
+			seq, err := t.tx.Incr(t.ctx, todoSeqKey).Result()
+			if err != nil {
+				return err
+			}
+			t.pipe.ZAdd(t.ctx, allTodosKey, goredis.Z{Score: float64(seq), Member: id})
+		}
+		if !exists || original.todo.Status != todo.Status {
+			if exists {
+				t.pipe.SRem(t.ctx, statusKey(original.todo.Status), id)
+			}
+			t.pipe.SAdd(t.ctx, statusKey(todo.Status), id)
+		}
+		if exists && !sameParent(original.todo.ParentID, todo.ParentID) {
+			t.pipe.ZRem(t.ctx, childrenKey(original.todo.ParentID), id)
+		}
+		t.pipe.ZAdd(t.ctx, childrenKey(todo.ParentID), goredis.Z{Score: float64(todo.Position), Member: id})
+		if todo.DeletedAt != nil {
+			t.pipe.ZAdd(t.ctx, trashKey, goredis.Z{Score: micros(*todo.DeletedAt), Member: id})
+		} else if exists && original.todo.DeletedAt != nil {
+			t.pipe.ZRem(t.ctx, trashKey, id)
+		}
+
+		if !slices.Equal(original.tagIDs, rec.tagIDs) {
+			t.pipe.Del(t.ctx, todoTagsKey(id))
+			for _, tagID := range original.tagIDs {
+				if !slices.Contains(rec.tagIDs, tagID) {
+					t.pipe.SRem(t.ctx, tagTodosKey(tagID), id)
+				}
+			}
+			for _, tagID := range rec.tagIDs {
+				t.pipe.SAdd(t.ctx, todoTagsKey(id), tagID)
+				t.pipe.SAdd(t.ctx, tagTodosKey(tagID), id)
+			}
+		}
+	}
+	return nil
+}
+
+// recordChange はTodoを変更したことを差分同期のためにこのトランザクションのリビジョンで記録する
+// (write は書き込みの最後にリビジョンを1つ進めるため、書き込んだ後のリビジョンは読み取った値 + 1 になる)
+func (t *todoTx) recordChange(id string) error {
+	if t.revision == 0 {
+		current, err := t.tx.Get(t.ctx, revisionKey).Int64()
+		if err != nil && !errors.Is(err, goredis.Nil) {
+			return err
+		}
+		t.revision = current + 1
+	}
+	t.pipe.ZAdd(t.ctx, changesKey, goredis.Z{Score: float64(t.revision), Member: id})
+	return nil
+}
+
+// completedAt は status に応じた done になった日時を返す (done のままの場合は current を維持する)
+func completedAt(current *time.Time, status model.TodoStatus, now time.Time) *time.Time {
+	if status != model.TodoStatusDone {
+		return nil
+	}
+	if current != nil {
+		return current
+	}
+	return &now
+}
+
+// sameParent は親のIDが等しいかを返す (ルート同士も等しいとみなす)
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TxManager は repository.TxManager の実装
+// Redisのトランザクション (MULTI) の中では書き込んだ結果を読み取れないため、複数の操作にまたがるトランザクションには対応しない
+// fn の中の各操作はそれぞれ1つのトランザクションで実行し、fn がエラーを返しても適用済みの操作は取り消さない
+type TxManager struct{}
+
+// NewTxManager は repository.TxManager のコンストラクタ
+func NewTxManager() repository.TxManager {
+	return &TxManager{}
+}
+
+// Do は fn をそのまま実行する
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,155 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// View はRedisをデータストアとして使うビューの実装
+type View struct {
+	client *goredis.Client
+}
+
+// NewView は repository.View のコンストラクタ
+func NewView(client *goredis.Client) repository.View {
+	return &View{
+		client: client,
+	}
+}
+
+// FindAll は全てのビューを名前順で取得する
+func (r *View) FindAll(ctx context.Context) ([]model.View, error) {
+	values, err := r.client.HVals(ctx, viewsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]model.View, len(values))
+	for i, v := range values {
+		if err := json.Unmarshal([]byte(v), &views[i]); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(views, func(a, b model.View) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return views, nil
+}
+
+// FindByID はIDによるビューの取得
+func (r *View) FindByID(ctx context.Context, id string) (*model.View, error) {
+	return findView(ctx, r.client, id)
+}
+
+// Create は新しいビューを作成する
+func (r *View) Create(ctx context.Context, view model.View) (*model.View, error) {
+	now := now()
+	v := model.View{
+		ID:        uuid.New().String(),
+		Name:      view.Name,
+		Query:     view.Query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		if err := checkViewName(ctx, tx, v.Name, ""); err != nil {
+			return err
+		}
+		return saveView(ctx, pipe, &v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Update はビューの名前と検索クエリを更新する
+func (r *View) Update(ctx context.Context, id string, view model.View) (*model.View, error) {
+	var updated *model.View
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		v, err := findView(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkViewName(ctx, tx, view.Name, id); err != nil {
+			return err
+		}
+
+		pipe.HDel(ctx, viewNamesKey, v.Name)
+		v.Name = view.Name
+		v.Query = view.Query
+		v.UpdatedAt = now()
+		updated = v
+		return saveView(ctx, pipe, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete はビューを削除する
+func (r *View) Delete(ctx context.Context, id string) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		v, err := findView(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		pipe.HDel(ctx, viewsKey, id)
+		pipe.HDel(ctx, viewNamesKey, v.Name)
+		return nil
+	})
+}
+
+// findView はIDによるビューの取得
+func findView(ctx context.Context, c goredis.Cmdable, id string) (*model.View, error) {
+	value, err := c.HGet(ctx, viewsKey, id).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var v model.View
+	if err := json.Unmarshal(value, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// checkViewName は exceptID 以外に name と同じ名前のビューがある場合に model.ErrConflict を返す
+func checkViewName(ctx context.Context, c goredis.Cmdable, name string, exceptID string) error {
+	id, err := c.HGet(ctx, viewNamesKey, name).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if id != exceptID {
+		return model.ErrConflict
+	}
+	return nil
+}
+
+// saveView はビューと名前の索引を保存する書き込みを追加する
+func saveView(ctx context.Context, pipe goredis.Pipeliner, v *model.View) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	pipe.HSet(ctx, viewsKey, v.ID, b)
+	pipe.HSet(ctx, viewNamesKey, v.Name, v.ID)
+	return nil
+}
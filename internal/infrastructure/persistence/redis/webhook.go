@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Webhook はRedisをデータストアとして使うWebhookの購読の実装
+type Webhook struct {
+	client *goredis.Client
+}
+
+// NewWebhook は repository.Webhook のコンストラクタ
+func NewWebhook(client *goredis.Client) repository.Webhook {
+	return &Webhook{
+		client: client,
+	}
+}
+
+// FindAll は全てのWebhookを作成した順に取得する
+func (r *Webhook) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	values, err := r.client.HVals(ctx, webhooksKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]model.Webhook, len(values))
+	for i, v := range values {
+		if err := json.Unmarshal([]byte(v), &webhooks[i]); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(webhooks, func(a, b model.Webhook) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return webhooks, nil
+}
+
+// FindByID はIDによるWebhookの取得
+func (r *Webhook) FindByID(ctx context.Context, id string) (*model.Webhook, error) {
+	return findWebhook(ctx, r.client, id)
+}
+
+// Create は新しいWebhookを作成する
+func (r *Webhook) Create(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	now := now()
+	w := model.Webhook{
+		ID:        uuid.New().String(),
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Secret:    webhook.Secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := write(ctx, r.client, func(_ *goredis.Tx, pipe goredis.Pipeliner) error {
+		return saveWebhook(ctx, pipe, &w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Update はWebhookの URL、イベントの種類と秘密鍵を更新する
+func (r *Webhook) Update(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error) {
+	var updated *model.Webhook
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		w, err := findWebhook(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		w.URL = webhook.URL
+		w.Events = webhook.Events
+		w.Secret = webhook.Secret
+		w.UpdatedAt = now()
+		updated = w
+		return saveWebhook(ctx, pipe, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete はWebhookとその配信を削除する
+func (r *Webhook) Delete(ctx context.Context, id string) error {
+	return write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		if _, err := findWebhook(ctx, tx, id); err != nil {
+			return err
+		}
+		deliveries, err := findWebhookDeliveries(ctx, tx, id, 0, -1)
+		if err != nil {
+			return err
+		}
+
+		pipe.HDel(ctx, webhooksKey, id)
+		for _, d := range deliveries {
+			pipe.HDel(ctx, webhookDeliveriesKey, d.ID)
+			pipe.HDel(ctx, webhookDeliveryEventsKey, webhookDeliveryEventField(d.WebhookID, d.EventID))
+			pipe.ZRem(ctx, dueWebhookDeliveriesKey, d.ID)
+		}
+		pipe.Del(ctx, webhookDeliveriesByWebhookKey(id))
+		return nil
+	})
+}
+
+// findWebhook はIDによるWebhookの取得
+func findWebhook(ctx context.Context, c goredis.Cmdable, id string) (*model.Webhook, error) {
+	value, err := c.HGet(ctx, webhooksKey, id).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var w model.Webhook
+	if err := json.Unmarshal(value, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// saveWebhook はWebhookを保存する書き込みを追加する
+func saveWebhook(ctx context.Context, pipe goredis.Pipeliner, w *model.Webhook) error {
+	b, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	pipe.HSet(ctx, webhooksKey, w.ID, b)
+	return nil
+}
@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// WebhookDelivery はRedisをデータストアとして使うWebhookの配信の実装
+// 配信は JSON のハッシュに保存し、Webhookごとの配信の一覧と配信待ちの配信にはソート済みセットの索引を使う
+type WebhookDelivery struct {
+	client *goredis.Client
+}
+
+// NewWebhookDelivery は repository.WebhookDelivery のコンストラクタ
+func NewWebhookDelivery(client *goredis.Client) repository.WebhookDelivery {
+	return &WebhookDelivery{
+		client: client,
+	}
+}
+
+// Create は配信を作成する
+func (r *WebhookDelivery) Create(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	now := now()
+	d := model.WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        delivery.Status,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		if _, err := findWebhook(ctx, tx, d.WebhookID); err != nil {
+			return err
+		}
+		exists, err := tx.HExists(ctx, webhookDeliveryEventsKey, webhookDeliveryEventField(d.WebhookID, d.EventID)).Result()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return model.ErrConflict
+		}
+
+		pipe.HSet(ctx, webhookDeliveryEventsKey, webhookDeliveryEventField(d.WebhookID, d.EventID), d.ID)
+		pipe.ZAdd(ctx, webhookDeliveriesByWebhookKey(d.WebhookID), goredis.Z{Score: micros(d.CreatedAt), Member: d.ID})
+		return saveWebhookDelivery(ctx, pipe, &d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// FindByWebhookID はWebhookの配信を新しい順に limit 件まで取得する
+func (r *WebhookDelivery) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	return findWebhookDeliveries(ctx, r.client, webhookID, 0, int64(limit)-1)
+}
+
+// FindDue は now までに配信を試みる日時になった配信待ちの配信を、配信を試みる日時の順に limit 件まで取得する
+func (r *WebhookDelivery) FindDue(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	ids, err := r.client.ZRangeByScore(ctx, dueWebhookDeliveriesKey, &goredis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatFloat(micros(now), 'f', -1, 64),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	return loadWebhookDeliveries(ctx, r.client, ids)
+}
+
+// Update は配信を試みた結果を保存する
+func (r *WebhookDelivery) Update(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	var updated *model.WebhookDelivery
+	err := write(ctx, r.client, func(tx *goredis.Tx, pipe goredis.Pipeliner) error {
+		deliveries, err := loadWebhookDeliveries(ctx, tx, []string{delivery.ID})
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return model.ErrNotFound
+		}
+
+		d := deliveries[0]
+		d.Status = delivery.Status
+		d.Attempts = delivery.Attempts
+		d.ResponseStatus = delivery.ResponseStatus
+		d.LastError = delivery.LastError
+		d.NextAttemptAt = delivery.NextAttemptAt
+		d.UpdatedAt = now()
+		updated = &d
+		return saveWebhookDelivery(ctx, pipe, &d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// webhookDeliveryEventField は webhookDeliveryEventsKey のフィールド
+func webhookDeliveryEventField(webhookID, eventID string) string {
+	return webhookID + ":" + eventID
+}
+
+// findWebhookDeliveries はWebhookの配信を新しい順に start から stop 番目まで取得する
+func findWebhookDeliveries(ctx context.Context, c goredis.Cmdable, webhookID string, start, stop int64) ([]model.WebhookDelivery, error) {
+	ids, err := c.ZRevRange(ctx, webhookDeliveriesByWebhookKey(webhookID), start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	return loadWebhookDeliveries(ctx, c, ids)
+}
+
+// loadWebhookDeliveries は ids の配信を ids の順に取得する (存在しない配信は含めない)
+func loadWebhookDeliveries(ctx context.Context, c goredis.Cmdable, ids []string) ([]model.WebhookDelivery, error) {
+	deliveries := []model.WebhookDelivery{}
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
+	values, err := c.HMGet(ctx, webhookDeliveriesKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var d model.WebhookDelivery
+		if err := json.Unmarshal([]byte(s), &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// saveWebhookDelivery は配信と配信待ちの索引を保存する書き込みを追加する
+func saveWebhookDelivery(ctx context.Context, pipe goredis.Pipeliner, d *model.WebhookDelivery) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	pipe.HSet(ctx, webhookDeliveriesKey, d.ID, b)
+	if d.Status == model.WebhookDeliveryPending && d.NextAttemptAt != nil {
+		pipe.ZAdd(ctx, dueWebhookDeliveriesKey, goredis.Z{Score: micros(*d.NextAttemptAt), Member: d.ID})
+	} else {
+		pipe.ZRem(ctx, dueWebhookDeliveriesKey, d.ID)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repositorytest
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// CountingTodo は取得の呼び出し回数をメソッドごとに数える repository.Todo (他の操作はそのまま Todo に委譲する)
+// キャッシュやデータローダーがリポジトリの呼び出しをまとめていることを検証するテストで使う
+type CountingTodo struct {
+	repository.Todo
+	// Gate を設定した場合、FindByID は Gate が閉じるまで取得を待つ
+	Gate chan struct{}
+
+	FindAllCalls                 atomic.Int32
+	FindByIDCalls                atomic.Int32
+	FindByIDsCalls               atomic.Int32
+	FindChildrenCalls            atomic.Int32
+	FindChildrenByParentIDsCalls atomic.Int32
+}
+
+// Loads は取得の呼び出し回数の合計を返す
+func (r *CountingTodo) Loads() int32 {
+	return r.FindAllCalls.Load() + r.FindByIDCalls.Load() + r.FindByIDsCalls.Load() +
+		r.FindChildrenCalls.Load() + r.FindChildrenByParentIDsCalls.Load()
+}
+
+func (r *CountingTodo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	r.FindAllCalls.Add(1)
+	return r.Todo.FindAll(ctx, query)
+}
+
+func (r *CountingTodo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
+	r.FindByIDCalls.Add(1)
+	if r.Gate != nil {
+		<-r.Gate
+	}
+	return r.Todo.FindByID(ctx, id)
+}
+
+func (r *CountingTodo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	r.FindByIDsCalls.Add(1)
+	return r.Todo.FindByIDs(ctx, ids)
+}
+
+func (r *CountingTodo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	r.FindChildrenCalls.Add(1)
+	return r.Todo.FindChildren(ctx, parentID)
+}
+
+func (r *CountingTodo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	r.FindChildrenByParentIDsCalls.Add(1)
+	return r.Todo.FindChildrenByParentIDs(ctx, parentIDs)
+}
+
+// CountingTodoHistory はまとめた取得の呼び出し回数を数える repository.TodoHistory
+type CountingTodoHistory struct {
+	repository.TodoHistory
+
+	FindByTodoIDsCalls atomic.Int32
+}
+
+func (r *CountingTodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	r.FindByTodoIDsCalls.Add(1)
+	return r.TodoHistory.FindByTodoIDs(ctx, todoIDs)
+}
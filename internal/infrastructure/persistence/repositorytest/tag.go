@@ -0,0 +1,43 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+func testTagMerge(t *testing.T, b Backend) {
+	ctx := context.Background()
+	tagged := create(t, b.Todo, newTodo("Tagged", model.TodoStatusTodo, "old"))
+	create(t, b.Todo, newTodo("Other", model.TodoStatusTodo, "new"))
+	tags, err := b.Tag.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("FindAll() = %+v, want 2 tags", tags)
+	}
+	// 名前順のため new, old の順になる
+	target, source := tags[0], tags[1]
+
+	_, err = b.Tag.Rename(ctx, source.ID, target.Name)
+	wantErr(t, "Rename() to an existing name", err, model.ErrConflict)
+	if _, err := b.Tag.Merge(ctx, source.ID, target.ID); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	got, err := b.Todo.FindByID(ctx, tagged.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"new"}, got.Tags); diff != "" {
+		t.Errorf("FindByID() tags after Merge (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Tagged", "Other"}, findAll(t, b.Todo, model.TodoQuery{Tags: []string{"new"}})); diff != "" {
+		t.Errorf("FindAll(tag=new) after Merge (-want +got):\n%s", diff)
+	}
+	_, err = b.Tag.FindByID(ctx, source.ID)
+	wantErr(t, "FindByID() of the merged tag", err, model.ErrNotFound)
+}
@@ -0,0 +1,1211 @@
+// Package repositorytest はリポジトリの実装が共通して満たすべき振る舞いを検証するテストを提供する
+// 各リポジトリの実装のテストから、空のデータストアを作成する関数を渡して呼び出す
+// リポジトリの呼び出しを数えるテスト用の実装 (CountingTodo など) も提供する
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// missingID は存在しないTodoのID (UUIDとして有効な値)
+const missingID = "00000000-0000-4000-a000-0000000000ff"
+
+// Backend はテスト対象の、同じデータストアを共有するリポジトリの組
+type Backend struct {
+	Todo           repository.Todo
+	TodoDependency repository.TodoDependency
+	TodoHistory    repository.TodoHistory
+	Tag            repository.Tag
+	// TxManager は複数の操作にまたがるトランザクションに対応しない場合は nil とする (Transaction のテストを省略する)
+	TxManager repository.TxManager
+	// Outbox はアウトボックスを実装しない場合は nil とする (Outbox のテストを省略する)
+	Outbox repository.Outbox
+	// Webhook と WebhookDelivery はWebhookを実装しない場合は nil とする (Webhook のテストを省略する)
+	Webhook         repository.Webhook
+	WebhookDelivery repository.WebhookDelivery
+}
+
+// TestTodo は repository.Todo の実装の振る舞いを検証する
+// newBackend はサブテストごとに呼び出し、空のデータストアを使うリポジトリを返すこと
+func TestTodo(t *testing.T, newBackend func(t *testing.T) Backend) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, b Backend)
+	}{
+		{"Create", testCreate},
+		{"NotFound", testNotFound},
+		{"NotFound/InvalidID", testNotFoundInvalidID},
+		{"Update", testUpdate},
+		{"FindAll/Filter", testFindAllFilter},
+		{"FindAll/Order", testFindAllOrder},
+		{"Stamp", testStamp},
+		{"Children", testChildren},
+		{"BatchLookups", testBatchLookups},
+		{"Trash", testTrash},
+		{"Archive", testArchive},
+		{"ApplyBatch", testApplyBatch},
+		{"Changes", testChanges},
+		{"Versioning", testVersioning},
+		{"Concurrency", testConcurrency},
+		{"Concurrency/BaseVersion", testConcurrentBaseVersion},
+		{"Touch", testTouch},
+		{"Tag/Merge", testTagMerge},
+		{"Transaction", testTransaction},
+		{"Outbox", testOutbox},
+		{"Webhook", testWebhook},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newBackend(t))
+		})
+	}
+}
+
+// newTodo は作成するTodoを返す
+func newTodo(title string, status model.TodoStatus, tags ...string) model.Todo {
+	return model.Todo{Title: title, Status: status, Tags: tags, Timezone: model.DefaultTimezone}
+}
+
+// create はTodoを作成し、失敗した場合はテストを終了する
+func create(t *testing.T, repo repository.Todo, todo model.Todo) *model.Todo {
+	t.Helper()
+	created, err := repo.Create(context.Background(), todo)
+	if err != nil {
+		t.Fatalf("Create(%q) error = %v", todo.Title, err)
+	}
+	return created
+}
+
+// findAll は query に一致するTodoのタイトルを返し、失敗した場合はテストを終了する
+func findAll(t *testing.T, repo repository.Todo, query model.TodoQuery) []string {
+	t.Helper()
+	todos, err := repo.FindAll(context.Background(), query)
+	if err != nil {
+		t.Fatalf("FindAll(%+v) error = %v", query, err)
+	}
+	return titles(todos)
+}
+
+// titles はTodoのタイトルの一覧を返す
+func titles(todos []model.Todo) []string {
+	s := make([]string, len(todos))
+	for i, t := range todos {
+		s[i] = t.Title
+	}
+	return s
+}
+
+// wantErr は err が want でない場合にテストを失敗させる
+func wantErr(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("%s error = %v, want %v", op, err, want)
+	}
+}
+
+func testCreate(t *testing.T, b Backend) {
+	todo := newTodo("Write report", model.TodoStatusDone, "work", "urgent")
+	todo.ID = missingID
+	todo.Content = "quarterly"
+	todo.Priority = 3
+	first := create(t, b.Todo, todo)
+	second := create(t, b.Todo, newTodo("Second", model.TodoStatusTodo))
+
+	// IDは指定した値を使わずに払い出す
+	if first.ID == "" || first.ID == missingID || first.ID == second.ID {
+		t.Errorf("Create() ids = %q, %q, want distinct generated ids", first.ID, second.ID)
+	}
+	if first.Version != 1 || first.Position != 0 || second.Position != 1 {
+		t.Errorf("Create() version = %d, positions = %d, %d, want 1, 0, 1", first.Version, first.Position, second.Position)
+	}
+	if first.CompletedAt == nil || !first.Done || second.CompletedAt != nil || second.Done {
+		t.Errorf("Create() completed_at/done are not derived from status")
+	}
+	if first.UpdatedAt.IsZero() {
+		t.Errorf("Create() updated_at is zero")
+	}
+
+	got, err := b.Todo.FindByID(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if diff := cmp.Diff(first, got); diff != "" {
+		t.Errorf("FindByID() differs from Create() (-create +find):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"urgent", "work"}, got.Tags); diff != "" {
+		t.Errorf("FindByID() tags are not sorted (-want +got):\n%s", diff)
+	}
+	if got.Content != "quarterly" || got.Priority != 3 || got.BlockedBy == nil || len(got.BlockedBy) != 0 {
+		t.Errorf("FindByID() = %+v, want content, priority and empty blocked_by", got)
+	}
+}
+
+func testNotFound(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Trashed", model.TodoStatusTodo))
+	if err := b.Todo.Delete(ctx, todo.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// 存在しないTodoとゴミ箱にあるTodoは同じように扱う
+	for _, id := range []string{missingID, todo.ID} {
+		_, err := b.Todo.FindByID(ctx, id)
+		wantErr(t, "FindByID()", err, model.ErrNotFound)
+		_, err = b.Todo.Update(ctx, id, newTodo("Updated", model.TodoStatusTodo))
+		wantErr(t, "Update()", err, model.ErrNotFound)
+		wantErr(t, "Delete()", b.Todo.Delete(ctx, id), model.ErrNotFound)
+		_, err = b.Todo.Archive(ctx, id)
+		wantErr(t, "Archive()", err, model.ErrNotFound)
+		_, err = b.Todo.Unarchive(ctx, id)
+		wantErr(t, "Unarchive()", err, model.ErrNotFound)
+	}
+	_, err := b.Todo.Restore(ctx, missingID)
+	wantErr(t, "Restore()", err, model.ErrNotFound)
+	wantErr(t, "Purge()", b.Todo.Purge(ctx, missingID), model.ErrNotFound)
+}
+
+func testNotFoundInvalidID(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Existing", model.TodoStatusTodo, "work"))
+
+	// UUID として正しくないIDも存在しないIDとして扱う
+	const id = "not-a-uuid"
+	_, err := b.Todo.FindByID(ctx, id)
+	wantErr(t, "FindByID()", err, model.ErrNotFound)
+	_, err = b.Todo.Update(ctx, id, newTodo("Updated", model.TodoStatusTodo))
+	wantErr(t, "Update()", err, model.ErrNotFound)
+	wantErr(t, "Delete()", b.Todo.Delete(ctx, id), model.ErrNotFound)
+	_, err = b.Todo.Archive(ctx, id)
+	wantErr(t, "Archive()", err, model.ErrNotFound)
+	_, err = b.Todo.Restore(ctx, id)
+	wantErr(t, "Restore()", err, model.ErrNotFound)
+	wantErr(t, "Purge()", b.Todo.Purge(ctx, id), model.ErrNotFound)
+	if children, err := b.Todo.FindChildren(ctx, id); err != nil || len(children) != 0 {
+		t.Errorf("FindChildren() = %v, %v, want none", titles(children), err)
+	}
+	wantErr(t, "ReorderChildren()", b.Todo.ReorderChildren(ctx, todo.ID, []string{id}), model.ErrNotFound)
+	_, err = b.Todo.ApplyBatch(ctx, []model.TodoWrite{{Kind: model.TodoWriteDelete, ID: id}})
+	wantErr(t, "ApplyBatch()", err, model.ErrNotFound)
+
+	wantErr(t, "TodoDependency.Add()", b.TodoDependency.Add(ctx, todo.ID, id), model.ErrNotFound)
+	wantErr(t, "TodoDependency.Remove()", b.TodoDependency.Remove(ctx, id, todo.ID), model.ErrNotFound)
+	if ids, err := b.TodoDependency.FindAllBlockerIDs(ctx, id); err != nil || len(ids) != 0 {
+		t.Errorf("FindAllBlockerIDs() = %v, %v, want none", ids, err)
+	}
+	if histories, err := b.TodoHistory.FindByTodoID(ctx, id); err != nil || len(histories) != 0 {
+		t.Errorf("FindByTodoID() = %v, %v, want none", histories, err)
+	}
+	_, err = b.TodoHistory.FindByVersion(ctx, id, 1)
+	wantErr(t, "FindByVersion()", err, model.ErrNotFound)
+
+	_, err = b.Tag.FindByID(ctx, id)
+	wantErr(t, "Tag.FindByID()", err, model.ErrNotFound)
+	_, err = b.Tag.Rename(ctx, id, "renamed")
+	wantErr(t, "Tag.Rename()", err, model.ErrNotFound)
+	wantErr(t, "Tag.Delete()", b.Tag.Delete(ctx, id), model.ErrNotFound)
+
+	if b.Webhook != nil && b.WebhookDelivery != nil {
+		_, err = b.Webhook.FindByID(ctx, id)
+		wantErr(t, "Webhook.FindByID()", err, model.ErrNotFound)
+		wantErr(t, "Webhook.Delete()", b.Webhook.Delete(ctx, id), model.ErrNotFound)
+		if deliveries, err := b.WebhookDelivery.FindByWebhookID(ctx, id, 10); err != nil || len(deliveries) != 0 {
+			t.Errorf("FindByWebhookID() = %v, %v, want none", deliveries, err)
+		}
+	}
+}
+
+func testUpdate(t *testing.T, b Backend) {
+	ctx := context.Background()
+	created := create(t, b.Todo, newTodo("Draft", model.TodoStatusTodo, "work"))
+
+	update := *created
+	update.Title = "Final"
+	update.Status = model.TodoStatusDone
+	update.Tags = []string{"work", "done"}
+	done, err := b.Todo.Update(ctx, created.ID, update)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if done.Version != 2 || done.Title != "Final" || done.CompletedAt == nil || !done.Done {
+		t.Errorf("Update() = %+v, want version 2 with completed_at", done)
+	}
+	if diff := cmp.Diff([]string{"done", "work"}, done.Tags); diff != "" {
+		t.Errorf("Update() tags (-want +got):\n%s", diff)
+	}
+	if done.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("Update() updated_at = %v, want after %v", done.UpdatedAt, created.UpdatedAt)
+	}
+
+	// done のまま更新しても完了した日時は変わらない
+	update.Content = "edited"
+	update.Version = done.Version
+	edited, err := b.Todo.Update(ctx, created.ID, update)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if edited.CompletedAt == nil || !edited.CompletedAt.Equal(*done.CompletedAt) {
+		t.Errorf("Update() completed_at = %v, want %v", edited.CompletedAt, done.CompletedAt)
+	}
+
+	update.Status = model.TodoStatusTodo
+	update.Version = edited.Version
+	reopened, err := b.Todo.Update(ctx, created.ID, update)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if reopened.CompletedAt != nil || reopened.Done || reopened.Version != 4 {
+		t.Errorf("Update() = %+v, want version 4 without completed_at", reopened)
+	}
+
+	got, err := b.Todo.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if diff := cmp.Diff(reopened, got); diff != "" {
+		t.Errorf("FindByID() differs from Update() (-update +find):\n%s", diff)
+	}
+}
+
+func testFindAllFilter(t *testing.T, b Backend) {
+	ctx := context.Background()
+	due := time.Now().Truncate(time.Second).Add(24 * time.Hour)
+	high := newTodo("Fix outage", model.TodoStatusInProgress, "work", "urgent")
+	high.Priority = 4
+	high.DueAt = &due
+	create(t, b.Todo, newTodo("Buy milk", model.TodoStatusTodo, "home"))
+	fix := create(t, b.Todo, high)
+	pay := create(t, b.Todo, newTodo("Pay bills", model.TodoStatusDone, "home", "urgent"))
+	report := newTodo("Write notes", model.TodoStatusTodo)
+	report.Content = "outage report"
+	blocked := create(t, b.Todo, report)
+	if err := b.TodoDependency.Add(ctx, blocked.ID, fix.ID); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := b.Todo.Archive(ctx, pay.ID); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	minPriority := 3
+
+	tests := []struct {
+		name  string
+		query model.TodoQuery
+		want  []string
+	}{
+		{"default excludes archived", model.TodoQuery{}, []string{"Buy milk", "Fix outage", "Write notes"}},
+		{"status", model.TodoQuery{Status: model.TodoStatusTodo}, []string{"Buy milk", "Write notes"}},
+		{"archived only", model.TodoQuery{Archived: model.ArchivedFilterTrue}, []string{"Pay bills"}},
+		{"archived any", model.TodoQuery{Archived: model.ArchivedFilterAny}, []string{"Buy milk", "Fix outage", "Pay bills", "Write notes"}},
+		{"any tag", model.TodoQuery{Tags: []string{"home", "urgent"}, Archived: model.ArchivedFilterAny}, []string{"Buy milk", "Fix outage", "Pay bills"}},
+		{"all tags", model.TodoQuery{Tags: []string{"home", "urgent"}, TagMatch: model.TagMatchAll, Archived: model.ArchivedFilterAny}, []string{"Pay bills"}},
+		{"min priority", model.TodoQuery{MinPriority: &minPriority}, []string{"Fix outage"}},
+		{"due range", model.TodoQuery{DueRange: &model.DueRange{From: &due}}, []string{"Fix outage"}},
+		{"no due date", model.TodoQuery{DueRange: &model.DueRange{None: true}}, []string{"Buy milk", "Write notes"}},
+		{"actionable excludes blocked", model.TodoQuery{Actionable: true}, []string{"Buy milk", "Fix outage"}},
+		// タイトルに一致したTodoを内容のみに一致したTodoより上位にする
+		{"search ranks title first", model.TodoQuery{Q: "outage"}, []string{"Fix outage", "Write notes"}},
+		{"no match", model.TodoQuery{Q: "nothing"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, findAll(t, b.Todo, tt.query)); diff != "" {
+				t.Errorf("FindAll() (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	todos, err := b.Todo.FindAll(ctx, model.TodoQuery{Q: "outage"})
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	for _, todo := range todos {
+		if todo.Search == nil {
+			t.Errorf("FindAll() with a search term returned %q without search match", todo.Title)
+		}
+	}
+}
+
+func testFindAllOrder(t *testing.T, b Backend) {
+	ctx := context.Background()
+	var created []*model.Todo
+	for _, title := range []string{"First", "Second", "Third"} {
+		created = append(created, create(t, b.Todo, newTodo(title, model.TodoStatusTodo)))
+	}
+	// 更新しても作成した順は変わらない
+	update := *created[0]
+	update.Priority = 2
+	if _, err := b.Todo.Update(ctx, created[0].ID, update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"First", "Second", "Third"}, findAll(t, b.Todo, model.TodoQuery{})); diff != "" {
+		t.Errorf("FindAll() (-want +got):\n%s", diff)
+	}
+}
+
+func testStamp(t *testing.T, b Backend) {
+	ctx := context.Background()
+	create(t, b.Todo, newTodo("Open", model.TodoStatusTodo))
+	done := create(t, b.Todo, newTodo("Closed", model.TodoStatusDone))
+
+	stamp, err := b.Todo.Stamp(ctx, model.TodoQuery{Status: model.TodoStatusTodo})
+	if err != nil {
+		t.Fatalf("Stamp() error = %v", err)
+	}
+	if stamp.Count != 1 || stamp.LastModified.Before(done.UpdatedAt.Truncate(time.Second)) {
+		t.Errorf("Stamp() = %+v, want count 1 and last modified at or after %v", stamp, done.UpdatedAt)
+	}
+
+	search, err := b.Todo.Stamp(ctx, model.TodoQuery{Q: "closed"})
+	if err != nil {
+		t.Fatalf("Stamp() error = %v", err)
+	}
+	if search.Count != 1 {
+		t.Errorf("Stamp() with a search term count = %d, want 1", search.Count)
+	}
+}
+
+func testChildren(t *testing.T, b Backend) {
+	ctx := context.Background()
+	parent := create(t, b.Todo, newTodo("Parent", model.TodoStatusTodo))
+	var ids []string
+	for _, title := range []string{"A", "B", "C"} {
+		child := newTodo(title, model.TodoStatusTodo)
+		child.ParentID = &parent.ID
+		created := create(t, b.Todo, child)
+		if created.Position != len(ids) {
+			t.Errorf("Create(%q) position = %d, want %d", title, created.Position, len(ids))
+		}
+		ids = append(ids, created.ID)
+	}
+
+	if err := b.Todo.ReorderChildren(ctx, parent.ID, []string{ids[2], ids[0], ids[1]}); err != nil {
+		t.Fatalf("ReorderChildren() error = %v", err)
+	}
+	children, err := b.Todo.FindChildren(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("FindChildren() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"C", "A", "B"}, titles(children)); diff != "" {
+		t.Errorf("FindChildren() after ReorderChildren (-want +got):\n%s", diff)
+	}
+	wantErr(t, "ReorderChildren() with a todo of another parent",
+		b.Todo.ReorderChildren(ctx, parent.ID, []string{parent.ID}), model.ErrNotFound)
+
+	// 親を変更したTodoは新しい兄弟の末尾に移動する
+	moved := children[1]
+	moved.ParentID = nil
+	root, err := b.Todo.Update(ctx, moved.ID, moved)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if root.ParentID != nil || root.Position != 1 {
+		t.Errorf("Update() moved to root parent_id = %v, position = %d, want nil, 1", root.ParentID, root.Position)
+	}
+	if children, _ := b.Todo.FindChildren(ctx, parent.ID); len(children) != 2 {
+		t.Errorf("FindChildren() after moving a child = %v, want 2 children", titles(children))
+	}
+}
+
+func testBatchLookups(t *testing.T, b Backend) {
+	ctx := context.Background()
+	first := create(t, b.Todo, newTodo("First", model.TodoStatusTodo))
+	second := create(t, b.Todo, newTodo("Second", model.TodoStatusTodo))
+	trashed := create(t, b.Todo, newTodo("Trashed", model.TodoStatusTodo))
+	for _, parent := range []*model.Todo{first, second} {
+		for _, title := range []string{"A", "B"} {
+			child := newTodo(parent.Title+title, model.TodoStatusTodo)
+			child.ParentID = &parent.ID
+			create(t, b.Todo, child)
+		}
+	}
+	if err := b.Todo.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	update := *first
+	update.Title = "First!"
+	if _, err := b.Todo.Update(ctx, first.ID, update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// 存在しないTodoとゴミ箱にあるTodoは結果に含めない
+	todos, err := b.Todo.FindByIDs(ctx, []string{first.ID, missingID, trashed.ID, second.ID})
+	if err != nil {
+		t.Fatalf("FindByIDs() error = %v", err)
+	}
+	got := titles(todos)
+	slices.Sort(got)
+	if diff := cmp.Diff([]string{"First!", "Second"}, got); diff != "" {
+		t.Errorf("FindByIDs() (-want +got):\n%s", diff)
+	}
+	if todos, err := b.Todo.FindByIDs(ctx, nil); err != nil || len(todos) != 0 {
+		t.Errorf("FindByIDs(nil) = %v, %v, want none", titles(todos), err)
+	}
+
+	children, err := b.Todo.FindChildrenByParentIDs(ctx, []string{second.ID, first.ID, missingID})
+	if err != nil {
+		t.Fatalf("FindChildrenByParentIDs() error = %v", err)
+	}
+	byParent := map[string][]string{}
+	for _, c := range children {
+		byParent[*c.ParentID] = append(byParent[*c.ParentID], c.Title)
+	}
+	want := map[string][]string{first.ID: {"FirstA", "FirstB"}, second.ID: {"SecondA", "SecondB"}}
+	if diff := cmp.Diff(want, byParent); diff != "" {
+		t.Errorf("FindChildrenByParentIDs() (-want +got):\n%s", diff)
+	}
+
+	histories, err := b.TodoHistory.FindByTodoIDs(ctx, []string{first.ID, second.ID, missingID})
+	if err != nil {
+		t.Fatalf("FindByTodoIDs() error = %v", err)
+	}
+	versions := map[string][]int{}
+	for _, h := range histories {
+		versions[h.TodoID] = append(versions[h.TodoID], h.Version)
+	}
+	if diff := cmp.Diff(map[string][]int{first.ID: {1, 2}, second.ID: {1}}, versions); diff != "" {
+		t.Errorf("FindByTodoIDs() versions (-want +got):\n%s", diff)
+	}
+}
+
+func testTrash(t *testing.T, b Backend) {
+	ctx := context.Background()
+	parent := create(t, b.Todo, newTodo("Parent", model.TodoStatusTodo))
+	child := newTodo("Child", model.TodoStatusTodo)
+	child.ParentID = &parent.ID
+	created := create(t, b.Todo, child)
+	other := create(t, b.Todo, newTodo("Other", model.TodoStatusTodo))
+	if err := b.TodoDependency.Add(ctx, other.ID, created.ID); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// 子孫のTodoも一緒にゴミ箱に移動し、ゴミ箱にあるTodoはブロックしているTodoに含めない
+	if err := b.Todo.Delete(ctx, parent.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"Other"}, findAll(t, b.Todo, model.TodoQuery{})); diff != "" {
+		t.Errorf("FindAll() after Delete (-want +got):\n%s", diff)
+	}
+	if got, _ := b.Todo.FindByID(ctx, other.ID); got == nil || len(got.BlockedBy) != 0 {
+		t.Errorf("FindByID() with a trashed blocker = %+v, want no blockers", got)
+	}
+	if ids, err := b.TodoDependency.FindBlockerIDs(ctx, other.ID); err != nil || len(ids) != 0 {
+		t.Errorf("FindBlockerIDs() with a trashed blocker = %v, %v, want none", ids, err)
+	}
+	// 循環の検証のため、ゴミ箱にあるTodoとの依存関係も返す
+	if ids, err := b.TodoDependency.FindAllBlockerIDs(ctx, other.ID); err != nil || !slices.Equal(ids, []string{created.ID}) {
+		t.Errorf("FindAllBlockerIDs() with a trashed blocker = %v, %v, want [%s]", ids, err, created.ID)
+	}
+	trash, err := b.Todo.FindTrash(ctx)
+	if err != nil {
+		t.Fatalf("FindTrash() error = %v", err)
+	}
+	if len(trash) != 2 || trash[0].DeletedAt == nil || !trash[0].DeletedAt.Equal(*trash[1].DeletedAt) {
+		t.Errorf("FindTrash() = %v, want parent and child trashed at the same time", titles(trash))
+	}
+
+	_, err = b.Todo.Restore(ctx, created.ID)
+	wantErr(t, "Restore() of a child of a trashed parent", err, model.ErrConflict)
+	restored, err := b.Todo.Restore(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("Restore() deleted_at = %v, want nil", restored.DeletedAt)
+	}
+	if children, _ := b.Todo.FindChildren(ctx, parent.ID); len(children) != 1 {
+		t.Errorf("FindChildren() after Restore = %v, want [Child]", titles(children))
+	}
+
+	if err := b.Todo.Delete(ctx, parent.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	n, err := b.Todo.PurgeDeletedBefore(ctx, time.Now().Add(-time.Hour))
+	if err != nil || n != 0 {
+		t.Errorf("PurgeDeletedBefore() an hour ago = %d, %v, want 0", n, err)
+	}
+	if n, err = b.Todo.PurgeDeletedBefore(ctx, time.Now().Add(time.Hour)); err != nil || n != 2 {
+		t.Errorf("PurgeDeletedBefore() = %d, %v, want 2", n, err)
+	}
+	if trash, _ := b.Todo.FindTrash(ctx); len(trash) != 0 {
+		t.Errorf("FindTrash() after purge = %v, want none", titles(trash))
+	}
+	_, err = b.Todo.Restore(ctx, parent.ID)
+	wantErr(t, "Restore() of a purged todo", err, model.ErrNotFound)
+	wantErr(t, "Remove() of a purged blocker", b.TodoDependency.Remove(ctx, other.ID, created.ID), model.ErrNotFound)
+
+	// 変更履歴は完全に削除したTodoのものも残す
+	if histories, _ := b.TodoHistory.FindByTodoID(ctx, parent.ID); len(histories) == 0 {
+		t.Errorf("FindByTodoID() of a purged todo returned no histories")
+	}
+
+	single := create(t, b.Todo, newTodo("Single", model.TodoStatusTodo))
+	wantErr(t, "Purge() of an active todo", b.Todo.Purge(ctx, single.ID), model.ErrNotFound)
+	if err := b.Todo.Delete(ctx, single.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := b.Todo.Purge(ctx, single.ID); err != nil {
+		t.Errorf("Purge() error = %v", err)
+	}
+}
+
+func testArchive(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Done", model.TodoStatusDone))
+	open := create(t, b.Todo, newTodo("Open", model.TodoStatusTodo))
+
+	archived, err := b.Todo.Archive(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if archived.ArchivedAt == nil {
+		t.Fatalf("Archive() archived_at = nil")
+	}
+	// アーカイブ済みの場合はアーカイブした日時を維持する
+	again, err := b.Todo.Archive(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if again.ArchivedAt == nil || !again.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Errorf("Archive() again archived_at = %v, want %v", again.ArchivedAt, archived.ArchivedAt)
+	}
+	unarchived, err := b.Todo.Unarchive(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("Unarchive() error = %v", err)
+	}
+	if unarchived.ArchivedAt != nil {
+		t.Errorf("Unarchive() archived_at = %v, want nil", unarchived.ArchivedAt)
+	}
+
+	n, err := b.Todo.ArchiveCompletedBefore(ctx, time.Now().Add(time.Hour))
+	if err != nil || n != 1 {
+		t.Errorf("ArchiveCompletedBefore() = %d, %v, want 1", n, err)
+	}
+	if got, _ := b.Todo.FindByID(ctx, open.ID); got == nil || got.ArchivedAt != nil {
+		t.Errorf("ArchiveCompletedBefore() archived an open todo")
+	}
+}
+
+func testApplyBatch(t *testing.T, b Backend) {
+	ctx := context.Background()
+	existing := create(t, b.Todo, newTodo("Existing", model.TodoStatusTodo))
+	trashed := create(t, b.Todo, newTodo("Trashed", model.TodoStatusTodo))
+
+	// 1つでも失敗した場合は何も書き込まない
+	_, err := b.Todo.ApplyBatch(ctx, []model.TodoWrite{
+		{Kind: model.TodoWriteCreate, Todo: newTodo("Rolled back", model.TodoStatusTodo)},
+		{Kind: model.TodoWriteDelete, ID: existing.ID},
+		{Kind: model.TodoWriteDelete, ID: missingID},
+	})
+	wantErr(t, "ApplyBatch()", err, model.ErrNotFound)
+	if diff := cmp.Diff([]string{"Existing", "Trashed"}, findAll(t, b.Todo, model.TodoQuery{})); diff != "" {
+		t.Errorf("FindAll() after a failed batch (-want +got):\n%s", diff)
+	}
+
+	update := *existing
+	update.Title = "Updated"
+	todos, err := b.Todo.ApplyBatch(ctx, []model.TodoWrite{
+		{Kind: model.TodoWriteUpdate, ID: existing.ID, Todo: update},
+		{Kind: model.TodoWriteCreate, Todo: newTodo("Created", model.TodoStatusTodo)},
+		{Kind: model.TodoWriteDelete, ID: trashed.ID},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() error = %v", err)
+	}
+	if len(todos) != 3 || todos[0] == nil || todos[0].Version != 2 || todos[1] == nil || todos[1].Position != 2 || todos[2] != nil {
+		t.Errorf("ApplyBatch() = %+v, want updated, created and nil for the trashed todo", todos)
+	}
+	if diff := cmp.Diff([]string{"Updated", "Created"}, findAll(t, b.Todo, model.TodoQuery{})); diff != "" {
+		t.Errorf("FindAll() after ApplyBatch (-want +got):\n%s", diff)
+	}
+}
+
+func testChanges(t *testing.T, b Backend) {
+	ctx := context.Background()
+	findChanges := func(since int64) *model.TodoChanges {
+		t.Helper()
+		changes, err := b.Todo.FindChanges(ctx, since)
+		if err != nil {
+			t.Fatalf("FindChanges(%d) error = %v", since, err)
+		}
+		return changes
+	}
+	changedIDs := func(changes *model.TodoChanges) []string {
+		ids := []string{}
+		for _, todo := range changes.Todos {
+			ids = append(ids, todo.ID)
+		}
+		for _, tombstone := range changes.Purged {
+			ids = append(ids, "purged:"+tombstone.ID)
+		}
+		return ids
+	}
+
+	kept := create(t, b.Todo, newTodo("Kept", model.TodoStatusTodo))
+	updated := create(t, b.Todo, newTodo("Updated", model.TodoStatusTodo))
+	purged := create(t, b.Todo, newTodo("Purged", model.TodoStatusTodo))
+	initial := findChanges(0)
+	if len(initial.Todos) < 3 || len(initial.Purged) != 0 {
+		t.Errorf("FindChanges(0) = %v, want all todos", changedIDs(initial))
+	}
+
+	// 同期トークン以降に変更、ゴミ箱への移動、完全な削除をしたTodoだけを変更した順に返す
+	updated.Title = "Updated again"
+	if _, err := b.Todo.Update(ctx, updated.ID, *updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := b.Todo.Delete(ctx, purged.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	trashed := findChanges(initial.Token)
+	if diff := cmp.Diff([]string{updated.ID, purged.ID}, changedIDs(trashed)); diff != "" {
+		t.Errorf("FindChanges() after Update and Delete (-want +got):\n%s", diff)
+	}
+	if len(trashed.Todos) == 2 && trashed.Todos[1].DeletedAt == nil {
+		t.Errorf("FindChanges() trashed todo deleted_at = nil, want set")
+	}
+	if trashed.Token < initial.Token {
+		t.Errorf("FindChanges() token = %d, want at least %d", trashed.Token, initial.Token)
+	}
+
+	if err := b.Todo.Purge(ctx, purged.ID); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	removed := findChanges(trashed.Token)
+	if diff := cmp.Diff([]string{"purged:" + purged.ID}, changedIDs(removed)); diff != "" {
+		t.Errorf("FindChanges() after Purge (-want +got):\n%s", diff)
+	}
+	if len(removed.Purged) == 1 && removed.Purged[0].DeletedAt.IsZero() {
+		t.Errorf("FindChanges() purged deleted_at is zero, want set")
+	}
+
+	// 変更がなければ何も返さず、同期トークンは戻らない
+	unchanged := findChanges(removed.Token)
+	if len(unchanged.Todos) != 0 || len(unchanged.Purged) != 0 || unchanged.Token < removed.Token {
+		t.Errorf("FindChanges() without changes = %v (token %d), want none (token >= %d)",
+			changedIDs(unchanged), unchanged.Token, removed.Token)
+	}
+	if all := findChanges(0); !slices.Contains(changedIDs(all), kept.ID) {
+		t.Errorf("FindChanges(0) = %v, want to contain %s", changedIDs(all), kept.ID)
+	}
+}
+
+func testVersioning(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Versioned", model.TodoStatusTodo))
+	update := *todo
+	update.Title = "Renamed"
+	if _, err := b.Todo.Update(ctx, todo.ID, update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := b.Todo.Archive(ctx, todo.ID); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if err := b.Todo.Delete(ctx, todo.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	restored, err := b.Todo.Restore(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.Version != 5 {
+		t.Errorf("Restore() version = %d, want 5", restored.Version)
+	}
+
+	histories, err := b.TodoHistory.FindByTodoID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("FindByTodoID() error = %v", err)
+	}
+	var got []string
+	for _, h := range histories {
+		got = append(got, fmt.Sprintf("%d:%s", h.Version, h.Action))
+	}
+	want := []string{"1:create", "2:update", "3:archive", "4:delete", "5:restore"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FindByTodoID() (-want +got):\n%s", diff)
+	}
+	h, err := b.TodoHistory.FindByVersion(ctx, todo.ID, 2)
+	if err != nil {
+		t.Fatalf("FindByVersion() error = %v", err)
+	}
+	if h.Snapshot.Title != "Renamed" || h.Snapshot.Version != 2 {
+		t.Errorf("FindByVersion() snapshot = %+v, want the todo at version 2", h.Snapshot)
+	}
+	_, err = b.TodoHistory.FindByVersion(ctx, todo.ID, 99)
+	wantErr(t, "FindByVersion()", err, model.ErrNotFound)
+}
+
+func testConcurrency(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Contended", model.TodoStatusTodo))
+
+	const writers = 10
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Go(func() {
+			// バージョンを指定しない更新は確認せずに適用する
+			update := *todo
+			update.Title = fmt.Sprintf("Writer %d", i)
+			update.Version = 0
+			if _, err := b.Todo.Update(ctx, todo.ID, update); err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	// 並行した更新は直列化され、バージョンの更新は失われない
+	got, err := b.Todo.FindByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got.Version != 1+writers {
+		t.Errorf("FindByID() version = %d, want %d", got.Version, 1+writers)
+	}
+	histories, err := b.TodoHistory.FindByTodoID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("FindByTodoID() error = %v", err)
+	}
+	if len(histories) != 1+writers {
+		t.Errorf("FindByTodoID() returned %d histories, want %d", len(histories), 1+writers)
+	}
+}
+
+func testConcurrentBaseVersion(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Contended", model.TodoStatusTodo))
+
+	// 同じバージョンを元にした2つの更新を交互に行うと、後の更新は先の更新を上書きせずに競合する
+	first, second := *todo, *todo
+	first.Title = "First"
+	second.Content = "Second"
+	if _, err := b.Todo.Update(ctx, todo.ID, first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	_, err := b.Todo.Update(ctx, todo.ID, second)
+	wantErr(t, "Update()", err, model.ErrConflict)
+
+	got, err := b.Todo.FindByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got.Title != "First" || got.Content != "" || got.Version != 2 {
+		t.Errorf("FindByID() = %+v, want only the first update at version 2", got)
+	}
+
+	// 同じバージョンを元にした並行した更新は、1つのみ適用される
+	const writers = 10
+	var (
+		wg      sync.WaitGroup
+		applied atomic.Int32
+	)
+	for i := range writers {
+		wg.Go(func() {
+			update := *got
+			update.Title = fmt.Sprintf("Writer %d", i)
+			_, err := b.Todo.Update(ctx, todo.ID, update)
+			switch {
+			case err == nil:
+				applied.Add(1)
+			case !errors.Is(err, model.ErrConflict):
+				t.Errorf("Update() error = %v, want %v", err, model.ErrConflict)
+			}
+		})
+	}
+	wg.Wait()
+	if n := applied.Load(); n != 1 {
+		t.Errorf("Update() applied %d concurrent updates, want 1", n)
+	}
+
+	// 一括操作の更新も同様に確認する
+	stale := *got
+	stale.Title = "Stale"
+	_, err = b.Todo.ApplyBatch(ctx, []model.TodoWrite{{Kind: model.TodoWriteUpdate, ID: todo.ID, Todo: stale}})
+	wantErr(t, "ApplyBatch()", err, model.ErrConflict)
+}
+
+func testTouch(t *testing.T, b Backend) {
+	ctx := context.Background()
+	todo := create(t, b.Todo, newTodo("Blocked", model.TodoStatusTodo))
+	blocker := create(t, b.Todo, newTodo("Blocker", model.TodoStatusTodo))
+	trashed := create(t, b.Todo, newTodo("Trashed", model.TodoStatusTodo))
+	if err := b.Todo.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// 依存関係の変更はTodoの更新として、バージョンと更新日時を更新して変更履歴を記録する (ゴミ箱にあるTodoは更新しない)
+	if err := b.TodoDependency.Add(ctx, todo.ID, blocker.ID); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	touched, err := b.Todo.Touch(ctx, []model.Todo{*todo, *trashed})
+	if err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+	if len(touched) != 1 || touched[0].ID != todo.ID || touched[0].Version != 2 || touched[0].UpdatedAt.Before(todo.UpdatedAt) {
+		t.Fatalf("Touch() = %+v, want only the blocked todo at version 2", touched)
+	}
+	if diff := cmp.Diff([]string{blocker.ID}, touched[0].BlockedBy); diff != "" {
+		t.Errorf("Touch() blocked_by mismatch (-want +got):\n%s", diff)
+	}
+	got, err := b.Todo.FindByID(ctx, todo.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got.Version != 2 {
+		t.Errorf("FindByID() version = %d, want 2", got.Version)
+	}
+
+	h, err := b.TodoHistory.FindByVersion(ctx, todo.ID, 2)
+	if err != nil {
+		t.Fatalf("FindByVersion() error = %v", err)
+	}
+	if h.Action != model.HistoryActionUpdate || len(h.Changes) != 1 || h.Changes[0].Field != "blocked_by" {
+		t.Errorf("FindByVersion() = %+v, want an update of blocked_by", h)
+	}
+}
+
+func testTransaction(t *testing.T, b Backend) {
+	if b.TxManager == nil {
+		t.Skip("multi-operation transactions are not supported")
+	}
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+	seed := create(t, b.Todo, newTodo("Seed", model.TodoStatusTodo))
+
+	// コミットした変更は読み取れ、トランザクションの中では自身の書き込みを読み取れる
+	var committed *model.Todo
+	err := b.TxManager.Do(ctx, func(ctx context.Context) error {
+		created, err := b.Todo.Create(ctx, newTodo("Committed", model.TodoStatusTodo))
+		if err != nil {
+			return err
+		}
+		if _, err := b.Todo.FindByID(ctx, created.ID); err != nil {
+			return fmt.Errorf("FindByID() in transaction: %w", err)
+		}
+		committed = created
+		return b.TodoDependency.Add(ctx, seed.ID, created.ID)
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// fn がエラーを返した場合は、全ての変更を取り消す
+	err = b.TxManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := b.Todo.Create(ctx, newTodo("RolledBack", model.TodoStatusTodo)); err != nil {
+			return err
+		}
+		updated := *seed
+		updated.Title = "Renamed"
+		if _, err := b.Todo.Update(ctx, seed.ID, updated); err != nil {
+			return err
+		}
+		if err := b.TodoDependency.Remove(ctx, seed.ID, committed.ID); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	wantErr(t, "Do()", err, errRollback)
+
+	// 入れ子の呼び出しがエラーを返した場合は、その呼び出しでの変更のみを取り消す
+	err = b.TxManager.Do(ctx, func(ctx context.Context) error {
+		if _, err := b.Todo.Create(ctx, newTodo("Outer", model.TodoStatusTodo)); err != nil {
+			return err
+		}
+		err := b.TxManager.Do(ctx, func(ctx context.Context) error {
+			if _, err := b.Todo.Create(ctx, newTodo("Inner", model.TodoStatusTodo)); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			return fmt.Errorf("nested Do() error = %v, want %v", err, errRollback)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"Seed", "Committed", "Outer"}, findAll(t, b.Todo, model.TodoQuery{})); diff != "" {
+		t.Errorf("FindAll() mismatch (-want +got):\n%s", diff)
+	}
+	got, err := b.Todo.FindByID(ctx, seed.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if got.Title != "Seed" || got.Version != 1 {
+		t.Errorf("FindByID() after rollback title = %q, version = %d, want %q, 1", got.Title, got.Version, "Seed")
+	}
+	if diff := cmp.Diff([]string{committed.ID}, got.BlockedBy); diff != "" {
+		t.Errorf("FindByID() blocked_by mismatch (-want +got):\n%s", diff)
+	}
+	histories, err := b.TodoHistory.FindByTodoID(ctx, seed.ID)
+	if err != nil {
+		t.Fatalf("FindByTodoID() error = %v", err)
+	}
+	if len(histories) != 1 {
+		t.Errorf("FindByTodoID() after rollback returned %d histories, want 1", len(histories))
+	}
+}
+
+func testOutbox(t *testing.T, b Backend) {
+	if b.Outbox == nil {
+		t.Skip("outbox is not implemented")
+	}
+	ctx := context.Background()
+	a := create(t, b.Todo, newTodo("A", model.TodoStatusTodo))
+	c := create(t, b.Todo, newTodo("C", model.TodoStatusTodo))
+	events := []model.TodoEvent{
+		model.NewTodoEvent(ctx, model.TodoEventCreated, a),
+		model.NewTodoEvent(ctx, model.TodoEventCreated, c),
+		model.NewTodoEvent(ctx, model.TodoEventUpdated, a),
+	}
+	if err := b.Outbox.Add(ctx, events); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if b.TxManager != nil {
+		// 書き込みを取り消した場合は、イベントも保存しない
+		err := b.TxManager.Do(ctx, func(ctx context.Context) error {
+			if err := b.Outbox.Add(ctx, []model.TodoEvent{model.NewTodoEvent(ctx, model.TodoEventDeleted, c)}); err != nil {
+				return err
+			}
+			return errors.New("rollback")
+		})
+		if err == nil {
+			t.Fatal("Do() error = nil, want rollback")
+		}
+	}
+
+	now := time.Now()
+	pending := findPending(t, b.Outbox, now, 10)
+	if diff := cmp.Diff([]string{"A:todo.created", "C:todo.created", "A:todo.updated"}, pending.events()); diff != "" {
+		t.Fatalf("FindPending() mismatch (-want +got):\n%s", diff)
+	}
+	for i, m := range pending {
+		if m.Event.ID == "" {
+			t.Errorf("FindPending()[%d].Event.ID is empty", i)
+		}
+		if i > 0 && m.Seq <= pending[i-1].Seq {
+			t.Errorf("FindPending()[%d].Seq = %d, want greater than %d", i, m.Seq, pending[i-1].Seq)
+		}
+	}
+	if diff := cmp.Diff([]string{"A:todo.created"}, findPending(t, b.Outbox, now, 1).events()); diff != "" {
+		t.Errorf("FindPending(limit 1) mismatch (-want +got):\n%s", diff)
+	}
+
+	// 配信を再試行するまでの間は、同じTodoの後続のイベントも返さない
+	retryAt := now.Add(time.Minute)
+	if err := b.Outbox.MarkFailed(ctx, pending[0].Seq, "unavailable", retryAt); err != nil {
+		t.Fatalf("MarkFailed() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"C:todo.created"}, findPending(t, b.Outbox, now, 10).events()); diff != "" {
+		t.Errorf("FindPending() while retrying mismatch (-want +got):\n%s", diff)
+	}
+	retried := findPending(t, b.Outbox, retryAt.Add(time.Second), 10)
+	if diff := cmp.Diff([]string{"A:todo.created", "C:todo.created", "A:todo.updated"}, retried.events()); diff != "" {
+		t.Errorf("FindPending() after retry delay mismatch (-want +got):\n%s", diff)
+	}
+	if retried[0].Attempts != 1 || retried[0].LastError != "unavailable" {
+		t.Errorf("FindPending()[0] attempts = %d, last error = %q, want 1, %q", retried[0].Attempts, retried[0].LastError, "unavailable")
+	}
+
+	if err := b.Outbox.Delete(ctx, pending[1].Seq); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"A:todo.created", "A:todo.updated"}, findPending(t, b.Outbox, retryAt, 10).events()); diff != "" {
+		t.Errorf("FindPending() after Delete mismatch (-want +got):\n%s", diff)
+	}
+	wantErr(t, "MarkFailed(deleted)", b.Outbox.MarkFailed(ctx, pending[1].Seq, "unavailable", retryAt), model.ErrNotFound)
+}
+
+// outboxMessages は配信待ちのイベントの一覧
+type outboxMessages []model.OutboxMessage
+
+// events はイベントを "Todoのタイトル:イベントの種類" の形式で返す
+func (ms outboxMessages) events() []string {
+	s := make([]string, len(ms))
+	for i, m := range ms {
+		s[i] = m.Event.Todo.Title + ":" + string(m.Event.Type)
+	}
+	return s
+}
+
+// findPending は配信待ちのイベントを返し、失敗した場合はテストを終了する
+func findPending(t *testing.T, outbox repository.Outbox, now time.Time, limit int) outboxMessages {
+	t.Helper()
+	messages, err := outbox.FindPending(context.Background(), now, limit)
+	if err != nil {
+		t.Fatalf("FindPending() error = %v", err)
+	}
+	return messages
+}
+
+func testWebhook(t *testing.T, b Backend) {
+	if b.Webhook == nil || b.WebhookDelivery == nil {
+		t.Skip("webhooks are not implemented")
+	}
+	ctx := context.Background()
+
+	all, err := b.Webhook.Create(ctx, model.Webhook{URL: "http://example.com/all", Secret: "whsec_all"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	filtered, err := b.Webhook.Create(ctx, model.Webhook{
+		URL:    "http://example.com/filtered",
+		Events: []model.TodoEventType{model.TodoEventCompleted},
+		Secret: "whsec_filtered",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	updated, err := b.Webhook.Update(ctx, all.ID, model.Webhook{URL: "http://example.com/updated", Secret: "whsec_updated"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.URL != "http://example.com/updated" || updated.Secret != "whsec_updated" {
+		t.Errorf("Update() url = %q, secret = %q", updated.URL, updated.Secret)
+	}
+	_, err = b.Webhook.Update(ctx, missingID, model.Webhook{URL: "http://example.com"})
+	wantErr(t, "Update(missing)", err, model.ErrNotFound)
+	_, err = b.Webhook.FindByID(ctx, missingID)
+	wantErr(t, "FindByID(missing)", err, model.ErrNotFound)
+
+	webhooks, err := b.Webhook.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	urls := make([]string, len(webhooks))
+	for i, w := range webhooks {
+		urls[i] = w.URL
+	}
+	if diff := cmp.Diff([]string{"http://example.com/updated", "http://example.com/filtered"}, urls); diff != "" {
+		t.Errorf("FindAll() mismatch (-want +got):\n%s", diff)
+	}
+	got, err := b.Webhook.FindByID(ctx, filtered.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if diff := cmp.Diff([]model.TodoEventType{model.TodoEventCompleted}, got.Events); diff != "" || got.Secret != "whsec_filtered" {
+		t.Errorf("FindByID() events mismatch (-want +got):\n%s, secret = %q", diff, got.Secret)
+	}
+
+	// 配信
+	now := time.Now()
+	newDelivery := func(webhookID, eventID string, nextAttemptAt time.Time) *model.WebhookDelivery {
+		t.Helper()
+		d, err := model.NewWebhookDelivery(webhookID, model.TodoEvent{ID: eventID, Type: model.TodoEventCreated}, nextAttemptAt)
+		if err != nil {
+			t.Fatalf("NewWebhookDelivery() error = %v", err)
+		}
+		created, err := b.WebhookDelivery.Create(ctx, *d)
+		if err != nil {
+			t.Fatalf("Create(%s, %s) error = %v", webhookID, eventID, err)
+		}
+		return created
+	}
+	first := newDelivery(all.ID, "event-1", now.Add(-time.Second))
+	later := newDelivery(all.ID, "event-2", now.Add(time.Minute))
+	other := newDelivery(filtered.ID, "event-1", now.Add(-2*time.Second))
+
+	d, _ := model.NewWebhookDelivery(all.ID, model.TodoEvent{ID: "event-1"}, now)
+	_, err = b.WebhookDelivery.Create(ctx, *d)
+	wantErr(t, "Create(duplicate event)", err, model.ErrConflict)
+	d.WebhookID = missingID
+	_, err = b.WebhookDelivery.Create(ctx, *d)
+	wantErr(t, "Create(missing webhook)", err, model.ErrNotFound)
+
+	if diff := cmp.Diff([]string{other.ID, first.ID}, findDue(t, b.WebhookDelivery, now, 10)); diff != "" {
+		t.Errorf("FindDue() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{other.ID}, findDue(t, b.WebhookDelivery, now, 1)); diff != "" {
+		t.Errorf("FindDue(limit 1) mismatch (-want +got):\n%s", diff)
+	}
+
+	// 失敗した配信は再試行する日時まで、成功した配信はそれ以降は配信待ちとしない
+	first.RecordAttempt(now, 500, nil)
+	if _, err := b.WebhookDelivery.Update(ctx, *first); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	other.RecordAttempt(now, 200, nil)
+	if _, err := b.WebhookDelivery.Update(ctx, *other); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	_, err = b.WebhookDelivery.Update(ctx, model.WebhookDelivery{ID: missingID, Status: model.WebhookDeliveryDead})
+	wantErr(t, "Update(missing)", err, model.ErrNotFound)
+	if diff := cmp.Diff([]string{}, findDue(t, b.WebhookDelivery, now, 10)); diff != "" {
+		t.Errorf("FindDue() after Update mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{first.ID, later.ID}, findDue(t, b.WebhookDelivery, now.Add(time.Hour), 10)); diff != "" {
+		t.Errorf("FindDue() after retry delay mismatch (-want +got):\n%s", diff)
+	}
+
+	deliveries, err := b.WebhookDelivery.FindByWebhookID(ctx, all.ID, 10)
+	if err != nil {
+		t.Fatalf("FindByWebhookID() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{later.ID, first.ID}, deliveryIDs(deliveries)); diff != "" {
+		t.Fatalf("FindByWebhookID() mismatch (-want +got):\n%s", diff)
+	}
+	got1 := deliveries[1]
+	if got1.Status != model.WebhookDeliveryPending || got1.Attempts != 1 || got1.ResponseStatus != 500 || got1.LastError == "" {
+		t.Errorf("FindByWebhookID()[1] = status %q, attempts %d, response %d, last error %q", got1.Status, got1.Attempts, got1.ResponseStatus, got1.LastError)
+	}
+	var event model.TodoEvent
+	if err := json.Unmarshal(got1.Payload, &event); err != nil || event.ID != "event-1" {
+		t.Errorf("FindByWebhookID()[1].Payload = %s (error %v), want event-1", got1.Payload, err)
+	}
+	limited, err := b.WebhookDelivery.FindByWebhookID(ctx, all.ID, 1)
+	if err != nil {
+		t.Fatalf("FindByWebhookID() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{later.ID}, deliveryIDs(limited)); diff != "" {
+		t.Errorf("FindByWebhookID(limit 1) mismatch (-want +got):\n%s", diff)
+	}
+
+	// Webhookを削除すると配信も削除する
+	if err := b.Webhook.Delete(ctx, all.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	wantErr(t, "Delete(deleted)", b.Webhook.Delete(ctx, all.ID), model.ErrNotFound)
+	deliveries, err = b.WebhookDelivery.FindByWebhookID(ctx, all.ID, 10)
+	if err != nil {
+		t.Fatalf("FindByWebhookID() error = %v", err)
+	}
+	if len(deliveries) != 0 {
+		t.Errorf("FindByWebhookID() after Delete returned %d deliveries, want 0", len(deliveries))
+	}
+	if diff := cmp.Diff([]string{}, findDue(t, b.WebhookDelivery, now.Add(time.Hour), 10)); diff != "" {
+		t.Errorf("FindDue() after Delete mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// findDue は配信待ちの配信のIDを返し、失敗した場合はテストを終了する
+func findDue(t *testing.T, repo repository.WebhookDelivery, now time.Time, limit int) []string {
+	t.Helper()
+	deliveries, err := repo.FindDue(context.Background(), now, limit)
+	if err != nil {
+		t.Fatalf("FindDue() error = %v", err)
+	}
+	return deliveryIDs(deliveries)
+}
+
+// deliveryIDs は配信のIDの一覧を返す
+func deliveryIDs(deliveries []model.WebhookDelivery) []string {
+	ids := make([]string, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+	}
+	return ids
+}
@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// timestampFormat は日時を TEXT のカラムに保存する形式
+// 固定長の UTC の文字列のため、文字列の比較で日時の大小を比較できる (STRFTIME('%Y-%m-%dT%H:%M:%fZ') と同じ形式)
+const timestampFormat = "2006-01-02T15:04:05.000Z"
+
+// nowExpr は現在日時を timestampFormat の形式で返す SQL の式
+// 1つの文の中では同じ値を返すため、1つの文で更新した行には同じ日時が設定される
+const nowExpr = "STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')"
+
+// querier は sql.DB と sql.Tx の共通のクエリ実行インターフェース
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// inTx は fn を1つのトランザクションで実行する (fn がエラーを返した場合はロールバックする)
+// ctx に TxManager のトランザクションがある場合は、セーブポイントを使ってそのトランザクションの中で実行する
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return inSavepoint(ctx, tx, fn)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// inSavepoint は fn をセーブポイントの中で実行する (fn がエラーを返した場合はセーブポイントまでロールバックする)
+// 同じ名前のセーブポイントは入れ子にでき、ROLLBACK TO と RELEASE は最も内側のセーブポイントを対象とする
+func inSavepoint(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT sp"); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_, _ = tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO sp")
+		_, _ = tx.ExecContext(context.WithoutCancel(ctx), "RELEASE sp")
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "RELEASE sp")
+	return err
+}
+
+// connFrom は ctx に TxManager のトランザクションがあればそれを、なければ db を返す
+func connFrom(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// timestamp は日時を timestampFormat の形式に変換する
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timestampFormat)
+}
+
+// nullTimestamp は nil になりうる日時をカラムの値に変換する (nil の場合は NULL)
+func nullTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// nullString は nil になりうる文字列をカラムの値に変換する (nil の場合は NULL)
+func nullString(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// timeScanner は timestampFormat の形式の TEXT のカラムを *time.Time に読み取る (NULL の場合は nil)
+type timeScanner struct {
+	dst **time.Time
+}
+
+// Scan は sql.Scanner の実装
+func (s timeScanner) Scan(src any) error {
+	if src == nil {
+		*s.dst = nil
+		return nil
+	}
+	v, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("unsupported timestamp type %T", src)
+	}
+	t, err := time.Parse(timestampFormat, v)
+	if err != nil {
+		return err
+	}
+	*s.dst = &t
+	return nil
+}
+
+// scanTime は NULL にならない日時のカラムを読み取る sql.Scanner を返す
+func scanTime(dst *time.Time) sql.Scanner {
+	return scanFunc(func(src any) error {
+		var t *time.Time
+		if err := (timeScanner{&t}).Scan(src); err != nil {
+			return err
+		}
+		if t == nil {
+			return errors.New("timestamp is null")
+		}
+		*dst = *t
+		return nil
+	})
+}
+
+// scanJSON は JSON の TEXT のカラムを dst に読み取る sql.Scanner を返す
+func scanJSON(dst any) sql.Scanner {
+	return scanFunc(func(src any) error {
+		switch v := src.(type) {
+		case string:
+			return json.Unmarshal([]byte(v), dst)
+		case []byte:
+			return json.Unmarshal(v, dst)
+		default:
+			return fmt.Errorf("unsupported JSON type %T", src)
+		}
+	})
+}
+
+// scanFunc は関数を sql.Scanner として扱う
+type scanFunc func(src any) error
+
+// Scan は sql.Scanner の実装
+func (f scanFunc) Scan(src any) error {
+	return f(src)
+}
+
+// isConstraintError は err が code の制約違反のエラーかを判定する
+func isConstraintError(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// isUniqueViolation は err が一意制約違反のエラーかを判定する
+func isUniqueViolation(err error) bool {
+	return isConstraintError(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+// isForeignKeyViolation は err が外部キー制約違反のエラーかを判定する
+func isForeignKeyViolation(err error) bool {
+	return isConstraintError(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
+
+// rowsAffectedOrNotFound は更新または削除した行がない場合に model.ErrNotFound を返す
+func rowsAffectedOrNotFound(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// IdempotencyKey はSQLiteを使った冪等キーの実装
+type IdempotencyKey struct {
+	db *sql.DB
+}
+
+// NewIdempotencyKey は repository.IdempotencyKey のコンストラクタ
+func NewIdempotencyKey(db *sql.DB) repository.IdempotencyKey {
+	return &IdempotencyKey{
+		db: db,
+	}
+}
+
+// Reserve は冪等キーが存在しない場合に処理中として ttl の間保存し、true を返す
+// 有効期限を過ぎた冪等キーは新しいリクエストで上書きする
+func (r *IdempotencyKey) Reserve(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	now := time.Now()
+	result, err := connFrom(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO idempotency_key (request_key, fingerprint, created_at, expires_at) VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (request_key) DO UPDATE SET
+			fingerprint = excluded.fingerprint, status_code = NULL, content_type = '', body = NULL,
+			created_at = excluded.created_at, expires_at = excluded.expires_at
+		WHERE idempotency_key.expires_at <= ?3`,
+		key, fingerprint, timestamp(now), timestamp(now.Add(ttl)))
+	if err != nil {
+		return nil, false, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, false, err
+	} else if n > 0 {
+		return nil, true, nil
+	}
+
+	rec := model.IdempotencyRecord{Key: key}
+	var statusCode *int
+	if err := connFrom(ctx, r.db).QueryRowContext(ctx,
+		"SELECT fingerprint, status_code, content_type, body, expires_at FROM idempotency_key WHERE request_key = ?",
+		key).Scan(&rec.Fingerprint, &statusCode, &rec.ContentType, &rec.Body, scanTime(&rec.ExpiresAt)); err != nil {
+		return nil, false, err
+	}
+	if statusCode != nil {
+		rec.Completed = true
+		rec.StatusCode = *statusCode
+	}
+	return &rec, false, nil
+}
+
+// Complete は処理中の冪等キーにレスポンスを保存する
+func (r *IdempotencyKey) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx,
+		"UPDATE idempotency_key SET status_code = ?, content_type = ?, body = ? WHERE request_key = ? AND status_code IS NULL",
+		statusCode, contentType, body, key)
+	if err != nil {
+		return err
+	}
+	return rowsAffectedOrNotFound(result)
+}
+
+// Release は処理中の冪等キーを削除する
+func (r *IdempotencyKey) Release(ctx context.Context, key string) error {
+	_, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM idempotency_key WHERE request_key = ? AND status_code IS NULL", key)
+	return err
+}
+
+// DeleteExpired は before の時点で有効期限を過ぎた冪等キーを削除し、削除した件数を返す
+func (r *IdempotencyKey) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM idempotency_key WHERE expires_at <= ?", timestamp(before))
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+)
+
+// migrations は postgres/init に対応するマイグレーション (ファイル名の順に適用する)
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrate は未適用のマイグレーションを適用する
+// 適用済みのマイグレーションの数は PRAGMA user_version に記録する
+func Migrate(ctx context.Context, db *sql.DB) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	var applied int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&applied); err != nil {
+		return err
+	}
+	for i, name := range names {
+		if i < applied {
+			continue
+		}
+		stmt, err := migrations.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := inTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, string(stmt)); err != nil {
+				return err
+			}
+			// PRAGMA はプレースホルダを使えないため、値を埋め込む
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrate %s: %w", name, err)
+		}
+		slog.Info("SQLite migration applied", slog.String("name", name))
+	}
+	return nil
+}
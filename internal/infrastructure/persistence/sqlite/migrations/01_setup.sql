@@ -0,0 +1,46 @@
+-- postgres/init/01_setup.sql と 02_todo_attributes.sql を合わせた todo テーブルに対応する SQLite のスキーマ
+-- UUID は TEXT、日時は固定長の UTC の文字列 (YYYY-MM-DDTHH:MM:SS.SSSZ) の TEXT で保存し、文字列の比較で大小を比較する
+-- 全文検索の 2-gram はアプリケーション側で判定するため、search_vector に対応するカラムはない
+
+-- ToDo
+CREATE TABLE IF NOT EXISTS todo (
+  id TEXT PRIMARY KEY -- ID
+  , title TEXT NOT NULL -- タイトル
+  , content TEXT -- 内容
+  , status TEXT NOT NULL DEFAULT 'todo' -- ステータス
+  , priority INTEGER NOT NULL DEFAULT 0 -- 優先度 (0-4)
+  , done INTEGER GENERATED ALWAYS AS (status = 'done') STORED -- 完了フラグ (status から導出)
+  , parent_id TEXT REFERENCES todo (id) ON DELETE CASCADE -- 親 ToDo ID
+  , position INTEGER NOT NULL DEFAULT 0 -- 兄弟間での並び順
+  , auto_complete INTEGER NOT NULL DEFAULT 0 -- 子が全て完了したら自動で完了にするか
+  , due_at TEXT -- 期限
+  , recurrence TEXT NOT NULL DEFAULT '' -- 繰り返しのルール (RFC 5545 の RRULE、空の場合は繰り返さない)
+  , timezone TEXT NOT NULL DEFAULT 'UTC' -- 繰り返しの計算に使うタイムゾーン (IANA)
+  , series_id TEXT -- 繰り返しの系列 ID
+  , occurrence_at TEXT -- 繰り返しの系列内での発生日時
+  , completed_at TEXT -- done になった日時 (status から導出)
+  , archived_at TEXT -- アーカイブした日時 (NULL の場合はアーカイブされていない)
+  , deleted_at TEXT -- ゴミ箱に移動した日時 (NULL の場合はゴミ箱にない)
+  , version INTEGER NOT NULL DEFAULT 1 -- バージョン
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , updated_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 更新日時
+  , CONSTRAINT todo_status_check CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled'))
+  , CONSTRAINT todo_priority_check CHECK (priority BETWEEN 0 AND 4)
+  , CONSTRAINT todo_recurrence_check CHECK (recurrence = '' OR due_at IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_parent_id ON todo (parent_id, position);
+CREATE INDEX IF NOT EXISTS idx_todo_series_id ON todo (series_id, occurrence_at);
+CREATE INDEX IF NOT EXISTS idx_todo_completed_at ON todo (completed_at) WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_todo_deleted_at ON todo (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_todo_updated_at ON todo (updated_at);
+
+-- バージョンと更新日時を更新するトリガー
+-- SQLite の BEFORE トリガーでは NEW を書き換えられないため、更新した行を AFTER トリガーで更新する
+-- (recursive_triggers は無効のため、このトリガーの UPDATE で再びトリガーは起動しない)
+CREATE TRIGGER IF NOT EXISTS trg_todo_version_updated_at
+AFTER UPDATE ON todo FOR EACH ROW
+BEGIN
+  UPDATE todo SET version = OLD.version + 1, updated_at = STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')
+  WHERE id = NEW.id;
+END;
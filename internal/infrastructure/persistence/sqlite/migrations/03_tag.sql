@@ -0,0 +1,14 @@
+-- タグ
+CREATE TABLE IF NOT EXISTS tag (
+  id TEXT PRIMARY KEY -- ID
+  , name TEXT NOT NULL UNIQUE -- タグ名
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+);
+
+-- ToDo とタグの紐付け
+CREATE TABLE IF NOT EXISTS todo_tag (
+  todo_id TEXT NOT NULL REFERENCES todo (id) ON DELETE CASCADE -- ToDo ID
+  , tag_id TEXT NOT NULL REFERENCES tag (id) ON DELETE CASCADE -- タグ ID
+  , PRIMARY KEY (todo_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_tag_tag_id ON todo_tag (tag_id);
@@ -0,0 +1,9 @@
+-- ToDo の依存関係 (todo_id は blocker_id が完了するまで完了できない)
+CREATE TABLE IF NOT EXISTS todo_dependency (
+  todo_id TEXT NOT NULL REFERENCES todo (id) ON DELETE CASCADE -- ブロックされている ToDo ID
+  , blocker_id TEXT NOT NULL REFERENCES todo (id) ON DELETE CASCADE -- ブロックしている ToDo ID
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , PRIMARY KEY (todo_id, blocker_id)
+  , CONSTRAINT todo_dependency_self_check CHECK (todo_id <> blocker_id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_dependency_blocker_id ON todo_dependency (blocker_id);
@@ -0,0 +1,29 @@
+-- ToDo の変更履歴 (追記のみ。ToDo を完全に削除しても残す)
+CREATE TABLE IF NOT EXISTS todo_history (
+  id TEXT PRIMARY KEY -- ID
+  , todo_id TEXT NOT NULL -- ToDo ID
+  , version INTEGER NOT NULL -- 変更後の ToDo のバージョン
+  , action TEXT NOT NULL -- 操作の種類
+  , actor TEXT NOT NULL -- 操作者
+  , trace_id TEXT NOT NULL DEFAULT '' -- トレース ID
+  , changes TEXT NOT NULL -- 項目ごとの変更前後の値 (JSON)
+  , snapshot TEXT NOT NULL -- 変更後の ToDo 全体 (JSON)
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , CONSTRAINT todo_history_action_check CHECK (
+    action IN ('create', 'update', 'delete', 'restore', 'archive', 'unarchive')
+  )
+);
+CREATE INDEX IF NOT EXISTS idx_todo_history_todo_id ON todo_history (todo_id, version);
+
+-- 変更履歴の更新と削除を禁止するトリガー
+CREATE TRIGGER IF NOT EXISTS trg_todo_history_forbid_update
+BEFORE UPDATE ON todo_history FOR EACH ROW
+BEGIN
+  SELECT RAISE(ABORT, 'todo_history is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_todo_history_forbid_delete
+BEFORE DELETE ON todo_history FOR EACH ROW
+BEGIN
+  SELECT RAISE(ABORT, 'todo_history is append-only');
+END;
@@ -0,0 +1,8 @@
+-- ビュー (名前を付けて保存した ToDo の検索クエリ)
+CREATE TABLE IF NOT EXISTS todo_view (
+  id TEXT PRIMARY KEY -- ID
+  , name TEXT NOT NULL UNIQUE -- ビュー名
+  , query TEXT NOT NULL DEFAULT '{}' -- 検索クエリ (JSON、期限の相対的な表現は取得する時点で解決する)
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , updated_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 更新日時
+);
@@ -0,0 +1,11 @@
+-- 冪等キー (Idempotency-Key ヘッダーで指定されたリクエストのレスポンス)
+CREATE TABLE IF NOT EXISTS idempotency_key (
+  request_key TEXT PRIMARY KEY -- 操作者ごとに区別した冪等キー
+  , fingerprint TEXT NOT NULL -- リクエストのメソッド、パス、ボディのハッシュ
+  , status_code INTEGER -- レスポンスのステータスコード (処理中の場合は NULL)
+  , content_type TEXT NOT NULL DEFAULT '' -- レスポンスの Content-Type
+  , body BLOB -- レスポンスのボディ
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , expires_at TEXT NOT NULL -- 有効期限
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires_at ON idempotency_key (expires_at);
@@ -0,0 +1,16 @@
+-- 配信待ちの ToDo のドメインイベント (配信したら削除する)
+-- 削除したイベントの順序を再利用しないよう AUTOINCREMENT を使う
+CREATE TABLE IF NOT EXISTS outbox_event (
+  seq INTEGER PRIMARY KEY AUTOINCREMENT -- 保存した順序
+  , id TEXT NOT NULL -- イベント ID
+  , event_type TEXT NOT NULL -- イベントの種類
+  , todo_id TEXT NOT NULL -- ToDo ID
+  , actor TEXT NOT NULL -- 操作者
+  , trace_id TEXT NOT NULL DEFAULT '' -- トレース ID
+  , todo TEXT NOT NULL -- イベントが発生した後の ToDo 全体 (JSON)
+  , occurred_at TEXT NOT NULL -- イベントが発生した日時
+  , attempts INTEGER NOT NULL DEFAULT 0 -- 配信に失敗した回数
+  , last_error TEXT NOT NULL DEFAULT '' -- 最後に配信に失敗した理由
+  , available_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 次に配信を試みる日時
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_event_todo_id ON outbox_event (todo_id, seq);
@@ -0,0 +1,28 @@
+-- Webhook の購読
+CREATE TABLE IF NOT EXISTS webhook (
+  id TEXT PRIMARY KEY -- ID
+  , url TEXT NOT NULL -- 配信先の URL
+  , events TEXT NOT NULL DEFAULT '[]' -- 配信するイベントの種類 (JSON、空の場合は全ての種類)
+  , secret TEXT NOT NULL -- 署名に使う秘密鍵
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , updated_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 更新日時
+);
+
+-- Webhook の配信とその結果
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+  id TEXT PRIMARY KEY -- ID (再試行しても変わらない)
+  , webhook_id TEXT NOT NULL REFERENCES webhook (id) ON DELETE CASCADE -- Webhook ID
+  , event_id TEXT NOT NULL -- イベント ID
+  , event_type TEXT NOT NULL -- イベントの種類
+  , payload TEXT NOT NULL -- 配信するリクエストの本文 (JSON)
+  , status TEXT NOT NULL -- 状態 (pending: 配信待ち, succeeded: 成功, dead: 配信を諦めた)
+  , attempts INTEGER NOT NULL DEFAULT 0 -- 配信を試みた回数
+  , response_status INTEGER NOT NULL DEFAULT 0 -- 最後の応答のステータスコード (応答がなかった場合は 0)
+  , last_error TEXT NOT NULL DEFAULT '' -- 最後に配信に失敗した理由
+  , next_attempt_at TEXT -- 次に配信を試みる日時 (配信待ちでない場合は NULL)
+  , created_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 作成日時
+  , updated_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 更新日時
+  , UNIQUE (webhook_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_next_attempt_at ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook_id ON webhook_delivery (webhook_id, created_at);
@@ -0,0 +1,35 @@
+-- postgres/init/11_todo_change.sql に対応する SQLite のスキーマ
+-- SQLite の書き込みは直列化されるため、同期トークンは変更ごとに払い出す連番とする (連番の順序はコミットした順と一致する)
+
+-- ToDo の最後の変更 (差分同期のため。ToDo を完全に削除しても残す)
+CREATE TABLE IF NOT EXISTS todo_change (
+  todo_id TEXT PRIMARY KEY -- ToDo ID
+  , seq INTEGER NOT NULL -- 変更の連番
+  , changed_at TEXT NOT NULL DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ', 'now')) -- 変更日時
+);
+CREATE INDEX IF NOT EXISTS idx_todo_change_seq ON todo_change (seq);
+
+INSERT OR IGNORE INTO todo_change (todo_id, seq) SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) FROM todo;
+
+-- ToDo の変更を todo_change に記録するトリガー
+-- trg_todo_version_updated_at の UPDATE でも起動するため、1 回の更新で 2 回記録する場合がある (最後の連番が残る)
+CREATE TRIGGER IF NOT EXISTS trg_todo_change_insert
+AFTER INSERT ON todo FOR EACH ROW
+BEGIN
+  INSERT OR REPLACE INTO todo_change (todo_id, seq)
+  VALUES (NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM todo_change));
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_todo_change_update
+AFTER UPDATE ON todo FOR EACH ROW
+BEGIN
+  INSERT OR REPLACE INTO todo_change (todo_id, seq)
+  VALUES (NEW.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM todo_change));
+END;
+
+CREATE TRIGGER IF NOT EXISTS trg_todo_change_delete
+AFTER DELETE ON todo FOR EACH ROW
+BEGIN
+  INSERT OR REPLACE INTO todo_change (todo_id, seq)
+  VALUES (OLD.id, (SELECT COALESCE(MAX(seq), 0) + 1 FROM todo_change));
+END;
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Outbox はSQLiteを使ったアウトボックスの実装
+type Outbox struct {
+	db *sql.DB
+}
+
+// NewOutbox は repository.Outbox のコンストラクタ
+func NewOutbox(db *sql.DB) repository.Outbox {
+	return &Outbox{
+		db: db,
+	}
+}
+
+// Add はイベントを配信待ちとして保存する
+func (r *Outbox) Add(ctx context.Context, events []model.TodoEvent) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, e := range events {
+			todo, err := json.Marshal(e.Todo)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO outbox_event (id, event_type, todo_id, actor, trace_id, todo, occurred_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				uuid.NewString(), e.Type, e.TodoID, e.Actor, e.TraceID, string(todo), timestamp(e.OccurredAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPending は配信を試みる日時になった配信待ちのイベントを保存した順に返す
+func (r *Outbox) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx,
+		`SELECT o.seq, o.id, o.event_type, o.todo_id, o.actor, o.trace_id, o.todo, o.occurred_at, o.attempts, o.last_error, o.available_at
+		FROM outbox_event o
+		WHERE o.available_at <= ?1
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_event w WHERE w.todo_id = o.todo_id AND w.seq < o.seq AND w.available_at > ?1
+			)
+		ORDER BY o.seq
+		LIMIT ?2`,
+		timestamp(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []model.OutboxMessage{}
+	for rows.Next() {
+		var m model.OutboxMessage
+		if err := rows.Scan(&m.Seq, &m.Event.ID, &m.Event.Type, &m.Event.TodoID, &m.Event.Actor, &m.Event.TraceID,
+			scanJSON(&m.Event.Todo), scanTime(&m.Event.OccurredAt), &m.Attempts, &m.LastError, scanTime(&m.AvailableAt)); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Delete は配信したイベントを削除する
+func (r *Outbox) Delete(ctx context.Context, seq int64) error {
+	_, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM outbox_event WHERE seq = ?", seq)
+	return err
+}
+
+// MarkFailed は配信に失敗したイベントの失敗回数を増やし、次に配信を試みる日時を設定する
+func (r *Outbox) MarkFailed(ctx context.Context, seq int64, lastError string, nextAttemptAt time.Time) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx,
+		"UPDATE outbox_event SET attempts = attempts + 1, last_error = ?, available_at = ? WHERE seq = ?",
+		lastError, timestamp(nextAttemptAt), seq)
+	if err != nil {
+		return err
+	}
+	return rowsAffectedOrNotFound(result)
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Tag はSQLiteを使ったタグの実装
+type Tag struct {
+	db *sql.DB
+}
+
+// NewTag は repository.Tag のコンストラクタ
+func NewTag(db *sql.DB) repository.Tag {
+	return &Tag{
+		db: db,
+	}
+}
+
+// scanTag は id, name の順で取得した行をタグに変換する
+func scanTag(row interface{ Scan(dest ...any) error }) (*model.Tag, error) {
+	var t model.Tag
+	if err := row.Scan(&t.ID, &t.Name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindAll は全てのタグを名前順で取得する
+func (r *Tag) FindAll(ctx context.Context) ([]model.Tag, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx, "SELECT id, name FROM tag ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// FindByID はIDによるタグの取得
+func (r *Tag) FindByID(ctx context.Context, id string) (*model.Tag, error) {
+	return scanTag(connFrom(ctx, r.db).QueryRowContext(ctx, "SELECT id, name FROM tag WHERE id = ?", id))
+}
+
+// Rename はタグ名を変更する
+func (r *Tag) Rename(ctx context.Context, id string, name string) (*model.Tag, error) {
+	t, err := scanTag(connFrom(ctx, r.db).QueryRowContext(ctx, "UPDATE tag SET name = ? WHERE id = ? RETURNING id, name", name, id))
+	if isUniqueViolation(err) {
+		return nil, model.ErrConflict
+	}
+	return t, err
+}
+
+// Merge は source が付与されたTodoを target に付け替え、source を削除する
+func (r *Tag) Merge(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	var target *model.Tag
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		target, err = scanTag(tx.QueryRowContext(ctx, "SELECT id, name FROM tag WHERE id = ?", targetID))
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO todo_tag (todo_id, tag_id)
+			SELECT todo_id, ?2 FROM todo_tag WHERE tag_id = ?1
+			ON CONFLICT DO NOTHING`,
+			sourceID, targetID); err != nil {
+			return err
+		}
+
+		// todo_tag は ON DELETE CASCADE で削除される
+		result, err := tx.ExecContext(ctx, "DELETE FROM tag WHERE id = ?", sourceID)
+		if err != nil {
+			return err
+		}
+		return rowsAffectedOrNotFound(result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// Delete はタグを削除し、付与されていたTodoからも外す
+func (r *Tag) Delete(ctx context.Context, id string) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM tag WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffectedOrNotFound(result)
+}
@@ -0,0 +1,737 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// todoColumns はTodoを取得する際のカラム一覧 (scanTodo と順番を合わせること)
+const todoColumns = `id, title, COALESCE(content, ''), status, priority, parent_id, position, auto_complete,
+	due_at, recurrence, timezone, series_id, occurrence_at, completed_at, archived_at, deleted_at, version,
+	updated_at, done,
+	(
+		SELECT JSON_GROUP_ARRAY(tag.name ORDER BY tag.name) FROM todo_tag INNER JOIN tag ON todo_tag.tag_id = tag.id
+		WHERE todo_tag.todo_id = todo.id
+	) AS tags,
+	(
+		SELECT JSON_GROUP_ARRAY(todo_dependency.blocker_id ORDER BY todo_dependency.created_at, todo_dependency.rowid)
+		FROM todo_dependency INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+		WHERE todo_dependency.todo_id = todo.id AND blocker.deleted_at IS NULL
+	) AS blocked_by`
+
+// nextPositionQuery は parentIDParam で指定した親の子の末尾の並び順を求めるサブクエリを返す
+func nextPositionQuery(parentIDParam string) string {
+	return "SELECT COALESCE(MAX(position) + 1, 0) FROM todo WHERE parent_id IS " + parentIDParam
+}
+
+// insertTodoQuery はTodoを作成するクエリ (引数は insertTodoArgs で組み立てる)
+var insertTodoQuery = `INSERT INTO todo (
+		id, title, content, status, priority, parent_id, position, auto_complete,
+		due_at, recurrence, timezone, series_id, occurrence_at, completed_at
+	)
+	VALUES (
+		?1, ?2, ?3, ?4, ?5, ?6, (` + nextPositionQuery("?6") + `), ?7, ?8, ?9, ?10, ?11, ?12,
+		CASE WHEN ?4 = 'done' THEN ` + nowExpr + ` END
+	)`
+
+// insertTodoArgs は insertTodoQuery の引数を返す
+func insertTodoArgs(id string, todo model.Todo) []any {
+	return []any{
+		id, todo.Title, todo.Content, todo.Status, todo.Priority, nullString(todo.ParentID), todo.AutoComplete,
+		nullTimestamp(todo.DueAt), todo.Recurrence, todo.Timezone, nullString(todo.SeriesID), nullTimestamp(todo.OccurrenceAt),
+	}
+}
+
+// updateTodoQuery はTodoを更新するクエリ (引数は updateTodoArgs で組み立てる)
+// 親が変わった場合は新しい兄弟の末尾に移動する。バージョンと更新日時はトリガーで更新する
+var updateTodoQuery = `UPDATE todo SET title = ?2, content = ?3, status = ?4, priority = ?6, parent_id = ?5, auto_complete = ?7,
+		due_at = ?8, recurrence = ?9, timezone = ?10, series_id = ?11, occurrence_at = ?12,
+		completed_at = CASE WHEN ?4 = 'done' THEN COALESCE(completed_at, ` + nowExpr + `) END,
+		position = CASE WHEN parent_id IS NOT ?5 THEN (` + nextPositionQuery("?5") + `) ELSE position END
+	WHERE id = ?1 AND deleted_at IS NULL`
+
+// updateTodoArgs は updateTodoQuery の引数を返す
+func updateTodoArgs(id string, todo model.Todo) []any {
+	return []any{
+		id, todo.Title, todo.Content, todo.Status, nullString(todo.ParentID), todo.Priority, todo.AutoComplete,
+		nullTimestamp(todo.DueAt), todo.Recurrence, todo.Timezone, nullString(todo.SeriesID), nullTimestamp(todo.OccurrenceAt),
+	}
+}
+
+// trashTodoQuery は ?1 のTodoを子孫のTodoも含めてゴミ箱に移動し、移動したTodoのIDを返すクエリ
+const trashTodoQuery = `WITH RECURSIVE subtree (id) AS (
+		SELECT id FROM todo WHERE id = ?1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT todo.id FROM todo INNER JOIN subtree ON todo.parent_id = subtree.id
+		WHERE todo.deleted_at IS NULL
+	)
+	UPDATE todo SET deleted_at = ` + nowExpr + ` WHERE id IN (SELECT id FROM subtree)
+	RETURNING id`
+
+// Todo はSQLiteを使ったTodoの実装
+type Todo struct {
+	db *sql.DB
+}
+
+// NewTodo は repository.Todo のコンストラクタ
+func NewTodo(db *sql.DB) repository.Todo {
+	return &Todo{
+		db: db,
+	}
+}
+
+// scanTodo は todoColumns の順で取得した行をTodoに変換する
+func scanTodo(row interface{ Scan(dest ...any) error }) (*model.Todo, error) {
+	var t model.Todo
+	if err := row.Scan(
+		&t.ID, &t.Title, &t.Content, &t.Status, &t.Priority, &t.ParentID, &t.Position, &t.AutoComplete,
+		timeScanner{&t.DueAt}, &t.Recurrence, &t.Timezone, &t.SeriesID, timeScanner{&t.OccurrenceAt},
+		timeScanner{&t.CompletedAt}, timeScanner{&t.ArchivedAt}, timeScanner{&t.DeletedAt},
+		&t.Version, scanTime(&t.UpdatedAt), &t.Done, scanJSON(&t.Tags), scanJSON(&t.BlockedBy),
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// queryTodos は todoColumns を取得するクエリを実行し、行をTodoに変換する
+func queryTodos(ctx context.Context, q querier, query string, args ...any) ([]model.Todo, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	todos := []model.Todo{}
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, *t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// queryIDs はIDを返すクエリを実行し、IDの一覧を返す
+func queryIDs(ctx context.Context, q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// placeholders は n 個のプレースホルダを返す
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// stringArgs は文字列の一覧をクエリの引数に変換する
+func stringArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
+
+// buildTodoFilter は検索キーワード以外の検索クエリからWHERE句と引数を組み立てる (ゴミ箱にあるTodoは常に除く)
+func buildTodoFilter(query model.TodoQuery) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+
+	if query.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, query.Status)
+	}
+	if len(query.Tags) > 0 {
+		tagged := "SELECT COUNT(DISTINCT tag.id) FROM todo_tag INNER JOIN tag ON todo_tag.tag_id = tag.id " +
+			"WHERE todo_tag.todo_id = todo.id AND tag.name IN (" + placeholders(len(query.Tags)) + ")"
+		for _, tag := range query.Tags {
+			args = append(args, tag)
+		}
+		if query.TagMatch == model.TagMatchAll {
+			conds = append(conds, fmt.Sprintf("(%s) = %d", tagged, len(query.Tags)))
+		} else {
+			conds = append(conds, fmt.Sprintf("(%s) > 0", tagged))
+		}
+	}
+
+	switch query.Archived {
+	case model.ArchivedFilterAny:
+	case model.ArchivedFilterTrue:
+		conds = append(conds, "archived_at IS NOT NULL")
+	default:
+		conds = append(conds, "archived_at IS NULL")
+	}
+	if query.SeriesID != "" {
+		conds = append(conds, "series_id = ?")
+		args = append(args, query.SeriesID)
+	}
+	if query.MinPriority != nil {
+		conds = append(conds, "priority >= ?")
+		args = append(args, *query.MinPriority)
+	}
+	if due := query.DueRange; due != nil {
+		if due.None {
+			conds = append(conds, "due_at IS NULL")
+		}
+		if due.From != nil {
+			conds = append(conds, "due_at >= ?")
+			args = append(args, timestamp(*due.From))
+		}
+		if due.To != nil {
+			conds = append(conds, "due_at < ?")
+			args = append(args, timestamp(*due.To))
+		}
+		if due.OpenOnly {
+			conds = append(conds, "status NOT IN ('done', 'cancelled')")
+		}
+	}
+
+	if query.Actionable {
+		conds = append(conds, `status NOT IN ('done', 'cancelled') AND NOT EXISTS (
+			SELECT 1 FROM todo_dependency INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+			WHERE todo_dependency.todo_id = todo.id AND blocker.deleted_at IS NULL
+				AND blocker.status NOT IN ('done', 'cancelled')
+		)`)
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// FindAll は全てのTodoを作成した順に取得する
+// 検索キーワードを指定した場合は、SQLで絞り込んだTodoのうち検索キーワードに一致するものを関連度の高い順に並べ、一致の情報を設定する
+func (r *Todo) FindAll(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	where, args := buildTodoFilter(query)
+	todos, err := queryTodos(ctx, connFrom(ctx, r.db), "SELECT "+todoColumns+" FROM todo"+where+" ORDER BY rowid", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := model.SearchTerms(query.Q)
+	if len(terms) == 0 {
+		return todos, nil
+	}
+	matched := []model.Todo{}
+	for _, t := range todos {
+		rank, ok := model.SearchRank(&t, terms)
+		if !ok {
+			continue
+		}
+		t.Search = model.NewTodoSearchMatch(&t, terms, rank)
+		matched = append(matched, t)
+	}
+	slices.SortStableFunc(matched, func(a, b model.Todo) int {
+		return cmp.Compare(b.Search.Rank, a.Search.Rank)
+	})
+	return matched, nil
+}
+
+// Stamp は query に一致するTodoの件数と、全てのTodoの最終更新日時を返す
+func (r *Todo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	stamp := model.TodoListStamp{DueRange: query.DueRange}
+	var lastModified *time.Time
+	if err := connFrom(ctx, r.db).QueryRowContext(ctx, "SELECT MAX(updated_at) FROM todo").Scan(timeScanner{&lastModified}); err != nil {
+		return nil, err
+	}
+	if lastModified != nil {
+		stamp.LastModified = *lastModified
+	}
+
+	// 検索キーワードによる絞り込みはSQLで行わないため、取得したTodoの件数を数える
+	if len(model.SearchTerms(query.Q)) > 0 {
+		todos, err := r.FindAll(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		stamp.Count = len(todos)
+		return &stamp, nil
+	}
+	where, args := buildTodoFilter(query)
+	if err := connFrom(ctx, r.db).QueryRowContext(ctx, "SELECT COUNT(*) FROM todo"+where, args...).Scan(&stamp.Count); err != nil {
+		return nil, err
+	}
+
+	return &stamp, nil
+}
+
+// FindByID はIDによるTodoの取得
+func (r *Todo) FindByID(ctx context.Context, id string) (*model.Todo, error) {
+	return findTodoByID(ctx, connFrom(ctx, r.db), id)
+}
+
+// findTodoByID はIDによるTodoの取得 (トランザクション内からも利用する)
+func findTodoByID(ctx context.Context, q querier, id string) (*model.Todo, error) {
+	return scanTodo(q.QueryRowContext(ctx, "SELECT "+todoColumns+" FROM todo WHERE id = ? AND deleted_at IS NULL", id))
+}
+
+// FindByIDs は ids のTodoをまとめて取得する
+func (r *Todo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	if len(ids) == 0 {
+		return []model.Todo{}, nil
+	}
+	return queryTodos(ctx, connFrom(ctx, r.db),
+		"SELECT "+todoColumns+" FROM todo WHERE id IN ("+placeholders(len(ids))+") AND deleted_at IS NULL",
+		stringArgs(ids)...)
+}
+
+// findTodosByIDs は複数のIDによるTodoの取得 (ゴミ箱にあるTodoも含む)
+func findTodosByIDs(ctx context.Context, q querier, ids []string) ([]model.Todo, error) {
+	if len(ids) == 0 {
+		return []model.Todo{}, nil
+	}
+	return queryTodos(ctx, q, "SELECT "+todoColumns+" FROM todo WHERE id IN ("+placeholders(len(ids))+")", stringArgs(ids)...)
+}
+
+// Create は新しいTodoを作成する
+func (r *Todo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
+	var t *model.Todo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		t, err = createTodo(ctx, tx, todo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// createTodo はトランザクション内で新しいTodoを作成し、変更履歴を記録する
+func createTodo(ctx context.Context, tx *sql.Tx, todo model.Todo) (*model.Todo, error) {
+	id := uuid.NewString()
+	if _, err := tx.ExecContext(ctx, insertTodoQuery, insertTodoArgs(id, todo)...); err != nil {
+		return nil, err
+	}
+	if err := setTodoTags(ctx, tx, id, todo.Tags); err != nil {
+		return nil, err
+	}
+
+	t, err := findTodoByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return t, recordTodoHistory(ctx, tx, model.HistoryActionCreate, nil, t)
+}
+
+// Update はTodoを更新する
+func (r *Todo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+	var t *model.Todo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		t, err = updateTodo(ctx, tx, id, todo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// updateTodo はトランザクション内でTodoを更新し、変更履歴を記録する
+func updateTodo(ctx context.Context, tx *sql.Tx, id string, todo model.Todo) (*model.Todo, error) {
+	before, err := findTodoByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.CheckBaseVersion(before, todo.Version); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, updateTodoQuery, updateTodoArgs(id, todo)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := rowsAffectedOrNotFound(result); err != nil {
+		return nil, err
+	}
+	if err := setTodoTags(ctx, tx, id, todo.Tags); err != nil {
+		return nil, err
+	}
+
+	t, err := findTodoByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return t, recordTodoHistory(ctx, tx, model.HistoryActionUpdate, before, t)
+}
+
+// setTodoTags はTodoに付与するタグを names で置き換える (存在しないタグは作成する)
+func setTodoTags(ctx context.Context, q querier, todoID string, names []string) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM todo_tag WHERE todo_id = ?", todoID); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := q.ExecContext(ctx,
+			"INSERT INTO tag (id, name) VALUES (?, ?) ON CONFLICT (name) DO NOTHING",
+			uuid.NewString(), name); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx,
+			"INSERT INTO todo_tag (todo_id, tag_id) SELECT ?, id FROM tag WHERE name = ? ON CONFLICT DO NOTHING",
+			todoID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete はTodoを子孫のTodoも含めてゴミ箱に移動する
+// 子孫のTodoには同じ日時を設定し、Restore で一緒に元に戻せるようにする
+func (r *Todo) Delete(ctx context.Context, id string) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		return trashTodo(ctx, tx, id)
+	})
+}
+
+// trashTodo はトランザクション内でTodoを子孫のTodoも含めてゴミ箱に移動し、変更履歴を記録する
+func trashTodo(ctx context.Context, tx *sql.Tx, id string) error {
+	ids, err := queryIDs(ctx, tx, trashTodoQuery, id)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return model.ErrNotFound
+	}
+
+	return recordTodoHistories(ctx, tx, model.HistoryActionDelete, ids, func(before *model.Todo) {
+		before.DeletedAt = nil
+	})
+}
+
+// FindChildren は子のTodoを並び順で取得する
+func (r *Todo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	return queryTodos(ctx, connFrom(ctx, r.db),
+		"SELECT "+todoColumns+" FROM todo WHERE parent_id = ? AND deleted_at IS NULL ORDER BY position, created_at",
+		parentID)
+}
+
+// FindChildrenByParentIDs は parentIDs の各親の子のTodoをまとめて取得する
+func (r *Todo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	if len(parentIDs) == 0 {
+		return []model.Todo{}, nil
+	}
+	return queryTodos(ctx, connFrom(ctx, r.db),
+		"SELECT "+todoColumns+" FROM todo WHERE parent_id IN ("+placeholders(len(parentIDs))+") AND deleted_at IS NULL ORDER BY parent_id, position, created_at",
+		stringArgs(parentIDs)...)
+}
+
+// ReorderChildren は子のTodoを ids の順に並び替える
+func (r *Todo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for position, id := range ids {
+			result, err := tx.ExecContext(ctx,
+				"UPDATE todo SET position = ? WHERE id = ? AND parent_id = ? AND deleted_at IS NULL",
+				position, id, parentID)
+			if err != nil {
+				return err
+			}
+			if err := rowsAffectedOrNotFound(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindTrash はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+func (r *Todo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	return queryTodos(ctx, connFrom(ctx, r.db),
+		"SELECT "+todoColumns+" FROM todo WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, position")
+}
+
+// Restore はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+func (r *Todo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	var t *model.Todo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var deletedAt *time.Time
+		var parentTrashed bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT todo.deleted_at, COALESCE(parent.deleted_at IS NOT NULL, FALSE) FROM todo
+			LEFT JOIN todo AS parent ON todo.parent_id = parent.id
+			WHERE todo.id = ? AND todo.deleted_at IS NOT NULL`,
+			id).Scan(timeScanner{&deletedAt}, &parentTrashed); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.ErrNotFound
+			}
+			return err
+		}
+		if parentTrashed {
+			return fmt.Errorf("%w: parent todo is in the trash, restore it first", model.ErrConflict)
+		}
+
+		ids, err := queryIDs(ctx, tx,
+			`WITH RECURSIVE subtree (id, deleted_at) AS (
+				SELECT id, deleted_at FROM todo WHERE id = ?1
+				UNION ALL
+				SELECT todo.id, todo.deleted_at FROM todo INNER JOIN subtree ON todo.parent_id = subtree.id
+				WHERE todo.deleted_at = subtree.deleted_at
+			)
+			UPDATE todo SET deleted_at = NULL WHERE id IN (SELECT id FROM subtree)
+			RETURNING id`,
+			id)
+		if err != nil {
+			return err
+		}
+		if err := recordTodoHistories(ctx, tx, model.HistoryActionRestore, ids, func(before *model.Todo) {
+			before.DeletedAt = deletedAt
+		}); err != nil {
+			return err
+		}
+
+		t, err = findTodoByID(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Purge はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する (子孫は ON DELETE CASCADE で削除される)
+func (r *Todo) Purge(ctx context.Context, id string) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM todo WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffectedOrNotFound(result)
+}
+
+// PurgeDeletedBefore は before より前にゴミ箱に移動したTodoを完全に削除し、削除した件数を返す
+// SQLiteは ON DELETE CASCADE で削除した行を削除した件数に含めないため、削除する前に件数を数える
+func (r *Todo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM todo WHERE deleted_at < ?", timestamp(before)).Scan(&n); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM todo WHERE deleted_at < ?", timestamp(before))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Archive はTodoをアーカイブする (アーカイブ済みの場合はアーカイブした日時を維持する)
+func (r *Todo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	return r.setArchivedAt(ctx, id, "COALESCE(archived_at, "+nowExpr+")", model.HistoryActionArchive)
+}
+
+// Unarchive はTodoのアーカイブを解除する
+func (r *Todo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	return r.setArchivedAt(ctx, id, "NULL", model.HistoryActionUnarchive)
+}
+
+// setArchivedAt はTodoの archived_at を expr の値に更新し、action として変更履歴を記録する
+func (r *Todo) setArchivedAt(ctx context.Context, id string, expr string, action model.HistoryAction) (*model.Todo, error) {
+	var t *model.Todo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		before, err := findTodoByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE todo SET archived_at = "+expr+" WHERE id = ?", id); err != nil {
+			return err
+		}
+
+		if t, err = findTodoByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return recordTodoHistory(ctx, tx, action, before, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// ArchiveCompletedBefore は before より前に done になったアーカイブされていないTodoをアーカイブし、アーカイブした件数を返す
+func (r *Todo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		ids, err := queryIDs(ctx, tx,
+			`UPDATE todo SET archived_at = `+nowExpr+`
+			WHERE status = 'done' AND completed_at < ? AND archived_at IS NULL AND deleted_at IS NULL
+			RETURNING id`,
+			timestamp(before))
+		if err != nil {
+			return err
+		}
+		n = len(ids)
+
+		return recordTodoHistories(ctx, tx, model.HistoryActionArchive, ids, func(before *model.Todo) {
+			before.ArchivedAt = nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// Touch は befores のTodoのバージョンと更新日時を更新し、befores からの変更を変更履歴に記録する
+// UPDATE でトリガーを実行してバージョンと更新日時を更新する
+func (r *Todo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	if len(befores) == 0 {
+		return []model.Todo{}, nil
+	}
+	ids := make([]string, len(befores))
+	beforeByID := make(map[string]*model.Todo, len(befores))
+	for i := range befores {
+		ids[i] = befores[i].ID
+		beforeByID[befores[i].ID] = &befores[i]
+	}
+
+	var todos []model.Todo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE todo SET updated_at = "+nowExpr+" WHERE id IN ("+placeholders(len(ids))+") AND deleted_at IS NULL",
+			stringArgs(ids)...); err != nil {
+			return err
+		}
+
+		var err error
+		if todos, err = queryTodos(ctx, tx,
+			"SELECT "+todoColumns+" FROM todo WHERE id IN ("+placeholders(len(ids))+") AND deleted_at IS NULL",
+			stringArgs(ids)...); err != nil {
+			return err
+		}
+		for i := range todos {
+			if err := recordTodoHistory(ctx, tx, model.HistoryActionUpdate, beforeByID[todos[i].ID], &todos[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// ApplyBatch は writes を1つのトランザクションで適用し、書き込みごとに作成または更新した後のTodoを返す (ゴミ箱に移動した場合は nil)
+// 作成する書き込みを先に適用し、続けて更新とゴミ箱への移動を順に適用する
+func (r *Todo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	todos := make([]*model.Todo, len(writes))
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		for i, w := range writes {
+			if w.Kind != model.TodoWriteCreate {
+				continue
+			}
+			var err error
+			if todos[i], err = createTodo(ctx, tx, w.Todo); err != nil {
+				return err
+			}
+		}
+		for i, w := range writes {
+			var err error
+			switch w.Kind {
+			case model.TodoWriteCreate:
+			case model.TodoWriteUpdate:
+				todos[i], err = updateTodo(ctx, tx, w.ID, w.Todo)
+			case model.TodoWriteDelete:
+				err = trashTodo(ctx, tx, w.ID)
+			default:
+				err = fmt.Errorf("unknown write kind %q", w.Kind)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// FindChanges は同期トークン since より後に変更したTodoを変更した順に返す
+// 同期トークンは todo_change にトリガーで記録する変更の連番で、変更と完全に削除したTodoは同じスナップショットから読み取る
+func (r *Todo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	changes := &model.TodoChanges{Purged: []model.TodoTombstone{}}
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, "SELECT MAX(COALESCE(MAX(seq), 0), ?) FROM todo_change", since).
+			Scan(&changes.Token); err != nil {
+			return err
+		}
+
+		var err error
+		changes.Todos, err = queryTodos(ctx, tx,
+			"SELECT "+todoColumns+" FROM todo INNER JOIN todo_change ON todo.id = todo_change.todo_id "+
+				"WHERE todo_change.seq > ? ORDER BY todo_change.seq",
+			since)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx,
+			`SELECT todo_id, changed_at FROM todo_change
+			WHERE seq > ? AND NOT EXISTS (SELECT 1 FROM todo WHERE todo.id = todo_change.todo_id) ORDER BY seq`,
+			since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			tombstone := model.TodoTombstone{Purged: true}
+			if err := rows.Scan(&tombstone.ID, scanTime(&tombstone.DeletedAt)); err != nil {
+				return err
+			}
+			changes.Purged = append(changes.Purged, tombstone)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
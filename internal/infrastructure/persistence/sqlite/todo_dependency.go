@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoDependency はSQLiteを使ったTodoの依存関係の実装
+type TodoDependency struct {
+	db *sql.DB
+}
+
+// NewTodoDependency は repository.TodoDependency のコンストラクタ
+func NewTodoDependency(db *sql.DB) repository.TodoDependency {
+	return &TodoDependency{
+		db: db,
+	}
+}
+
+// FindBlockerIDs は todoID のTodoをブロックしているTodoのIDを返す (ゴミ箱にあるTodoは除く)
+func (r *TodoDependency) FindBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	ids, err := queryIDs(ctx, connFrom(ctx, r.db),
+		`SELECT todo_dependency.blocker_id FROM todo_dependency
+		INNER JOIN todo AS blocker ON todo_dependency.blocker_id = blocker.id
+		WHERE todo_dependency.todo_id = ? AND blocker.deleted_at IS NULL
+		ORDER BY todo_dependency.created_at, todo_dependency.rowid`,
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return ids, nil
+}
+
+// FindAllBlockerIDs は todoID のTodoをブロックしている全てのTodoのIDを返す (ゴミ箱にあるTodoも含む)
+func (r *TodoDependency) FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	ids, err := queryIDs(ctx, connFrom(ctx, r.db),
+		"SELECT blocker_id FROM todo_dependency WHERE todo_id = ? ORDER BY created_at, rowid",
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return ids, nil
+}
+
+// Lock は何もしない (トランザクションは開始した時点で書き込みのロックを取得するため、依存関係の変更は既に直列化されている)
+func (r *TodoDependency) Lock(_ context.Context) error {
+	return nil
+}
+
+// Add は todoID のTodoが blockerID のTodoにブロックされる依存関係を追加する
+func (r *TodoDependency) Add(ctx context.Context, todoID string, blockerID string) error {
+	_, err := connFrom(ctx, r.db).ExecContext(ctx,
+		"INSERT INTO todo_dependency (todo_id, blocker_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		todoID, blockerID)
+	if isForeignKeyViolation(err) {
+		return model.ErrNotFound
+	}
+	return err
+}
+
+// Remove は todoID のTodoが blockerID のTodoにブロックされる依存関係を削除する
+func (r *TodoDependency) Remove(ctx context.Context, todoID string, blockerID string) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx,
+		"DELETE FROM todo_dependency WHERE todo_id = ? AND blocker_id = ?",
+		todoID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffectedOrNotFound(result)
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// todoHistoryColumns はTodoの変更履歴を取得する際のカラム一覧 (scanTodoHistory と順番を合わせること)
+const todoHistoryColumns = "id, todo_id, version, action, actor, trace_id, changes, snapshot, created_at"
+
+// TodoHistory はSQLiteを使ったTodoの変更履歴の実装
+type TodoHistory struct {
+	db *sql.DB
+}
+
+// NewTodoHistory は repository.TodoHistory のコンストラクタ
+func NewTodoHistory(db *sql.DB) repository.TodoHistory {
+	return &TodoHistory{
+		db: db,
+	}
+}
+
+// scanTodoHistory は todoHistoryColumns の順で取得した行をTodoの変更履歴に変換する
+func scanTodoHistory(row interface{ Scan(dest ...any) error }) (*model.TodoHistory, error) {
+	var h model.TodoHistory
+	if err := row.Scan(
+		&h.ID, &h.TodoID, &h.Version, &h.Action, &h.Actor, &h.TraceID,
+		scanJSON(&h.Changes), scanJSON(&h.Snapshot), scanTime(&h.CreatedAt),
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &h, nil
+}
+
+// FindByTodoID はTodoの変更履歴を古い順に取得する
+func (r *TodoHistory) FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id = ? ORDER BY version, created_at",
+		todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histories := []model.TodoHistory{}
+	for rows.Next() {
+		h, err := scanTodoHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, *h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// FindByTodoIDs は todoIDs の各Todoの変更履歴をまとめて取得する
+func (r *TodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	histories := []model.TodoHistory{}
+	if len(todoIDs) == 0 {
+		return histories, nil
+	}
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id IN ("+placeholders(len(todoIDs))+") ORDER BY todo_id, version, created_at",
+		stringArgs(todoIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h, err := scanTodoHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, *h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histories, nil
+}
+
+// FindByVersion はTodoの指定したバージョンの変更履歴を取得する
+func (r *TodoHistory) FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error) {
+	return scanTodoHistory(connFrom(ctx, r.db).QueryRowContext(ctx,
+		"SELECT "+todoHistoryColumns+" FROM todo_history WHERE todo_id = ? AND version = ?",
+		todoID, version))
+}
+
+// recordTodoHistory は before から after への変更履歴を記録する (更新で変更がない場合は記録しない)
+// 操作者とトレースIDは ctx の model.AuditInfo から取得する
+func recordTodoHistory(ctx context.Context, q querier, action model.HistoryAction, before, after *model.Todo) error {
+	changes := model.DiffTodo(before, after)
+	if before != nil && len(changes) == 0 {
+		return nil
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	info := model.AuditInfoFromContext(ctx)
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO todo_history (id, todo_id, version, action, actor, trace_id, changes, snapshot)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		uuid.NewString(), after.ID, after.Version, action, info.Actor, info.TraceID, string(changesJSON), string(snapshot))
+	return err
+}
+
+// recordTodoHistories は ids のTodoの変更履歴をまとめて記録する
+// 変更前のTodoは変更後のTodoに undo を適用して求める
+func recordTodoHistories(ctx context.Context, q querier, action model.HistoryAction, ids []string, undo func(before *model.Todo)) error {
+	todos, err := findTodosByIDs(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+	for _, after := range todos {
+		before := after
+		undo(&before)
+		if err := recordTodoHistory(ctx, q, action, &before, &after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/db"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/sqlite"
+)
+
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := db.InitializeSQLite(filepath.Join(t.TempDir(), "todo.db"))
+	if err != nil {
+		t.Fatalf("InitializeSQLite() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestTodo(t *testing.T) {
+	repositorytest.TestTodo(t, func(t *testing.T) repositorytest.Backend {
+		conn := newDB(t)
+		return repositorytest.Backend{
+			Todo:            sqlite.NewTodo(conn),
+			TodoDependency:  sqlite.NewTodoDependency(conn),
+			TodoHistory:     sqlite.NewTodoHistory(conn),
+			Tag:             sqlite.NewTag(conn),
+			TxManager:       sqlite.NewTxManager(conn),
+			Outbox:          sqlite.NewOutbox(conn),
+			Webhook:         sqlite.NewWebhook(conn),
+			WebhookDelivery: sqlite.NewWebhookDelivery(conn),
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// txKey は実行中のトランザクションを context に保存するキー
+type txKey struct{}
+
+// TxManager は repository.TxManager の実装
+type TxManager struct {
+	db *sql.DB
+}
+
+// NewTxManager は repository.TxManager のコンストラクタ
+func NewTxManager(db *sql.DB) repository.TxManager {
+	return &TxManager{
+		db: db,
+	}
+}
+
+// Do は fn を1つのトランザクションで実行する (トランザクションの中ではセーブポイントを使う)
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTx(ctx, m.db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
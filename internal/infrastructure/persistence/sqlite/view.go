@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// viewColumns はビューを取得する際のカラム一覧 (scanView と順番を合わせること)
+const viewColumns = "id, name, query, created_at, updated_at"
+
+// View はSQLiteを使ったビューの実装
+type View struct {
+	db *sql.DB
+}
+
+// NewView は repository.View のコンストラクタ
+func NewView(db *sql.DB) repository.View {
+	return &View{
+		db: db,
+	}
+}
+
+// scanView は viewColumns の順で取得した行をビューに変換する
+func scanView(row interface{ Scan(dest ...any) error }) (*model.View, error) {
+	var v model.View
+	if err := row.Scan(&v.ID, &v.Name, scanJSON(&v.Query), scanTime(&v.CreatedAt), scanTime(&v.UpdatedAt)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, model.ErrConflict
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindAll は全てのビューを名前順で取得する
+func (r *View) FindAll(ctx context.Context) ([]model.View, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx, "SELECT "+viewColumns+" FROM todo_view ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := []model.View{}
+	for rows.Next() {
+		v, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+// FindByID はIDによるビューの取得
+func (r *View) FindByID(ctx context.Context, id string) (*model.View, error) {
+	return scanView(connFrom(ctx, r.db).QueryRowContext(ctx, "SELECT "+viewColumns+" FROM todo_view WHERE id = ?", id))
+}
+
+// Create は新しいビューを作成する
+func (r *View) Create(ctx context.Context, view model.View) (*model.View, error) {
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return nil, err
+	}
+	return scanView(connFrom(ctx, r.db).QueryRowContext(ctx,
+		"INSERT INTO todo_view (id, name, query) VALUES (?, ?, ?) RETURNING "+viewColumns,
+		uuid.NewString(), view.Name, string(query)))
+}
+
+// Update はビューの名前と検索クエリを更新する
+func (r *View) Update(ctx context.Context, id string, view model.View) (*model.View, error) {
+	query, err := json.Marshal(view.Query)
+	if err != nil {
+		return nil, err
+	}
+	return scanView(connFrom(ctx, r.db).QueryRowContext(ctx,
+		"UPDATE todo_view SET name = ?, query = ?, updated_at = "+nowExpr+" WHERE id = ? RETURNING "+viewColumns,
+		view.Name, string(query), id))
+}
+
+// Delete はビューを削除する
+func (r *View) Delete(ctx context.Context, id string) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM todo_view WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffectedOrNotFound(result)
+}
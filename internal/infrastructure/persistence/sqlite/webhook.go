@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookColumns はWebhookを取得する際のカラム一覧 (scanWebhook と順番を合わせること)
+const webhookColumns = "id, url, events, secret, created_at, updated_at"
+
+// Webhook はSQLiteを使ったWebhookの購読の実装
+type Webhook struct {
+	db *sql.DB
+}
+
+// NewWebhook は repository.Webhook のコンストラクタ
+func NewWebhook(db *sql.DB) repository.Webhook {
+	return &Webhook{
+		db: db,
+	}
+}
+
+// scanWebhook は webhookColumns の順で取得した行をWebhookに変換する
+func scanWebhook(row interface{ Scan(dest ...any) error }) (*model.Webhook, error) {
+	var w model.Webhook
+	if err := row.Scan(&w.ID, &w.URL, scanJSON(&w.Events), &w.Secret, scanTime(&w.CreatedAt), scanTime(&w.UpdatedAt)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// FindAll は全てのWebhookを作成した順に取得する
+func (r *Webhook) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx, "SELECT "+webhookColumns+" FROM webhook ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []model.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// FindByID はIDによるWebhookの取得
+func (r *Webhook) FindByID(ctx context.Context, id string) (*model.Webhook, error) {
+	return scanWebhook(connFrom(ctx, r.db).QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhook WHERE id = ?", id))
+}
+
+// Create は新しいWebhookを作成する
+func (r *Webhook) Create(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	events, err := webhookEvents(webhook.Events)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhook(connFrom(ctx, r.db).QueryRowContext(ctx,
+		"INSERT INTO webhook (id, url, events, secret) VALUES (?, ?, ?, ?) RETURNING "+webhookColumns,
+		uuid.NewString(), webhook.URL, events, webhook.Secret))
+}
+
+// Update はWebhookの URL、イベントの種類と秘密鍵を更新する
+func (r *Webhook) Update(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error) {
+	events, err := webhookEvents(webhook.Events)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhook(connFrom(ctx, r.db).QueryRowContext(ctx,
+		"UPDATE webhook SET url = ?, events = ?, secret = ?, updated_at = "+nowExpr+" WHERE id = ? RETURNING "+webhookColumns,
+		webhook.URL, events, webhook.Secret, id))
+}
+
+// Delete はWebhookを削除する (配信は外部キーの ON DELETE CASCADE で削除する)
+func (r *Webhook) Delete(ctx context.Context, id string) error {
+	result, err := connFrom(ctx, r.db).ExecContext(ctx, "DELETE FROM webhook WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffectedOrNotFound(result)
+}
+
+// webhookEvents はイベントの種類の一覧を events カラムの値 (JSON) に変換する
+func webhookEvents(events []model.TodoEventType) (string, error) {
+	if events == nil {
+		events = []model.TodoEventType{}
+	}
+	b, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
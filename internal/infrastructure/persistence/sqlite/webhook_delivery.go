@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookDeliveryColumns はWebhookの配信を取得する際のカラム一覧 (scanWebhookDelivery と順番を合わせること)
+const webhookDeliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, updated_at"
+
+// WebhookDelivery はSQLiteを使ったWebhookの配信の実装
+type WebhookDelivery struct {
+	db *sql.DB
+}
+
+// NewWebhookDelivery は repository.WebhookDelivery のコンストラクタ
+func NewWebhookDelivery(db *sql.DB) repository.WebhookDelivery {
+	return &WebhookDelivery{
+		db: db,
+	}
+}
+
+// scanWebhookDelivery は webhookDeliveryColumns の順で取得した行をWebhookの配信に変換する
+func scanWebhookDelivery(row interface{ Scan(dest ...any) error }) (*model.WebhookDelivery, error) {
+	var (
+		d       model.WebhookDelivery
+		payload string
+	)
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, timeScanner{&d.NextAttemptAt}, scanTime(&d.CreatedAt), scanTime(&d.UpdatedAt)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, model.ErrConflict
+		}
+		if isForeignKeyViolation(err) {
+			return nil, model.ErrNotFound
+		}
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	return &d, nil
+}
+
+// queryWebhookDeliveries は query で取得したWebhookの配信の一覧を返す
+func (r *WebhookDelivery) queryWebhookDeliveries(ctx context.Context, query string, args ...any) ([]model.WebhookDelivery, error) {
+	rows, err := connFrom(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Create は配信を作成する
+func (r *WebhookDelivery) Create(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return scanWebhookDelivery(connFrom(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO webhook_delivery (id, webhook_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING `+webhookDeliveryColumns,
+		uuid.NewString(), delivery.WebhookID, delivery.EventID, delivery.EventType, string(delivery.Payload), delivery.Status,
+		nullTimestamp(delivery.NextAttemptAt)))
+}
+
+// FindByWebhookID はWebhookの配信を新しい順に limit 件まで取得する
+func (r *WebhookDelivery) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_delivery WHERE webhook_id = ? ORDER BY created_at DESC, rowid DESC LIMIT ?",
+		webhookID, limit)
+}
+
+// FindDue は now までに配信を試みる日時になった配信待ちの配信を、配信を試みる日時の順に limit 件まで取得する
+func (r *WebhookDelivery) FindDue(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_delivery WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, rowid LIMIT ?",
+		model.WebhookDeliveryPending, timestamp(now), limit)
+}
+
+// Update は配信を試みた結果を保存する
+func (r *WebhookDelivery) Update(ctx context.Context, delivery model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	return scanWebhookDelivery(connFrom(ctx, r.db).QueryRowContext(ctx,
+		`UPDATE webhook_delivery SET status = ?, attempts = ?, response_status = ?, last_error = ?, next_attempt_at = ?, updated_at = `+nowExpr+`
+		WHERE id = ? RETURNING `+webhookDeliveryColumns,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError, nullTimestamp(delivery.NextAttemptAt), delivery.ID))
+}
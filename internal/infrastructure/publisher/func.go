@@ -0,0 +1,16 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// Func は関数を repository.EventPublisher として使うためのアダプター
+// イベントを配信するユースケース (Webhookの配信の作成など) を配信先にする場合に使う
+type Func func(ctx context.Context, event model.TodoEvent) error
+
+// Publish は f(ctx, event) を呼び出す
+func (f Func) Publish(ctx context.Context, event model.TodoEvent) error {
+	return f(ctx, event)
+}
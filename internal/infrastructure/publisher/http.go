@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// HTTP はイベントを JSON として URL に POST する repository.EventPublisher の実装
+// 受信側が 2xx 以外のステータスを返した場合は配信に失敗したものとして扱う
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP は repository.EventPublisher のコンストラクタ (タイムアウトは client に設定すること)
+func NewHTTP(url string, client *http.Client) repository.EventPublisher {
+	return &HTTP{
+		url:    url,
+		client: client,
+	}
+}
+
+// Publish はイベントを POST する
+// 受信側で重複を除けるよう、イベントのIDを X-Event-ID ヘッダーにも設定する
+func (p *HTTP) Publish(ctx context.Context, event model.TodoEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// 接続を再利用できるよう、応答の本文を読み捨てる
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	return nil
+}
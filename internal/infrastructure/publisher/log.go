@@ -0,0 +1,29 @@
+// Package publisher はドメインイベントを外部に配信する repository.EventPublisher の実装を提供する
+package publisher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Log はイベントをログに出力する repository.EventPublisher の実装
+type Log struct{}
+
+// NewLog は repository.EventPublisher のコンストラクタ
+func NewLog() repository.EventPublisher {
+	return &Log{}
+}
+
+// Publish はイベントをログに出力する
+func (p *Log) Publish(ctx context.Context, event model.TodoEvent) error {
+	slog.InfoContext(ctx, "Todo event published",
+		slog.String("event_id", event.ID),
+		slog.String("event_type", string(event.Type)),
+		slog.String("todo_id", event.TodoID),
+		slog.String("actor", event.Actor),
+	)
+	return nil
+}
@@ -0,0 +1,33 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// Multi はイベントを複数の配信先に配信する repository.EventPublisher の実装
+// いずれかの配信先で失敗した場合はイベント全体を再試行するため、配信に成功した配信先にも再度配信する
+type Multi struct {
+	publishers []repository.EventPublisher
+}
+
+// NewMulti は repository.EventPublisher のコンストラクタ
+func NewMulti(publishers ...repository.EventPublisher) repository.EventPublisher {
+	return &Multi{
+		publishers: publishers,
+	}
+}
+
+// Publish はイベントを全ての配信先に配信し、失敗した配信先のエラーをまとめて返す
+func (p *Multi) Publish(ctx context.Context, event model.TodoEvent) error {
+	var errs []error
+	for _, pub := range p.publishers {
+		if err := pub.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,129 @@
+package publisher_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/publisher"
+)
+
+// newEvent はテストで配信するイベントを返す
+func newEvent() model.TodoEvent {
+	return model.TodoEvent{
+		ID:         "00000000-0000-4000-a000-000000000001",
+		Type:       model.TodoEventCreated,
+		TodoID:     "00000000-0000-4000-a000-000000000002",
+		Actor:      "alice",
+		Todo:       model.Todo{ID: "00000000-0000-4000-a000-000000000002", Title: "Test", Status: model.TodoStatusTodo},
+		OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestHTTP_Publish(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusNoContent},
+		{name: "server error", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotHeader http.Header
+				gotEvent  model.TodoEvent
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Clone()
+				body, _ := io.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &gotEvent); err != nil {
+					t.Errorf("json.Unmarshal() error = %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(srv.Close)
+
+			event := newEvent()
+			err := publisher.NewHTTP(srv.URL, srv.Client()).Publish(context.Background(), event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := gotHeader.Get("X-Event-ID"); got != event.ID {
+				t.Errorf("X-Event-ID = %q, want %q", got, event.ID)
+			}
+			if got := gotHeader.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want %q", got, "application/json")
+			}
+			if diff := cmp.Diff(event, gotEvent); diff != "" {
+				t.Errorf("body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedisStream_Publish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	ctx := context.Background()
+
+	event := newEvent()
+	if err := publisher.NewRedisStream(client, "todo-events", publisher.DefaultStreamMaxLen).Publish(ctx, event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	entries, err := client.XRange(ctx, "todo-events", "-", "+").Result()
+	if err != nil {
+		t.Fatalf("XRange() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("XRange() returned %d entries, want 1", len(entries))
+	}
+	values := entries[0].Values
+	if values["id"] != event.ID || values["type"] != string(event.Type) || values["todo_id"] != event.TodoID {
+		t.Errorf("entry values = %v", values)
+	}
+	var got model.TodoEvent
+	if err := json.Unmarshal([]byte(values["payload"].(string)), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if diff := cmp.Diff(event, got); diff != "" {
+		t.Errorf("payload mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMulti_Publish(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	var calls int
+	ok := publisher.Func(func(context.Context, model.TodoEvent) error {
+		calls++
+		return nil
+	})
+	failing := publisher.Func(func(context.Context, model.TodoEvent) error {
+		calls++
+		return errUnavailable
+	})
+
+	pubs := []repository.EventPublisher{failing, ok}
+	err := publisher.NewMulti(pubs...).Publish(context.Background(), newEvent())
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("Publish() error = %v, want %v", err, errUnavailable)
+	}
+	// 失敗した配信先があっても、残りの配信先に配信する
+	if calls != len(pubs) {
+		t.Errorf("publishers called %d times, want %d", calls, len(pubs))
+	}
+}
@@ -0,0 +1,51 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DefaultStreamMaxLen はストリームに保持するイベントのおおよその件数のデフォルト値
+const DefaultStreamMaxLen = 10000
+
+// RedisStream はイベントをRedis Streamsに追加する repository.EventPublisher の実装
+// エントリには id, type, todo_id とイベント全体の JSON (payload) を設定する
+type RedisStream struct {
+	client *goredis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStream は repository.EventPublisher のコンストラクタ
+// ストリームには古いものから削除して、おおよそ maxLen 件のイベントを保持する
+func NewRedisStream(client *goredis.Client, stream string, maxLen int64) repository.EventPublisher {
+	return &RedisStream{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Publish はイベントをストリームに追加する
+func (p *RedisStream) Publish(ctx context.Context, event model.TodoEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.client.XAdd(ctx, &goredis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: map[string]any{
+			"id":      event.ID,
+			"type":    string(event.Type),
+			"todo_id": event.TodoID,
+			"payload": payload,
+		},
+	}).Err()
+}
@@ -0,0 +1,110 @@
+// Package stream はTodoのイベントを同じプロセスの購読者に配信する repository.TodoEventStream の実装を提供する
+package stream
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+const (
+	// DefaultRetention は購読を再開するために保持するイベントの件数のデフォルト値
+	DefaultRetention = 1000
+	// subscriberBufferSize は購読ごとに受け取りを待つイベントの件数の上限 (超えた場合は購読を終了する)
+	subscriberBufferSize = 256
+)
+
+// Broker は配信したイベントを購読者に配信する repository.TodoEventStream の実装
+// 直近の retention 件のイベントを配信した順に保持し、Last-Event-ID による購読の再開に使う
+type Broker struct {
+	retention int
+
+	mu          sync.Mutex
+	events      []model.TodoEvent
+	retained    map[string]struct{}
+	subscribers map[chan model.TodoEvent]struct{}
+}
+
+// NewBroker は stream.Broker のコンストラクタ
+func NewBroker(retention int) *Broker {
+	return &Broker{
+		retention:   retention,
+		retained:    make(map[string]struct{}),
+		subscribers: make(map[chan model.TodoEvent]struct{}),
+	}
+}
+
+// Publish はイベントを保持し、全ての購読者に配信する
+// 受け取りを待つイベントが上限に達した購読者は、配信を待たずに購読を終了する
+// 保持しているイベントと同じIDのイベント (アウトボックスから再度配信されたイベントなど) は無視する
+func (b *Broker) Publish(_ context.Context, event model.TodoEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.retained[event.ID]; ok {
+		return nil
+	}
+	b.events = append(b.events, event)
+	b.retained[event.ID] = struct{}{}
+	if n := len(b.events) - b.retention; n > 0 {
+		for _, e := range b.events[:n] {
+			delete(b.retained, e.ID)
+		}
+		b.events = slices.Delete(b.events, 0, n)
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.unsubscribe(ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe は ctx がキャンセルされるまで配信したイベントを受け取る購読を開始する
+// lastEventID を指定した場合は、保持しているイベントのうちそれより後に配信したイベントを backlog として返す
+func (b *Broker) Subscribe(ctx context.Context, lastEventID string) ([]model.TodoEvent, <-chan model.TodoEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []model.TodoEvent
+	if lastEventID != "" {
+		i := slices.IndexFunc(b.events, func(e model.TodoEvent) bool { return e.ID == lastEventID })
+		if i < 0 {
+			return nil, nil, model.ErrEventExpired
+		}
+		backlog = slices.Clone(b.events[i+1:])
+	}
+
+	ch := make(chan model.TodoEvent, subscriberBufferSize)
+	b.subscribers[ch] = struct{}{}
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.unsubscribe(ch)
+	})
+	return backlog, ch, nil
+}
+
+// Reset は保持しているイベントを破棄し、全ての購読を終了する
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.events = nil
+	clear(b.retained)
+	for ch := range b.subscribers {
+		b.unsubscribe(ch)
+	}
+}
+
+// unsubscribe は購読を終了する (b.mu をロックした状態で呼び出す)
+func (b *Broker) unsubscribe(ch chan model.TodoEvent) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,173 @@
+package stream_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+)
+
+// publish は ids のイベントを順に配信する
+func publish(t *testing.T, b *stream.Broker, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := b.Publish(context.Background(), model.TodoEvent{ID: id, Type: model.TodoEventUpdated}); err != nil {
+			t.Fatalf("Publish(%s) error = %v", id, err)
+		}
+	}
+}
+
+// eventIDs はイベントのIDの一覧を返す
+func eventIDs(events []model.TodoEvent) []string {
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// receive は events から n 件のイベントを受け取る
+func receive(t *testing.T, events <-chan model.TodoEvent, n int) []model.TodoEvent {
+	t.Helper()
+	var got []model.TodoEvent
+	for range n {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed after %d events", len(got))
+			}
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out after %d events", len(got))
+		}
+	}
+	return got
+}
+
+// assertClosed は events が閉じていることを確認する
+func assertClosed(t *testing.T, events <-chan model.TodoEvent) {
+	t.Helper()
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("events is not closed")
+		}
+	}
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	b := stream.NewBroker(3)
+	publish(t, b, "1", "2")
+
+	backlog, events, err := b.Subscribe(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(backlog) != 0 {
+		t.Errorf("backlog = %v, want empty", eventIDs(backlog))
+	}
+	// 再度配信されたイベントは無視する
+	publish(t, b, "2", "3", "3", "4")
+	if diff := cmp.Diff([]string{"3", "4"}, eventIDs(receive(t, events, 2))); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %q", e.ID)
+	default:
+	}
+}
+
+func TestBroker_Subscribe_resume(t *testing.T) {
+	b := stream.NewBroker(3)
+	publish(t, b, "1", "2", "3", "4")
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+		wantErr     error
+	}{
+		{name: "resume after retained event", lastEventID: "2", want: []string{"3", "4"}},
+		{name: "resume after latest event", lastEventID: "4", want: []string{}},
+		{name: "event no longer retained", lastEventID: "1", wantErr: model.ErrEventExpired},
+		{name: "unknown event", lastEventID: "unknown", wantErr: model.ErrEventExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, _, err := b.Subscribe(t.Context(), tt.lastEventID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Subscribe() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, eventIDs(backlog)); diff != "" {
+				t.Errorf("backlog mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBroker_Subscribe_cancel(t *testing.T) {
+	b := stream.NewBroker(stream.DefaultRetention)
+	ctx, cancel := context.WithCancel(t.Context())
+	_, events, err := b.Subscribe(ctx, "")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	cancel()
+	assertClosed(t, events)
+	// 終了した購読には配信しない
+	publish(t, b, "1")
+}
+
+func TestBroker_Publish_slowSubscriber(t *testing.T) {
+	b := stream.NewBroker(stream.DefaultRetention)
+	_, slow, err := b.Subscribe(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	// 受け取りを待つイベントが上限を超えるまで配信する
+	for i := range 1000 {
+		publish(t, b, fmt.Sprint(i))
+	}
+	assertClosed(t, slow)
+
+	// 取りこぼした続きから再開できる
+	backlog, _, err := b.Subscribe(t.Context(), "499")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(backlog) != 500 {
+		t.Fatalf("len(backlog) = %d, want 500", len(backlog))
+	}
+	if backlog[0].ID != "500" {
+		t.Errorf("backlog[0].ID = %q, want %q", backlog[0].ID, "500")
+	}
+}
+
+func TestBroker_Reset(t *testing.T) {
+	b := stream.NewBroker(stream.DefaultRetention)
+	publish(t, b, "1")
+	_, events, err := b.Subscribe(t.Context(), "")
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	b.Reset()
+	assertClosed(t, events)
+	if _, _, err := b.Subscribe(t.Context(), "1"); !errors.Is(err, model.ErrEventExpired) {
+		t.Errorf("Subscribe() after Reset error = %v, want %v", err, model.ErrEventExpired)
+	}
+}
@@ -0,0 +1,51 @@
+// Package webhook はWebhookの配信を HTTP で送信する repository.WebhookSender の実装を提供する
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DefaultTimeout は配信を送信してから応答を待つ時間のデフォルト値
+const DefaultTimeout = 10 * time.Second
+
+// Sender は配信の本文を JSON としてWebhookの URL に POST する repository.WebhookSender の実装
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender は repository.WebhookSender のコンストラクタ (タイムアウトは client に設定すること)
+func NewSender(client *http.Client) repository.WebhookSender {
+	return &Sender{
+		client: client,
+	}
+}
+
+// Send は配信の本文を POST し、応答のステータスコードを返す
+// 送信するたびに現在日時で署名するため、再試行した配信も受信側でタイムスタンプを検証できる
+func (s *Sender) Send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.WebhookIDHeader, delivery.ID)
+	req.Header.Set(model.WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(model.WebhookSignatureHeader, model.SignWebhook(webhook.Secret, time.Now(), delivery.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// 接続を再利用できるよう、応答の本文を読み捨てる
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
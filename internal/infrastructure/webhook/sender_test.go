@@ -0,0 +1,75 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/webhook"
+)
+
+func TestSender_Send(t *testing.T) {
+	const secret = "whsec_test"
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{name: "ok", status: http.StatusNoContent},
+		{name: "server error is returned as status", status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotHeader http.Header
+				verifyErr error
+			)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Clone()
+				body, _ := io.ReadAll(r.Body)
+				verifyErr = model.VerifyWebhookSignature(secret, r.Header.Get(model.WebhookSignatureHeader), body, time.Now(), model.WebhookSignatureTolerance)
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(receiver.Close)
+
+			event := model.TodoEvent{ID: "event", Type: model.TodoEventCreated, TodoID: "todo"}
+			delivery, err := model.NewWebhookDelivery("webhook", event, time.Now())
+			if err != nil {
+				t.Fatalf("NewWebhookDelivery() error = %v", err)
+			}
+			delivery.ID = "delivery"
+			hook := model.Webhook{ID: "webhook", URL: receiver.URL, Secret: secret}
+
+			status, err := webhook.NewSender(receiver.Client()).Send(context.Background(), hook, *delivery)
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			if status != tt.status {
+				t.Errorf("Send() status = %d, want %d", status, tt.status)
+			}
+			if verifyErr != nil {
+				t.Errorf("VerifyWebhookSignature() in receiver error = %v", verifyErr)
+			}
+			if got := gotHeader.Get(model.WebhookIDHeader); got != "delivery" {
+				t.Errorf("%s = %q, want %q", model.WebhookIDHeader, got, "delivery")
+			}
+			if got := gotHeader.Get(model.WebhookEventHeader); got != string(model.TodoEventCreated) {
+				t.Errorf("%s = %q, want %q", model.WebhookEventHeader, got, model.TodoEventCreated)
+			}
+		})
+	}
+}
+
+func TestSender_Send_Unreachable(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+
+	hook := model.Webhook{URL: receiver.URL, Secret: "whsec_test"}
+	status, err := webhook.NewSender(&http.Client{Timeout: time.Second}).Send(context.Background(), hook, model.WebhookDelivery{})
+	if err == nil {
+		t.Errorf("Send() error = nil, want error (status %d)", status)
+	}
+}
@@ -0,0 +1,266 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+const (
+	// sendQueueSize は接続ごとに送信を待てるメッセージの数 (超えた場合は受け取りが遅れている接続として切断する)
+	sendQueueSize = 256
+	// maxMessageSize はクライアントから受け取るメッセージの最大サイズ
+	maxMessageSize = 64 << 10
+	// maxSubscriptions は接続ごとの購読の最大数
+	maxSubscriptions = 20
+	// heartbeatInterval は接続を確認するために ping を送る間隔
+	heartbeatInterval = 20 * time.Second
+	// writeTimeout はメッセージと ping の送信 (ping の場合は pong の受信まで) を待つ時間
+	writeTimeout = 10 * time.Second
+)
+
+// conn は1つの WebSocket の接続を表す
+// メッセージの受信と mutate の処理は run を呼び出した goroutine で順番に行い、送信は送信キューを介して別の goroutine で行う
+type conn struct {
+	handler *Handler
+	ws      *websocket.Conn
+	id      string
+	actor   string
+	send    chan any
+	// subscriptions は購読のIDごとの購読を終了する関数 (受信の goroutine からのみ参照する)
+	subscriptions map[string]context.CancelFunc
+	closeOnce     sync.Once
+}
+
+// newConn は collab.conn のコンストラクタ
+func newConn(handler *Handler, ws *websocket.Conn, actor string) *conn {
+	return &conn{
+		handler:       handler,
+		ws:            ws,
+		id:            uuid.NewString(),
+		actor:         actor,
+		send:          make(chan any, sendQueueSize),
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+}
+
+// run は接続が切断されるまでメッセージを処理する
+func (c *conn) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	presence := c.handler.hub.join(c, model.Presence{ConnectionID: c.id, Actor: c.actor, UpdatedAt: time.Now()})
+	defer c.handler.hub.leave(c)
+	c.enqueue(helloMessage{Type: messageHello, ConnectionID: c.id, Actor: c.actor, Presence: presence})
+
+	var wg sync.WaitGroup
+	wg.Go(func() { c.writeLoop(ctx) })
+	err := c.readLoop(ctx)
+	// 購読と送信の goroutine を終了する
+	cancel()
+	wg.Wait()
+
+	switch websocket.CloseStatus(err) {
+	case websocket.StatusNormalClosure, websocket.StatusGoingAway:
+	default:
+		if !errors.Is(err, context.Canceled) {
+			slog.InfoContext(ctx, "Collaboration connection closed",
+				slog.String("connection_id", c.id), slog.String("actor", c.actor), slog.Any("error", err))
+		}
+	}
+	c.close(websocket.StatusNormalClosure, "")
+}
+
+// readLoop はクライアントからのメッセージを受信して処理する (受信できなくなった場合はそのエラーを返す)
+func (c *conn) readLoop(ctx context.Context) error {
+	for {
+		typ, data, err := c.ws.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if typ != websocket.MessageText {
+			c.enqueue(errorMessage{Type: messageError, Code: codeInvalidArgument, Error: "message must be a text frame"})
+			continue
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.enqueue(errorMessage{Type: messageError, Code: codeInvalidArgument, Error: err.Error()})
+			continue
+		}
+		c.handle(ctx, msg)
+	}
+}
+
+// writeLoop は送信キューのメッセージと、接続を確認する ping を送信する
+// 送信できない場合や pong が返ってこない場合は接続を閉じる (受信も終了する)
+func (c *conn) writeLoop(ctx context.Context) {
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-c.send:
+			writeCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err := wsjson.Write(writeCtx, c.ws, msg)
+			cancel()
+			if err != nil {
+				c.close(websocket.StatusInternalError, "write failed")
+				return
+			}
+		case <-heartbeat.C:
+			pingCtx, cancel := context.WithTimeout(ctx, writeTimeout)
+			err := c.ws.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				c.close(websocket.StatusPolicyViolation, "heartbeat timeout")
+				return
+			}
+		}
+	}
+}
+
+// enqueue はメッセージを送信キューに追加する
+// 送信キューがいっぱいの場合は、メッセージを取りこぼさないように接続を閉じる (クライアントは再接続して購読し直す)
+func (c *conn) enqueue(msg any) {
+	select {
+	case c.send <- msg:
+	default:
+		c.close(websocket.StatusTryAgainLater, "slow consumer")
+	}
+}
+
+// close は接続を閉じる (最初に呼び出した時の status と reason をクライアントに送る)
+// Close は終了のハンドシェイクを待つため、呼び出し元をブロックしないように別の goroutine で行う
+func (c *conn) close(status websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		go func() { _ = c.ws.Close(status, reason) }()
+	})
+}
+
+// handle はクライアントからのメッセージを種類に応じて処理する
+func (c *conn) handle(ctx context.Context, msg clientMessage) {
+	switch msg.Type {
+	case messageSubscribe:
+		c.subscribe(ctx, msg)
+	case messageUnsubscribe:
+		if cancel, ok := c.subscriptions[msg.ID]; ok {
+			cancel()
+			delete(c.subscriptions, msg.ID)
+		}
+		c.enqueue(unsubscribedMessage{Type: messageUnsubscribed, ID: msg.ID})
+	case messageMutate:
+		c.mutate(ctx, msg)
+	case messagePresence:
+		p := model.Presence{ConnectionID: c.id, Actor: c.actor, TodoID: msg.TodoID, State: msg.State, UpdatedAt: time.Now()}
+		if err := p.Validate(); err != nil {
+			c.enqueue(newErrorMessage(msg.ID, err))
+			return
+		}
+		c.handler.hub.update(c, p)
+	case messagePing:
+		c.enqueue(pongMessage{Type: messagePong, ID: msg.ID})
+	default:
+		c.enqueue(errorMessage{Type: messageError, ID: msg.ID, Code: codeInvalidArgument, Error: fmt.Sprintf("unknown message type %q", msg.Type)})
+	}
+}
+
+// subscribe は検索クエリに一致するTodoの一覧を送り、その後のTodoのイベントを送る購読を開始する
+// 同じIDの購読がある場合は、その購読を終了して新しい検索クエリで購読し直す
+func (c *conn) subscribe(ctx context.Context, msg clientMessage) {
+	if msg.ID == "" {
+		c.enqueue(errorMessage{Type: messageError, Code: codeInvalidArgument, Error: "id is required"})
+		return
+	}
+	if cancel, ok := c.subscriptions[msg.ID]; ok {
+		cancel()
+		delete(c.subscriptions, msg.ID)
+	}
+	if len(c.subscriptions) >= maxSubscriptions {
+		c.enqueue(errorMessage{Type: messageError, ID: msg.ID, Code: codeInvalidArgument,
+			Error: fmt.Sprintf("too many subscriptions (max %d)", maxSubscriptions)})
+		return
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	// 一覧を取得する間のイベントを取りこぼさないように、一覧より先に購読を開始する
+	stream, err := c.handler.streamTodosUseCase.Execute(subCtx, msg.Query, "")
+	if err != nil {
+		cancel()
+		c.enqueue(newErrorMessage(msg.ID, err))
+		return
+	}
+	todos, err := c.handler.getAllTodosUseCase.Execute(subCtx, msg.Query)
+	if err != nil {
+		cancel()
+		c.enqueue(newErrorMessage(msg.ID, err))
+		return
+	}
+	c.subscriptions[msg.ID] = cancel
+	c.enqueue(subscribedMessage{Type: messageSubscribed, ID: msg.ID, Todos: todos})
+	go c.forward(subCtx, msg.ID, stream)
+}
+
+// forward は購読のイベントを送る
+// サーバー側で購読が終了した場合 (受け取りが遅れた場合など) は unsubscribed を送り、クライアントに購読し直してもらう
+func (c *conn) forward(ctx context.Context, id string, stream *usecase.TodoStream) {
+	for event := range stream.Events {
+		c.enqueue(eventMessage{Type: messageEvent, ID: id, Event: event})
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	reason := "stream closed"
+	if err := stream.Err(); err != nil {
+		reason = err.Error()
+	}
+	c.enqueue(unsubscribedMessage{Type: messageUnsubscribed, ID: id, Reason: reason})
+}
+
+// mutate はTodoを作成・更新・削除し、結果を送る
+// 変更は HTTP の API と同じユースケースで行うため、購読している全ての接続にイベントとして届く
+func (c *conn) mutate(ctx context.Context, msg clientMessage) {
+	ctx = model.WithAuditInfo(ctx, model.AuditInfo{Actor: c.actor})
+	if msg.Version != nil {
+		ctx = model.WithExpectedVersion(ctx, *msg.Version)
+	}
+
+	var (
+		todo *model.Todo
+		err  error
+	)
+	switch {
+	case msg.Op != opCreate && msg.TodoID == "":
+		err = fmt.Errorf("%w: todo_id is required", model.ErrInvalidArgument)
+	case (msg.Op == opCreate || msg.Op == opUpdate) && msg.Todo == nil:
+		err = fmt.Errorf("%w: todo is required", model.ErrInvalidArgument)
+	case msg.Op == opPatch && msg.Patch == nil:
+		err = fmt.Errorf("%w: patch is required", model.ErrInvalidArgument)
+	case msg.Op == opCreate:
+		todo, err = c.handler.createTodoUseCase.Execute(ctx, *msg.Todo)
+	case msg.Op == opUpdate:
+		todo, err = c.handler.updateTodoUseCase.Execute(ctx, msg.TodoID, *msg.Todo, msg.Scope)
+	case msg.Op == opPatch:
+		todo, err = c.handler.patchTodoUseCase.Execute(ctx, msg.TodoID, *msg.Patch, msg.Scope)
+	case msg.Op == opDelete:
+		err = c.handler.deleteTodoUseCase.Execute(ctx, msg.TodoID, msg.Cascade)
+	default:
+		err = fmt.Errorf("%w: unknown op %q", model.ErrInvalidArgument, msg.Op)
+	}
+	if err != nil {
+		c.enqueue(newErrorMessage(msg.ID, err))
+		return
+	}
+	c.enqueue(resultMessage{Type: messageResult, ID: msg.ID, Todo: todo})
+}
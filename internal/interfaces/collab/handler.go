@@ -0,0 +1,76 @@
+package collab
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/interfaces/wsaccept"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// Handler はTodoの共同編集の WebSocket の接続を受け付ける
+type Handler struct {
+	tickets            *Tickets
+	hub                *hub
+	streamTodosUseCase usecase.StreamTodos
+	getAllTodosUseCase usecase.GetAllTodos
+	createTodoUseCase  usecase.CreateTodo
+	updateTodoUseCase  usecase.UpdateTodo
+	patchTodoUseCase   usecase.PatchTodo
+	deleteTodoUseCase  usecase.DeleteTodo
+}
+
+// NewHandler は collab.Handler のコンストラクタ
+func NewHandler(
+	tickets *Tickets,
+	streamTodosUseCase usecase.StreamTodos,
+	getAllTodosUseCase usecase.GetAllTodos,
+	createTodoUseCase usecase.CreateTodo,
+	updateTodoUseCase usecase.UpdateTodo,
+	patchTodoUseCase usecase.PatchTodo,
+	deleteTodoUseCase usecase.DeleteTodo,
+) *Handler {
+	return &Handler{
+		tickets:            tickets,
+		hub:                newHub(),
+		streamTodosUseCase: streamTodosUseCase,
+		getAllTodosUseCase: getAllTodosUseCase,
+		createTodoUseCase:  createTodoUseCase,
+		updateTodoUseCase:  updateTodoUseCase,
+		patchTodoUseCase:   patchTodoUseCase,
+		deleteTodoUseCase:  deleteTodoUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/collab/tickets", h.IssueTicket)
+	router.GET("/collab", h.Connect)
+}
+
+// IssueTicket はリクエストの操作者として接続するためのチケットを発行するハンドラー
+func (h *Handler) IssueTicket(ctx *gin.Context) {
+	actor := model.AuditInfoFromContext(ctx.Request.Context()).Actor
+	ticket, expiresAt := h.tickets.Issue(actor, time.Now())
+	ctx.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
+// Connect はチケットを検証して WebSocket の接続を受け付け、切断されるまでメッセージを処理するハンドラー
+// ブラウザからの接続は同じオリジンからのみ受け付ける
+func (h *Handler) Connect(ctx *gin.Context) {
+	actor, err := h.tickets.Verify(ctx.Query("ticket"), time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	ws, err := wsaccept.Accept(ctx, nil)
+	if err != nil {
+		// Accept がエラーのレスポンスを返している
+		return
+	}
+	ws.SetReadLimit(maxMessageSize)
+	newConn(h, ws, actor).run(ctx.Request.Context())
+}
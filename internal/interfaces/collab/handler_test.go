@@ -0,0 +1,188 @@
+package collab_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+	"github.com/qushot/gin-todo-api/internal/interfaces/collab"
+	"github.com/qushot/gin-todo-api/internal/interfaces/middleware"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// message はサーバーから受け取ったメッセージ (検証に使うフィールドのみ)
+type message struct {
+	Type         string                `json:"type"`
+	ID           string                `json:"id"`
+	ConnectionID string                `json:"connection_id"`
+	Actor        string                `json:"actor"`
+	Code         string                `json:"code"`
+	Todo         *model.Todo           `json:"todo"`
+	Todos        []model.Todo          `json:"todos"`
+	Event        model.TodoStreamEvent `json:"event"`
+	Presence     json.RawMessage       `json:"presence"`
+}
+
+// testServer は in-memory のリポジトリで共同編集の API を提供するサーバー
+type testServer struct {
+	*httptest.Server
+	relay usecase.RelayTodoEvents
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := inmemory.NewEmptyDB()
+	todoRepo := inmemory.NewTodo(db)
+	dependencyRepo := inmemory.NewTodoDependency(db)
+	outboxRepo := inmemory.NewOutbox(db)
+	txManager := inmemory.NewTxManager(db)
+	broker := stream.NewBroker(stream.DefaultRetention)
+	handler := collab.NewHandler(
+		collab.NewTickets([]byte("secret"), time.Minute),
+		usecase.NewStreamTodos(todoRepo, broker),
+		usecase.NewGetAllTodos(todoRepo),
+		usecase.NewCreateTodo(txManager, todoRepo, outboxRepo),
+		usecase.NewUpdateTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewPatchTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewDeleteTodo(txManager, todoRepo, outboxRepo),
+	)
+
+	r := gin.New()
+	r.Use(middleware.AuditInfo)
+	handler.RegisterRoutes(r.Group("/api/v1"))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return &testServer{Server: srv, relay: usecase.NewRelayTodoEvents(outboxRepo, broker)}
+}
+
+// dial は actor のチケットを発行して接続し、hello のメッセージを返す
+func (s *testServer) dial(t *testing.T, ctx context.Context, actor string) (*websocket.Conn, message) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, s.URL+"/api/v1/collab/tickets", nil)
+	req.Header.Set(middleware.ActorHeader, actor)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("issue ticket: %v", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Ticket string `json:"ticket"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode ticket: %v", err)
+	}
+
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(s.URL, "http")+"/api/v1/collab?ticket="+body.Ticket, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { ws.CloseNow() })
+	return ws, receive(t, ctx, ws, "hello")
+}
+
+// receive は typ のメッセージを受け取るまで読み進める
+func receive(t *testing.T, ctx context.Context, ws *websocket.Conn, typ string) message {
+	t.Helper()
+	for {
+		var msg message
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("waiting for %q: %v", typ, err)
+		}
+		if msg.Type == typ {
+			return msg
+		}
+	}
+}
+
+func send(t *testing.T, ctx context.Context, ws *websocket.Conn, msg map[string]any) {
+	t.Helper()
+	if err := wsjson.Write(ctx, ws, msg); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+}
+
+func TestHandler_Connect_invalidTicket(t *testing.T) {
+	srv := newTestServer(t)
+
+	_, resp, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/collab?ticket=invalid", nil)
+	if err == nil {
+		t.Fatal("Dial() error = nil, want error")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Dial() response = %v, want status %d", resp, http.StatusUnauthorized)
+	}
+}
+
+func TestHandler_Connect(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	alice, hello := srv.dial(t, ctx, "alice")
+	if hello.Actor != "alice" || hello.ConnectionID == "" {
+		t.Errorf("hello = %+v, want actor alice with a connection id", hello)
+	}
+	bob, bobHello := srv.dial(t, ctx, "bob")
+	if got := receive(t, ctx, alice, "presence"); !strings.Contains(string(got.Presence), bobHello.ConnectionID) {
+		t.Errorf("presence = %s, want bob's connection", got.Presence)
+	}
+
+	send(t, ctx, alice, map[string]any{"type": "subscribe", "id": "all"})
+	if got := receive(t, ctx, alice, "subscribed"); got.ID != "all" || len(got.Todos) != 0 {
+		t.Errorf("subscribed = %+v, want an empty snapshot", got)
+	}
+
+	// bob が作成したTodoのイベントが alice に届く
+	send(t, ctx, bob, map[string]any{"type": "mutate", "id": "m1", "op": "create", "todo": map[string]any{"title": "shared"}})
+	created := receive(t, ctx, bob, "result")
+	if created.ID != "m1" || created.Todo == nil || created.Todo.Title != "shared" {
+		t.Fatalf("result = %+v, want the created todo", created)
+	}
+	if _, err := srv.relay.Execute(ctx, time.Now()); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	event := receive(t, ctx, alice, "event")
+	if event.ID != "all" || event.Event.Type != model.TodoEventCreated || event.Event.TodoID != created.Todo.ID ||
+		event.Event.Actor != "bob" || !event.Event.Match {
+		t.Errorf("event = %+v, want bob's created event for %s", event, created.Todo.ID)
+	}
+
+	// 古いバージョンを指定した変更は競合する
+	send(t, ctx, alice, map[string]any{
+		"type": "mutate", "id": "m2", "op": "patch", "todo_id": created.Todo.ID,
+		"version": created.Todo.Version + 1, "patch": map[string]any{"title": "stale"},
+	})
+	if got := receive(t, ctx, alice, "error"); got.ID != "m2" || got.Code != "conflict" {
+		t.Errorf("error = %+v, want conflict for m2", got)
+	}
+	send(t, ctx, alice, map[string]any{
+		"type": "mutate", "id": "m3", "op": "patch", "todo_id": created.Todo.ID,
+		"version": created.Todo.Version, "patch": map[string]any{"title": "renamed"},
+	})
+	if got := receive(t, ctx, alice, "result"); got.ID != "m3" || got.Todo == nil || got.Todo.Title != "renamed" {
+		t.Errorf("result = %+v, want the renamed todo", got)
+	}
+
+	// 在席状況と切断が他の接続に届く
+	send(t, ctx, bob, map[string]any{"type": "presence", "todo_id": created.Todo.ID, "state": "editing"})
+	if got := receive(t, ctx, alice, "presence"); !strings.Contains(string(got.Presence), `"state":"editing"`) {
+		t.Errorf("presence = %s, want bob editing", got.Presence)
+	}
+	bob.Close(websocket.StatusNormalClosure, "")
+	if got := receive(t, ctx, alice, "left"); got.ConnectionID != bobHello.ConnectionID {
+		t.Errorf("left = %+v, want bob's connection %s", got, bobHello.ConnectionID)
+	}
+}
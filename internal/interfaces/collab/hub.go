@@ -0,0 +1,74 @@
+package collab
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// hub は同じプロセスの全ての接続とその在席状況を管理する
+// 在席状況はプロセス内でのみ共有する (複数のレプリカで共有するには、同じレプリカに接続する必要がある)
+type hub struct {
+	mu       sync.Mutex
+	conns    map[*conn]struct{}
+	presence map[*conn]model.Presence
+}
+
+// newHub は collab.hub のコンストラクタ
+func newHub() *hub {
+	return &hub{
+		conns:    make(map[*conn]struct{}),
+		presence: make(map[*conn]model.Presence),
+	}
+}
+
+// join は接続を登録して参加したことを全ての接続に送り、参加者全員の在席状況を返す
+func (h *hub) join(c *conn, p model.Presence) []model.Presence {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 参加した接続には hello で在席状況を送るため、登録する前に他の接続に送る
+	h.broadcast(presenceMessage{Type: messagePresence, Presence: p})
+	h.conns[c] = struct{}{}
+	h.presence[c] = p
+
+	snapshot := make([]model.Presence, 0, len(h.presence))
+	for _, p := range h.presence {
+		snapshot = append(snapshot, p)
+	}
+	slices.SortFunc(snapshot, func(a, b model.Presence) int {
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ConnectionID, b.ConnectionID))
+	})
+	return snapshot
+}
+
+// leave は接続の登録を解除し、切断したことを残りの接続に送る
+func (h *hub) leave(c *conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns, c)
+	delete(h.presence, c)
+	h.broadcast(leftMessage{Type: messageLeft, ConnectionID: c.id, Actor: c.actor})
+}
+
+// update は接続の在席状況を更新し、全ての接続に送る
+func (h *hub) update(c *conn, p model.Presence) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.conns[c]; !ok {
+		return
+	}
+	h.presence[c] = p
+	h.broadcast(presenceMessage{Type: messagePresence, Presence: p})
+}
+
+// broadcast は全ての接続にメッセージを送る (h.mu をロックした状態で呼び出す)
+func (h *hub) broadcast(msg any) {
+	for c := range h.conns {
+		c.enqueue(msg)
+	}
+}
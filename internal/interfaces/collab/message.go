@@ -0,0 +1,154 @@
+package collab
+
+import (
+	"errors"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// クライアントから送るメッセージの種類
+const (
+	// messageSubscribe は検索クエリに一致するTodoの一覧と、その変更の購読を開始する
+	messageSubscribe = "subscribe"
+	// messageUnsubscribe は購読を終了する
+	messageUnsubscribe = "unsubscribe"
+	// messageMutate はTodoを作成・更新・削除する
+	messageMutate = "mutate"
+	// messagePresence は在席状況 (閲覧中・編集中のTodo) を更新する
+	messagePresence = "presence"
+	// messagePing は接続を確認する (pong を返す)
+	messagePing = "ping"
+)
+
+// サーバーから送るメッセージの種類
+const (
+	// messageHello は接続した直後に、接続のIDと操作者、全ての参加者の在席状況を送る
+	messageHello = "hello"
+	// messageSubscribed は購読を開始した時点のTodoの一覧を送る
+	messageSubscribed = "subscribed"
+	// messageUnsubscribed は購読が終了したことを送る (サーバー側で終了した場合は reason を設定する)
+	messageUnsubscribed = "unsubscribed"
+	// messageEvent は購読の検索クエリに関係するTodoのイベントを送る
+	messageEvent = "event"
+	// messageResult は mutate の結果を送る
+	messageResult = "result"
+	// messageError はメッセージを処理できなかったことを送る
+	messageError = "error"
+	// messageLeft は参加者が切断したことを送る
+	messageLeft = "left"
+	// messagePong は ping への応答
+	messagePong = "pong"
+)
+
+// mutate の操作の種類 (それぞれ HTTP の API と同じユースケースで処理する)
+const (
+	opCreate = "create"
+	opUpdate = "update"
+	opPatch  = "patch"
+	opDelete = "delete"
+)
+
+// エラーの種類
+const (
+	codeInvalidArgument = "invalid_argument"
+	codeNotFound        = "not_found"
+	codeConflict        = "conflict"
+	codeInternal        = "internal"
+)
+
+// clientMessage はクライアントから送るメッセージ
+type clientMessage struct {
+	Type string `json:"type"`
+	// ID はメッセージを識別するID (応答に同じIDを設定する)。subscribe と unsubscribe では購読のID
+	ID string `json:"id"`
+	// Query は subscribe で購読する検索クエリ (一覧の API のクエリパラメータと同じ)
+	Query model.TodoQuery `json:"query"`
+	// Op は mutate の操作の種類
+	Op     string `json:"op"`
+	TodoID string `json:"todo_id"`
+	// Version を指定した場合、update、patch、delete で対象のTodoのバージョンが異なると conflict にする
+	Version *int                  `json:"version"`
+	Todo    *model.Todo           `json:"todo"`
+	Patch   *model.TodoPatch      `json:"patch"`
+	Scope   model.RecurrenceScope `json:"scope"`
+	Cascade bool                  `json:"cascade"`
+	// State は presence で更新する在席状況 (TodoID のTodoに対する状況、どのTodoも開いていない場合は両方とも空)
+	State model.PresenceState `json:"state"`
+}
+
+// helloMessage は messageHello のメッセージ
+type helloMessage struct {
+	Type         string           `json:"type"`
+	ConnectionID string           `json:"connection_id"`
+	Actor        string           `json:"actor"`
+	Presence     []model.Presence `json:"presence"`
+}
+
+// subscribedMessage は messageSubscribed のメッセージ
+type subscribedMessage struct {
+	Type  string       `json:"type"`
+	ID    string       `json:"id"`
+	Todos []model.Todo `json:"todos"`
+}
+
+// unsubscribedMessage は messageUnsubscribed のメッセージ
+type unsubscribedMessage struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// eventMessage は messageEvent のメッセージ
+type eventMessage struct {
+	Type  string                `json:"type"`
+	ID    string                `json:"id"`
+	Event model.TodoStreamEvent `json:"event"`
+}
+
+// resultMessage は messageResult のメッセージ (delete の場合は Todo を省略する)
+type resultMessage struct {
+	Type string      `json:"type"`
+	ID   string      `json:"id"`
+	Todo *model.Todo `json:"todo,omitempty"`
+}
+
+// errorMessage は messageError のメッセージ
+type errorMessage struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+// presenceMessage は messagePresence のメッセージ (参加した時と在席状況を更新した時に全ての接続に送る)
+type presenceMessage struct {
+	Type     string         `json:"type"`
+	Presence model.Presence `json:"presence"`
+}
+
+// leftMessage は messageLeft のメッセージ
+type leftMessage struct {
+	Type         string `json:"type"`
+	ConnectionID string `json:"connection_id"`
+	Actor        string `json:"actor"`
+}
+
+// pongMessage は messagePong のメッセージ
+type pongMessage struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+// newErrorMessage はエラーの種類に応じた errorMessage を返す
+func newErrorMessage(id string, err error) errorMessage {
+	code := codeInternal
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		code = codeNotFound
+	case errors.Is(err, model.ErrInvalidArgument):
+		code = codeInvalidArgument
+	case errors.Is(err, model.ErrConflict), errors.Is(err, model.ErrInvalidStatusTransition):
+		code = codeConflict
+	}
+	return errorMessage{Type: messageError, ID: id, Code: code, Error: err.Error()}
+}
@@ -0,0 +1,89 @@
+// Package collab は WebSocket によるTodoの共同編集 (変更の購読、更新、在席状況の共有) を提供する
+package collab
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTicketTTL は接続に使うチケットの有効期間のデフォルト値
+const DefaultTicketTTL = time.Minute
+
+// ErrInvalidTicket はチケットが正しくないか、有効期限が切れていることを表す
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+// Tickets は WebSocket の接続を認証するチケットを発行・検証する
+// ブラウザの WebSocket はヘッダーを指定できないため、HTTP のリクエストで発行したチケットをクエリパラメータで指定して接続する
+// チケットは操作者と有効期限に HMAC-SHA256 で署名したもので、同じ秘密鍵を使う全てのレプリカで検証できる
+type Tickets struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewTickets は collab.Tickets のコンストラクタ
+// secret が空の場合はランダムな秘密鍵を生成する (発行したプロセスでのみ検証できる)
+func NewTickets(secret []byte, ttl time.Duration) *Tickets {
+	if len(secret) == 0 {
+		slog.Warn("NOTE: Collaboration ticket secret is not set; tickets are valid only on this process")
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+	}
+	return &Tickets{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// Issue は actor として接続するためのチケットとその有効期限を返す
+// チケットは "<base64url の操作者>.<有効期限の UNIX 時間>.<署名の16進数>" の形式
+func (t *Tickets) Issue(actor string, now time.Time) (string, time.Time) {
+	expiresAt := now.Add(t.ttl).Truncate(time.Second)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(actor)) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + t.sign(payload), expiresAt
+}
+
+// Verify はチケットを検証し、チケットの操作者を返す
+func (t *Tickets) Verify(ticket string, now time.Time) (string, error) {
+	payload, sig, ok := cutLast(ticket, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(t.sign(payload))) {
+		return "", fmt.Errorf("%w: signature mismatch", ErrInvalidTicket)
+	}
+	encodedActor, expiresAt, _ := strings.Cut(payload, ".")
+	unix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed expiry", ErrInvalidTicket)
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return "", fmt.Errorf("%w: expired", ErrInvalidTicket)
+	}
+	actor, err := base64.RawURLEncoding.DecodeString(encodedActor)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed actor", ErrInvalidTicket)
+	}
+	return string(actor), nil
+}
+
+// sign は payload の HMAC-SHA256 を16進数で返す
+func (t *Tickets) sign(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// cutLast は s を最後の sep の前後に分ける
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
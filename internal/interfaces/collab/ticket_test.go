@@ -0,0 +1,76 @@
+package collab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTickets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tickets := NewTickets([]byte("secret"), time.Minute)
+	ticket, expiresAt := tickets.Issue("alice.example", now)
+	if want := now.Add(time.Minute); !expiresAt.Equal(want) {
+		t.Errorf("Issue() expiresAt = %v, want %v", expiresAt, want)
+	}
+
+	tests := []struct {
+		name    string
+		tickets *Tickets
+		ticket  string
+		now     time.Time
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "valid ticket",
+			tickets: tickets,
+			ticket:  ticket,
+			now:     now.Add(30 * time.Second),
+			want:    "alice.example",
+		},
+		{
+			name:    "expired ticket",
+			tickets: tickets,
+			ticket:  ticket,
+			now:     now.Add(2 * time.Minute),
+			wantErr: true,
+		},
+		{
+			name:    "different secret",
+			tickets: NewTickets([]byte("other"), time.Minute),
+			ticket:  ticket,
+			now:     now,
+			wantErr: true,
+		},
+		{
+			name:    "tampered expiry",
+			tickets: tickets,
+			ticket:  strings.Replace(ticket, ".", ".9", 1),
+			now:     now,
+			wantErr: true,
+		},
+		{
+			name:    "malformed ticket",
+			tickets: tickets,
+			ticket:  "",
+			now:     now,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tickets.Verify(tt.ticket, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("Verify() error = %v, want %v", err, ErrInvalidTicket)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
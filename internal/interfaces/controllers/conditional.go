@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// todoETag はTodoのバージョンから求めた強いETagを返す
+// (タグや依存関係の変更でもTodoのバージョンと更新日時を更新するため、レスポンスの内容が変わればETagも変わる)
+func todoETag(todo *model.Todo) string {
+	return fmt.Sprintf(`"v%d"`, todo.Version)
+}
+
+// todoListETag はTodoの一覧の件数と最終更新日時、期限による絞り込みの範囲から求めた強いETagを返す
+func todoListETag(stamp *model.TodoListStamp) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%d\n", stamp.Count, stamp.LastModified.UnixNano())
+	if err := json.NewEncoder(h).Encode(stamp.DueRange); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`, nil
+}
+
+// checkNotModified は ETag と Last-Modified (lastModified がゼロ値の場合は省略) をレスポンスヘッダーに設定し、
+// 条件付きリクエストの条件に一致する場合は 304 を返して true を返す
+// If-None-Match を指定した場合は If-Modified-Since を無視する (RFC 9110)
+func checkNotModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if inm := ctx.GetHeader("If-None-Match"); inm != "" {
+		if !matchETag(inm, etag) {
+			return false
+		}
+	} else {
+		ims, err := http.ParseTime(ctx.GetHeader("If-Modified-Since"))
+		if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(ims) {
+			return false
+		}
+	}
+
+	ctx.Status(http.StatusNotModified)
+	return true
+}
+
+// matchETag は If-None-Match の値のいずれかが etag に一致するかを返す (弱い比較)
+func matchETag(ifNoneMatch string, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// abortWithError はエラーの種類に応じたステータスコードでエラーレスポンスを返す
+func abortWithError(ctx *gin.Context, err error) {
+	ctx.JSON(errorStatus(err), gin.H{"error": err.Error()})
+}
+
+// errorStatus はエラーの種類に応じたステータスコードを返す
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrConflict), errors.Is(err, model.ErrInvalidStatusTransition):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
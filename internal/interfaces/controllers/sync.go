@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// Sync はオフラインのクライアントとの差分同期のためのコントローラー
+type Sync struct {
+	getTodoChangesUseCase  usecase.GetTodoChanges
+	pushTodoChangesUseCase usecase.PushTodoChanges
+}
+
+// NewSync は controllers.Sync のコンストラクタ
+func NewSync(getTodoChangesUseCase usecase.GetTodoChanges, pushTodoChangesUseCase usecase.PushTodoChanges) *Sync {
+	return &Sync{
+		getTodoChangesUseCase:  getTodoChangesUseCase,
+		pushTodoChangesUseCase: pushTodoChangesUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *Sync) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/sync", c.Pull)
+	router.POST("/sync", c.Push)
+}
+
+// syncResult は送信された1件の変更の結果のレスポンス
+type syncResult struct {
+	model.TodoSyncResult
+	// Status は変更ごとのステータスコード
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// syncPushResponse は変更の送信のレスポンス
+type syncPushResponse struct {
+	Results []syncResult `json:"results"`
+}
+
+// Pull は同期トークン (クエリパラメータ since) 以降のTodoの変更の差分を取得するハンドラー
+// since を省略した場合は全てのTodoを返す。レスポンスの token を次回の since に指定する
+func (c *Sync) Pull(ctx *gin.Context) {
+	sync, err := c.getTodoChangesUseCase.Execute(ctx.Request.Context(), ctx.Query("since"))
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sync)
+}
+
+// Push はクライアントでオフラインの間に行った変更をまとめて適用するハンドラー
+// 一部の変更が競合または失敗しても 200 を返し、変更ごとの結果で報告する
+func (c *Sync) Push(ctx *gin.Context) {
+	var push model.TodoSyncPush
+	if err := ctx.ShouldBindJSON(&push); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := c.pushTodoChangesUseCase.Execute(ctx.Request.Context(), push)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	res := syncPushResponse{Results: make([]syncResult, len(results))}
+	for i, r := range results {
+		res.Results[i] = syncResult{TodoSyncResult: r, Status: syncResultStatus(r)}
+		if r.Err != nil {
+			res.Results[i].Error = r.Err.Error()
+		}
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// syncResultStatus は変更の結果に応じたステータスコードを返す
+func syncResultStatus(r model.TodoSyncResult) int {
+	switch {
+	case r.Resolution == model.SyncResolutionConflict:
+		return http.StatusConflict
+	case r.Err != nil:
+		return errorStatus(r.Err)
+	case r.Op == model.SyncOperationCreate:
+		return http.StatusCreated
+	case r.Op == model.SyncOperationDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
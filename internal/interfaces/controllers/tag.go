@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// Tag はタグ操作のためのコントローラー
+type Tag struct {
+	listTagsUseCase  usecase.ListTags
+	renameTagUseCase usecase.RenameTag
+	mergeTagsUseCase usecase.MergeTags
+	deleteTagUseCase usecase.DeleteTag
+}
+
+// NewTag は controllers.Tag のコンストラクタ
+func NewTag(
+	listTagsUseCase usecase.ListTags,
+	renameTagUseCase usecase.RenameTag,
+	mergeTagsUseCase usecase.MergeTags,
+	deleteTagUseCase usecase.DeleteTag,
+) *Tag {
+	return &Tag{
+		listTagsUseCase:  listTagsUseCase,
+		renameTagUseCase: renameTagUseCase,
+		mergeTagsUseCase: mergeTagsUseCase,
+		deleteTagUseCase: deleteTagUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *Tag) RegisterRoutes(router *gin.RouterGroup) {
+	tagRoutes := router.Group("/tags")
+	{
+		tagRoutes.GET("", c.List)
+		tagRoutes.PATCH("/:id", c.Rename)
+		tagRoutes.POST("/:id/merge", c.Merge)
+		tagRoutes.DELETE("/:id", c.Delete)
+	}
+}
+
+// renameTagRequest はタグ名変更のリクエストボディ
+type renameTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// mergeTagsRequest はタグ統合のリクエストボディ
+type mergeTagsRequest struct {
+	Into string `json:"into" binding:"required"`
+}
+
+// List は全てのタグを取得するハンドラー
+func (c *Tag) List(ctx *gin.Context) {
+	tags, err := c.listTagsUseCase.Execute(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tags)
+}
+
+// Rename は指定されたIDのタグ名を変更するハンドラー
+func (c *Tag) Rename(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req renameTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := c.renameTagUseCase.Execute(ctx.Request.Context(), id, req.Name)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+// Merge は指定されたIDのタグを別のタグに統合するハンドラー
+func (c *Tag) Merge(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req mergeTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := c.mergeTagsUseCase.Execute(ctx.Request.Context(), id, req.Into)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
+// Delete は指定されたIDのタグを削除するハンドラー
+func (c *Tag) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := c.deleteTagUseCase.Execute(ctx.Request.Context(), id); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -1,8 +1,8 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -12,27 +12,41 @@ import (
 
 // Todo はTodo操作のためのコントローラー
 type Todo struct {
-	getAllTodosUseCase usecase.GetAllTodos
-	getTodoByIDUseCase usecase.GetTodoByID
-	createTodoUseCase  usecase.CreateTodo
-	updateTodoUseCase  usecase.UpdateTodo
-	deleteTodoUseCase  usecase.DeleteTodo
+	getAllTodosUseCase      usecase.GetAllTodos
+	getTodoListStampUseCase usecase.GetTodoListStamp
+	getTodoByIDUseCase      usecase.GetTodoByID
+	createTodoUseCase       usecase.CreateTodo
+	updateTodoUseCase       usecase.UpdateTodo
+	patchTodoUseCase        usecase.PatchTodo
+	deleteTodoUseCase       usecase.DeleteTodo
+
+	listTodoChildrenUseCase    usecase.ListTodoChildren
+	reorderTodoChildrenUseCase usecase.ReorderTodoChildren
 }
 
 // NewTodo は controllers.Todo のコンストラクタ
 func NewTodo(
 	getAllTodosUseCase usecase.GetAllTodos,
+	getTodoListStampUseCase usecase.GetTodoListStamp,
 	getTodoByIDUseCase usecase.GetTodoByID,
 	createTodoUseCase usecase.CreateTodo,
 	updateTodoUseCase usecase.UpdateTodo,
+	patchTodoUseCase usecase.PatchTodo,
 	deleteTodoUseCase usecase.DeleteTodo,
+	listTodoChildrenUseCase usecase.ListTodoChildren,
+	reorderTodoChildrenUseCase usecase.ReorderTodoChildren,
 ) *Todo {
 	return &Todo{
-		getAllTodosUseCase: getAllTodosUseCase,
-		getTodoByIDUseCase: getTodoByIDUseCase,
-		createTodoUseCase:  createTodoUseCase,
-		updateTodoUseCase:  updateTodoUseCase,
-		deleteTodoUseCase:  deleteTodoUseCase,
+		getAllTodosUseCase:      getAllTodosUseCase,
+		getTodoListStampUseCase: getTodoListStampUseCase,
+		getTodoByIDUseCase:      getTodoByIDUseCase,
+		createTodoUseCase:       createTodoUseCase,
+		updateTodoUseCase:       updateTodoUseCase,
+		patchTodoUseCase:        patchTodoUseCase,
+		deleteTodoUseCase:       deleteTodoUseCase,
+
+		listTodoChildrenUseCase:    listTodoChildrenUseCase,
+		reorderTodoChildrenUseCase: reorderTodoChildrenUseCase,
 	}
 }
 
@@ -44,11 +58,30 @@ func (c *Todo) RegisterRoutes(router *gin.RouterGroup) {
 		todoRoutes.POST("", c.Create)
 		todoRoutes.GET("/:id", c.Read)
 		todoRoutes.PUT("/:id", c.Update)
+		todoRoutes.PATCH("/:id", c.Patch)
 		todoRoutes.DELETE("/:id", c.Delete)
+		todoRoutes.GET("/:id/children", c.ListChildren)
+		todoRoutes.PUT("/:id/children/order", c.ReorderChildren)
 	}
 }
 
+// deleteTodoQuery はTodo削除のクエリパラメータ
+type deleteTodoQuery struct {
+	Cascade bool `form:"cascade"`
+}
+
+// updateTodoQuery はTodo更新のクエリパラメータ
+type updateTodoQuery struct {
+	Scope model.RecurrenceScope `form:"scope"`
+}
+
+// reorderChildrenRequest は子のTodoの並び替えのリクエストボディ
+type reorderChildrenRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
 // List は全てのTodoを取得するハンドラー
+// 一覧が変わっていない場合は、一覧を取得せずに 304 を返す
 func (c *Todo) List(ctx *gin.Context) {
 	var query model.TodoQuery
 	if err := ctx.ShouldBindQuery(&query); err != nil {
@@ -56,9 +89,28 @@ func (c *Todo) List(ctx *gin.Context) {
 		return
 	}
 
+	stamp, err := c.getTodoListStampUseCase.Execute(ctx.Request.Context(), query)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	etag, err := todoListETag(stamp)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+	// 期限による絞り込みは日時の経過により結果が変わるため、更新日時では判定しない
+	lastModified := stamp.LastModified
+	if stamp.DueRange != nil {
+		lastModified = time.Time{}
+	}
+	if checkNotModified(ctx, etag, lastModified) {
+		return
+	}
+
 	todos, err := c.getAllTodosUseCase.Execute(ctx.Request.Context(), query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -75,7 +127,7 @@ func (c *Todo) Create(ctx *gin.Context) {
 
 	todo, err := c.createTodoUseCase.Execute(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(ctx, err)
 		return
 	}
 
@@ -88,11 +140,10 @@ func (c *Todo) Read(ctx *gin.Context) {
 
 	todo, err := c.getTodoByIDUseCase.Execute(ctx.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, errors.New("not found")) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(ctx, err)
+		return
+	}
+	if checkNotModified(ctx, todoETag(todo), todo.UpdatedAt) {
 		return
 	}
 
@@ -102,38 +153,94 @@ func (c *Todo) Read(ctx *gin.Context) {
 // Update は指定されたIDのTodoを更新するハンドラー
 func (c *Todo) Update(ctx *gin.Context) {
 	id := ctx.Param("id")
+	var query updateTodoQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	var req model.Todo
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	todo, err := c.updateTodoUseCase.Execute(ctx.Request.Context(), id, req)
+	todo, err := c.updateTodoUseCase.Execute(ctx.Request.Context(), id, req, query.Scope)
 	if err != nil {
-		if errors.Is(err, errors.New("not found")) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, todo)
 }
 
-// Delete は指定されたIDのTodoを削除するハンドラー
+// Patch は指定されたIDのTodoを部分更新するハンドラー
+func (c *Todo) Patch(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var query updateTodoQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req model.TodoPatch
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	todo, err := c.patchTodoUseCase.Execute(ctx.Request.Context(), id, req, query.Scope)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
+
+// Delete は指定されたIDのTodoをゴミ箱に移動するハンドラー
 func (c *Todo) Delete(ctx *gin.Context) {
 	id := ctx.Param("id")
+	var query deleteTodoQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	err := c.deleteTodoUseCase.Execute(ctx.Request.Context(), id)
+	err := c.deleteTodoUseCase.Execute(ctx.Request.Context(), id, query.Cascade)
 	if err != nil {
-		if errors.Is(err, errors.New("not found")) {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "not found"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abortWithError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusNoContent, nil)
 }
+
+// ListChildren は指定されたIDのTodoの子を取得するハンドラー
+func (c *Todo) ListChildren(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	children, err := c.listTodoChildrenUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, children)
+}
+
+// ReorderChildren は指定されたIDのTodoの子を並び替えるハンドラー
+func (c *Todo) ReorderChildren(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req reorderChildrenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	children, err := c.reorderTodoChildrenUseCase.Execute(ctx.Request.Context(), id, req.IDs)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, children)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoArchive はTodoのアーカイブの操作のためのコントローラー
+type TodoArchive struct {
+	archiveTodoUseCase   usecase.ArchiveTodo
+	unarchiveTodoUseCase usecase.UnarchiveTodo
+}
+
+// NewTodoArchive は controllers.TodoArchive のコンストラクタ
+func NewTodoArchive(
+	archiveTodoUseCase usecase.ArchiveTodo,
+	unarchiveTodoUseCase usecase.UnarchiveTodo,
+) *TodoArchive {
+	return &TodoArchive{
+		archiveTodoUseCase:   archiveTodoUseCase,
+		unarchiveTodoUseCase: unarchiveTodoUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *TodoArchive) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/todos/:id/archive", c.Archive)
+	router.POST("/todos/:id/unarchive", c.Unarchive)
+}
+
+// Archive は指定されたIDのTodoをアーカイブするハンドラー
+func (c *TodoArchive) Archive(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	todo, err := c.archiveTodoUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
+
+// Unarchive は指定されたIDのTodoのアーカイブを解除するハンドラー
+func (c *TodoArchive) Unarchive(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	todo, err := c.unarchiveTodoUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoBatch はTodoの一括操作のためのコントローラー
+type TodoBatch struct {
+	batchTodosUseCase usecase.BatchTodos
+}
+
+// NewTodoBatch は controllers.TodoBatch のコンストラクタ
+func NewTodoBatch(batchTodosUseCase usecase.BatchTodos) *TodoBatch {
+	return &TodoBatch{
+		batchTodosUseCase: batchTodosUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+// gin はパスの途中の ":" もパスパラメータとして扱い、エスケープした "\\:" は Engine.Run を経由しない場合に元に戻されないため、
+// "/todos" に続く部分をパラメータで受け取り、リテラルの "/todos:batch" の場合のみ一括操作を行う
+func (c *TodoBatch) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/todos:action", c.dispatch)
+}
+
+// dispatch は "/todos:batch" へのリクエストを Batch に渡し、それ以外のパスは 404 を返す
+func (c *TodoBatch) dispatch(ctx *gin.Context) {
+	if ctx.Param("action") != ":batch" {
+		abortWithError(ctx, model.ErrNotFound)
+		return
+	}
+	c.Batch(ctx)
+}
+
+// batchResult は一括操作の1件の操作の結果のレスポンス
+type batchResult struct {
+	model.TodoBatchResult
+	// Status は操作ごとのステータスコード (atomic で他の操作が失敗したために適用しなかった場合は 424)
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// batchResponse は一括操作のレスポンス
+type batchResponse struct {
+	Mode    model.BatchMode `json:"mode"`
+	Results []batchResult   `json:"results"`
+}
+
+// Batch はTodoの作成、更新、完了、ゴミ箱への移動をまとめて行うハンドラー
+// best_effort の場合は一部の操作が失敗しても 200 を返し、atomic で失敗した場合は最初に失敗した操作のステータスコードを返す
+func (c *TodoBatch) Batch(ctx *gin.Context) {
+	var batch model.TodoBatch
+	if err := ctx.ShouldBindJSON(&batch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := c.batchTodosUseCase.Execute(ctx.Request.Context(), batch)
+	if results == nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	res := batchResponse{Mode: batch.Mode, Results: make([]batchResult, len(results))}
+	if res.Mode == "" {
+		res.Mode = model.BatchModeAtomic
+	}
+	for i, r := range results {
+		res.Results[i] = batchResult{TodoBatchResult: r, Status: batchResultStatus(r)}
+		if r.Err != nil {
+			res.Results[i].Error = r.Err.Error()
+		}
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = errorStatus(err)
+	}
+	ctx.JSON(status, res)
+}
+
+// batchResultStatus は操作の結果に応じたステータスコードを返す
+func batchResultStatus(r model.TodoBatchResult) int {
+	switch {
+	case r.Skipped:
+		return http.StatusFailedDependency
+	case r.Err != nil:
+		return errorStatus(r.Err)
+	case r.Op == model.BatchOperationCreate:
+		return http.StatusCreated
+	case r.Op == model.BatchOperationDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
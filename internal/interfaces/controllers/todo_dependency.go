@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoDependency はTodoの依存関係の操作のためのコントローラー
+type TodoDependency struct {
+	listTodoBlockersUseCase     usecase.ListTodoBlockers
+	addTodoDependencyUseCase    usecase.AddTodoDependency
+	removeTodoDependencyUseCase usecase.RemoveTodoDependency
+}
+
+// NewTodoDependency は controllers.TodoDependency のコンストラクタ
+func NewTodoDependency(
+	listTodoBlockersUseCase usecase.ListTodoBlockers,
+	addTodoDependencyUseCase usecase.AddTodoDependency,
+	removeTodoDependencyUseCase usecase.RemoveTodoDependency,
+) *TodoDependency {
+	return &TodoDependency{
+		listTodoBlockersUseCase:     listTodoBlockersUseCase,
+		addTodoDependencyUseCase:    addTodoDependencyUseCase,
+		removeTodoDependencyUseCase: removeTodoDependencyUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *TodoDependency) RegisterRoutes(router *gin.RouterGroup) {
+	dependencyRoutes := router.Group("/todos/:id/dependencies")
+	{
+		dependencyRoutes.GET("", c.List)
+		dependencyRoutes.POST("", c.Add)
+		dependencyRoutes.DELETE("/:blockerId", c.Remove)
+	}
+}
+
+// addTodoDependencyRequest は依存関係の追加のリクエストボディ
+type addTodoDependencyRequest struct {
+	BlockedBy string `json:"blocked_by" binding:"required"`
+}
+
+// List は指定されたIDのTodoをブロックしているTodoを取得するハンドラー
+func (c *TodoDependency) List(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	blockers, err := c.listTodoBlockersUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, blockers)
+}
+
+// Add は指定されたIDのTodoに依存関係を追加するハンドラー
+func (c *TodoDependency) Add(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req addTodoDependencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	todo, err := c.addTodoDependencyUseCase.Execute(ctx.Request.Context(), id, req.BlockedBy)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
+
+// Remove は指定されたIDのTodoから依存関係を削除するハンドラー
+func (c *TodoDependency) Remove(ctx *gin.Context) {
+	id := ctx.Param("id")
+	blockerID := ctx.Param("blockerId")
+
+	if err := c.removeTodoDependencyUseCase.Execute(ctx.Request.Context(), id, blockerID); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoHistory はTodoの変更履歴の操作のためのコントローラー
+type TodoHistory struct {
+	listTodoHistoryUseCase usecase.ListTodoHistory
+	revertTodoUseCase      usecase.RevertTodo
+}
+
+// NewTodoHistory は controllers.TodoHistory のコンストラクタ
+func NewTodoHistory(
+	listTodoHistoryUseCase usecase.ListTodoHistory,
+	revertTodoUseCase usecase.RevertTodo,
+) *TodoHistory {
+	return &TodoHistory{
+		listTodoHistoryUseCase: listTodoHistoryUseCase,
+		revertTodoUseCase:      revertTodoUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *TodoHistory) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/todos/:id/history", c.List)
+	router.POST("/todos/:id/revert", c.Revert)
+}
+
+// List は指定されたIDのTodoの変更履歴を取得するハンドラー
+func (c *TodoHistory) List(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	histories, err := c.listTodoHistoryUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, histories)
+}
+
+// revertTodoRequest は以前のバージョンに戻すリクエストボディ
+type revertTodoRequest struct {
+	Version int `json:"version" binding:"required,min=1"`
+}
+
+// Revert は指定されたIDのTodoを以前のバージョンの内容に戻すハンドラー
+func (c *TodoHistory) Revert(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req revertTodoRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	todo, err := c.revertTodoUseCase.Execute(ctx.Request.Context(), id, req.Version)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
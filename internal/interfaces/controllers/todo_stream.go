@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// streamHeartbeatInterval はイベントがない間も接続を維持するために、コメントを送る間隔
+const streamHeartbeatInterval = 15 * time.Second
+
+// TodoStream はTodoの変更をリアルタイムに配信するためのコントローラー
+type TodoStream struct {
+	streamTodosUseCase usecase.StreamTodos
+}
+
+// NewTodoStream は controllers.TodoStream のコンストラクタ
+func NewTodoStream(streamTodosUseCase usecase.StreamTodos) *TodoStream {
+	return &TodoStream{
+		streamTodosUseCase: streamTodosUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *TodoStream) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/todos/stream", c.Stream)
+}
+
+// Stream は一覧と同じ検索クエリに関係するTodoのイベントを Server-Sent Events で配信するハンドラー
+// イベントのIDはTodoのイベントのIDで、Last-Event-ID ヘッダーを指定した場合はその続きから配信する
+// 続きから配信できない場合は stream.reset イベントを最初に配信する (受信側で一覧を取得し直す)
+func (c *TodoStream) Stream(ctx *gin.Context) {
+	var query model.TodoQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stream, err := c.streamTodosUseCase.Execute(ctx.Request.Context(), query, ctx.GetHeader("Last-Event-ID"))
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	// リバースプロキシ (nginx) にバッファリングさせない
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	// 接続したことをすぐに受信側に伝えるため、最初のイベントを待たずにヘッダーを送る
+	if _, err := io.WriteString(ctx.Writer, ": connected\n\n"); err != nil {
+		return
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-stream.Events:
+			if !ok {
+				if err := stream.Err(); err != nil {
+					slog.ErrorContext(ctx.Request.Context(), "Todo stream closed", slog.Any("error", err))
+				}
+				return
+			}
+			if err := writeStreamEvent(ctx.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// writeStreamEvent はイベントを Server-Sent Events の形式で書き込む
+func writeStreamEvent(w io.Writer, event model.TodoStreamEvent) error {
+	if event.Type == model.TodoEventStreamReset {
+		_, err := fmt.Fprintf(w, "event: %s\ndata: {}\n\n", event.Type)
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
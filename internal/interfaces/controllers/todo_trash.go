@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoTrash はTodoのゴミ箱の操作のためのコントローラー
+type TodoTrash struct {
+	listTrashedTodosUseCase usecase.ListTrashedTodos
+	restoreTodoUseCase      usecase.RestoreTodo
+	purgeTodoUseCase        usecase.PurgeTodo
+}
+
+// NewTodoTrash は controllers.TodoTrash のコンストラクタ
+func NewTodoTrash(
+	listTrashedTodosUseCase usecase.ListTrashedTodos,
+	restoreTodoUseCase usecase.RestoreTodo,
+	purgeTodoUseCase usecase.PurgeTodo,
+) *TodoTrash {
+	return &TodoTrash{
+		listTrashedTodosUseCase: listTrashedTodosUseCase,
+		restoreTodoUseCase:      restoreTodoUseCase,
+		purgeTodoUseCase:        purgeTodoUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *TodoTrash) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/todos/trash", c.List)
+	router.DELETE("/todos/trash/:id", c.Purge)
+	router.POST("/todos/:id/restore", c.Restore)
+}
+
+// List はゴミ箱にあるTodoを取得するハンドラー
+func (c *TodoTrash) List(ctx *gin.Context) {
+	todos, err := c.listTrashedTodosUseCase.Execute(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todos)
+}
+
+// Restore は指定されたIDのTodoをゴミ箱から元に戻すハンドラー
+func (c *TodoTrash) Restore(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	todo, err := c.restoreTodoUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todo)
+}
+
+// Purge は指定されたIDのTodoをゴミ箱から完全に削除するハンドラー
+func (c *TodoTrash) Purge(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := c.purgeTodoUseCase.Execute(ctx.Request.Context(), id); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// View はビュー操作のためのコントローラー
+type View struct {
+	listViewsUseCase    usecase.ListViews
+	getViewByIDUseCase  usecase.GetViewByID
+	createViewUseCase   usecase.CreateView
+	updateViewUseCase   usecase.UpdateView
+	deleteViewUseCase   usecase.DeleteView
+	getViewTodosUseCase usecase.GetViewTodos
+}
+
+// NewView は controllers.View のコンストラクタ
+func NewView(
+	listViewsUseCase usecase.ListViews,
+	getViewByIDUseCase usecase.GetViewByID,
+	createViewUseCase usecase.CreateView,
+	updateViewUseCase usecase.UpdateView,
+	deleteViewUseCase usecase.DeleteView,
+	getViewTodosUseCase usecase.GetViewTodos,
+) *View {
+	return &View{
+		listViewsUseCase:    listViewsUseCase,
+		getViewByIDUseCase:  getViewByIDUseCase,
+		createViewUseCase:   createViewUseCase,
+		updateViewUseCase:   updateViewUseCase,
+		deleteViewUseCase:   deleteViewUseCase,
+		getViewTodosUseCase: getViewTodosUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *View) RegisterRoutes(router *gin.RouterGroup) {
+	viewRoutes := router.Group("/views")
+	{
+		viewRoutes.GET("", c.List)
+		viewRoutes.POST("", c.Create)
+		viewRoutes.GET("/:id", c.Read)
+		viewRoutes.PUT("/:id", c.Update)
+		viewRoutes.DELETE("/:id", c.Delete)
+		viewRoutes.GET("/:id/todos", c.ListTodos)
+	}
+}
+
+// List は全てのビューを取得するハンドラー
+func (c *View) List(ctx *gin.Context) {
+	views, err := c.listViewsUseCase.Execute(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views)
+}
+
+// Create は新しいビューを作成するハンドラー
+func (c *View) Create(ctx *gin.Context) {
+	var req model.View
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := c.createViewUseCase.Execute(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, view)
+}
+
+// Read は指定されたIDのビューを取得するハンドラー
+func (c *View) Read(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	view, err := c.getViewByIDUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// Update は指定されたIDのビューを更新するハンドラー
+func (c *View) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req model.View
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := c.updateViewUseCase.Execute(ctx.Request.Context(), id, req)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// Delete は指定されたIDのビューを削除するハンドラー
+func (c *View) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := c.deleteViewUseCase.Execute(ctx.Request.Context(), id); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// ListTodos は指定されたIDのビューの検索クエリに一致するTodoを取得するハンドラー
+func (c *View) ListTodos(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	todos, err := c.getViewTodosUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, todos)
+}
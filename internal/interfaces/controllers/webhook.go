@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// Webhook はWebhookの購読の操作のためのコントローラー
+type Webhook struct {
+	listWebhooksUseCase          usecase.ListWebhooks
+	getWebhookByIDUseCase        usecase.GetWebhookByID
+	createWebhookUseCase         usecase.CreateWebhook
+	updateWebhookUseCase         usecase.UpdateWebhook
+	deleteWebhookUseCase         usecase.DeleteWebhook
+	listWebhookDeliveriesUseCase usecase.ListWebhookDeliveries
+	sendTestWebhookUseCase       usecase.SendTestWebhook
+}
+
+// NewWebhook は controllers.Webhook のコンストラクタ
+func NewWebhook(
+	listWebhooksUseCase usecase.ListWebhooks,
+	getWebhookByIDUseCase usecase.GetWebhookByID,
+	createWebhookUseCase usecase.CreateWebhook,
+	updateWebhookUseCase usecase.UpdateWebhook,
+	deleteWebhookUseCase usecase.DeleteWebhook,
+	listWebhookDeliveriesUseCase usecase.ListWebhookDeliveries,
+	sendTestWebhookUseCase usecase.SendTestWebhook,
+) *Webhook {
+	return &Webhook{
+		listWebhooksUseCase:          listWebhooksUseCase,
+		getWebhookByIDUseCase:        getWebhookByIDUseCase,
+		createWebhookUseCase:         createWebhookUseCase,
+		updateWebhookUseCase:         updateWebhookUseCase,
+		deleteWebhookUseCase:         deleteWebhookUseCase,
+		listWebhookDeliveriesUseCase: listWebhookDeliveriesUseCase,
+		sendTestWebhookUseCase:       sendTestWebhookUseCase,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+func (c *Webhook) RegisterRoutes(router *gin.RouterGroup) {
+	webhookRoutes := router.Group("/webhooks")
+	{
+		webhookRoutes.GET("", c.List)
+		webhookRoutes.POST("", c.Create)
+		webhookRoutes.GET("/:id", c.Read)
+		webhookRoutes.PUT("/:id", c.Update)
+		webhookRoutes.DELETE("/:id", c.Delete)
+		webhookRoutes.GET("/:id/deliveries", c.ListDeliveries)
+		webhookRoutes.POST("/:id/test", c.SendTest)
+	}
+}
+
+// List は全てのWebhookを取得するハンドラー
+func (c *Webhook) List(ctx *gin.Context) {
+	webhooks, err := c.listWebhooksUseCase.Execute(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+// Create は新しいWebhookを作成するハンドラー (レスポンスにのみ秘密鍵を含める)
+func (c *Webhook) Create(ctx *gin.Context) {
+	var req model.Webhook
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := c.createWebhookUseCase.Execute(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+// Read は指定されたIDのWebhookを取得するハンドラー
+func (c *Webhook) Read(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	webhook, err := c.getWebhookByIDUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// Update は指定されたIDのWebhookを更新するハンドラー (秘密鍵を省略した場合は変更しない)
+func (c *Webhook) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	var req model.Webhook
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := c.updateWebhookUseCase.Execute(ctx.Request.Context(), id, req)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// Delete は指定されたIDのWebhookを削除するハンドラー
+func (c *Webhook) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := c.deleteWebhookUseCase.Execute(ctx.Request.Context(), id); err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// ListDeliveries は指定されたIDのWebhookの配信の履歴を新しい順に取得するハンドラー
+func (c *Webhook) ListDeliveries(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	deliveries, err := c.listWebhookDeliveriesUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// SendTest は指定されたIDのWebhookにテストイベントを送信し、その配信を返すハンドラー
+func (c *Webhook) SendTest(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	delivery, err := c.sendTestWebhookUseCase.Execute(ctx.Request.Context(), id)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// toEnum はモデルの値をスキーマの列挙値に変換する (列挙値はモデルの値を大文字にした名前で定義する)
+func toEnum[T ~string](v T) string {
+	return strings.ToUpper(string(v))
+}
+
+// fromEnum はスキーマの列挙値をモデルの値に変換する (nil の場合は空文字)
+func fromEnum[T ~string](v *string) T {
+	if v == nil {
+		return ""
+	}
+	return T(strings.ToLower(*v))
+}
+
+// toTime は日時をスキーマの Time に変換する (nil の場合は nil)
+func toTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// fromTime はスキーマの Time を日時に変換する (nil の場合は nil)
+func fromTime(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
+
+// fromID はスキーマの ID を文字列に変換する (nil の場合は nil)
+func fromID(id *graphql.ID) *string {
+	if id == nil {
+		return nil
+	}
+	s := string(*id)
+	return &s
+}
+
+// fromInt はスキーマの Int を int に変換する (nil の場合は nil)
+func fromInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+// deref は v の値を返す (nil の場合はゼロ値)
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+
+// todoFilterInput はスキーマの TodoFilter を表す
+type todoFilterInput struct {
+	Status      *string
+	Tags        *[]string
+	TagMatch    *string
+	Actionable  *bool
+	SeriesID    *graphql.ID
+	Archived    *string
+	Q           *string
+	MinPriority *int32
+	Due         *string
+	Timezone    *string
+}
+
+// query は検索条件を model.TodoQuery に変換する (nil の場合は条件なし)
+func (f *todoFilterInput) query() model.TodoQuery {
+	if f == nil {
+		return model.TodoQuery{}
+	}
+	return model.TodoQuery{
+		Status:      fromEnum[model.TodoStatus](f.Status),
+		Tags:        deref(f.Tags),
+		TagMatch:    fromEnum[model.TagMatch](f.TagMatch),
+		Actionable:  deref(f.Actionable),
+		SeriesID:    deref(fromID(f.SeriesID)),
+		Archived:    fromEnum[model.ArchivedFilter](f.Archived),
+		Q:           deref(f.Q),
+		MinPriority: fromInt(f.MinPriority),
+		Due:         fromEnum[model.DueFilter](f.Due),
+		Timezone:    deref(f.Timezone),
+	}
+}
+
+// todoInput はスキーマの TodoInput を表す
+type todoInput struct {
+	Title        string
+	Content      *string
+	Status       *string
+	Priority     *int32
+	Done         *bool
+	Tags         *[]string
+	ParentID     *graphql.ID
+	AutoComplete *bool
+	DueAt        *graphql.Time
+	Recurrence   *string
+	Timezone     *string
+}
+
+// todo は作成・更新するTodoに変換する (REST API のリクエストボディと同じく、タグを省略した場合は nil にする)
+func (in *todoInput) todo() model.Todo {
+	return model.Todo{
+		Title:        in.Title,
+		Content:      deref(in.Content),
+		Status:       fromEnum[model.TodoStatus](in.Status),
+		Priority:     deref(fromInt(in.Priority)),
+		Done:         deref(in.Done),
+		Tags:         deref(in.Tags),
+		ParentID:     fromID(in.ParentID),
+		AutoComplete: deref(in.AutoComplete),
+		DueAt:        fromTime(in.DueAt),
+		Recurrence:   deref(in.Recurrence),
+		Timezone:     deref(in.Timezone),
+	}
+}
+
+// todoPatchInput はスキーマの TodoPatchInput を表す
+type todoPatchInput struct {
+	Title        *string
+	Content      *string
+	Status       *string
+	Priority     *int32
+	Done         *bool
+	Tags         *[]string
+	ParentID     *graphql.ID
+	AutoComplete *bool
+	DueAt        graphql.NullTime
+	Recurrence   *string
+	Timezone     *string
+}
+
+// patch は部分更新の内容を model.TodoPatch に変換する
+func (in *todoPatchInput) patch() model.TodoPatch {
+	patch := model.TodoPatch{
+		Title:        in.Title,
+		Content:      in.Content,
+		Priority:     fromInt(in.Priority),
+		Done:         in.Done,
+		ParentID:     fromID(in.ParentID),
+		AutoComplete: in.AutoComplete,
+		Recurrence:   in.Recurrence,
+		Timezone:     in.Timezone,
+	}
+	if in.DueAt.Set {
+		patch.DueAt = model.NullableOf(fromTime(in.DueAt.Value))
+	}
+	if in.Status != nil {
+		status := fromEnum[model.TodoStatus](in.Status)
+		patch.Status = &status
+	}
+	if in.Tags != nil {
+		tags := *in.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		patch.Tags = &tags
+	}
+	return patch
+}
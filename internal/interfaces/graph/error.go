@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// エラーの拡張情報 (extensions) の code (gRPC の ErrorInfo の理由と揃える)
+const (
+	codeNotFound                = "NOT_FOUND"
+	codeInvalidArgument         = "INVALID_ARGUMENT"
+	codeConflict                = "CONFLICT"
+	codeInvalidStatusTransition = "INVALID_STATUS_TRANSITION"
+	codeInternal                = "INTERNAL"
+	codeQueryTooDeep            = "QUERY_TOO_DEEP"
+	codeQueryTooComplex         = "QUERY_TOO_COMPLEX"
+)
+
+// resolverError は拡張情報に code を持つリゾルバーのエラー
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions はレスポンスのエラーの拡張情報を返す
+func (e *resolverError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+// toResolverError はエラーの種類に応じた code を持つエラーに変換する (REST API の errorStatus に対応する)
+// 想定していないエラーは記録した上で、詳細を返さずに INTERNAL のエラーにする
+func toResolverError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var code string
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		code = codeNotFound
+	case errors.Is(err, model.ErrInvalidArgument):
+		code = codeInvalidArgument
+	case errors.Is(err, model.ErrConflict):
+		code = codeConflict
+	case errors.Is(err, model.ErrInvalidStatusTransition):
+		code = codeInvalidStatusTransition
+	default:
+		slog.ErrorContext(ctx, "GraphQL resolver failed", slog.Any("error", err))
+		return &resolverError{message: "internal error", code: codeInternal}
+	}
+	return &resolverError{message: err.Error(), code: code}
+}
+
+// queryError はリクエスト全体のエラー (リゾルバーを実行する前のエラー) を返す
+func queryError(code, format string, args ...any) *gqlerrors.QueryError {
+	return &gqlerrors.QueryError{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": code},
+	}
+}
+
+// panicHandler はリゾルバーの panic を記録して INTERNAL のエラーにする (REST API の gin.Recovery に対応する)
+// graphql-go の log.Logger と errors.PanicHandler を実装する
+type panicHandler struct{}
+
+// LogPanic は panic を記録する
+func (panicHandler) LogPanic(ctx context.Context, value any) {
+	slog.ErrorContext(ctx, "GraphQL resolver panicked", slog.Any("panic", value), slog.String("stack", string(debug.Stack())))
+}
+
+// MakePanicError は panic から回復したエラーを返す
+func (panicHandler) MakePanicError(context.Context, any) *gqlerrors.QueryError {
+	return queryError(codeInternal, "internal error")
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+//go:embed graphiql.html
+var graphiqlHTML []byte
+
+// maxParallelism は1つのリクエストで同時に解決するフィールドの最大数
+// 一覧の各要素の読み込みを graph.loader でまとめるため、既定値 (10) より大きくする
+const maxParallelism = 100
+
+// Handler は GraphQL の API のリクエストを受け付ける (REST API の controllers と同じユースケースを使う)
+type Handler struct {
+	schema        *graphql.Schema
+	resolver      *resolver
+	maxDepth      int
+	maxComplexity int
+}
+
+// NewHandler は graph.Handler のコンストラクタ
+func NewHandler(
+	getAllTodosUseCase usecase.GetAllTodos,
+	getTodoByIDUseCase usecase.GetTodoByID,
+	createTodoUseCase usecase.CreateTodo,
+	updateTodoUseCase usecase.UpdateTodo,
+	patchTodoUseCase usecase.PatchTodo,
+	deleteTodoUseCase usecase.DeleteTodo,
+	streamTodosUseCase usecase.StreamTodos,
+	listTagsUseCase usecase.ListTags,
+	getTodosByIDsUseCase usecase.GetTodosByIDs,
+	listTodoChildrenByParentIDsUseCase usecase.ListTodoChildrenByParentIDs,
+	listTodoHistoryByTodoIDsUseCase usecase.ListTodoHistoryByTodoIDs,
+) *Handler {
+	r := &resolver{
+		getAllTodosUseCase:                 getAllTodosUseCase,
+		getTodoByIDUseCase:                 getTodoByIDUseCase,
+		createTodoUseCase:                  createTodoUseCase,
+		updateTodoUseCase:                  updateTodoUseCase,
+		patchTodoUseCase:                   patchTodoUseCase,
+		deleteTodoUseCase:                  deleteTodoUseCase,
+		streamTodosUseCase:                 streamTodosUseCase,
+		listTagsUseCase:                    listTagsUseCase,
+		getTodosByIDsUseCase:               getTodosByIDsUseCase,
+		listTodoChildrenByParentIDsUseCase: listTodoChildrenByParentIDsUseCase,
+		listTodoHistoryByTodoIDsUseCase:    listTodoHistoryByTodoIDsUseCase,
+	}
+	return &Handler{
+		// スキーマとリゾルバーの対応は起動時に検証する
+		schema: graphql.MustParseSchema(schemaSDL, r,
+			graphql.UseStringDescriptions(),
+			graphql.MaxParallelism(maxParallelism),
+			graphql.Logger(panicHandler{}),
+			graphql.PanicHandler(panicHandler{}),
+		),
+		resolver:      r,
+		maxDepth:      DefaultMaxDepth,
+		maxComplexity: DefaultMaxComplexity,
+	}
+}
+
+// RegisterRoutes はルーティング設定を行う
+// デバッグモードの場合はブラウザからのアクセスに GraphiQL を返す
+func (h *Handler) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/graphql", h.Query)
+	router.GET("/graphql", h.Get)
+}
+
+// request は GraphQL のリクエスト
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query は JSON のリクエストボディのクエリを実行するハンドラー
+func (h *Handler) Query(ctx *gin.Context) {
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, h.exec(ctx.Request.Context(), req, gqlast.Query, gqlast.Mutation))
+}
+
+// Get は GET のリクエストを処理するハンドラー
+// WebSocket の接続はサブスクリプション、デバッグモードのブラウザからのアクセスは GraphiQL、
+// それ以外はクエリパラメーターのクエリを実行する (更新は POST のみで受け付ける)
+func (h *Handler) Get(ctx *gin.Context) {
+	if strings.EqualFold(ctx.GetHeader("Upgrade"), "websocket") {
+		h.Subscribe(ctx)
+		return
+	}
+	if gin.IsDebugging() && strings.Contains(ctx.GetHeader("Accept"), "text/html") {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", graphiqlHTML)
+		return
+	}
+
+	req := request{Query: ctx.Query("query"), OperationName: ctx.Query("operationName")}
+	if v := ctx.Query("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "variables must be a JSON object"})
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, h.exec(ctx.Request.Context(), req, gqlast.Query))
+}
+
+// exec はリクエストごとの読み込みを設定して、allowed の種類の操作を実行する
+func (h *Handler) exec(ctx context.Context, req request, allowed ...gqlast.Operation) *graphql.Response {
+	if err := h.check(req, allowed...); err != nil {
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{err}}
+	}
+	ctx = withLoaders(ctx, newLoaders(ctx, h.resolver))
+	return h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+// check は操作の種類と、クエリの深さ・複雑度が上限を超えていないことを検証する
+// 構文などのエラーは graphql-go の実行時の検証で返す
+func (h *Handler) check(req request, allowed ...gqlast.Operation) *gqlerrors.QueryError {
+	cost, ok := estimateCost(h.schema.AST(), req.Query, req.OperationName)
+	if !ok {
+		return nil
+	}
+	if !slices.Contains(allowed, cost.operation) {
+		return queryError(codeInvalidArgument, "%s operations are not allowed on this transport", cost.operation)
+	}
+	if cost.depth > h.maxDepth {
+		return queryError(codeQueryTooDeep, "query depth %d exceeds the maximum of %d", cost.depth, h.maxDepth)
+	}
+	if cost.complexity > h.maxComplexity {
+		return queryError(codeQueryTooComplex, "query complexity %d exceeds the maximum of %d", cost.complexity, h.maxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+package graph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/repositorytest"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+	"github.com/qushot/gin-todo-api/internal/interfaces/graph"
+	"github.com/qushot/gin-todo-api/internal/interfaces/middleware"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// response は GraphQL のレスポンス
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// code は最初のエラーの拡張情報の code を返す
+func (r response) code() string {
+	if len(r.Errors) == 0 {
+		return ""
+	}
+	code, _ := r.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+// testServer は in-memory のリポジトリで GraphQL の API を提供するサーバー
+type testServer struct {
+	*httptest.Server
+	todoRepo    *repositorytest.CountingTodo
+	historyRepo *repositorytest.CountingTodoHistory
+	relay       usecase.RelayTodoEvents
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db := inmemory.NewEmptyDB()
+	todoRepo := &repositorytest.CountingTodo{Todo: inmemory.NewTodo(db)}
+	historyRepo := &repositorytest.CountingTodoHistory{TodoHistory: inmemory.NewTodoHistory(db)}
+	dependencyRepo := inmemory.NewTodoDependency(db)
+	outboxRepo := inmemory.NewOutbox(db)
+	txManager := inmemory.NewTxManager(db)
+	broker := stream.NewBroker(stream.DefaultRetention)
+	handler := graph.NewHandler(
+		usecase.NewGetAllTodos(todoRepo),
+		usecase.NewGetTodoByID(todoRepo),
+		usecase.NewCreateTodo(txManager, todoRepo, outboxRepo),
+		usecase.NewUpdateTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewPatchTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewDeleteTodo(txManager, todoRepo, outboxRepo),
+		usecase.NewStreamTodos(todoRepo, broker),
+		usecase.NewListTags(inmemory.NewTag(db)),
+		usecase.NewGetTodosByIDs(todoRepo),
+		usecase.NewListTodoChildrenByParentIDs(todoRepo),
+		usecase.NewListTodoHistoryByTodoIDs(historyRepo),
+	)
+
+	r := gin.New()
+	r.Use(middleware.AuditInfo)
+	handler.RegisterRoutes(&r.RouterGroup)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return &testServer{
+		Server:      srv,
+		todoRepo:    todoRepo,
+		historyRepo: historyRepo,
+		relay:       usecase.NewRelayTodoEvents(outboxRepo, broker),
+	}
+}
+
+// post はクエリを POST で実行する
+func (s *testServer) post(t *testing.T, query string, variables map[string]any) response {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req, _ := http.NewRequest(http.MethodPost, s.URL+"/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.ActorHeader, "alice")
+	return s.do(t, req)
+}
+
+func (s *testServer) do(t *testing.T, req *http.Request) response {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var res response
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return res
+}
+
+// createTodo はTodoを作成してIDを返す
+func (s *testServer) createTodo(t *testing.T, input map[string]any) string {
+	t.Helper()
+	res := s.post(t, `mutation($input: TodoInput!) { createTodo(input: $input) { id } }`, map[string]any{"input": input})
+	var data struct {
+		CreateTodo struct{ ID string } `json:"createTodo"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil || len(res.Errors) > 0 {
+		t.Fatalf("createTodo(%v) = %s, %+v", input, res.Data, res.Errors)
+	}
+	return data.CreateTodo.ID
+}
+
+func TestHandler_Query_batchesRelations(t *testing.T) {
+	srv := newTestServer(t)
+	for _, title := range []string{"A", "B", "C"} {
+		parent := srv.createTodo(t, map[string]any{"title": title, "tags": []string{"graphql"}})
+		for _, child := range []string{"1", "2"} {
+			srv.createTodo(t, map[string]any{"title": title + child, "parentId": parent})
+		}
+	}
+	srv.todoRepo.FindByIDCalls.Store(0)
+
+	res := srv.post(t, `{
+		todos(filter: {tags: ["graphql"]}) {
+			title
+			tags { name }
+			children { title parent { title } history { action } }
+			history { version action actor changes { field after } }
+		}
+	}`, nil)
+	if len(res.Errors) > 0 {
+		t.Fatalf("errors = %+v", res.Errors)
+	}
+	var data struct {
+		Todos []struct {
+			Title    string
+			Tags     []struct{ Name string }
+			Children []struct {
+				Title  string
+				Parent struct{ Title string }
+			}
+			History []struct {
+				Version int
+				Action  string
+				Actor   string
+			}
+		}
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if len(data.Todos) != 3 {
+		t.Fatalf("todos = %+v, want 3 todos", data.Todos)
+	}
+	for _, todo := range data.Todos {
+		if len(todo.Tags) != 1 || todo.Tags[0].Name != "graphql" {
+			t.Errorf("%s tags = %+v, want graphql", todo.Title, todo.Tags)
+		}
+		if len(todo.Children) != 2 || todo.Children[0].Title != todo.Title+"1" || todo.Children[1].Parent.Title != todo.Title {
+			t.Errorf("%s children = %+v, want %[1]s1 and %[1]s2", todo.Title, todo.Children)
+		}
+		if len(todo.History) != 1 || todo.History[0].Action != "CREATE" || todo.History[0].Actor != "alice" {
+			t.Errorf("%s history = %+v, want the creation by alice", todo.Title, todo.History)
+		}
+	}
+
+	// 一覧の各要素の関連は、深さごとに1回の問い合わせでまとめて取得する
+	if got := srv.todoRepo.FindChildrenByParentIDsCalls.Load(); got != 1 {
+		t.Errorf("FindChildrenByParentIDs() calls = %d, want 1", got)
+	}
+	if got := srv.todoRepo.FindByIDsCalls.Load(); got != 1 {
+		t.Errorf("FindByIDs() calls = %d, want 1", got)
+	}
+	// 変更履歴は深さ1と2で読み込むため、深さごとにまとめた最大2回の問い合わせになる
+	if got := srv.historyRepo.FindByTodoIDsCalls.Load(); got < 1 || got > 2 {
+		t.Errorf("FindByTodoIDs() calls = %d, want 1 or 2", got)
+	}
+	if got := srv.todoRepo.FindByIDCalls.Load() + srv.todoRepo.FindChildrenCalls.Load(); got != 0 {
+		t.Errorf("FindByID() and FindChildren() calls = %d, want 0", got)
+	}
+}
+
+func TestHandler_Mutation(t *testing.T) {
+	srv := newTestServer(t)
+	id := srv.createTodo(t, map[string]any{"title": "write schema"})
+
+	// 期待するバージョンが現在のバージョンと異なる場合は CONFLICT を返す
+	patch := `mutation($id: ID!, $version: Int) {
+		patchTodo(id: $id, input: {status: DONE}, expectedVersion: $version) { status version completedAt }
+	}`
+	if res := srv.post(t, patch, map[string]any{"id": id, "version": 2}); res.code() != "CONFLICT" {
+		t.Errorf("patchTodo() with a stale version = %+v, want CONFLICT", res.Errors)
+	}
+	res := srv.post(t, patch, map[string]any{"id": id, "version": 1})
+	if len(res.Errors) > 0 || !strings.Contains(string(res.Data), `"status":"DONE","version":2`) {
+		t.Errorf("patchTodo() = %s, %+v, want a done todo at version 2", res.Data, res.Errors)
+	}
+
+	if res := srv.post(t, `mutation($id: ID!) { patchTodo(id: $id, input: {status: BLOCKED}) { id } }`, map[string]any{"id": id}); res.code() != "INVALID_STATUS_TRANSITION" {
+		t.Errorf("patchTodo() to blocked = %+v, want INVALID_STATUS_TRANSITION", res.Errors)
+	}
+	if res := srv.post(t, `mutation { createTodo(input: {title: "x", priority: 9}) { id } }`, nil); res.code() != "INVALID_ARGUMENT" {
+		t.Errorf("createTodo() with an invalid priority = %+v, want INVALID_ARGUMENT", res.Errors)
+	}
+
+	if res := srv.post(t, `mutation($id: ID!) { deleteTodo(id: $id) }`, map[string]any{"id": id}); string(res.Data) != `{"deleteTodo":true}` {
+		t.Errorf("deleteTodo() = %s, %+v, want true", res.Data, res.Errors)
+	}
+	if res := srv.post(t, `query($id: ID!) { todo(id: $id) { id } }`, map[string]any{"id": id}); string(res.Data) != `{"todo":null}` {
+		t.Errorf("todo() after deleteTodo = %s, want null", res.Data)
+	}
+}
+
+func TestHandler_limits(t *testing.T) {
+	srv := newTestServer(t)
+
+	deep := "{ todos { " + strings.Repeat("children { ", graph.DefaultMaxDepth) + "id" + strings.Repeat(" }", graph.DefaultMaxDepth) + " } }"
+	if res := srv.post(t, deep, nil); res.code() != "QUERY_TOO_DEEP" {
+		t.Errorf("deep query = %+v, want QUERY_TOO_DEEP", res.Errors)
+	}
+	// 深さの上限以内でも、一覧の入れ子で取得する件数が多くなるクエリは拒否する
+	wide := `{ todos { children { children { children { blockers { id title history { version } } } } } } }`
+	if res := srv.post(t, wide, nil); res.code() != "QUERY_TOO_COMPLEX" {
+		t.Errorf("complex query = %+v, want QUERY_TOO_COMPLEX", res.Errors)
+	}
+	// イントロスペクションのフィールドは深さと複雑度に数えない
+	introspection := `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name ofType { name ofType { name } } } } } } } } } }`
+	if res := srv.post(t, introspection, nil); len(res.Errors) > 0 {
+		t.Errorf("introspection query errors = %+v, want none", res.Errors)
+	}
+}
+
+func TestHandler_Get(t *testing.T) {
+	srv := newTestServer(t)
+
+	res := srv.do(t, mustRequest(t, http.MethodGet, srv.URL+"/graphql?query="+url.QueryEscape(`{ tags { name } }`)))
+	if len(res.Errors) > 0 || string(res.Data) != `{"tags":[]}` {
+		t.Errorf("GET query = %s, %+v, want no tags", res.Data, res.Errors)
+	}
+	// 更新は GET で受け付けない
+	res = srv.do(t, mustRequest(t, http.MethodGet, srv.URL+"/graphql?query="+url.QueryEscape(`mutation { createTodo(input: {title: "x"}) { id } }`)))
+	if res.code() != "INVALID_ARGUMENT" {
+		t.Errorf("GET mutation = %+v, want INVALID_ARGUMENT", res.Errors)
+	}
+
+	// GraphiQL はデバッグモードの場合のみ返す
+	req := mustRequest(t, http.MethodGet, srv.URL+"/graphql")
+	req.Header.Set("Accept", "text/html")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("GraphiQL served in test mode")
+	}
+
+	gin.SetMode(gin.DebugMode)
+	t.Cleanup(func() { gin.SetMode(gin.TestMode) })
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("GraphiQL response = %d %s, want HTML", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+}
+
+func mustRequest(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	return req
+}
+
+// wsMessage は graphql-transport-ws のメッセージ
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/graphql", &websocket.DialOptions{
+		Subprotocols: []string{"graphql-transport-ws"},
+	})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer ws.CloseNow()
+
+	write := func(msg wsMessage) {
+		t.Helper()
+		if err := wsjson.Write(ctx, ws, msg); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	read := func() wsMessage {
+		t.Helper()
+		var msg wsMessage
+		if err := wsjson.Read(ctx, ws, &msg); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		return msg
+	}
+
+	write(wsMessage{Type: "connection_init"})
+	if got := read(); got.Type != "connection_ack" {
+		t.Fatalf("message = %+v, want connection_ack", got)
+	}
+
+	// 検証のエラーは error のメッセージで返す
+	write(wsMessage{ID: "bad", Type: "subscribe", Payload: json.RawMessage(`{"query":"subscription { unknown }"}`)})
+	if got := read(); got.ID != "bad" || got.Type != "error" {
+		t.Errorf("message = %+v, want error for bad", got)
+	}
+
+	payload := `{"query":"subscription { todoEvents(filter: {tags: [\"live\"]}) { type actor match todo { title tags { name } } } }"}`
+	write(wsMessage{ID: "events", Type: "subscribe", Payload: json.RawMessage(payload)})
+	// 購読を開始してからTodoを作成するため、ping と pong の往復で購読の開始を待つ
+	write(wsMessage{Type: "ping"})
+	if got := read(); got.Type != "pong" {
+		t.Fatalf("message = %+v, want pong", got)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	srv.createTodo(t, map[string]any{"title": "streamed", "tags": []string{"live"}})
+	if _, err := srv.relay.Execute(ctx, time.Now()); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+	got := read()
+	if got.ID != "events" || got.Type != "next" {
+		t.Fatalf("message = %+v, want next for events", got)
+	}
+	want := `{"data":{"todoEvents":{"type":"todo.created","actor":"alice","match":true,"todo":{"title":"streamed","tags":[{"name":"live"}]}}}}`
+	if string(got.Payload) != want {
+		t.Errorf("payload = %s, want %s", got.Payload, want)
+	}
+
+	write(wsMessage{ID: "events", Type: "complete"})
+	// 同じ接続でクエリも実行でき、結果を送った後に完了する
+	write(wsMessage{ID: "query", Type: "subscribe", Payload: json.RawMessage(`{"query":"{ tags { name } }"}`)})
+	if got := read(); got.ID != "query" || got.Type != "next" || string(got.Payload) != `{"data":{"tags":[{"name":"live"}]}}` {
+		t.Errorf("message = %+v, want the tags", got)
+	}
+	if got := read(); got.ID != "query" || got.Type != "complete" {
+		t.Errorf("message = %+v, want complete for query", got)
+	}
+	ws.Close(websocket.StatusNormalClosure, "")
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/graph-gophers/graphql-go/ast"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	// DefaultMaxDepth はクエリのフィールドの入れ子の最大の深さの既定値 (イントロスペクションのフィールドは数えない)
+	DefaultMaxDepth = 8
+	// DefaultMaxComplexity はクエリの複雑度の最大値の既定値
+	DefaultMaxComplexity = 5000
+	// listSizeEstimate は複雑度の計算で一覧のフィールドの要素数として見積もる数
+	listSizeEstimate = 10
+)
+
+// queryCost はクエリの操作の種類と、深さ・複雑度の見積もり
+type queryCost struct {
+	operation  gqlast.Operation
+	depth      int
+	complexity int
+}
+
+// estimateCost は実行する操作の深さと複雑度を見積もる
+// 複雑度は各フィールドを1とし、一覧のフィールドは子のフィールドの複雑度を listSizeEstimate 倍して数える
+// 構文が正しくない場合や操作が見つからない場合は false を返す (実行時のエラーとして返す)
+func estimateCost(schema *ast.Schema, query, operationName string) (queryCost, bool) {
+	doc, err := parser.ParseQuery(&gqlast.Source{Input: query})
+	if err != nil {
+		return queryCost{}, false
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return queryCost{}, false
+	}
+	root, ok := schema.RootOperationTypes[string(op.Operation)]
+	if !ok {
+		return queryCost{}, false
+	}
+
+	e := &costEstimator{schema: schema, fragments: doc.Fragments, visiting: map[string]bool{}}
+	depth, complexity := e.selectionSet(root, op.SelectionSet)
+	return queryCost{operation: op.Operation, depth: depth, complexity: complexity}, true
+}
+
+// costEstimator はスキーマの型をたどってクエリの深さと複雑度を見積もる
+type costEstimator struct {
+	schema    *ast.Schema
+	fragments gqlast.FragmentDefinitionList
+	// visiting は展開中のフラグメント (循環するフラグメントを展開し続けないため)
+	visiting map[string]bool
+}
+
+// selectionSet は typ の選択の深さと複雑度を返す
+func (e *costEstimator) selectionSet(typ ast.NamedType, set gqlast.SelectionSet) (depth, complexity int) {
+	for _, sel := range set {
+		var d, c int
+		switch sel := sel.(type) {
+		case *gqlast.Field:
+			d, c = e.field(typ, sel)
+		case *gqlast.InlineFragment:
+			d, c = e.selectionSet(e.typeCondition(typ, sel.TypeCondition), sel.SelectionSet)
+		case *gqlast.FragmentSpread:
+			frag := e.fragments.ForName(sel.Name)
+			if frag == nil || e.visiting[sel.Name] {
+				continue
+			}
+			e.visiting[sel.Name] = true
+			d, c = e.selectionSet(e.typeCondition(typ, frag.TypeCondition), frag.SelectionSet)
+			e.visiting[sel.Name] = false
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// field はフィールドの深さと複雑度を返す
+// イントロスペクションのフィールドはスキーマの情報のみを返すため数えない
+func (e *costEstimator) field(parent ast.NamedType, f *gqlast.Field) (depth, complexity int) {
+	if strings.HasPrefix(f.Name, "__") {
+		return 0, 0
+	}
+	obj, ok := parent.(*ast.ObjectTypeDefinition)
+	if !ok {
+		return 1, 1
+	}
+	def := obj.Fields.Get(f.Name)
+	if def == nil || len(f.SelectionSet) == 0 {
+		return 1, 1
+	}
+
+	named, list := unwrapType(def.Type)
+	childDepth, childComplexity := e.selectionSet(named, f.SelectionSet)
+	if list {
+		childComplexity *= listSizeEstimate
+	}
+	return 1 + childDepth, 1 + childComplexity
+}
+
+// typeCondition はフラグメントの型条件の型を返す (省略した場合は parent)
+func (e *costEstimator) typeCondition(parent ast.NamedType, name string) ast.NamedType {
+	if t, ok := e.schema.Types[name]; ok {
+		return t
+	}
+	return parent
+}
+
+// unwrapType は非 null と一覧を外した型と、一覧かどうかを返す
+func unwrapType(t ast.Type) (named ast.NamedType, list bool) {
+	for {
+		switch tt := t.(type) {
+		case *ast.NonNull:
+			t = tt.OfType
+		case *ast.List:
+			list = true
+			t = tt.OfType
+		case ast.NamedType:
+			return tt, list
+		default:
+			return nil, list
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// loaderWait は最初の読み込みからまとめて取得するまでに、同時に解決中の他のフィールドの読み込みを待つ時間
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch は1回の問い合わせでまとめて取得するキーの最大数
+	loaderMaxBatch = 500
+)
+
+// loaderResult は1つのキーの読み込みの結果 (done が閉じた後に参照する)
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+// loader は同時に解決中のフィールドの読み込みをまとめて1回の問い合わせで取得する (N+1 の問い合わせを避ける)
+// 読み込んだ結果はリクエストの間キャッシュする
+type loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu        sync.Mutex
+	results   map[K]*loaderResult[V]
+	pending   []K
+	scheduled bool
+}
+
+// newLoader は fetch でまとめて取得する graph.loader のコンストラクタ
+// fetch は ctx (リクエストの context) で呼び出し、見つからないキーは結果に含めない
+func newLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		ctx:     ctx,
+		fetch:   fetch,
+		results: make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load は key の値を取得する (見つからない場合は found が false)
+func (l *loader[K, V]) Load(ctx context.Context, key K) (value V, found bool, err error) {
+	l.mu.Lock()
+	r := l.enqueue(key)
+	if len(l.pending) >= loaderMaxBatch {
+		go l.dispatch()
+	} else if len(l.pending) > 0 && !l.scheduled {
+		l.scheduled = true
+		time.AfterFunc(loaderWait, l.dispatch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.found, r.err
+	case <-ctx.Done():
+		return value, false, ctx.Err()
+	}
+}
+
+// Prime は keys を次にまとめて取得するキーに加える (取得はいずれかのキーを読み込むまで行わない)
+// 一覧の各要素で読み込まれるキーを事前に加えておくことで、待つ時間に関係なく1回の問い合わせにまとめる
+func (l *loader[K, V]) Prime(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		l.enqueue(key)
+	}
+}
+
+// Clear はキャッシュした結果を破棄する (更新した後に読み込み直すため)
+func (l *loader[K, V]) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, r := range l.results {
+		select {
+		case <-r.done:
+			delete(l.results, key)
+		default:
+			// 取得中の結果は待っている読み込みがあるため残す
+		}
+	}
+}
+
+// enqueue は key の結果を返し、まだ取得していない場合は次にまとめて取得するキーに加える (l.mu を取得して呼び出す)
+func (l *loader[K, V]) enqueue(key K) *loaderResult[V] {
+	if r, ok := l.results[key]; ok {
+		return r
+	}
+	r := &loaderResult[V]{done: make(chan struct{})}
+	l.results[key] = r
+	l.pending = append(l.pending, key)
+	return r
+}
+
+// dispatch は取得待ちのキーをまとめて取得し、結果を読み込みに返す
+func (l *loader[K, V]) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.scheduled = false
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.results[key]
+	}
+	l.mu.Unlock()
+
+	for start := 0; start < len(keys); start += loaderMaxBatch {
+		end := min(start+loaderMaxBatch, len(keys))
+		values, err := l.fetch(l.ctx, keys[start:end])
+		for i, key := range keys[start:end] {
+			r := results[start+i]
+			r.value, r.found = values[key]
+			r.err = err
+			close(r.done)
+		}
+	}
+}
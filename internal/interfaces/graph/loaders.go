@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// loaders はリクエストごとの読み込みをまとめる graph.loader の組
+type loaders struct {
+	todo     *loader[string, model.Todo]
+	children *loader[string, []model.Todo]
+	history  *loader[string, []model.TodoHistory]
+	tag      *loader[string, model.Tag]
+}
+
+// newLoaders は r のユースケースで読み込む graph.loaders のコンストラクタ
+func newLoaders(ctx context.Context, r *resolver) *loaders {
+	l := &loaders{
+		todo:    newLoader(ctx, r.getTodosByIDsUseCase.Execute),
+		history: newLoader(ctx, r.listTodoHistoryByTodoIDsUseCase.Execute),
+		tag:     newLoader(ctx, r.fetchTags),
+	}
+	l.children = newLoader(ctx, func(ctx context.Context, parentIDs []string) (map[string][]model.Todo, error) {
+		children, err := r.listTodoChildrenByParentIDsUseCase.Execute(ctx, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		// 全ての親の子を1回で取得したため、子の各要素で読み込まれる孫なども親をまたいでまとめて取得する
+		for _, todos := range children {
+			l.prime(todos...)
+		}
+		return children, nil
+	})
+	return l
+}
+
+// prime は todos の各要素のフィールドで読み込まれるキーを、次にまとめて取得するキーに加える
+func (l *loaders) prime(todos ...model.Todo) {
+	for _, todo := range todos {
+		l.children.Prime(todo.ID)
+		l.history.Prime(todo.ID)
+		l.tag.Prime(todo.Tags...)
+		l.todo.Prime(todo.BlockedBy...)
+		if todo.ParentID != nil {
+			l.todo.Prime(*todo.ParentID)
+		}
+	}
+}
+
+// clear は読み込んだ結果を全て破棄する
+func (l *loaders) clear() {
+	l.todo.Clear()
+	l.children.Clear()
+	l.history.Clear()
+	l.tag.Clear()
+}
+
+// loadersKey は context に loaders を設定するキー
+type loadersKey struct{}
+
+// withLoaders は l を設定した context を返す
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext は context に設定された loaders を返す
+func loadersFromContext(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
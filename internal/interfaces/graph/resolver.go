@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// resolver はスキーマのルート (Query, Mutation, Subscription) のリゾルバー
+type resolver struct {
+	getAllTodosUseCase                 usecase.GetAllTodos
+	getTodoByIDUseCase                 usecase.GetTodoByID
+	createTodoUseCase                  usecase.CreateTodo
+	updateTodoUseCase                  usecase.UpdateTodo
+	patchTodoUseCase                   usecase.PatchTodo
+	deleteTodoUseCase                  usecase.DeleteTodo
+	streamTodosUseCase                 usecase.StreamTodos
+	listTagsUseCase                    usecase.ListTags
+	getTodosByIDsUseCase               usecase.GetTodosByIDs
+	listTodoChildrenByParentIDsUseCase usecase.ListTodoChildrenByParentIDs
+	listTodoHistoryByTodoIDsUseCase    usecase.ListTodoHistoryByTodoIDs
+}
+
+// fetchTags は names のタグを取得する (タグの一覧は件数が少ないため全て取得する)
+func (r *resolver) fetchTags(ctx context.Context, names []string) (map[string]model.Tag, error) {
+	tags, err := r.listTagsUseCase.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]model.Tag, len(tags))
+	for _, tag := range tags {
+		result[tag.Name] = tag
+	}
+	return result, nil
+}
+
+// todosArgs は Query.todos の引数
+type todosArgs struct {
+	Filter *todoFilterInput
+}
+
+// Todos は検索条件に一致するTodoを取得する
+func (r *resolver) Todos(ctx context.Context, args todosArgs) ([]*todoResolver, error) {
+	todos, err := r.getAllTodosUseCase.Execute(ctx, args.Filter.query())
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolvers(loadersFromContext(ctx), todos), nil
+}
+
+// todoArgs は Query.todo の引数
+type todoArgs struct {
+	ID graphql.ID
+}
+
+// Todo は指定されたIDのTodoを取得する (存在しない場合は null)
+func (r *resolver) Todo(ctx context.Context, args todoArgs) (*todoResolver, error) {
+	todo, err := r.getTodoByIDUseCase.Execute(ctx, string(args.ID))
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolver(loadersFromContext(ctx), *todo), nil
+}
+
+// Tags は全てのタグを取得する
+func (r *resolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	tags, err := r.listTagsUseCase.Execute(ctx)
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	res := make([]*tagResolver, len(tags))
+	for i, tag := range tags {
+		res[i] = &tagResolver{tag: tag}
+	}
+	return res, nil
+}
+
+// withMutation は更新の前に読み込んだ結果を破棄し、期待するバージョンが指定されている場合は context に設定する
+// 更新は順番に実行されるため、前の更新の結果を読み込んだ値を次の更新の結果に使わないようにする
+func withMutation(ctx context.Context, expectedVersion *int32) context.Context {
+	if l := loadersFromContext(ctx); l != nil {
+		l.clear()
+	}
+	if expectedVersion == nil {
+		return ctx
+	}
+	return model.WithExpectedVersion(ctx, int(*expectedVersion))
+}
+
+// createTodoArgs は Mutation.createTodo の引数
+type createTodoArgs struct {
+	Input todoInput
+}
+
+// CreateTodo は新しいTodoを作成する
+func (r *resolver) CreateTodo(ctx context.Context, args createTodoArgs) (*todoResolver, error) {
+	ctx = withMutation(ctx, nil)
+	todo, err := r.createTodoUseCase.Execute(ctx, args.Input.todo())
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolver(loadersFromContext(ctx), *todo), nil
+}
+
+// updateTodoArgs は Mutation.updateTodo の引数
+type updateTodoArgs struct {
+	ID              graphql.ID
+	Input           todoInput
+	Scope           *string
+	ExpectedVersion *int32
+}
+
+// UpdateTodo は指定されたIDのTodoを更新する
+func (r *resolver) UpdateTodo(ctx context.Context, args updateTodoArgs) (*todoResolver, error) {
+	ctx = withMutation(ctx, args.ExpectedVersion)
+	todo, err := r.updateTodoUseCase.Execute(ctx, string(args.ID), args.Input.todo(), fromEnum[model.RecurrenceScope](args.Scope))
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolver(loadersFromContext(ctx), *todo), nil
+}
+
+// patchTodoArgs は Mutation.patchTodo の引数
+type patchTodoArgs struct {
+	ID              graphql.ID
+	Input           todoPatchInput
+	Scope           *string
+	ExpectedVersion *int32
+}
+
+// PatchTodo は指定されたIDのTodoを部分更新する
+func (r *resolver) PatchTodo(ctx context.Context, args patchTodoArgs) (*todoResolver, error) {
+	ctx = withMutation(ctx, args.ExpectedVersion)
+	todo, err := r.patchTodoUseCase.Execute(ctx, string(args.ID), args.Input.patch(), fromEnum[model.RecurrenceScope](args.Scope))
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolver(loadersFromContext(ctx), *todo), nil
+}
+
+// deleteTodoArgs は Mutation.deleteTodo の引数
+type deleteTodoArgs struct {
+	ID              graphql.ID
+	Cascade         bool
+	ExpectedVersion *int32
+}
+
+// DeleteTodo は指定されたIDのTodoをゴミ箱に移動する
+func (r *resolver) DeleteTodo(ctx context.Context, args deleteTodoArgs) (bool, error) {
+	ctx = withMutation(ctx, args.ExpectedVersion)
+	if err := r.deleteTodoUseCase.Execute(ctx, string(args.ID), args.Cascade); err != nil {
+		return false, toResolverError(ctx, err)
+	}
+	return true, nil
+}
+
+// todoEventsArgs は Subscription.todoEvents の引数
+type todoEventsArgs struct {
+	Filter      *todoFilterInput
+	LastEventID *string
+}
+
+// TodoEvents は検索条件に関係するTodoのイベントを ctx がキャンセルされるまで配信する
+// 受け取りが遅れて購読が終了した場合はチャネルを閉じる (購読の完了として通知する)
+func (r *resolver) TodoEvents(ctx context.Context, args todoEventsArgs) (<-chan *todoEventResolver, error) {
+	stream, err := r.streamTodosUseCase.Execute(ctx, args.Filter.query(), deref(args.LastEventID))
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+
+	out := make(chan *todoEventResolver)
+	go func() {
+		defer close(out)
+		for event := range stream.Events {
+			// イベントごとに発生した時点のTodoを読み込むため、読み込んだ結果はイベントをまたいで使わない
+			e := &todoEventResolver{event: event, loaders: newLoaders(ctx, r)}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := stream.Err(); err != nil {
+			slog.ErrorContext(ctx, "Todo stream closed", slog.Any("error", err))
+		}
+	}()
+	return out, nil
+}
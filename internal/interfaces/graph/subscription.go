@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/qushot/gin-todo-api/internal/interfaces/wsaccept"
+)
+
+// subprotocol はサブスクリプションの WebSocket のサブプロトコル
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const subprotocol = "graphql-transport-ws"
+
+const (
+	// connectionInitTimeout は接続してから connection_init のメッセージを待つ時間
+	connectionInitTimeout = 10 * time.Second
+	// maxMessageSize はクライアントから受け取るメッセージの最大サイズ
+	maxMessageSize = 64 << 10
+	// maxSubscriptions は接続ごとの購読の最大数
+	maxSubscriptions = 20
+	// writeTimeout はメッセージの送信を待つ時間
+	writeTimeout = 10 * time.Second
+)
+
+// graphql-transport-ws のメッセージの種類
+const (
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+)
+
+// graphql-transport-ws でプロトコルの違反を表す切断のステータスコード
+const (
+	statusBadRequest               websocket.StatusCode = 4400
+	statusUnauthorized             websocket.StatusCode = 4401
+	statusSubprotocolNotAcceptable websocket.StatusCode = 4406
+	statusInitTimeout              websocket.StatusCode = 4408
+	statusSubscriberExists         websocket.StatusCode = 4409
+	statusTooManyInitRequests      websocket.StatusCode = 4429
+)
+
+// wsMessage は graphql-transport-ws のメッセージ
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Subscribe は graphql-transport-ws の WebSocket の接続を受け付け、切断されるまで購読を処理するハンドラー
+// ブラウザからの接続は同じオリジンからのみ受け付ける
+func (h *Handler) Subscribe(ctx *gin.Context) {
+	ws, err := wsaccept.Accept(ctx, &websocket.AcceptOptions{Subprotocols: []string{subprotocol}})
+	if err != nil {
+		// Accept がエラーのレスポンスを返している
+		return
+	}
+	if ws.Subprotocol() != subprotocol {
+		ws.Close(statusSubprotocolNotAcceptable, "Subprotocol not acceptable")
+		return
+	}
+	ws.SetReadLimit(maxMessageSize)
+	(&wsConn{handler: h, ws: ws, subscriptions: make(map[string]context.CancelFunc)}).run(ctx.Request.Context())
+}
+
+// wsConn は1つのサブスクリプションの WebSocket の接続を表す
+// メッセージの受信は run を呼び出した goroutine で行い、各購読の送信は購読ごとの goroutine で行う
+type wsConn struct {
+	handler      *Handler
+	ws           *websocket.Conn
+	acknowledged atomic.Bool
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// run は接続が切断されるまでメッセージを処理する
+func (c *wsConn) run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		// 購読を全て終了してから戻る
+		cancel()
+		c.wg.Wait()
+	}()
+
+	initTimer := time.AfterFunc(connectionInitTimeout, func() {
+		if !c.acknowledged.Load() {
+			c.ws.Close(statusInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	for {
+		var msg wsMessage
+		if err := wsjson.Read(ctx, c.ws, &msg); err != nil {
+			c.logClose(ctx, err)
+			return
+		}
+		if !c.handle(ctx, msg) {
+			return
+		}
+	}
+}
+
+// handle は受け取ったメッセージを処理し、プロトコルの違反で切断した場合は false を返す
+func (c *wsConn) handle(ctx context.Context, msg wsMessage) bool {
+	switch msg.Type {
+	case messageConnectionInit:
+		if c.acknowledged.Swap(true) {
+			return c.closeWith(statusTooManyInitRequests, "Too many initialisation requests")
+		}
+		c.write(ctx, wsMessage{Type: messageConnectionAck})
+	case messagePing:
+		c.write(ctx, wsMessage{Type: messagePong})
+	case messagePong:
+	case messageSubscribe:
+		if !c.acknowledged.Load() {
+			return c.closeWith(statusUnauthorized, "Unauthorized")
+		}
+		var req request
+		if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+			return c.closeWith(statusBadRequest, "Invalid message received")
+		}
+		return c.subscribe(ctx, msg.ID, req)
+	case messageComplete:
+		c.mu.Lock()
+		if cancel, ok := c.subscriptions[msg.ID]; ok {
+			cancel()
+			delete(c.subscriptions, msg.ID)
+		}
+		c.mu.Unlock()
+	default:
+		return c.closeWith(statusBadRequest, "Invalid message received")
+	}
+	return true
+}
+
+// subscribe は id の購読を開始する (同じIDの購読が既にある場合は切断する)
+// クエリと更新の操作も受け付け、結果を1回送って完了する
+func (c *wsConn) subscribe(ctx context.Context, id string, req request) bool {
+	c.mu.Lock()
+	if _, ok := c.subscriptions[id]; ok {
+		c.mu.Unlock()
+		return c.closeWith(statusSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", id))
+	}
+	if len(c.subscriptions) >= maxSubscriptions {
+		c.mu.Unlock()
+		c.writeErrors(ctx, id, queryError(codeInvalidArgument, "too many subscriptions (max %d)", maxSubscriptions))
+		return true
+	}
+	subCtx, cancel := context.WithCancel(ctx)
+	c.subscriptions[id] = cancel
+	c.mu.Unlock()
+
+	c.wg.Go(func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.subscriptions, id)
+			c.mu.Unlock()
+			cancel()
+		}()
+		c.stream(subCtx, id, req)
+	})
+	return true
+}
+
+// stream は購読の結果を終了するまで送る
+// 実行する前のエラー (検証のエラーなど) は error のメッセージで送り、それ以外の終了は complete のメッセージで送る
+// クライアントが complete のメッセージで終了した場合は何も送らない
+func (c *wsConn) stream(ctx context.Context, id string, req request) {
+	if err := c.handler.check(req, gqlast.Query, gqlast.Mutation, gqlast.Subscription); err != nil {
+		c.writeErrors(ctx, id, err)
+		return
+	}
+	responses, err := c.handler.schema.Subscribe(withLoaders(ctx, newLoaders(ctx, c.handler.resolver)), req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		c.writeErrors(ctx, id, queryError(codeInternal, "%s", err))
+		return
+	}
+
+	first := true
+	for r := range responses {
+		res, ok := r.(*graphql.Response)
+		if !ok {
+			continue
+		}
+		if first && res.Data == nil && len(res.Errors) > 0 {
+			c.writeErrors(ctx, id, res.Errors...)
+			return
+		}
+		first = false
+		payload, _ := json.Marshal(res)
+		c.write(ctx, wsMessage{ID: id, Type: messageNext, Payload: payload})
+	}
+	if ctx.Err() == nil {
+		c.write(ctx, wsMessage{ID: id, Type: messageComplete})
+	}
+}
+
+// writeErrors は id の購読のエラーを送る
+func (c *wsConn) writeErrors(ctx context.Context, id string, errs ...*gqlerrors.QueryError) {
+	payload, _ := json.Marshal(errs)
+	c.write(ctx, wsMessage{ID: id, Type: messageError, Payload: payload})
+}
+
+// write はメッセージを送る (coder/websocket の書き込みは複数の goroutine から呼び出せる)
+// 送れなかった場合は受信の goroutine が切断を検知するため、ここでは何もしない
+func (c *wsConn) write(ctx context.Context, msg wsMessage) {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	_ = wsjson.Write(ctx, c.ws, msg)
+}
+
+// closeWith はプロトコルの違反のステータスで切断して false を返す
+func (c *wsConn) closeWith(code websocket.StatusCode, reason string) bool {
+	c.ws.Close(code, reason)
+	return false
+}
+
+// logClose は想定していない理由で切断された場合に記録する
+func (c *wsConn) logClose(ctx context.Context, err error) {
+	switch websocket.CloseStatus(err) {
+	case websocket.StatusNormalClosure, websocket.StatusGoingAway:
+		return
+	}
+	if !errors.Is(err, context.Canceled) {
+		slog.InfoContext(ctx, "GraphQL subscription connection closed", slog.Any("error", err))
+	}
+}
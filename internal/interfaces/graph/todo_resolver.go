@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// todoResolver はスキーマの Todo のリゾルバー
+// 関連するTodo・タグ・変更履歴は loaders でまとめて読み込む
+type todoResolver struct {
+	todo    model.Todo
+	loaders *loaders
+}
+
+// newTodoResolver は graph.todoResolver のコンストラクタ
+func newTodoResolver(l *loaders, todo model.Todo) *todoResolver {
+	l.prime(todo)
+	return &todoResolver{todo: todo, loaders: l}
+}
+
+// newTodoResolvers はTodoの一覧の graph.todoResolver を返す
+// 各要素のフィールドで読み込まれるキーを事前に加え、一覧全体で1回の問い合わせにまとめる
+func newTodoResolvers(l *loaders, todos []model.Todo) []*todoResolver {
+	l.prime(todos...)
+	res := make([]*todoResolver, len(todos))
+	for i, todo := range todos {
+		res[i] = &todoResolver{todo: todo, loaders: l}
+	}
+	return res
+}
+
+func (r *todoResolver) ID() graphql.ID              { return graphql.ID(r.todo.ID) }
+func (r *todoResolver) Title() string               { return r.todo.Title }
+func (r *todoResolver) Content() string             { return r.todo.Content }
+func (r *todoResolver) Status() string              { return toEnum(r.todo.Status) }
+func (r *todoResolver) Priority() int32             { return int32(r.todo.Priority) }
+func (r *todoResolver) Done() bool                  { return r.todo.Done }
+func (r *todoResolver) Position() int32             { return int32(r.todo.Position) }
+func (r *todoResolver) AutoComplete() bool          { return r.todo.AutoComplete }
+func (r *todoResolver) DueAt() *graphql.Time        { return toTime(r.todo.DueAt) }
+func (r *todoResolver) Recurrence() string          { return r.todo.Recurrence }
+func (r *todoResolver) Timezone() string            { return r.todo.Timezone }
+func (r *todoResolver) OccurrenceAt() *graphql.Time { return toTime(r.todo.OccurrenceAt) }
+func (r *todoResolver) CompletedAt() *graphql.Time  { return toTime(r.todo.CompletedAt) }
+func (r *todoResolver) ArchivedAt() *graphql.Time   { return toTime(r.todo.ArchivedAt) }
+func (r *todoResolver) Version() int32              { return int32(r.todo.Version) }
+func (r *todoResolver) UpdatedAt() graphql.Time     { return graphql.Time{Time: r.todo.UpdatedAt} }
+
+func (r *todoResolver) SeriesID() *graphql.ID {
+	if r.todo.SeriesID == nil {
+		return nil
+	}
+	id := graphql.ID(*r.todo.SeriesID)
+	return &id
+}
+
+// Tags はTodoに付与されたタグを取得する
+// タグの一覧を取得した後に付与されたタグは、IDを空にしてタグ名のみを返す
+func (r *todoResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	res := make([]*tagResolver, len(r.todo.Tags))
+	for i, name := range r.todo.Tags {
+		tag, found, err := r.loaders.tag.Load(ctx, name)
+		if err != nil {
+			return nil, toResolverError(ctx, err)
+		}
+		if !found {
+			tag = model.Tag{Name: name}
+		}
+		res[i] = &tagResolver{tag: tag}
+	}
+	return res, nil
+}
+
+// Parent は親のTodoを取得する (ルートの場合は null)
+func (r *todoResolver) Parent(ctx context.Context) (*todoResolver, error) {
+	if r.todo.ParentID == nil {
+		return nil, nil
+	}
+	parent, found, err := r.loaders.todo.Load(ctx, *r.todo.ParentID)
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return newTodoResolver(r.loaders, parent), nil
+}
+
+// Children は子のTodoを並び順で取得する
+func (r *todoResolver) Children(ctx context.Context) ([]*todoResolver, error) {
+	children, _, err := r.loaders.children.Load(ctx, r.todo.ID)
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	return newTodoResolvers(r.loaders, children), nil
+}
+
+// Blockers はこのTodoをブロックしているTodoを取得する
+func (r *todoResolver) Blockers(ctx context.Context) ([]*todoResolver, error) {
+	blockers := make([]model.Todo, 0, len(r.todo.BlockedBy))
+	for _, id := range r.todo.BlockedBy {
+		blocker, found, err := r.loaders.todo.Load(ctx, id)
+		if err != nil {
+			return nil, toResolverError(ctx, err)
+		}
+		if found {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return newTodoResolvers(r.loaders, blockers), nil
+}
+
+// History はTodoの変更履歴を古い順に取得する
+func (r *todoResolver) History(ctx context.Context) ([]*todoHistoryResolver, error) {
+	histories, _, err := r.loaders.history.Load(ctx, r.todo.ID)
+	if err != nil {
+		return nil, toResolverError(ctx, err)
+	}
+	res := make([]*todoHistoryResolver, len(histories))
+	for i, h := range histories {
+		res[i] = &todoHistoryResolver{history: h}
+	}
+	return res, nil
+}
+
+// tagResolver はスキーマの Tag のリゾルバー
+type tagResolver struct {
+	tag model.Tag
+}
+
+func (r *tagResolver) ID() graphql.ID { return graphql.ID(r.tag.ID) }
+func (r *tagResolver) Name() string   { return r.tag.Name }
+
+// todoHistoryResolver はスキーマの TodoHistory のリゾルバー
+type todoHistoryResolver struct {
+	history model.TodoHistory
+}
+
+func (r *todoHistoryResolver) ID() graphql.ID  { return graphql.ID(r.history.ID) }
+func (r *todoHistoryResolver) Version() int32  { return int32(r.history.Version) }
+func (r *todoHistoryResolver) Action() string  { return toEnum(r.history.Action) }
+func (r *todoHistoryResolver) Actor() string   { return r.history.Actor }
+func (r *todoHistoryResolver) TraceID() string { return r.history.TraceID }
+func (r *todoHistoryResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.history.CreatedAt}
+}
+
+func (r *todoHistoryResolver) Changes() []*fieldChangeResolver {
+	res := make([]*fieldChangeResolver, len(r.history.Changes))
+	for i, c := range r.history.Changes {
+		res[i] = &fieldChangeResolver{change: c}
+	}
+	return res
+}
+
+// fieldChangeResolver はスキーマの FieldChange のリゾルバー
+type fieldChangeResolver struct {
+	change model.FieldChange
+}
+
+func (r *fieldChangeResolver) Field() string      { return r.change.Field }
+func (r *fieldChangeResolver) Before() *jsonValue { return newJSONValue(r.change.Before) }
+func (r *fieldChangeResolver) After() *jsonValue  { return newJSONValue(r.change.After) }
+
+// todoEventResolver はスキーマの TodoEvent のリゾルバー
+type todoEventResolver struct {
+	event   model.TodoStreamEvent
+	loaders *loaders
+}
+
+func (r *todoEventResolver) ID() graphql.ID           { return graphql.ID(r.event.ID) }
+func (r *todoEventResolver) Type() string             { return string(r.event.Type) }
+func (r *todoEventResolver) TodoID() graphql.ID       { return graphql.ID(r.event.TodoID) }
+func (r *todoEventResolver) Actor() string            { return r.event.Actor }
+func (r *todoEventResolver) TraceID() string          { return r.event.TraceID }
+func (r *todoEventResolver) OccurredAt() graphql.Time { return graphql.Time{Time: r.event.OccurredAt} }
+func (r *todoEventResolver) Match() bool              { return r.event.Match }
+
+// Todo はイベントが発生した後のTodoを返す (stream.reset の場合は null)
+func (r *todoEventResolver) Todo() *todoResolver {
+	if r.event.Type == model.TodoEventStreamReset {
+		return nil
+	}
+	return newTodoResolver(r.loaders, r.event.Todo)
+}
+
+// jsonValue はスキーマの JSON のスカラー
+type jsonValue struct {
+	value any
+}
+
+// newJSONValue は v の jsonValue を返す (nil の場合は nil)
+func newJSONValue(v any) *jsonValue {
+	if v == nil {
+		return nil
+	}
+	return &jsonValue{value: v}
+}
+
+// ImplementsGraphQLType はスキーマの JSON のスカラーに対応することを表す
+func (jsonValue) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL は入力の値をそのまま保持する
+func (v *jsonValue) UnmarshalGraphQL(input any) error {
+	v.value = input
+	return nil
+}
+
+// MarshalJSON は保持している値を JSON に変換する
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.value)
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// autoArchiverActor は自動アーカイブの変更履歴に記録する操作者
+const autoArchiverActor = "system:auto-archiver"
+
+// AutoArchiver は完了してから一定期間が経過したTodoを定期的にアーカイブするジョブ
+type AutoArchiver struct {
+	archiveCompletedTodosUseCase usecase.ArchiveCompletedTodos
+	interval                     time.Duration
+	after                        time.Duration
+}
+
+// NewAutoArchiver は job.AutoArchiver のコンストラクタ
+func NewAutoArchiver(archiveCompletedTodosUseCase usecase.ArchiveCompletedTodos, interval, after time.Duration) *AutoArchiver {
+	return &AutoArchiver{
+		archiveCompletedTodosUseCase: archiveCompletedTodosUseCase,
+		interval:                     interval,
+		after:                        after,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに完了したTodoをアーカイブする (起動直後にも1回実行する)
+func (j *AutoArchiver) Run(ctx context.Context) {
+	ctx = model.WithAuditInfo(ctx, model.AuditInfo{Actor: autoArchiverActor})
+	runPeriodically(ctx, j.interval, j.archive)
+}
+
+// archive は完了したTodoを1回アーカイブする (失敗しても次の実行で再試行するためログのみ出力する)
+func (j *AutoArchiver) archive(ctx context.Context) {
+	n, err := j.archiveCompletedTodosUseCase.Execute(ctx, time.Now(), j.after)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to archive completed todos", slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Archived completed todos", slog.Int("count", n), slog.Duration("after", j.after))
+	}
+}
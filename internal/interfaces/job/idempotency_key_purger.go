@@ -0,0 +1,40 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// IdempotencyKeyPurger は有効期限を過ぎた冪等キーを定期的に削除するジョブ
+type IdempotencyKeyPurger struct {
+	purgeExpiredIdempotencyKeysUseCase usecase.PurgeExpiredIdempotencyKeys
+	interval                           time.Duration
+}
+
+// NewIdempotencyKeyPurger は job.IdempotencyKeyPurger のコンストラクタ
+func NewIdempotencyKeyPurger(purgeExpiredIdempotencyKeysUseCase usecase.PurgeExpiredIdempotencyKeys, interval time.Duration) *IdempotencyKeyPurger {
+	return &IdempotencyKeyPurger{
+		purgeExpiredIdempotencyKeysUseCase: purgeExpiredIdempotencyKeysUseCase,
+		interval:                           interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに冪等キーを削除する (起動直後にも1回実行する)
+func (j *IdempotencyKeyPurger) Run(ctx context.Context) {
+	runPeriodically(ctx, j.interval, j.purge)
+}
+
+// purge は冪等キーを1回削除する (失敗しても次の実行で再試行するためログのみ出力する)
+func (j *IdempotencyKeyPurger) purge(ctx context.Context) {
+	n, err := j.purgeExpiredIdempotencyKeysUseCase.Execute(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge expired idempotency keys", slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Purged expired idempotency keys", slog.Int("count", n))
+	}
+}
@@ -0,0 +1,22 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// runPeriodically は ctx がキャンセルされるまで interval ごとに fn を実行する (起動直後にも1回実行する)
+func runPeriodically(ctx context.Context, interval time.Duration, fn func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// OutboxRelay はアウトボックスに保存したTodoのイベントを定期的に配信するジョブ
+type OutboxRelay struct {
+	relayTodoEventsUseCase usecase.RelayTodoEvents
+	interval               time.Duration
+}
+
+// NewOutboxRelay は job.OutboxRelay のコンストラクタ
+func NewOutboxRelay(relayTodoEventsUseCase usecase.RelayTodoEvents, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		relayTodoEventsUseCase: relayTodoEventsUseCase,
+		interval:               interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとにイベントを配信する (起動直後にも1回実行する)
+func (j *OutboxRelay) Run(ctx context.Context) {
+	runPeriodically(ctx, j.interval, j.relay)
+}
+
+// relay はイベントを1回配信する (配信に失敗したイベントはアウトボックスに残して再試行するためログのみ出力する)
+func (j *OutboxRelay) relay(ctx context.Context) {
+	n, err := j.relayTodoEventsUseCase.Execute(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to relay todo events", slog.Int("published", n), slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Relayed todo events", slog.Int("count", n))
+	}
+}
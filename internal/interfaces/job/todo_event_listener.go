@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// TodoEventListener はTodoの変更を監視し、購読者に配信するジョブ
+type TodoEventListener struct {
+	listener      repository.TodoEventListener
+	stream        repository.TodoEventStream
+	retryInterval time.Duration
+}
+
+// NewTodoEventListener は job.TodoEventListener のコンストラクタ
+func NewTodoEventListener(listener repository.TodoEventListener, stream repository.TodoEventStream, retryInterval time.Duration) *TodoEventListener {
+	return &TodoEventListener{
+		listener:      listener,
+		stream:        stream,
+		retryInterval: retryInterval,
+	}
+}
+
+// Run は ctx がキャンセルされるまでTodoの変更を監視する
+// 監視が中断した場合は中断している間のイベントを取りこぼすため、購読を全て終了させた上で retryInterval 後に再開する
+func (j *TodoEventListener) Run(ctx context.Context) {
+	for {
+		err := j.listener.Listen(ctx, j.stream)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "Todo event listener stopped", slog.Any("error", err))
+		j.stream.Reset()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(j.retryInterval):
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TrashPurger は保持期間を過ぎたゴミ箱のTodoを定期的に完全に削除するジョブ
+type TrashPurger struct {
+	purgeTrashUseCase usecase.PurgeTrash
+	interval          time.Duration
+	retention         time.Duration
+}
+
+// NewTrashPurger は job.TrashPurger のコンストラクタ
+func NewTrashPurger(purgeTrashUseCase usecase.PurgeTrash, interval, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		purgeTrashUseCase: purgeTrashUseCase,
+		interval:          interval,
+		retention:         retention,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとにゴミ箱のTodoを削除する (起動直後にも1回実行する)
+func (j *TrashPurger) Run(ctx context.Context) {
+	runPeriodically(ctx, j.interval, j.purge)
+}
+
+// purge はゴミ箱のTodoを1回削除する (失敗しても次の実行で再試行するためログのみ出力する)
+func (j *TrashPurger) purge(ctx context.Context) {
+	n, err := j.purgeTrashUseCase.Execute(ctx, time.Now(), j.retention)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge trash", slog.Any("error", err))
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Purged trash", slog.Int("count", n), slog.Duration("retention", j.retention))
+	}
+}
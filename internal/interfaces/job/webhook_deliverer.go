@@ -0,0 +1,43 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// WebhookDeliverer は配信待ちのWebhookの配信を定期的に送信するジョブ
+type WebhookDeliverer struct {
+	deliverWebhooksUseCase usecase.DeliverWebhooks
+	interval               time.Duration
+}
+
+// NewWebhookDeliverer は job.WebhookDeliverer のコンストラクタ
+func NewWebhookDeliverer(deliverWebhooksUseCase usecase.DeliverWebhooks, interval time.Duration) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		deliverWebhooksUseCase: deliverWebhooksUseCase,
+		interval:               interval,
+	}
+}
+
+// Run は ctx がキャンセルされるまで interval ごとに配信を送信する (起動直後にも1回実行する)
+func (j *WebhookDeliverer) Run(ctx context.Context) {
+	runPeriodically(ctx, j.interval, j.deliver)
+}
+
+// deliver は配信を1回送信する (失敗しても次の実行で再試行するためログのみ出力する)
+func (j *WebhookDeliverer) deliver(ctx context.Context) {
+	result, err := j.deliverWebhooksUseCase.Execute(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to deliver webhooks", slog.Any("error", err))
+		return
+	}
+	if result.Dead > 0 {
+		slog.WarnContext(ctx, "Gave up delivering webhooks", slog.Int("count", result.Dead))
+	}
+	if result.Succeeded > 0 || result.Retrying > 0 {
+		slog.InfoContext(ctx, "Delivered webhooks", slog.Int("succeeded", result.Succeeded), slog.Int("retrying", result.Retrying))
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// ActorHeader は操作者を指定するリクエストヘッダー (認証を導入するまでの暫定)
+const ActorHeader = "X-Actor"
+
+// AuditInfo はリクエストの操作者とトレースIDを変更履歴のために context に設定するミドルウェア
+// トレースIDを取得するため TraceContext の後に設定すること
+func AuditInfo(c *gin.Context) {
+	ctx := c.Request.Context()
+	info := model.AuditInfo{
+		Actor: strings.TrimSpace(c.GetHeader(ActorHeader)),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		info.TraceID = sc.TraceID().String()
+	}
+	c.Request = c.Request.WithContext(model.WithAuditInfo(ctx, info))
+	c.Next()
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+const (
+	// IdempotencyKeyHeader は冪等キーを指定するリクエストヘッダー
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader は保存したレスポンスを再送したことを表すレスポンスヘッダー
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency は Idempotency-Key ヘッダーを指定した POST リクエストを冪等にするミドルウェアを返す
+// 最初のリクエストのレスポンスを ttl の間保存し、同じキーで再試行されたリクエストには保存したレスポンスを返す
+// 同じキーで異なるリクエストを送信した場合は 422、最初のリクエストを処理中の場合は 409 を返す
+// サーバーエラーのレスポンスは保存せず、同じキーで再試行できるようにする
+// 冪等キーは操作者ごとに区別するため AuditInfo の後に設定すること
+func Idempotency(idempotencyKeyRepo repository.IdempotencyKey, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > model.MaxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, model.MaxIdempotencyKeyLength),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// リクエストボディは一度読み込むと読み込めなくなるため、読み込んだ内容を再度Bodyにセットする
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		actor := model.AuditInfoFromContext(ctx).Actor
+		scopedKey := fmt.Sprintf("%d:%s:%s", len(actor), actor, key)
+		fingerprint := requestFingerprint(c.Request, body)
+		rec, reserved, err := idempotencyKeyRepo.Reserve(ctx, scopedKey, fingerprint, ttl)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !reserved {
+			switch {
+			case rec.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error": fmt.Sprintf("%s has already been used for a different request", IdempotencyKeyHeader),
+				})
+			case !rec.Completed:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": fmt.Sprintf("a request with the same %s is still being processed", IdempotencyKeyHeader),
+				})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(rec.StatusCode, rec.ContentType, rec.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// ハンドラーが panic した場合も冪等キーを削除して再試行できるようにし、panic は Recovery ミドルウェアに任せる
+		defer func() {
+			if r := recover(); r != nil {
+				if err := idempotencyKeyRepo.Release(context.WithoutCancel(ctx), scopedKey); err != nil {
+					slog.ErrorContext(ctx, "Failed to release idempotency key", slog.Any("error", err))
+				}
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// クライアントが切断した場合もレスポンスの保存または冪等キーの削除を行う
+		ctx = context.WithoutCancel(ctx)
+		if recorder.Status() >= http.StatusInternalServerError {
+			err = idempotencyKeyRepo.Release(ctx, scopedKey)
+		} else {
+			err = idempotencyKeyRepo.Complete(ctx, scopedKey, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to save idempotency key", slog.Any("error", err))
+		}
+	}
+}
+
+// requestFingerprint はリクエストのメソッド、パス (クエリパラメータを含む)、ボディから求めたハッシュを返す
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder はレスポンスボディを記録する gin.ResponseWriter
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write はレスポンスボディを記録してから書き込む
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString はレスポンスボディを記録してから書き込む
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,146 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/interfaces/middleware"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		key   string
+		actor string
+		body  string
+	}
+	tests := []struct {
+		name         string
+		status       int
+		requests     []request
+		wantStatus   []int
+		wantReplayed []bool
+		wantCalls    int
+	}{
+		{
+			name:         "retry replays the first response",
+			status:       http.StatusCreated,
+			requests:     []request{{key: "k1", body: `{"title":"a"}`}, {key: "k1", body: `{"title":"a"}`}},
+			wantStatus:   []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, true},
+			wantCalls:    1,
+		},
+		{
+			name:         "reuse with a different body is rejected",
+			status:       http.StatusCreated,
+			requests:     []request{{key: "k1", body: `{"title":"a"}`}, {key: "k1", body: `{"title":"b"}`}},
+			wantStatus:   []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			wantReplayed: []bool{false, false},
+			wantCalls:    1,
+		},
+		{
+			name:         "keys are scoped to the actor",
+			status:       http.StatusCreated,
+			requests:     []request{{key: "k1", actor: "alice", body: `{}`}, {key: "k1", actor: "bob", body: `{}`}},
+			wantStatus:   []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantCalls:    2,
+		},
+		{
+			name:         "requests without a key are not deduplicated",
+			status:       http.StatusCreated,
+			requests:     []request{{body: `{}`}, {body: `{}`}},
+			wantStatus:   []int{http.StatusCreated, http.StatusCreated},
+			wantReplayed: []bool{false, false},
+			wantCalls:    2,
+		},
+		{
+			name:         "server errors can be retried",
+			status:       http.StatusInternalServerError,
+			requests:     []request{{key: "k1", body: `{}`}, {key: "k1", body: `{}`}},
+			wantStatus:   []int{http.StatusInternalServerError, http.StatusInternalServerError},
+			wantReplayed: []bool{false, false},
+			wantCalls:    2,
+		},
+		{
+			name:         "too long key is rejected",
+			status:       http.StatusCreated,
+			requests:     []request{{key: strings.Repeat("k", 256), body: `{}`}},
+			wantStatus:   []int{http.StatusBadRequest},
+			wantReplayed: []bool{false},
+			wantCalls:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := gin.New()
+			r.Use(middleware.AuditInfo, middleware.Idempotency(inmemory.NewIdempotencyKey(inmemory.NewDB()), time.Minute))
+			r.POST("/todos", func(ctx *gin.Context) {
+				calls++
+				ctx.JSON(tt.status, gin.H{"calls": calls})
+			})
+
+			for i, req := range tt.requests {
+				httpReq := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(req.body))
+				if req.key != "" {
+					httpReq.Header.Set(middleware.IdempotencyKeyHeader, req.key)
+				}
+				httpReq.Header.Set(middleware.ActorHeader, req.actor)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httpReq)
+
+				if w.Code != tt.wantStatus[i] {
+					t.Errorf("request %d: status = %d, want %d", i, w.Code, tt.wantStatus[i])
+				}
+				if got := w.Header().Get(middleware.IdempotentReplayedHeader) == "true"; got != tt.wantReplayed[i] {
+					t.Errorf("request %d: replayed = %v, want %v", i, got, tt.wantReplayed[i])
+				}
+				if tt.wantReplayed[i] && w.Body.String() != `{"calls":1}` {
+					t.Errorf("request %d: body = %s, want the first response", i, w.Body.String())
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotency_Panic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// ハンドラーが panic した場合も冪等キーを削除し、同じキーで再試行できる
+	calls := 0
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard), middleware.AuditInfo, middleware.Idempotency(inmemory.NewIdempotencyKey(inmemory.NewDB()), time.Minute))
+	r.POST("/todos", func(ctx *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"calls": calls})
+	})
+
+	for i, want := range []int{http.StatusInternalServerError, http.StatusCreated} {
+		httpReq := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(`{}`))
+		httpReq.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+
+		if w.Code != want {
+			t.Errorf("request %d: status = %d, want %d", i, w.Code, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	todov1 "github.com/qushot/gin-todo-api/internal/gen/todo/v1"
+)
+
+// todoStatusPrefix は TodoStatus の列挙値の名前の接頭辞 (取り除いて小文字にするとモデルのステータスになる)
+const todoStatusPrefix = "TODO_STATUS_"
+
+// toProtoStatus はモデルのステータスを TodoStatus に変換する
+func toProtoStatus(s model.TodoStatus) todov1.TodoStatus {
+	return todov1.TodoStatus(todov1.TodoStatus_value[todoStatusPrefix+strings.ToUpper(string(s))])
+}
+
+// fromProtoStatus は TodoStatus をモデルのステータスに変換する (未指定の場合は空文字)
+// 未定義の値は不正なステータスに変換し、ユースケースの検証でエラーにする
+func fromProtoStatus(s todov1.TodoStatus) model.TodoStatus {
+	if s == todov1.TodoStatus_TODO_STATUS_UNSPECIFIED {
+		return ""
+	}
+	return model.TodoStatus(strings.ToLower(strings.TrimPrefix(s.String(), todoStatusPrefix)))
+}
+
+// toProtoTime は日時を Timestamp に変換する (nil の場合は nil)
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromProtoTime は Timestamp を日時に変換する (nil の場合は nil)
+func fromProtoTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toProtoTodo はTodoを todov1.Todo に変換する
+func toProtoTodo(t *model.Todo) *todov1.Todo {
+	return &todov1.Todo{
+		Id:           t.ID,
+		Title:        t.Title,
+		Content:      t.Content,
+		Status:       toProtoStatus(t.Status),
+		Priority:     int32(t.Priority),
+		Tags:         t.Tags,
+		ParentId:     t.ParentID,
+		Position:     int32(t.Position),
+		AutoComplete: t.AutoComplete,
+		BlockedBy:    t.BlockedBy,
+		DueAt:        toProtoTime(t.DueAt),
+		Recurrence:   t.Recurrence,
+		Timezone:     t.Timezone,
+		SeriesId:     t.SeriesID,
+		OccurrenceAt: toProtoTime(t.OccurrenceAt),
+		CompletedAt:  toProtoTime(t.CompletedAt),
+		ArchivedAt:   toProtoTime(t.ArchivedAt),
+		DeletedAt:    toProtoTime(t.DeletedAt),
+		Version:      int64(t.Version),
+		UpdatedAt:    timestamppb.New(t.UpdatedAt),
+		Done:         t.Done,
+	}
+}
+
+// toProtoTodos はTodoの一覧を todov1.Todo の一覧に変換する
+func toProtoTodos(todos []model.Todo) []*todov1.Todo {
+	res := make([]*todov1.Todo, len(todos))
+	for i := range todos {
+		res[i] = toProtoTodo(&todos[i])
+	}
+	return res
+}
+
+// fromProtoTodo は作成・更新するTodoを変換する (REST API のリクエストボディと同じく、サーバーが決める値は変換しない)
+// タグが空の場合は nil にする (更新では現在のタグを維持する)
+func fromProtoTodo(t *todov1.Todo) model.Todo {
+	todo := model.Todo{
+		Title:        t.GetTitle(),
+		Content:      t.GetContent(),
+		Status:       fromProtoStatus(t.GetStatus()),
+		Priority:     int(t.GetPriority()),
+		ParentID:     t.ParentId,
+		AutoComplete: t.GetAutoComplete(),
+		DueAt:        fromProtoTime(t.GetDueAt()),
+		Recurrence:   t.GetRecurrence(),
+		Timezone:     t.GetTimezone(),
+		Done:         t.GetDone(),
+	}
+	if len(t.GetTags()) > 0 {
+		todo.Tags = t.GetTags()
+	}
+	return todo
+}
+
+// fromProtoPatch は部分更新のリクエストを model.TodoPatch に変換する
+func fromProtoPatch(req *todov1.PatchTodoRequest) model.TodoPatch {
+	patch := model.TodoPatch{
+		Title:        req.Title,
+		Content:      req.Content,
+		Done:         req.Done,
+		ParentID:     req.ParentId,
+		AutoComplete: req.AutoComplete,
+		Recurrence:   req.Recurrence,
+		Timezone:     req.Timezone,
+	}
+	if req.DueAt != nil {
+		patch.DueAt = model.NullableOf(fromProtoTime(req.GetDueAt()))
+	}
+	if req.Status != nil {
+		status := fromProtoStatus(req.GetStatus())
+		patch.Status = &status
+	}
+	if req.Priority != nil {
+		priority := int(req.GetPriority())
+		patch.Priority = &priority
+	}
+	if req.Tags != nil {
+		tags := req.GetTags().GetTags()
+		if tags == nil {
+			tags = []string{}
+		}
+		patch.Tags = &tags
+	}
+	return patch
+}
+
+// fromProtoFilter は検索条件を model.TodoQuery に変換する
+func fromProtoFilter(f *todov1.TodoFilter) model.TodoQuery {
+	query := model.TodoQuery{
+		Status:     fromProtoStatus(f.GetStatus()),
+		Tags:       f.GetTags(),
+		TagMatch:   model.TagMatch(f.GetTagMatch()),
+		Actionable: f.GetActionable(),
+		SeriesID:   f.GetSeriesId(),
+		Archived:   model.ArchivedFilter(f.GetArchived()),
+		Q:          f.GetQ(),
+		Due:        model.DueFilter(f.GetDue()),
+		Timezone:   f.GetTimezone(),
+	}
+	if f != nil && f.MinPriority != nil {
+		minPriority := int(f.GetMinPriority())
+		query.MinPriority = &minPriority
+	}
+	return query
+}
+
+// toProtoEvent は配信するイベントを todov1.TodoEvent に変換する (stream.reset のイベントは種類のみ)
+func toProtoEvent(e model.TodoStreamEvent) *todov1.TodoEvent {
+	if e.Type == model.TodoEventStreamReset {
+		return &todov1.TodoEvent{Type: string(e.Type)}
+	}
+	return &todov1.TodoEvent{
+		Id:         e.ID,
+		Type:       string(e.Type),
+		TodoId:     e.TodoID,
+		Actor:      e.Actor,
+		TraceId:    e.TraceID,
+		Todo:       toProtoTodo(&e.Todo),
+		OccurredAt: timestamppb.New(e.OccurredAt),
+		Match:      e.Match,
+	}
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// errorDomain はエラーの詳細 (ErrorInfo) のドメイン
+const errorDomain = "todo.v1"
+
+// エラーの詳細 (ErrorInfo) の理由
+const (
+	reasonNotFound                = "NOT_FOUND"
+	reasonInvalidArgument         = "INVALID_ARGUMENT"
+	reasonConflict                = "CONFLICT"
+	reasonInvalidStatusTransition = "INVALID_STATUS_TRANSITION"
+	reasonInternal                = "INTERNAL"
+)
+
+// toStatusError はエラーの種類に応じたステータスコードと詳細を持つ gRPC のエラーに変換する (REST API の errorStatus に対応する)
+// 既に gRPC のエラーの場合はそのまま返す
+func toStatusError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var (
+		code   codes.Code
+		reason string
+	)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		code, reason = codes.NotFound, reasonNotFound
+	case errors.Is(err, model.ErrInvalidArgument):
+		code, reason = codes.InvalidArgument, reasonInvalidArgument
+	case errors.Is(err, model.ErrConflict):
+		code, reason = codes.Aborted, reasonConflict
+	case errors.Is(err, model.ErrInvalidStatusTransition):
+		code, reason = codes.FailedPrecondition, reasonInvalidStatusTransition
+	default:
+		slog.ErrorContext(ctx, "gRPC request failed", slog.Any("error", err))
+		code, reason = codes.Internal, reasonInternal
+	}
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/interfaces/middleware"
+)
+
+// actorMetadataKey は操作者を指定するメタデータのキー (REST API の X-Actor ヘッダーに対応する)
+var actorMetadataKey = strings.ToLower(middleware.ActorHeader)
+
+// metadataCarrier は gRPC のメタデータを propagation.TextMapCarrier として扱う
+type metadataCarrier metadata.MD
+
+// Get は key の最初の値を返す
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set は key の値を設定する
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys は全てのキーを返す
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// withRequestInfo はメタデータのトレースコンテキストと操作者を context に設定する
+// REST API の middleware.TraceContext と middleware.AuditInfo に対応する
+func withRequestInfo(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = propagation.TraceContext{}.Extract(ctx, metadataCarrier(md))
+
+	info := model.AuditInfo{
+		Actor: strings.TrimSpace(metadataCarrier(md).Get(actorMetadataKey)),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		info.TraceID = sc.TraceID().String()
+	}
+	return model.WithAuditInfo(ctx, info)
+}
+
+// recoverPanic はハンドラーの panic から回復して INTERNAL のエラーにする (REST API の gin.Recovery に対応する)
+func recoverPanic(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		slog.ErrorContext(ctx, "gRPC handler panicked", slog.String("method", method), slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+// unaryInterceptor はリクエストの情報を context に設定し、エラーを gRPC のエラーに変換する
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+	ctx = withRequestInfo(ctx)
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	res, err = handler(ctx, req)
+	if err != nil {
+		return nil, toStatusError(ctx, err)
+	}
+	return res, nil
+}
+
+// serverStream は context を差し替えた grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context はリクエストの情報を設定した context を返す
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamInterceptor はストリームのリクエストの情報を context に設定し、エラーを gRPC のエラーに変換する
+func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := withRequestInfo(ss.Context())
+	defer recoverPanic(ctx, info.FullMethod, &err)
+	if err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx}); err != nil {
+		return toStatusError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	todov1 "github.com/qushot/gin-todo-api/internal/gen/todo/v1"
+)
+
+// Server は gRPC のAPIサーバーを表す (ヘルスチェックとリフレクションのサービスも提供する)
+type Server struct {
+	srv    *grpc.Server
+	health *health.Server
+}
+
+// NewServer は todoService を提供する新しい Server を作成する
+func NewServer(todoService todov1.TodoServiceServer) *Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptor),
+		grpc.ChainStreamInterceptor(streamInterceptor),
+	)
+	todov1.RegisterTodoServiceServer(srv, todoService)
+
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	healthSrv.SetServingStatus(todov1.TodoService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	reflection.Register(srv)
+
+	return &Server{
+		srv:    srv,
+		health: healthSrv,
+	}
+}
+
+// Serve は lis で接続を受け付ける (停止するまで戻らない)
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Start は addr で接続の受け付けを開始する
+func (s *Server) Start(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			slog.Error("grpc.Server.Serve error", slog.Any("error", err))
+			return
+		}
+	}()
+
+	return nil
+}
+
+// GracefulShutdown は処理中のリクエストの完了を待ってサーバーを停止する
+// ctx が終了した場合 (配信中の WatchTodos など) は残りの接続を切断する
+func (s *Server) GracefulShutdown(ctx context.Context) {
+	// ヘルスチェックを NOT_SERVING にして、新しいリクエストを受け付けないことを伝える
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-stopped
+	}
+}
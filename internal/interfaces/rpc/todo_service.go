@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	todov1 "github.com/qushot/gin-todo-api/internal/gen/todo/v1"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// TodoService はTodo操作のための gRPC サービス (REST API の controllers.Todo と同じユースケースを使う)
+type TodoService struct {
+	todov1.UnimplementedTodoServiceServer
+
+	getAllTodosUseCase usecase.GetAllTodos
+	getTodoByIDUseCase usecase.GetTodoByID
+	createTodoUseCase  usecase.CreateTodo
+	updateTodoUseCase  usecase.UpdateTodo
+	patchTodoUseCase   usecase.PatchTodo
+	deleteTodoUseCase  usecase.DeleteTodo
+	streamTodosUseCase usecase.StreamTodos
+}
+
+// NewTodoService は rpc.TodoService のコンストラクタ
+func NewTodoService(
+	getAllTodosUseCase usecase.GetAllTodos,
+	getTodoByIDUseCase usecase.GetTodoByID,
+	createTodoUseCase usecase.CreateTodo,
+	updateTodoUseCase usecase.UpdateTodo,
+	patchTodoUseCase usecase.PatchTodo,
+	deleteTodoUseCase usecase.DeleteTodo,
+	streamTodosUseCase usecase.StreamTodos,
+) *TodoService {
+	return &TodoService{
+		getAllTodosUseCase: getAllTodosUseCase,
+		getTodoByIDUseCase: getTodoByIDUseCase,
+		createTodoUseCase:  createTodoUseCase,
+		updateTodoUseCase:  updateTodoUseCase,
+		patchTodoUseCase:   patchTodoUseCase,
+		deleteTodoUseCase:  deleteTodoUseCase,
+		streamTodosUseCase: streamTodosUseCase,
+	}
+}
+
+// withExpectedVersion は期待するバージョンが指定されている場合に context に設定する
+func withExpectedVersion(ctx context.Context, version *int64) context.Context {
+	if version == nil {
+		return ctx
+	}
+	return model.WithExpectedVersion(ctx, int(*version))
+}
+
+// ListTodos は検索条件に一致するTodoを取得する
+func (s *TodoService) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
+	todos, err := s.getAllTodosUseCase.Execute(ctx, fromProtoFilter(req.GetFilter()))
+	if err != nil {
+		return nil, err
+	}
+	return &todov1.ListTodosResponse{Todos: toProtoTodos(todos)}, nil
+}
+
+// GetTodo は指定されたIDのTodoを取得する
+func (s *TodoService) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.Todo, error) {
+	todo, err := s.getTodoByIDUseCase.Execute(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toProtoTodo(todo), nil
+}
+
+// CreateTodo は新しいTodoを作成する
+func (s *TodoService) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.Todo, error) {
+	if req.GetTodo() == nil {
+		return nil, fmt.Errorf("%w: todo is required", model.ErrInvalidArgument)
+	}
+	todo, err := s.createTodoUseCase.Execute(ctx, fromProtoTodo(req.GetTodo()))
+	if err != nil {
+		return nil, err
+	}
+	return toProtoTodo(todo), nil
+}
+
+// UpdateTodo は指定されたIDのTodoを更新する
+func (s *TodoService) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest) (*todov1.Todo, error) {
+	if req.GetTodo() == nil {
+		return nil, fmt.Errorf("%w: todo is required", model.ErrInvalidArgument)
+	}
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	todo, err := s.updateTodoUseCase.Execute(ctx, req.GetId(), fromProtoTodo(req.GetTodo()), model.RecurrenceScope(req.GetScope()))
+	if err != nil {
+		return nil, err
+	}
+	return toProtoTodo(todo), nil
+}
+
+// PatchTodo は指定されたIDのTodoを部分更新する
+func (s *TodoService) PatchTodo(ctx context.Context, req *todov1.PatchTodoRequest) (*todov1.Todo, error) {
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	todo, err := s.patchTodoUseCase.Execute(ctx, req.GetId(), fromProtoPatch(req), model.RecurrenceScope(req.GetScope()))
+	if err != nil {
+		return nil, err
+	}
+	return toProtoTodo(todo), nil
+}
+
+// DeleteTodo は指定されたIDのTodoをゴミ箱に移動する
+func (s *TodoService) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	if err := s.deleteTodoUseCase.Execute(ctx, req.GetId(), req.GetCascade()); err != nil {
+		return nil, err
+	}
+	return &todov1.DeleteTodoResponse{}, nil
+}
+
+// WatchTodos は検索条件に関係するTodoのイベントをキャンセルされるまで配信する
+// last_event_id を指定した場合はその続きから配信し、続きから配信できない場合は stream.reset のイベントを最初に配信する
+// 受け取りが遅れて配信を終了した場合は UNAVAILABLE を返す
+func (s *TodoService) WatchTodos(req *todov1.WatchTodosRequest, srv grpc.ServerStreamingServer[todov1.TodoEvent]) error {
+	ctx := srv.Context()
+	stream, err := s.streamTodosUseCase.Execute(ctx, fromProtoFilter(req.GetFilter()), req.GetLastEventId())
+	if err != nil {
+		return err
+	}
+	// 購読を開始したことをすぐに受信側に伝えるため、最初のイベントを待たずにヘッダーを送る
+	if err := srv.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for event := range stream.Events {
+		if err := srv.Send(toProtoEvent(event)); err != nil {
+			return err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		slog.ErrorContext(ctx, "Todo stream closed", slog.Any("error", err))
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// 受け取りが遅れて購読が終了した場合は、受信側に最後に受け取ったイベントのIDを指定して再開させる
+	return status.Error(codes.Unavailable, "todo stream closed; resume with last_event_id")
+}
@@ -0,0 +1,228 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	todov1 "github.com/qushot/gin-todo-api/internal/gen/todo/v1"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/persistence/inmemory"
+	"github.com/qushot/gin-todo-api/internal/infrastructure/stream"
+	"github.com/qushot/gin-todo-api/internal/interfaces/rpc"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// testServer は in-memory のリポジトリで gRPC の API を提供するサーバー
+type testServer struct {
+	conn  *grpc.ClientConn
+	relay usecase.RelayTodoEvents
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	db := inmemory.NewEmptyDB()
+	todoRepo := inmemory.NewTodo(db)
+	dependencyRepo := inmemory.NewTodoDependency(db)
+	outboxRepo := inmemory.NewOutbox(db)
+	txManager := inmemory.NewTxManager(db)
+	broker := stream.NewBroker(stream.DefaultRetention)
+	srv := rpc.NewServer(rpc.NewTodoService(
+		usecase.NewGetAllTodos(todoRepo),
+		usecase.NewGetTodoByID(todoRepo),
+		usecase.NewCreateTodo(txManager, todoRepo, outboxRepo),
+		usecase.NewUpdateTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewPatchTodo(txManager, todoRepo, dependencyRepo, outboxRepo),
+		usecase.NewDeleteTodo(txManager, todoRepo, outboxRepo),
+		usecase.NewStreamTodos(todoRepo, broker),
+	))
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.GracefulShutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testServer{conn: conn, relay: usecase.NewRelayTodoEvents(outboxRepo, broker)}
+}
+
+// assertStatus は err が code のステータスで、詳細の ErrorInfo の理由が reason であることを検証する
+func assertStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != code {
+		t.Fatalf("error = %v, want code %v", err, code)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.GetReason() != reason || info.GetDomain() != "todo.v1" {
+				t.Errorf("ErrorInfo = %v, want reason %q", info, reason)
+			}
+			return
+		}
+	}
+	t.Errorf("details = %v, want ErrorInfo", st.Details())
+}
+
+func TestTodoService_CRUD(t *testing.T) {
+	srv := newTestServer(t)
+	client := todov1.NewTodoServiceClient(srv.conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Todo: &todov1.Todo{Title: "write proto", Tags: []string{"grpc"}}})
+	if err != nil {
+		t.Fatalf("CreateTodo() error = %v", err)
+	}
+	if created.GetId() == "" || created.GetStatus() != todov1.TodoStatus_TODO_STATUS_TODO || created.GetVersion() != 1 {
+		t.Fatalf("CreateTodo() = %v, want a new todo", created)
+	}
+
+	got, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("GetTodo() error = %v", err)
+	}
+	if got.GetTitle() != "write proto" {
+		t.Errorf("GetTodo() title = %q, want %q", got.GetTitle(), "write proto")
+	}
+
+	// 部分更新では指定したフィールドのみを更新し、空のタグのリストで全てのタグを外す
+	done := true
+	patched, err := client.PatchTodo(ctx, &todov1.PatchTodoRequest{Id: created.GetId(), Done: &done, Tags: &todov1.TagList{}})
+	if err != nil {
+		t.Fatalf("PatchTodo() error = %v", err)
+	}
+	if patched.GetStatus() != todov1.TodoStatus_TODO_STATUS_DONE || patched.GetCompletedAt() == nil || len(patched.GetTags()) != 0 {
+		t.Errorf("PatchTodo() = %v, want a done todo without tags", patched)
+	}
+
+	all, err := client.ListTodos(ctx, &todov1.ListTodosRequest{})
+	if err != nil {
+		t.Fatalf("ListTodos() without filter error = %v", err)
+	}
+	if len(all.GetTodos()) != 1 {
+		t.Errorf("ListTodos() without filter = %v, want all todos", all.GetTodos())
+	}
+	list, err := client.ListTodos(ctx, &todov1.ListTodosRequest{Filter: &todov1.TodoFilter{Status: todov1.TodoStatus_TODO_STATUS_DONE}})
+	if err != nil {
+		t.Fatalf("ListTodos() error = %v", err)
+	}
+	if len(list.GetTodos()) != 1 || list.GetTodos()[0].GetId() != created.GetId() {
+		t.Errorf("ListTodos() = %v, want the done todo", list.GetTodos())
+	}
+
+	// 期待するバージョンが現在のバージョンと異なる場合は ABORTED を返す
+	stale := created.GetVersion()
+	_, err = client.UpdateTodo(ctx, &todov1.UpdateTodoRequest{Id: created.GetId(), Todo: &todov1.Todo{Title: "stale"}, ExpectedVersion: &stale})
+	assertStatus(t, err, codes.Aborted, "CONFLICT")
+
+	current := patched.GetVersion()
+	updated, err := client.UpdateTodo(ctx, &todov1.UpdateTodoRequest{
+		Id:              created.GetId(),
+		Todo:            &todov1.Todo{Title: "rewrite proto", Status: todov1.TodoStatus_TODO_STATUS_TODO},
+		ExpectedVersion: &current,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTodo() error = %v", err)
+	}
+	if updated.GetTitle() != "rewrite proto" || updated.GetVersion() != current+1 {
+		t.Errorf("UpdateTodo() = %v, want the updated todo", updated)
+	}
+
+	if _, err := client.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+	_, err = client.GetTodo(ctx, &todov1.GetTodoRequest{Id: created.GetId()})
+	assertStatus(t, err, codes.NotFound, "NOT_FOUND")
+}
+
+func TestTodoService_errors(t *testing.T) {
+	srv := newTestServer(t)
+	client := todov1.NewTodoServiceClient(srv.conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{})
+	assertStatus(t, err, codes.InvalidArgument, "INVALID_ARGUMENT")
+
+	_, err = client.CreateTodo(ctx, &todov1.CreateTodoRequest{Todo: &todov1.Todo{Title: "invalid", Priority: 9}})
+	assertStatus(t, err, codes.InvalidArgument, "INVALID_ARGUMENT")
+
+	created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Todo: &todov1.Todo{Title: "closed", Status: todov1.TodoStatus_TODO_STATUS_DONE}})
+	if err != nil {
+		t.Fatalf("CreateTodo() error = %v", err)
+	}
+	blocked := todov1.TodoStatus_TODO_STATUS_BLOCKED
+	_, err = client.PatchTodo(ctx, &todov1.PatchTodoRequest{Id: created.GetId(), Status: &blocked})
+	assertStatus(t, err, codes.FailedPrecondition, "INVALID_STATUS_TRANSITION")
+}
+
+func TestTodoService_WatchTodos(t *testing.T) {
+	srv := newTestServer(t)
+	client := todov1.NewTodoServiceClient(srv.conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchTodos(ctx, &todov1.WatchTodosRequest{Filter: &todov1.TodoFilter{Tags: []string{"grpc"}}})
+	if err != nil {
+		t.Fatalf("WatchTodos() error = %v", err)
+	}
+	// 購読を開始するとヘッダーが届く
+	if _, err := watch.Header(); err != nil {
+		t.Fatalf("Header() error = %v", err)
+	}
+
+	// 操作者はメタデータ x-actor で指定する
+	actorCtx := metadata.AppendToOutgoingContext(ctx, "x-actor", "alice")
+	created, err := client.CreateTodo(actorCtx, &todov1.CreateTodoRequest{Todo: &todov1.Todo{Title: "watched", Tags: []string{"grpc"}}})
+	if err != nil {
+		t.Fatalf("CreateTodo() error = %v", err)
+	}
+	if _, err := srv.relay.Execute(ctx, time.Now()); err != nil {
+		t.Fatalf("relay: %v", err)
+	}
+
+	event, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if event.GetType() != "todo.created" || event.GetTodoId() != created.GetId() || event.GetActor() != "alice" || !event.GetMatch() {
+		t.Errorf("Recv() = %v, want todo.created by alice", event)
+	}
+	if event.GetTodo().GetTitle() != "watched" {
+		t.Errorf("Recv() todo = %v, want the created todo", event.GetTodo())
+	}
+}
+
+func TestServer_health(t *testing.T) {
+	srv := newTestServer(t)
+	client := healthpb.NewHealthClient(srv.conn)
+
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: todov1.TodoService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Check() status = %v, want SERVING", res.GetStatus())
+	}
+}
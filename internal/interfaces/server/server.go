@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"expvar"
 	"log/slog"
 	"net/http"
 	"os"
@@ -24,7 +25,7 @@ type Server struct {
 func New() *Server {
 	gin.SetMode(gin.DebugMode)
 	r := gin.Default()
-	r.Use(middleware.TraceContext, middleware.DumpRequestBody)
+	r.Use(middleware.TraceContext, middleware.AuditInfo, middleware.DumpRequestBody)
 
 	return &Server{
 		router: r,
@@ -32,14 +33,32 @@ func New() *Server {
 }
 
 // SetupRoutes はルーティングを設定する
-func (s *Server) SetupRoutes() {
+// idempotencyKeyTTL は Idempotency-Key ヘッダーを指定したリクエストのレスポンスを保持する期間
+func (s *Server) SetupRoutes(idempotencyKeyTTL time.Duration) {
 	// DI Containerからコントローラーを取得
 	c := di.GetContainer()
 	// ルートグループの設定
-	baseRouter := s.router.Group("/api/v1")
+	// 実行時の統計情報 (Todoのキャッシュの利用状況を含む) はデバッグモードの場合のみ公開する
+	if gin.IsDebugging() {
+		s.router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+	baseRouter := s.router.Group("/api/v1", middleware.Idempotency(c.IdempotencyKeyRepo, idempotencyKeyTTL))
 	{
 		c.TodoController.RegisterRoutes(baseRouter)
+		c.TodoBatchController.RegisterRoutes(baseRouter)
+		c.TodoStreamController.RegisterRoutes(baseRouter)
+		c.TodoDependencyController.RegisterRoutes(baseRouter)
+		c.TodoTrashController.RegisterRoutes(baseRouter)
+		c.TodoArchiveController.RegisterRoutes(baseRouter)
+		c.TodoHistoryController.RegisterRoutes(baseRouter)
+		c.TagController.RegisterRoutes(baseRouter)
+		c.ViewController.RegisterRoutes(baseRouter)
+		c.WebhookController.RegisterRoutes(baseRouter)
+		c.SyncController.RegisterRoutes(baseRouter)
+		c.CollaborationHandler.RegisterRoutes(baseRouter)
 	}
+	// GraphQL はクエリごとに取得する内容が異なるため、REST API のバージョンのパスの外で提供する
+	c.GraphHandler.RegisterRoutes(&s.router.RouterGroup)
 }
 
 // Start はサーバーを起動する
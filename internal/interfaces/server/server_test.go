@@ -0,0 +1,256 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/infrastructure/db"
+)
+
+// TestMain はビルドタグで選ばれる保存先を一時的なSQLiteとRedis (miniredis) で初期化する
+// (DI コンテナはプロセスで1つのため、全てのテストで同じ保存先を使う)
+func TestMain(m *testing.M) {
+	os.Exit(func() int {
+		dir, err := os.MkdirTemp("", "server-test")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer os.RemoveAll(dir)
+		if _, err := db.InitializeSQLite(filepath.Join(dir, "todo.db")); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer db.CloseSQLite()
+
+		mr, err := miniredis.Run()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer mr.Close()
+		if _, err := db.InitializeRedis("redis://" + mr.Addr()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer db.CloseRedis()
+
+		return m.Run()
+	}())
+}
+
+// newTestServer はルーティングを設定したサーバーを返す
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	s := New()
+	s.SetupRoutes(time.Hour)
+	return s
+}
+
+// serve はサーバーのルーターにリクエストを送り、レスポンスを返す
+func serve(t *testing.T, s *Server, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTodoBatch_Route(t *testing.T) {
+	s := newTestServer(t)
+
+	w := serve(t, s, http.MethodPost, "/api/v1/todos", `{"title": "existing"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %s", w.Code, w.Body)
+	}
+	var existing struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &existing); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+
+	body := fmt.Sprintf(`{"mode": "atomic", "operations": [
+		{"op": "create", "todo": {"title": "batch"}},
+		{"op": "complete", "id": %q}
+	]}`, existing.ID)
+	w = serve(t, s, http.MethodPost, "/api/v1/todos:batch", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("batch status = %d, body = %s", w.Code, w.Body)
+	}
+	var res struct {
+		Mode    string `json:"mode"`
+		Results []struct {
+			Status int `json:"status"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode batch response: %v", err)
+	}
+	if len(res.Results) != 2 || res.Results[0].Status != http.StatusCreated || res.Results[1].Status != http.StatusOK {
+		t.Errorf("batch results = %+v, want [201 200]", res.Results)
+	}
+
+	// "/todos" に続くパスは ":batch" の場合のみ一括操作として扱う
+	for _, path := range []string{"/api/v1/todos:other", "/api/v1/todos/batch", "/api/v1/todosbatch"} {
+		if w := serve(t, s, http.MethodPost, path, body); w.Code != http.StatusNotFound {
+			t.Errorf("POST %s status = %d, want %d", path, w.Code, http.StatusNotFound)
+		}
+	}
+
+	// 一括操作のパスを追加しても、IDのパスパラメータのルーティングは変わらない
+	w = serve(t, s, http.MethodGet, "/api/v1/todos/"+existing.ID, "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"done"`) {
+		t.Errorf("get status = %d, body = %s", w.Code, w.Body)
+	}
+}
+
+// createTodo はTodoを作成し、作成したTodoのIDを返す
+func createTodo(t *testing.T, s *Server, title string) string {
+	t.Helper()
+	w := serve(t, s, http.MethodPost, "/api/v1/todos", fmt.Sprintf(`{"title": %q}`, title))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %s", w.Code, w.Body)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+	return created.ID
+}
+
+func TestTodoDependency_ConcurrentCycle(t *testing.T) {
+	s := newTestServer(t)
+	a := createTodo(t, s, "A")
+	b := createTodo(t, s, "B")
+
+	// A→B と B→A を同時に追加しても、循環する依存関係は片方しか追加されない
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i, pair := range [][2]string{{a, b}, {b, a}} {
+		wg.Go(func() {
+			w := serve(t, s, http.MethodPost, "/api/v1/todos/"+pair[0]+"/dependencies", fmt.Sprintf(`{"blocked_by": %q}`, pair[1]))
+			codes[i] = w.Code
+		})
+	}
+	wg.Wait()
+	slices.Sort(codes)
+	if diff := cmp.Diff([]int{http.StatusOK, http.StatusBadRequest}, codes); diff != "" {
+		t.Errorf("add statuses mismatch (-want +got):\n%s", diff)
+	}
+
+	// 依存関係を追加したTodoのみ、更新としてバージョンを上げる
+	type state struct {
+		Version   int      `json:"version"`
+		BlockedBy []string `json:"blocked_by"`
+	}
+	got := map[string]state{}
+	for _, id := range []string{a, b} {
+		w := serve(t, s, http.MethodGet, "/api/v1/todos/"+id, "")
+		var todo state
+		if err := json.Unmarshal(w.Body.Bytes(), &todo); err != nil {
+			t.Fatalf("decode get response: %v", err)
+		}
+		got[id] = todo
+	}
+	want := map[string]state{a: {Version: 2, BlockedBy: []string{b}}, b: {Version: 1, BlockedBy: []string{}}}
+	if len(got[b].BlockedBy) > 0 {
+		want = map[string]state{a: {Version: 1, BlockedBy: []string{}}, b: {Version: 2, BlockedBy: []string{a}}}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("todos mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// findTagID は name のタグのIDを返す
+func findTagID(t *testing.T, s *Server, name string) string {
+	t.Helper()
+	w := serve(t, s, http.MethodGet, "/api/v1/tags", "")
+	var tags []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &tags); err != nil {
+		t.Fatalf("decode tags response: %v", err)
+	}
+	for _, tag := range tags {
+		if tag.Name == name {
+			return tag.ID
+		}
+	}
+	t.Fatalf("tag %q not found in %s", name, w.Body)
+	return ""
+}
+
+func TestTag_ChangesTodoETag(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T, tagID, otherID string) *httptest.ResponseRecorder
+	}{
+		{
+			name: "rename",
+			change: func(t *testing.T, tagID, _ string) *httptest.ResponseRecorder {
+				return serve(t, s, http.MethodPatch, "/api/v1/tags/"+tagID, `{"name": "renamed-etag"}`)
+			},
+		},
+		{
+			name: "merge",
+			change: func(t *testing.T, tagID, otherID string) *httptest.ResponseRecorder {
+				return serve(t, s, http.MethodPost, "/api/v1/tags/"+tagID+"/merge", fmt.Sprintf(`{"into": %q}`, otherID))
+			},
+		},
+		{
+			name: "delete",
+			change: func(t *testing.T, tagID, _ string) *httptest.ResponseRecorder {
+				return serve(t, s, http.MethodDelete, "/api/v1/tags/"+tagID, "")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, other := tt.name+"-etag", tt.name+"-other"
+			w := serve(t, s, http.MethodPost, "/api/v1/todos", fmt.Sprintf(`{"title": %q, "tags": [%q, %q]}`, tt.name, tag, other))
+			if w.Code != http.StatusCreated {
+				t.Fatalf("create status = %d, body = %s", w.Code, w.Body)
+			}
+			var created struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+				t.Fatalf("decode create response: %v", err)
+			}
+			todoETag := serve(t, s, http.MethodGet, "/api/v1/todos/"+created.ID, "").Header().Get("ETag")
+			listETag := serve(t, s, http.MethodGet, "/api/v1/todos", "").Header().Get("ETag")
+
+			if w := tt.change(t, findTagID(t, s, tag), findTagID(t, s, other)); w.Code >= http.StatusBadRequest {
+				t.Fatalf("%s status = %d, body = %s", tt.name, w.Code, w.Body)
+			}
+
+			// タグの変更でTodoの内容が変わるため、Todoと一覧のETagも変わる
+			w = serve(t, s, http.MethodGet, "/api/v1/todos/"+created.ID, "")
+			if got := w.Header().Get("ETag"); got == todoETag || strings.Contains(w.Body.String(), tag) {
+				t.Errorf("todo ETag = %s (before %s), body = %s", got, todoETag, w.Body)
+			}
+			if got := serve(t, s, http.MethodGet, "/api/v1/todos", "").Header().Get("ETag"); got == listETag {
+				t.Errorf("list ETag = %s, want a change from %s", got, listETag)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package wsaccept は gin のハンドラーで WebSocket の接続を受け付ける
+package wsaccept
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"github.com/gin-gonic/gin"
+)
+
+// Accept は ctx のリクエストを WebSocket の接続にアップグレードする
+// 失敗した場合は Accept がエラーのレスポンスを返している
+func Accept(ctx *gin.Context, opts *websocket.AcceptOptions) (*websocket.Conn, error) {
+	return websocket.Accept(acceptWriter{w: ctx.Writer}, ctx.Request, opts)
+}
+
+// acceptWriter は websocket.Accept に渡す ResponseWriter
+// gin はステータスを書き込んだ後のハイジャックを拒否するため、Accept が呼び出す gin の WriteHeaderNow を隠し、
+// 101 Switching Protocols は gin に記録した上で、ハイジャックする元の ResponseWriter に直接書き込む
+type acceptWriter struct {
+	w gin.ResponseWriter
+}
+
+func (a acceptWriter) Header() http.Header {
+	return a.w.Header()
+}
+
+func (a acceptWriter) Write(b []byte) (int, error) {
+	return a.w.Write(b)
+}
+
+func (a acceptWriter) WriteHeader(code int) {
+	a.w.WriteHeader(code)
+	if u, ok := a.w.(interface{ Unwrap() http.ResponseWriter }); ok && code == http.StatusSwitchingProtocols {
+		u.Unwrap().WriteHeader(code)
+	}
+}
+
+func (a acceptWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return a.w.Hijack()
+}
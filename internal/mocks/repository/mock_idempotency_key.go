@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency_key.go
+//
+// Generated by this command:
+//
+//	mockgen -source=idempotency_key.go -destination=../../mocks/repository/mock_idempotency_key.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/qushot/gin-todo-api/internal/domain/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdempotencyKey is a mock of IdempotencyKey interface.
+type MockIdempotencyKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyKeyMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyKeyMockRecorder is the mock recorder for MockIdempotencyKey.
+type MockIdempotencyKeyMockRecorder struct {
+	mock *MockIdempotencyKey
+}
+
+// NewMockIdempotencyKey creates a new mock instance.
+func NewMockIdempotencyKey(ctrl *gomock.Controller) *MockIdempotencyKey {
+	mock := &MockIdempotencyKey{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyKey) EXPECT() *MockIdempotencyKeyMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyKey) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, statusCode, contentType, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyKeyMockRecorder) Complete(ctx, key, statusCode, contentType, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyKey)(nil).Complete), ctx, key, statusCode, contentType, body)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyKey) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyKeyMockRecorder) DeleteExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyKey)(nil).DeleteExpired), ctx, before)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyKey) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyKeyMockRecorder) Release(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyKey)(nil).Release), ctx, key)
+}
+
+// Reserve mocks base method.
+func (m *MockIdempotencyKey) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*model.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, fingerprint, ttl)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIdempotencyKeyMockRecorder) Reserve(ctx, key, fingerprint, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIdempotencyKey)(nil).Reserve), ctx, key, fingerprint, ttl)
+}
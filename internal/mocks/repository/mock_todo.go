@@ -12,6 +12,7 @@ package mock_repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/qushot/gin-todo-api/internal/domain/model"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,51 @@ func (m *MockTodo) EXPECT() *MockTodoMockRecorder {
 	return m.recorder
 }
 
+// ApplyBatch mocks base method.
+func (m *MockTodo) ApplyBatch(ctx context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyBatch", ctx, writes)
+	ret0, _ := ret[0].([]*model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyBatch indicates an expected call of ApplyBatch.
+func (mr *MockTodoMockRecorder) ApplyBatch(ctx, writes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyBatch", reflect.TypeOf((*MockTodo)(nil).ApplyBatch), ctx, writes)
+}
+
+// Archive mocks base method.
+func (m *MockTodo) Archive(ctx context.Context, id string) (*model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, id)
+	ret0, _ := ret[0].(*model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockTodoMockRecorder) Archive(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockTodo)(nil).Archive), ctx, id)
+}
+
+// ArchiveCompletedBefore mocks base method.
+func (m *MockTodo) ArchiveCompletedBefore(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveCompletedBefore", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveCompletedBefore indicates an expected call of ArchiveCompletedBefore.
+func (mr *MockTodoMockRecorder) ArchiveCompletedBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveCompletedBefore", reflect.TypeOf((*MockTodo)(nil).ArchiveCompletedBefore), ctx, before)
+}
+
 // Create mocks base method.
 func (m *MockTodo) Create(ctx context.Context, todo model.Todo) (*model.Todo, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +146,184 @@ func (mr *MockTodoMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTodo)(nil).FindByID), ctx, id)
 }
 
+// FindByIDs mocks base method.
+func (m *MockTodo) FindByIDs(ctx context.Context, ids []string) ([]model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockTodoMockRecorder) FindByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockTodo)(nil).FindByIDs), ctx, ids)
+}
+
+// FindChanges mocks base method.
+func (m *MockTodo) FindChanges(ctx context.Context, since int64) (*model.TodoChanges, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChanges", ctx, since)
+	ret0, _ := ret[0].(*model.TodoChanges)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChanges indicates an expected call of FindChanges.
+func (mr *MockTodoMockRecorder) FindChanges(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChanges", reflect.TypeOf((*MockTodo)(nil).FindChanges), ctx, since)
+}
+
+// FindChildren mocks base method.
+func (m *MockTodo) FindChildren(ctx context.Context, parentID string) ([]model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildren", ctx, parentID)
+	ret0, _ := ret[0].([]model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChildren indicates an expected call of FindChildren.
+func (mr *MockTodoMockRecorder) FindChildren(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildren", reflect.TypeOf((*MockTodo)(nil).FindChildren), ctx, parentID)
+}
+
+// FindChildrenByParentIDs mocks base method.
+func (m *MockTodo) FindChildrenByParentIDs(ctx context.Context, parentIDs []string) ([]model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildrenByParentIDs", ctx, parentIDs)
+	ret0, _ := ret[0].([]model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChildrenByParentIDs indicates an expected call of FindChildrenByParentIDs.
+func (mr *MockTodoMockRecorder) FindChildrenByParentIDs(ctx, parentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildrenByParentIDs", reflect.TypeOf((*MockTodo)(nil).FindChildrenByParentIDs), ctx, parentIDs)
+}
+
+// FindTrash mocks base method.
+func (m *MockTodo) FindTrash(ctx context.Context) ([]model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTrash", ctx)
+	ret0, _ := ret[0].([]model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTrash indicates an expected call of FindTrash.
+func (mr *MockTodoMockRecorder) FindTrash(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTrash", reflect.TypeOf((*MockTodo)(nil).FindTrash), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockTodo) Purge(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTodoMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTodo)(nil).Purge), ctx, id)
+}
+
+// PurgeDeletedBefore mocks base method.
+func (m *MockTodo) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBefore", ctx, before)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedBefore indicates an expected call of PurgeDeletedBefore.
+func (mr *MockTodoMockRecorder) PurgeDeletedBefore(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBefore", reflect.TypeOf((*MockTodo)(nil).PurgeDeletedBefore), ctx, before)
+}
+
+// ReorderChildren mocks base method.
+func (m *MockTodo) ReorderChildren(ctx context.Context, parentID string, ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderChildren", ctx, parentID, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderChildren indicates an expected call of ReorderChildren.
+func (mr *MockTodoMockRecorder) ReorderChildren(ctx, parentID, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderChildren", reflect.TypeOf((*MockTodo)(nil).ReorderChildren), ctx, parentID, ids)
+}
+
+// Restore mocks base method.
+func (m *MockTodo) Restore(ctx context.Context, id string) (*model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTodoMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTodo)(nil).Restore), ctx, id)
+}
+
+// Stamp mocks base method.
+func (m *MockTodo) Stamp(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stamp", ctx, query)
+	ret0, _ := ret[0].(*model.TodoListStamp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stamp indicates an expected call of Stamp.
+func (mr *MockTodoMockRecorder) Stamp(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stamp", reflect.TypeOf((*MockTodo)(nil).Stamp), ctx, query)
+}
+
+// Touch mocks base method.
+func (m *MockTodo) Touch(ctx context.Context, befores []model.Todo) ([]model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, befores)
+	ret0, _ := ret[0].([]model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockTodoMockRecorder) Touch(ctx, befores any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockTodo)(nil).Touch), ctx, befores)
+}
+
+// Unarchive mocks base method.
+func (m *MockTodo) Unarchive(ctx context.Context, id string) (*model.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unarchive", ctx, id)
+	ret0, _ := ret[0].(*model.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unarchive indicates an expected call of Unarchive.
+func (mr *MockTodoMockRecorder) Unarchive(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unarchive", reflect.TypeOf((*MockTodo)(nil).Unarchive), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockTodo) Update(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo_dependency.go
+//
+// Generated by this command:
+//
+//	mockgen -source=todo_dependency.go -destination=../../mocks/repository/mock_todo_dependency.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTodoDependency is a mock of TodoDependency interface.
+type MockTodoDependency struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoDependencyMockRecorder
+	isgomock struct{}
+}
+
+// MockTodoDependencyMockRecorder is the mock recorder for MockTodoDependency.
+type MockTodoDependencyMockRecorder struct {
+	mock *MockTodoDependency
+}
+
+// NewMockTodoDependency creates a new mock instance.
+func NewMockTodoDependency(ctrl *gomock.Controller) *MockTodoDependency {
+	mock := &MockTodoDependency{ctrl: ctrl}
+	mock.recorder = &MockTodoDependencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoDependency) EXPECT() *MockTodoDependencyMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockTodoDependency) Add(ctx context.Context, todoID, blockerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, todoID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockTodoDependencyMockRecorder) Add(ctx, todoID, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockTodoDependency)(nil).Add), ctx, todoID, blockerID)
+}
+
+// FindAllBlockerIDs mocks base method.
+func (m *MockTodoDependency) FindAllBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllBlockerIDs", ctx, todoID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllBlockerIDs indicates an expected call of FindAllBlockerIDs.
+func (mr *MockTodoDependencyMockRecorder) FindAllBlockerIDs(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllBlockerIDs", reflect.TypeOf((*MockTodoDependency)(nil).FindAllBlockerIDs), ctx, todoID)
+}
+
+// FindBlockerIDs mocks base method.
+func (m *MockTodoDependency) FindBlockerIDs(ctx context.Context, todoID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlockerIDs", ctx, todoID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlockerIDs indicates an expected call of FindBlockerIDs.
+func (mr *MockTodoDependencyMockRecorder) FindBlockerIDs(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlockerIDs", reflect.TypeOf((*MockTodoDependency)(nil).FindBlockerIDs), ctx, todoID)
+}
+
+// Lock mocks base method.
+func (m *MockTodoDependency) Lock(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockTodoDependencyMockRecorder) Lock(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockTodoDependency)(nil).Lock), ctx)
+}
+
+// Remove mocks base method.
+func (m *MockTodoDependency) Remove(ctx context.Context, todoID, blockerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, todoID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockTodoDependencyMockRecorder) Remove(ctx, todoID, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTodoDependency)(nil).Remove), ctx, todoID, blockerID)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo_event_stream.go
+//
+// Generated by this command:
+//
+//	mockgen -source=todo_event_stream.go -destination=../../mocks/repository/mock_todo_event_stream.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/qushot/gin-todo-api/internal/domain/model"
+	repository "github.com/qushot/gin-todo-api/internal/domain/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTodoEventStream is a mock of TodoEventStream interface.
+type MockTodoEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoEventStreamMockRecorder
+	isgomock struct{}
+}
+
+// MockTodoEventStreamMockRecorder is the mock recorder for MockTodoEventStream.
+type MockTodoEventStreamMockRecorder struct {
+	mock *MockTodoEventStream
+}
+
+// NewMockTodoEventStream creates a new mock instance.
+func NewMockTodoEventStream(ctrl *gomock.Controller) *MockTodoEventStream {
+	mock := &MockTodoEventStream{ctrl: ctrl}
+	mock.recorder = &MockTodoEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoEventStream) EXPECT() *MockTodoEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockTodoEventStream) Publish(ctx context.Context, event model.TodoEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockTodoEventStreamMockRecorder) Publish(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockTodoEventStream)(nil).Publish), ctx, event)
+}
+
+// Reset mocks base method.
+func (m *MockTodoEventStream) Reset() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset")
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockTodoEventStreamMockRecorder) Reset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTodoEventStream)(nil).Reset))
+}
+
+// Subscribe mocks base method.
+func (m *MockTodoEventStream) Subscribe(ctx context.Context, lastEventID string) ([]model.TodoEvent, <-chan model.TodoEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, lastEventID)
+	ret0, _ := ret[0].([]model.TodoEvent)
+	ret1, _ := ret[1].(<-chan model.TodoEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockTodoEventStreamMockRecorder) Subscribe(ctx, lastEventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockTodoEventStream)(nil).Subscribe), ctx, lastEventID)
+}
+
+// MockTodoEventListener is a mock of TodoEventListener interface.
+type MockTodoEventListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoEventListenerMockRecorder
+	isgomock struct{}
+}
+
+// MockTodoEventListenerMockRecorder is the mock recorder for MockTodoEventListener.
+type MockTodoEventListenerMockRecorder struct {
+	mock *MockTodoEventListener
+}
+
+// NewMockTodoEventListener creates a new mock instance.
+func NewMockTodoEventListener(ctrl *gomock.Controller) *MockTodoEventListener {
+	mock := &MockTodoEventListener{ctrl: ctrl}
+	mock.recorder = &MockTodoEventListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoEventListener) EXPECT() *MockTodoEventListenerMockRecorder {
+	return m.recorder
+}
+
+// Listen mocks base method.
+func (m *MockTodoEventListener) Listen(ctx context.Context, stream repository.TodoEventStream) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Listen", ctx, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Listen indicates an expected call of Listen.
+func (mr *MockTodoEventListenerMockRecorder) Listen(ctx, stream any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Listen", reflect.TypeOf((*MockTodoEventListener)(nil).Listen), ctx, stream)
+}
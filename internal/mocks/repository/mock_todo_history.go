@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo_history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=todo_history.go -destination=../../mocks/repository/mock_todo_history.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/qushot/gin-todo-api/internal/domain/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTodoHistory is a mock of TodoHistory interface.
+type MockTodoHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoHistoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTodoHistoryMockRecorder is the mock recorder for MockTodoHistory.
+type MockTodoHistoryMockRecorder struct {
+	mock *MockTodoHistory
+}
+
+// NewMockTodoHistory creates a new mock instance.
+func NewMockTodoHistory(ctrl *gomock.Controller) *MockTodoHistory {
+	mock := &MockTodoHistory{ctrl: ctrl}
+	mock.recorder = &MockTodoHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoHistory) EXPECT() *MockTodoHistoryMockRecorder {
+	return m.recorder
+}
+
+// FindByTodoID mocks base method.
+func (m *MockTodoHistory) FindByTodoID(ctx context.Context, todoID string) ([]model.TodoHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTodoID", ctx, todoID)
+	ret0, _ := ret[0].([]model.TodoHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTodoID indicates an expected call of FindByTodoID.
+func (mr *MockTodoHistoryMockRecorder) FindByTodoID(ctx, todoID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTodoID", reflect.TypeOf((*MockTodoHistory)(nil).FindByTodoID), ctx, todoID)
+}
+
+// FindByTodoIDs mocks base method.
+func (m *MockTodoHistory) FindByTodoIDs(ctx context.Context, todoIDs []string) ([]model.TodoHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTodoIDs", ctx, todoIDs)
+	ret0, _ := ret[0].([]model.TodoHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTodoIDs indicates an expected call of FindByTodoIDs.
+func (mr *MockTodoHistoryMockRecorder) FindByTodoIDs(ctx, todoIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTodoIDs", reflect.TypeOf((*MockTodoHistory)(nil).FindByTodoIDs), ctx, todoIDs)
+}
+
+// FindByVersion mocks base method.
+func (m *MockTodoHistory) FindByVersion(ctx context.Context, todoID string, version int) (*model.TodoHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", ctx, todoID, version)
+	ret0, _ := ret[0].(*model.TodoHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion.
+func (mr *MockTodoHistoryMockRecorder) FindByVersion(ctx, todoID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockTodoHistory)(nil).FindByVersion), ctx, todoID, version)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: view.go
+//
+// Generated by this command:
+//
+//	mockgen -source=view.go -destination=../../mocks/repository/mock_view.go
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/qushot/gin-todo-api/internal/domain/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockView is a mock of View interface.
+type MockView struct {
+	ctrl     *gomock.Controller
+	recorder *MockViewMockRecorder
+	isgomock struct{}
+}
+
+// MockViewMockRecorder is the mock recorder for MockView.
+type MockViewMockRecorder struct {
+	mock *MockView
+}
+
+// NewMockView creates a new mock instance.
+func NewMockView(ctrl *gomock.Controller) *MockView {
+	mock := &MockView{ctrl: ctrl}
+	mock.recorder = &MockViewMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockView) EXPECT() *MockViewMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockView) Create(ctx context.Context, view model.View) (*model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, view)
+	ret0, _ := ret[0].(*model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockViewMockRecorder) Create(ctx, view any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockView)(nil).Create), ctx, view)
+}
+
+// Delete mocks base method.
+func (m *MockView) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockViewMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockView)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockView) FindAll(ctx context.Context) ([]model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockViewMockRecorder) FindAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockView)(nil).FindAll), ctx)
+}
+
+// FindByID mocks base method.
+func (m *MockView) FindByID(ctx context.Context, id string) (*model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockViewMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockView)(nil).FindByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockView) Update(ctx context.Context, id string, view model.View) (*model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, view)
+	ret0, _ := ret[0].(*model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockViewMockRecorder) Update(ctx, id, view any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockView)(nil).Update), ctx, id, view)
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// AddTodoDependency はTodoの依存関係を追加するユースケースを表すインターフェース
+type AddTodoDependency interface {
+	Execute(ctx context.Context, id string, blockerID string) (*model.Todo, error)
+}
+
+// addTodoDependency は usecase.AddTodoDependency の実装
+type addTodoDependency struct {
+	txManager      repository.TxManager
+	todoRepo       repository.Todo
+	dependencyRepo repository.TodoDependency
+	outboxRepo     repository.Outbox
+}
+
+// NewAddTodoDependency は usecase.AddTodoDependency のコンストラクタ
+func NewAddTodoDependency(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) AddTodoDependency {
+	return &addTodoDependency{
+		txManager:      txManager,
+		todoRepo:       todoRepo,
+		dependencyRepo: dependencyRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+// Execute は id のTodoが blockerID のTodoにブロックされる依存関係を追加し、更新後のTodoを返す
+// 循環の検証と追加は依存関係のロックを取得した1つのトランザクションで行い、Todoの更新として変更履歴とイベントを記録する
+func (uc *addTodoDependency) Execute(ctx context.Context, id string, blockerID string) (*model.Todo, error) {
+	if id == blockerID {
+		return nil, fmt.Errorf("%w: todo cannot be blocked by itself", model.ErrInvalidArgument)
+	}
+
+	var todo *model.Todo
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := uc.dependencyRepo.Lock(ctx); err != nil {
+			return err
+		}
+		current, err := uc.todoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := current.CheckWritable(); err != nil {
+			return err
+		}
+		if _, err := uc.todoRepo.FindByID(ctx, blockerID); errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: blocker todo %s does not exist", model.ErrInvalidArgument, blockerID)
+		} else if err != nil {
+			return err
+		}
+		// 既に存在する依存関係の場合は何も変更しない
+		if slices.Contains(current.BlockedBy, blockerID) {
+			todo = current
+			return nil
+		}
+		if err := checkNoDependencyCycle(ctx, uc.dependencyRepo, id, blockerID); err != nil {
+			return err
+		}
+
+		if err := uc.dependencyRepo.Add(ctx, id, blockerID); err != nil {
+			return err
+		}
+
+		todos, err := touchTodos(ctx, uc.todoRepo, uc.outboxRepo, []model.Todo{*current})
+		if err != nil {
+			return err
+		}
+		if len(todos) == 0 {
+			return model.ErrNotFound
+		}
+		todo = &todos[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_addTodoDependency_Execute(t *testing.T) {
+	// 既存の依存関係: 3 は 2 に、2 は 1 にブロックされている
+	// 6 はゴミ箱にある 5 に、5 は 1 にブロックされている
+	dependencies := map[string][]string{
+		"2": {"1"},
+		"3": {"2"},
+		"5": {"1"},
+		"6": {"5"},
+	}
+	trashed := map[string]bool{"5": true}
+
+	tests := []struct {
+		name      string
+		id        string
+		blockerID string
+		wantAdd   bool
+		wantErr   error
+	}{
+		{
+			name:      "new dependency",
+			id:        "3",
+			blockerID: "1",
+			wantAdd:   true,
+		},
+		{
+			name:      "existing dependency",
+			id:        "3",
+			blockerID: "2",
+		},
+		{
+			name:      "self dependency",
+			id:        "1",
+			blockerID: "1",
+			wantErr:   model.ErrInvalidArgument,
+		},
+		{
+			name:      "direct cycle",
+			id:        "1",
+			blockerID: "2",
+			wantErr:   model.ErrInvalidArgument,
+		},
+		{
+			name:      "transitive cycle",
+			id:        "1",
+			blockerID: "3",
+			wantErr:   model.ErrInvalidArgument,
+		},
+		{
+			name:      "cycle through trashed todo",
+			id:        "1",
+			blockerID: "6",
+			wantErr:   model.ErrInvalidArgument,
+		},
+		{
+			name:      "blocker does not exist",
+			id:        "1",
+			blockerID: "4",
+			wantErr:   model.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string) (*model.Todo, error) {
+					if id == "4" {
+						return nil, model.ErrNotFound
+					}
+					return &model.Todo{ID: id, BlockedBy: dependencies[id], Version: 1}, nil
+				}).AnyTimes()
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockDependencyRepo.EXPECT().
+				FindBlockerIDs(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string) ([]string, error) {
+					var ids []string
+					for _, blockerID := range dependencies[id] {
+						if !trashed[blockerID] {
+							ids = append(ids, blockerID)
+						}
+					}
+					return ids, nil
+				}).AnyTimes()
+			mockDependencyRepo.EXPECT().
+				FindAllBlockerIDs(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string) ([]string, error) {
+					return dependencies[id], nil
+				}).AnyTimes()
+			mockDependencyRepo.EXPECT().Lock(gomock.Any()).Return(nil).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			if tt.wantAdd {
+				mockDependencyRepo.EXPECT().Add(gomock.Any(), tt.id, tt.blockerID).Return(nil)
+				// 依存関係の追加はTodoの更新としてバージョンを上げ、更新のイベントを記録する
+				mockTodoRepo.EXPECT().
+					Touch(gomock.Any(), []model.Todo{{ID: tt.id, BlockedBy: dependencies[tt.id], Version: 1}}).
+					Return([]model.Todo{{ID: tt.id, BlockedBy: append(dependencies[tt.id], tt.blockerID), Version: 2}}, nil)
+				mockOutboxRepo.EXPECT().
+					Add(gomock.Any(), gomock.Len(1)).
+					DoAndReturn(func(ctx context.Context, events []model.TodoEvent) error {
+						if events[0].Type != model.TodoEventUpdated || events[0].Todo.Version != 2 {
+							t.Errorf("recorded event = %+v, want updated event of version 2", events[0])
+						}
+						return nil
+					})
+			}
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+
+			uc := usecase.NewAddTodoDependency(mockTxManager, mockTodoRepo, mockDependencyRepo, mockOutboxRepo)
+			got, gotErr := uc.Execute(context.Background(), tt.id, tt.blockerID)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if gotErr == nil && tt.wantAdd && got.Version != 2 {
+				t.Errorf("Execute() version = %d, want 2", got.Version)
+			}
+		})
+	}
+}
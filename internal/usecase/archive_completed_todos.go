@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ArchiveCompletedTodos は完了してから一定期間が経過したTodoをアーカイブするユースケースを表すインターフェース
+type ArchiveCompletedTodos interface {
+	Execute(ctx context.Context, now time.Time, after time.Duration) (int, error)
+}
+
+// archiveCompletedTodos は usecase.ArchiveCompletedTodos の実装
+type archiveCompletedTodos struct {
+	todoRepo repository.Todo
+}
+
+// NewArchiveCompletedTodos は usecase.ArchiveCompletedTodos のコンストラクタ
+func NewArchiveCompletedTodos(todoRepo repository.Todo) ArchiveCompletedTodos {
+	return &archiveCompletedTodos{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は now 時点で done になってから after より長く経過したTodoをアーカイブし、アーカイブした件数を返す
+func (uc *archiveCompletedTodos) Execute(ctx context.Context, now time.Time, after time.Duration) (int, error) {
+	if after <= 0 {
+		return 0, fmt.Errorf("%w: after must be positive", model.ErrInvalidArgument)
+	}
+
+	return uc.todoRepo.ArchiveCompletedBefore(ctx, now.Add(-after))
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ArchiveTodo はTodoをアーカイブするユースケースを表すインターフェース
+type ArchiveTodo interface {
+	Execute(ctx context.Context, id string) (*model.Todo, error)
+}
+
+// archiveTodo は usecase.ArchiveTodo の実装
+type archiveTodo struct {
+	txManager  repository.TxManager
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
+}
+
+// NewArchiveTodo は usecase.ArchiveTodo のコンストラクタ
+func NewArchiveTodo(txManager repository.TxManager, todoRepo repository.Todo, outboxRepo repository.Outbox) ArchiveTodo {
+	return &archiveTodo{
+		txManager:  txManager,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute はTodoをアーカイブする
+// アーカイブできるのは完了または中止のTodoのみで、アーカイブしたTodoはアーカイブを解除するまで更新できない
+func (uc *archiveTodo) Execute(ctx context.Context, id string) (*model.Todo, error) {
+	todo, err := uc.todoRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !todo.Status.IsClosed() {
+		return nil, fmt.Errorf("%w: only done or cancelled todos can be archived", model.ErrConflict)
+	}
+
+	return writeTodo(ctx, uc.txManager, uc.outboxRepo, model.TodoEventUpdated, func(ctx context.Context) (*model.Todo, error) {
+		return uc.todoRepo.Archive(ctx, id)
+	})
+}
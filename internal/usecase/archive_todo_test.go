@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_archiveTodo_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     model.Todo
+		wantArchive bool
+		wantErr     error
+	}{
+		{
+			name:        "done todo",
+			current:     model.Todo{ID: "1", Status: model.TodoStatusDone},
+			wantArchive: true,
+		},
+		{
+			name:        "cancelled todo",
+			current:     model.Todo{ID: "1", Status: model.TodoStatusCancelled},
+			wantArchive: true,
+		},
+		{
+			name:    "open todo",
+			current: model.Todo{ID: "1", Status: model.TodoStatusInProgress},
+			wantErr: model.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), tt.current.ID).
+				Return(&tt.current, nil)
+			if tt.wantArchive {
+				mockTodoRepo.EXPECT().
+					Archive(gomock.Any(), tt.current.ID).
+					Return(&tt.current, nil)
+			}
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			uc := usecase.NewArchiveTodo(mockTxManager, mockTodoRepo, mockOutboxRepo)
+			_, gotErr := uc.Execute(context.Background(), tt.current.ID)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// BatchTodos はTodoの作成、更新、完了、ゴミ箱への移動をまとめて行うユースケースを表すインターフェース
+type BatchTodos interface {
+	Execute(ctx context.Context, batch model.TodoBatch) ([]model.TodoBatchResult, error)
+}
+
+// batchTodos は usecase.BatchTodos の実装
+type batchTodos struct {
+	todoUpdater
+}
+
+// NewBatchTodos は usecase.BatchTodos のコンストラクタ
+func NewBatchTodos(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) BatchTodos {
+	return &batchTodos{
+		todoUpdater: todoUpdater{
+			txManager:      txManager,
+			todoRepo:       todoRepo,
+			dependencyRepo: dependencyRepo,
+			outboxRepo:     outboxRepo,
+		},
+	}
+}
+
+// batchWrite は検証済みの操作と、更新に続く処理に必要な情報
+type batchWrite struct {
+	index   int
+	write   model.TodoWrite
+	current *model.Todo
+	scope   model.RecurrenceScope
+	// cascade は削除で子孫のTodoも一緒にゴミ箱に移動するかどうか
+	cascade bool
+}
+
+// Execute は一括操作を実行し、操作ごとの結果を返す
+// 各操作は一括操作を実行する前の状態に対して検証する
+// atomic の場合は1つでも失敗した操作があれば全て適用せず、最初に失敗した操作のエラーを結果と合わせて返す
+// best_effort の場合は成功した操作のみを適用し、失敗した操作は結果のエラーとして報告する
+func (uc *batchTodos) Execute(ctx context.Context, batch model.TodoBatch) ([]model.TodoBatchResult, error) {
+	if err := batch.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]model.TodoBatchResult, len(batch.Operations))
+	deletedIDs := make(map[string]bool)
+	for _, op := range batch.Operations {
+		if op.Op == model.BatchOperationDelete {
+			deletedIDs[op.ID] = true
+		}
+	}
+	seenIDs := make(map[string]bool)
+	var writes []batchWrite
+	for i, op := range batch.Operations {
+		results[i] = model.TodoBatchResult{Index: i, Op: op.Op, ID: op.ID}
+		w, err := uc.prepareOperation(ctx, op, seenIDs, deletedIDs)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		w.index = i
+		writes = append(writes, w)
+	}
+
+	if batch.Mode == model.BatchModeAtomic {
+		if len(writes) < len(batch.Operations) {
+			return results, skipUnfailed(results)
+		}
+		if err := uc.apply(ctx, results, writes); err != nil {
+			for i := range results {
+				results[i].Err = err
+			}
+			return results, fmt.Errorf("operations could not be applied: %w", err)
+		}
+		return results, nil
+	}
+
+	if len(writes) > 0 && uc.apply(ctx, results, writes) != nil {
+		// まとめて適用できなかった場合は、適用できる操作を特定するために1件ずつ適用する
+		for _, w := range writes {
+			if err := uc.apply(ctx, results, []batchWrite{w}); err != nil {
+				results[w.index].Err = err
+			}
+		}
+	}
+	return results, nil
+}
+
+// prepareOperation は操作を検証し、リポジトリに渡す書き込みに変換する
+// 同じTodoを対象とする操作が複数ある場合と、同じ一括操作でゴミ箱に移動するTodoを親とする場合はエラーとする
+func (uc *batchTodos) prepareOperation(ctx context.Context, op model.TodoBatchOperation, seenIDs, deletedIDs map[string]bool) (batchWrite, error) {
+	if op.Op == model.BatchOperationCreate {
+		if op.Todo == nil {
+			return batchWrite{}, fmt.Errorf("%w: todo is required for %s", model.ErrInvalidArgument, op.Op)
+		}
+		if op.Todo.ParentID != nil && deletedIDs[*op.Todo.ParentID] {
+			return batchWrite{}, fmt.Errorf("%w: parent todo is deleted in the same batch", model.ErrConflict)
+		}
+		todo, err := prepareNewTodo(ctx, uc.todoRepo, *op.Todo)
+		if err != nil {
+			return batchWrite{}, err
+		}
+		return batchWrite{write: model.TodoWrite{Kind: model.TodoWriteCreate, Todo: todo}}, nil
+	}
+
+	switch op.Op {
+	case model.BatchOperationUpdate, model.BatchOperationComplete, model.BatchOperationDelete:
+	default:
+		return batchWrite{}, fmt.Errorf("%w: unknown op %q", model.ErrInvalidArgument, op.Op)
+	}
+	if op.ID == "" {
+		return batchWrite{}, fmt.Errorf("%w: id is required for %s", model.ErrInvalidArgument, op.Op)
+	}
+	if seenIDs[op.ID] {
+		return batchWrite{}, fmt.Errorf("%w: todo %s is targeted by more than one operation", model.ErrInvalidArgument, op.ID)
+	}
+	seenIDs[op.ID] = true
+
+	current, err := uc.todoRepo.FindByID(ctx, op.ID)
+	if err != nil {
+		return batchWrite{}, err
+	}
+
+	var todo model.Todo
+	switch op.Op {
+	case model.BatchOperationUpdate:
+		if op.Patch == nil {
+			return batchWrite{}, fmt.Errorf("%w: patch is required for %s", model.ErrInvalidArgument, op.Op)
+		}
+		todo = op.Patch.Apply(*current)
+	case model.BatchOperationComplete:
+		todo = *current
+		todo.Status = model.TodoStatusDone
+		todo.Done = true
+	case model.BatchOperationDelete:
+		if !op.Cascade {
+			if err := checkNoChildren(ctx, uc.todoRepo, op.ID); err != nil {
+				return batchWrite{}, err
+			}
+		}
+		return batchWrite{write: model.TodoWrite{Kind: model.TodoWriteDelete, ID: op.ID}, current: current, cascade: op.Cascade}, nil
+	}
+
+	todo, scope, err := uc.prepare(ctx, current, todo, "")
+	if err != nil {
+		return batchWrite{}, err
+	}
+	if todo.ParentID != nil && deletedIDs[*todo.ParentID] {
+		return batchWrite{}, fmt.Errorf("%w: parent todo is deleted in the same batch", model.ErrConflict)
+	}
+	return batchWrite{
+		write:   model.TodoWrite{Kind: model.TodoWriteUpdate, ID: op.ID, Todo: todo},
+		current: current,
+		scope:   scope,
+	}, nil
+}
+
+// apply は writes をまとめて適用して結果に反映し、更新に続く処理を行う
+// 書き込みとイベントの記録は1つのトランザクションで行う
+// 更新に続く処理に失敗した場合は、その操作の結果のエラーとする (更新自体は適用済み)
+func (uc *batchTodos) apply(ctx context.Context, results []model.TodoBatchResult, writes []batchWrite) error {
+	todoWrites := make([]model.TodoWrite, len(writes))
+	for i, w := range writes {
+		todoWrites[i] = w.write
+	}
+
+	return uc.txManager.Do(ctx, func(ctx context.Context) error {
+		// 一緒にゴミ箱に移動する子孫のTodoのイベントも記録するため、適用前に子孫を取得する
+		descendants := map[int][]model.Todo{}
+		for i, w := range writes {
+			if w.write.Kind != model.TodoWriteDelete || !w.cascade {
+				continue
+			}
+			found, err := findDescendants(ctx, uc.todoRepo, w.write.ID)
+			if err != nil {
+				return err
+			}
+			descendants[i] = found
+		}
+
+		todos, err := uc.todoRepo.ApplyBatch(ctx, todoWrites)
+		if err != nil {
+			return err
+		}
+
+		var events []model.TodoEvent
+		deleted := map[string]bool{}
+		for i, w := range writes {
+			switch w.write.Kind {
+			case model.TodoWriteCreate:
+				events = append(events, model.NewTodoEvent(ctx, model.TodoEventCreated, todos[i]))
+			case model.TodoWriteUpdate:
+				events = append(events, model.TodoUpdateEvents(ctx, w.current, todos[i])...)
+			case model.TodoWriteDelete:
+				events = append(events, model.NewTodoEvent(ctx, model.TodoEventDeleted, w.current))
+				deleted[w.write.ID] = true
+			}
+		}
+		for i := range writes {
+			for j := range descendants[i] {
+				// 子孫が同じ一括操作で削除の対象にもなっている場合は、イベントを重複して記録しない
+				if descendant := &descendants[i][j]; !deleted[descendant.ID] {
+					events = append(events, model.NewTodoEvent(ctx, model.TodoEventDeleted, descendant))
+					deleted[descendant.ID] = true
+				}
+			}
+		}
+		if err := recordEvents(ctx, uc.outboxRepo, events...); err != nil {
+			return err
+		}
+
+		for i, w := range writes {
+			result := &results[w.index]
+			result.Todo = todos[i]
+			if w.write.Kind == model.TodoWriteCreate {
+				result.ID = todos[i].ID
+			}
+			if w.write.Kind == model.TodoWriteUpdate {
+				result.Err = uc.afterUpdate(ctx, w.current, todos[i], w.scope)
+			}
+		}
+		return nil
+	})
+}
+
+// skipUnfailed は失敗していない操作を適用しなかったものとし、最初に失敗した操作のエラーを返す
+func skipUnfailed(results []model.TodoBatchResult) error {
+	var firstErr error
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Skipped = true
+		} else if firstErr == nil {
+			firstErr = fmt.Errorf("operation %d failed: %w", results[i].Index, results[i].Err)
+		}
+	}
+	return firstErr
+}
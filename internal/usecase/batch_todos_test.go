@@ -0,0 +1,206 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_batchTodos_Execute(t *testing.T) {
+	existing := map[string]model.Todo{
+		"1": {ID: "1", Title: "First", Status: model.TodoStatusTodo, Timezone: model.DefaultTimezone, Tags: []string{}},
+		"2": {ID: "2", Title: "Second", Status: model.TodoStatusInProgress, Timezone: model.DefaultTimezone, Tags: []string{}},
+	}
+	title := "Renamed"
+	errApply := errors.New("apply failed")
+
+	type result struct {
+		Op      model.BatchOperationType
+		Err     error
+		Skipped bool
+	}
+	tests := []struct {
+		name       string
+		batch      model.TodoBatch
+		applyErr   error
+		wantWrites [][]model.TodoWriteKind
+		want       []result
+		wantErr    error
+	}{
+		{
+			name: "atomic applies all operations at once",
+			batch: model.TodoBatch{Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationCreate, Todo: &model.Todo{Title: "New"}},
+				{Op: model.BatchOperationUpdate, ID: "1", Patch: &model.TodoPatch{Title: &title}},
+				{Op: model.BatchOperationComplete, ID: "2"},
+			}},
+			wantWrites: [][]model.TodoWriteKind{{model.TodoWriteCreate, model.TodoWriteUpdate, model.TodoWriteUpdate}},
+			want: []result{
+				{Op: model.BatchOperationCreate},
+				{Op: model.BatchOperationUpdate},
+				{Op: model.BatchOperationComplete},
+			},
+		},
+		{
+			name: "atomic skips everything when an operation is invalid",
+			batch: model.TodoBatch{Mode: model.BatchModeAtomic, Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationDelete, ID: "1"},
+				{Op: model.BatchOperationComplete, ID: "404"},
+			}},
+			want: []result{
+				{Op: model.BatchOperationDelete, Skipped: true},
+				{Op: model.BatchOperationComplete, Err: model.ErrNotFound},
+			},
+			wantErr: model.ErrNotFound,
+		},
+		{
+			name: "atomic reports a repository failure on every operation",
+			batch: model.TodoBatch{Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationDelete, ID: "1"},
+			}},
+			applyErr:   errApply,
+			wantWrites: [][]model.TodoWriteKind{{model.TodoWriteDelete}},
+			want:       []result{{Op: model.BatchOperationDelete, Err: errApply}},
+			wantErr:    errApply,
+		},
+		{
+			name: "best effort applies only valid operations",
+			batch: model.TodoBatch{Mode: model.BatchModeBestEffort, Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationDelete, ID: "1"},
+				{Op: model.BatchOperationDelete, ID: "1"},
+				{Op: model.BatchOperationUpdate, ID: "2"},
+				{Op: model.BatchOperationCreate, Todo: &model.Todo{Title: "Invalid", Priority: model.MaxPriority + 1}},
+				{Op: "archive", ID: "2"},
+			}},
+			wantWrites: [][]model.TodoWriteKind{{model.TodoWriteDelete}},
+			want: []result{
+				{Op: model.BatchOperationDelete},
+				{Op: model.BatchOperationDelete, Err: model.ErrInvalidArgument},
+				{Op: model.BatchOperationUpdate, Err: model.ErrInvalidArgument},
+				{Op: model.BatchOperationCreate, Err: model.ErrInvalidArgument},
+				{Op: "archive", Err: model.ErrInvalidArgument},
+			},
+		},
+		{
+			name: "best effort falls back to one write at a time",
+			batch: model.TodoBatch{Mode: model.BatchModeBestEffort, Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationDelete, ID: "1"},
+				{Op: model.BatchOperationDelete, ID: "2"},
+			}},
+			applyErr: errApply,
+			wantWrites: [][]model.TodoWriteKind{
+				{model.TodoWriteDelete, model.TodoWriteDelete},
+				{model.TodoWriteDelete},
+				{model.TodoWriteDelete},
+			},
+			want: []result{
+				{Op: model.BatchOperationDelete, Err: errApply},
+				{Op: model.BatchOperationDelete, Err: errApply},
+			},
+		},
+		{
+			name: "child of a todo deleted in the same batch is rejected",
+			batch: model.TodoBatch{Mode: model.BatchModeBestEffort, Operations: []model.TodoBatchOperation{
+				{Op: model.BatchOperationDelete, ID: "1"},
+				{Op: model.BatchOperationCreate, Todo: &model.Todo{Title: "Child", ParentID: func() *string { s := "1"; return &s }()}},
+			}},
+			wantWrites: [][]model.TodoWriteKind{{model.TodoWriteDelete}},
+			want: []result{
+				{Op: model.BatchOperationDelete},
+				{Op: model.BatchOperationCreate, Err: model.ErrConflict},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, id string) (*model.Todo, error) {
+					todo, ok := existing[id]
+					if !ok {
+						return nil, model.ErrNotFound
+					}
+					return &todo, nil
+				}).AnyTimes()
+			mockTodoRepo.EXPECT().
+				FindChildren(gomock.Any(), gomock.Any()).
+				Return([]model.Todo{}, nil).AnyTimes()
+			var gotWrites [][]model.TodoWriteKind
+			mockTodoRepo.EXPECT().
+				ApplyBatch(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, writes []model.TodoWrite) ([]*model.Todo, error) {
+					kinds := make([]model.TodoWriteKind, len(writes))
+					todos := make([]*model.Todo, len(writes))
+					for i, w := range writes {
+						kinds[i] = w.Kind
+						if w.Kind != model.TodoWriteDelete {
+							todo := w.Todo
+							todo.ID = "created"
+							if w.ID != "" {
+								todo.ID = w.ID
+							}
+							todos[i] = &todo
+						}
+					}
+					gotWrites = append(gotWrites, kinds)
+					if tt.applyErr != nil {
+						return nil, tt.applyErr
+					}
+					return todos, nil
+				}).AnyTimes()
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockDependencyRepo.EXPECT().
+				FindBlockerIDs(gomock.Any(), gomock.Any()).
+				Return([]string{}, nil).AnyTimes()
+			mockDependencyRepo.EXPECT().
+				FindAllBlockerIDs(gomock.Any(), gomock.Any()).
+				Return([]string{}, nil).AnyTimes()
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			uc := usecase.NewBatchTodos(mockTxManager, mockTodoRepo, mockDependencyRepo, mockOutboxRepo)
+			results, gotErr := uc.Execute(context.Background(), tt.batch)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+
+			got := make([]result, len(results))
+			for i, r := range results {
+				got[i] = result{Op: r.Op, Err: r.Err, Skipped: r.Skipped}
+				if r.Err == nil && !r.Skipped && r.Op != model.BatchOperationDelete && r.Todo == nil {
+					t.Errorf("results[%d].Todo is nil", i)
+				}
+			}
+			opt := cmp.Comparer(func(x, y error) bool {
+				return errors.Is(x, y) || errors.Is(y, x)
+			})
+			if diff := cmp.Diff(tt.want, got, opt); diff != "" {
+				t.Errorf("Execute() results mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantWrites, gotWrites); diff != "" {
+				t.Errorf("ApplyBatch() writes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -14,17 +14,46 @@ type CreateTodo interface {
 
 // createTodo は usecase.CreateTodo の実装
 type createTodo struct {
-	todoRepo repository.Todo
+	txManager  repository.TxManager
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
 }
 
 // NewCreateTodo は usecase.CreateTodo のコンストラクタ
-func NewCreateTodo(todoRepo repository.Todo) CreateTodo {
+func NewCreateTodo(txManager repository.TxManager, todoRepo repository.Todo, outboxRepo repository.Outbox) CreateTodo {
 	return &createTodo{
-		todoRepo: todoRepo,
+		txManager:  txManager,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
 	}
 }
 
 // Execute は新しいTodoを作成する
 func (uc *createTodo) Execute(ctx context.Context, todo model.Todo) (*model.Todo, error) {
-	return uc.todoRepo.Create(ctx, todo)
+	todo, err := prepareNewTodo(ctx, uc.todoRepo, todo)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeTodo(ctx, uc.txManager, uc.outboxRepo, model.TodoEventCreated, func(ctx context.Context) (*model.Todo, error) {
+		return uc.todoRepo.Create(ctx, todo)
+	})
+}
+
+// prepareNewTodo は todo を作成できるかを検証し、保存する内容を返す
+func prepareNewTodo(ctx context.Context, todoRepo repository.Todo, todo model.Todo) (model.Todo, error) {
+	todo.Status = resolveStatus("", todo)
+	todo.Done = todo.Status == model.TodoStatusDone
+	if todo.Tags == nil {
+		todo.Tags = []string{}
+	}
+	todo.ParentID = resolveParentID(nil, todo.ParentID)
+	if err := todo.Validate(); err != nil {
+		return todo, err
+	}
+	startRecurrence(&todo)
+	if err := checkParent(ctx, todoRepo, "", todo.ParentID); err != nil {
+		return todo, err
+	}
+	return todo, nil
 }
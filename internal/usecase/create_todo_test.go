@@ -23,6 +23,16 @@ func Test_createTodo_Execute(t *testing.T) {
 		DoAndReturn(func(ctx context.Context, todo model.Todo) (*model.Todo, error) {
 			return &todo, nil
 		}).AnyTimes()
+	mockTxManager := mock_repository.NewMockTxManager(ctrl)
+	mockTxManager.EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+	mockOutboxRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
 
 	tests := []struct {
 		name    string
@@ -33,13 +43,46 @@ func Test_createTodo_Execute(t *testing.T) {
 		{
 			name:    "success",
 			todo:    model.Todo{ID: "1", Title: "Test Todo", Done: false},
-			want:    &model.Todo{ID: "1", Title: "Test Todo", Done: false},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo, Tags: []string{}, Timezone: model.DefaultTimezone, Done: false},
+			wantErr: false,
+		},
+		{
+			name:    "status is derived from done",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Done: true},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusDone, Tags: []string{}, Timezone: model.DefaultTimezone, Done: true},
+			wantErr: false,
+		},
+		{
+			name:    "done is derived from status",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress, Priority: 2, Done: true},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress, Priority: 2, Tags: []string{}, Timezone: model.DefaultTimezone, Done: false},
+			wantErr: false,
+		},
+		{
+			name:    "tags are normalized",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Tags: []string{" work ", "home", "work"}},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo, Tags: []string{"home", "work"}, Timezone: model.DefaultTimezone},
 			wantErr: false,
 		},
+		{
+			name:    "empty tag",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Tags: []string{" "}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown status",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Status: "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "priority out of range",
+			todo:    model.Todo{ID: "1", Title: "Test Todo", Priority: model.MaxPriority + 1},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			uc := usecase.NewCreateTodo(mockTodoRepo)
+			uc := usecase.NewCreateTodo(mockTxManager, mockTodoRepo, mockOutboxRepo)
 			got, gotErr := uc.Execute(context.Background(), tt.todo)
 			if gotErr != nil {
 				if !tt.wantErr {
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// CreateView はビューを作成するユースケースを表すインターフェース
+type CreateView interface {
+	Execute(ctx context.Context, view model.View) (*model.View, error)
+}
+
+// createView は usecase.CreateView の実装
+type createView struct {
+	viewRepo repository.View
+}
+
+// NewCreateView は usecase.CreateView のコンストラクタ
+func NewCreateView(viewRepo repository.View) CreateView {
+	return &createView{
+		viewRepo: viewRepo,
+	}
+}
+
+// Execute は検索クエリを検証してビューを作成する
+// 同じ名前のビューが既に存在する場合は model.ErrConflict を返す
+func (uc *createView) Execute(ctx context.Context, view model.View) (*model.View, error) {
+	if err := view.Validate(); err != nil {
+		return nil, err
+	}
+
+	return uc.viewRepo.Create(ctx, view)
+}
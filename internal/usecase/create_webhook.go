@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// CreateWebhook はWebhookを作成するユースケースを表すインターフェース
+type CreateWebhook interface {
+	Execute(ctx context.Context, webhook model.Webhook) (*model.Webhook, error)
+}
+
+// createWebhook は usecase.CreateWebhook の実装
+type createWebhook struct {
+	webhookRepo repository.Webhook
+}
+
+// NewCreateWebhook は usecase.CreateWebhook のコンストラクタ
+func NewCreateWebhook(webhookRepo repository.Webhook) CreateWebhook {
+	return &createWebhook{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Execute はWebhookを検証して作成し、秘密鍵を含めて返す
+// 秘密鍵を指定しない場合はランダムに生成する
+func (uc *createWebhook) Execute(ctx context.Context, webhook model.Webhook) (*model.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = model.NewWebhookSecret()
+	}
+
+	return uc.webhookRepo.Create(ctx, webhook)
+}
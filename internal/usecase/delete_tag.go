@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DeleteTag はタグを削除するユースケースを表すインターフェース
+type DeleteTag interface {
+	Execute(ctx context.Context, id string) error
+}
+
+// deleteTag は usecase.DeleteTag の実装
+type deleteTag struct {
+	txManager  repository.TxManager
+	tagRepo    repository.Tag
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
+}
+
+// NewDeleteTag は usecase.DeleteTag のコンストラクタ
+func NewDeleteTag(txManager repository.TxManager, tagRepo repository.Tag, todoRepo repository.Todo, outboxRepo repository.Outbox) DeleteTag {
+	return &deleteTag{
+		txManager:  txManager,
+		tagRepo:    tagRepo,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute はタグを削除する (付与されていたTodoからも外れ、それらのTodoは同じトランザクションで更新として記録する)
+func (uc *deleteTag) Execute(ctx context.Context, id string) error {
+	return changeTag(ctx, uc.txManager, uc.tagRepo, uc.todoRepo, uc.outboxRepo, id, func(ctx context.Context) error {
+		return uc.tagRepo.Delete(ctx, id)
+	})
+}
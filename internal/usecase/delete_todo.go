@@ -2,28 +2,96 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/qushot/gin-todo-api/internal/domain/model"
 	"github.com/qushot/gin-todo-api/internal/domain/repository"
 )
 
 // DeleteTodo はTodoを削除するユースケースを表すインターフェース
 type DeleteTodo interface {
-	Execute(ctx context.Context, id string) error
+	Execute(ctx context.Context, id string, cascade bool) error
 }
 
 // deleteTodo は usecase.DeleteTodo の実装
 type deleteTodo struct {
-	todoRepo repository.Todo
+	txManager  repository.TxManager
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
 }
 
 // NewDeleteTodo は usecase.DeleteTodo のコンストラクタ
-func NewDeleteTodo(todoRepo repository.Todo) DeleteTodo {
+func NewDeleteTodo(txManager repository.TxManager, todoRepo repository.Todo, outboxRepo repository.Outbox) DeleteTodo {
 	return &deleteTodo{
-		todoRepo: todoRepo,
+		txManager:  txManager,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
 	}
 }
 
-// Execute はTodoを削除する
-func (uc *deleteTodo) Execute(ctx context.Context, id string) error {
-	return uc.todoRepo.Delete(ctx, id)
+// Execute はTodoをゴミ箱に移動する
+// 子のTodoが存在する場合は cascade が true の場合のみ子孫も含めてゴミ箱に移動する
+// イベントは id のTodoと、一緒にゴミ箱に移動した子孫のTodoのそれぞれについて記録する
+func (uc *deleteTodo) Execute(ctx context.Context, id string, cascade bool) error {
+	return trashTodo(ctx, uc.txManager, uc.todoRepo, uc.outboxRepo, id, cascade)
+}
+
+// trashTodo はTodoをゴミ箱に移動し、イベントを記録する (context に期待するバージョンがあれば検証する)
+// 子のTodoの検証は、検証と移動の間に子が追加されないようトランザクション内で行う
+func trashTodo(ctx context.Context, txManager repository.TxManager, todoRepo repository.Todo, outboxRepo repository.Outbox, id string, cascade bool) error {
+	return txManager.Do(ctx, func(ctx context.Context) error {
+		todo, err := todoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := model.CheckExpectedVersion(ctx, todo); err != nil {
+			return err
+		}
+		var descendants []model.Todo
+		if cascade {
+			if descendants, err = findDescendants(ctx, todoRepo, id); err != nil {
+				return err
+			}
+		} else if err := checkNoChildren(ctx, todoRepo, id); err != nil {
+			return err
+		}
+		if err := todoRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		events := []model.TodoEvent{model.NewTodoEvent(ctx, model.TodoEventDeleted, todo)}
+		for i := range descendants {
+			events = append(events, model.NewTodoEvent(ctx, model.TodoEventDeleted, &descendants[i]))
+		}
+		return recordEvents(ctx, outboxRepo, events...)
+	})
+}
+
+// findDescendants は id のTodoの子孫のTodoを階層の浅い順に取得する
+func findDescendants(ctx context.Context, todoRepo repository.Todo, id string) ([]model.Todo, error) {
+	var descendants []model.Todo
+	for parentIDs := []string{id}; len(parentIDs) > 0; {
+		children, err := todoRepo.FindChildrenByParentIDs(ctx, parentIDs)
+		if err != nil {
+			return nil, err
+		}
+		parentIDs = make([]string, 0, len(children))
+		for _, child := range children {
+			parentIDs = append(parentIDs, child.ID)
+		}
+		descendants = append(descendants, children...)
+	}
+	return descendants, nil
+}
+
+// checkNoChildren はTodoに子のTodoが存在する場合に model.ErrConflict を返す
+func checkNoChildren(ctx context.Context, todoRepo repository.Todo, id string) error {
+	children, err := todoRepo.FindChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("%w: todo has %d children, specify cascade=true to delete them", model.ErrConflict, len(children))
+	}
+	return nil
 }
@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_deleteTodo_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		children   map[string][]model.Todo
+		cascade    bool
+		wantDelete bool
+		wantEvents []string
+		wantErr    error
+	}{
+		{
+			name:       "no children",
+			children:   map[string][]model.Todo{},
+			wantDelete: true,
+			wantEvents: []string{"1"},
+		},
+		{
+			name:     "children without cascade",
+			children: map[string][]model.Todo{"1": {{ID: "2"}}},
+			wantErr:  model.ErrConflict,
+		},
+		{
+			// 一緒にゴミ箱に移動した子孫のTodoのイベントも記録する
+			name:       "children with cascade",
+			children:   map[string][]model.Todo{"1": {{ID: "2"}, {ID: "3"}}, "2": {{ID: "4"}}},
+			cascade:    true,
+			wantDelete: true,
+			wantEvents: []string{"1", "2", "3", "4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// 子のTodoの検証は、検証と移動の間に子が追加されないようトランザクション内で行う
+			inTx := false
+			findChildren := func(parentIDs ...string) []model.Todo {
+				if !inTx {
+					t.Errorf("children of %v are looked up outside the transaction", parentIDs)
+				}
+				var children []model.Todo
+				for _, id := range parentIDs {
+					children = append(children, tt.children[id]...)
+				}
+				return children
+			}
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindChildren(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, parentID string) ([]model.Todo, error) {
+					return findChildren(parentID), nil
+				}).AnyTimes()
+			mockTodoRepo.EXPECT().
+				FindChildrenByParentIDs(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, parentIDs []string) ([]model.Todo, error) {
+					return findChildren(parentIDs...), nil
+				}).AnyTimes()
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), "1").
+				Return(&model.Todo{ID: "1"}, nil).AnyTimes()
+			if tt.wantDelete {
+				mockTodoRepo.EXPECT().Delete(gomock.Any(), "1").Return(nil)
+			}
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					inTx = true
+					defer func() { inTx = false }()
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			if tt.wantDelete {
+				mockOutboxRepo.EXPECT().
+					Add(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, events []model.TodoEvent) error {
+						var gotIDs []string
+						for _, event := range events {
+							if event.Type != model.TodoEventDeleted {
+								t.Errorf("Add() event type = %s, want %s", event.Type, model.TodoEventDeleted)
+							}
+							gotIDs = append(gotIDs, event.TodoID)
+						}
+						if diff := cmp.Diff(tt.wantEvents, gotIDs); diff != "" {
+							t.Errorf("Add() event todo IDs (-want +got):\n%s", diff)
+						}
+						return nil
+					})
+			}
+
+			uc := usecase.NewDeleteTodo(mockTxManager, mockTodoRepo, mockOutboxRepo)
+			gotErr := uc.Execute(context.Background(), "1", tt.cascade)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DeleteView はビューを削除するユースケースを表すインターフェース
+type DeleteView interface {
+	Execute(ctx context.Context, id string) error
+}
+
+// deleteView は usecase.DeleteView の実装
+type deleteView struct {
+	viewRepo repository.View
+}
+
+// NewDeleteView は usecase.DeleteView のコンストラクタ
+func NewDeleteView(viewRepo repository.View) DeleteView {
+	return &deleteView{
+		viewRepo: viewRepo,
+	}
+}
+
+// Execute はビューを削除する (ビューに一致するTodoは削除しない)
+func (uc *deleteView) Execute(ctx context.Context, id string) error {
+	return uc.viewRepo.Delete(ctx, id)
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// DeleteWebhook はWebhookを削除するユースケースを表すインターフェース
+type DeleteWebhook interface {
+	Execute(ctx context.Context, id string) error
+}
+
+// deleteWebhook は usecase.DeleteWebhook の実装
+type deleteWebhook struct {
+	webhookRepo repository.Webhook
+}
+
+// NewDeleteWebhook は usecase.DeleteWebhook のコンストラクタ
+func NewDeleteWebhook(webhookRepo repository.Webhook) DeleteWebhook {
+	return &deleteWebhook{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Execute はWebhookとその配信 (配信待ちのものを含む) を削除する
+func (uc *deleteWebhook) Execute(ctx context.Context, id string) error {
+	return uc.webhookRepo.Delete(ctx, id)
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookDeliveryBatchSize は1回の実行で送信する配信の最大件数
+const webhookDeliveryBatchSize = 50
+
+// DeliverWebhooks は配信待ちのWebhookの配信を送信するユースケースを表すインターフェース
+type DeliverWebhooks interface {
+	Execute(ctx context.Context, now time.Time) (WebhookDeliveryResult, error)
+}
+
+// WebhookDeliveryResult はWebhookの配信を送信した結果の件数を表す
+type WebhookDeliveryResult struct {
+	// Succeeded は送信に成功した件数
+	Succeeded int
+	// Retrying は送信に失敗し、再試行を予約した件数
+	Retrying int
+	// Dead は送信に失敗し、再試行の回数の上限に達した件数
+	Dead int
+}
+
+// deliverWebhooks は usecase.DeliverWebhooks の実装
+type deliverWebhooks struct {
+	webhookRepo         repository.Webhook
+	webhookDeliveryRepo repository.WebhookDelivery
+	sender              repository.WebhookSender
+}
+
+// NewDeliverWebhooks は usecase.DeliverWebhooks のコンストラクタ
+func NewDeliverWebhooks(webhookRepo repository.Webhook, webhookDeliveryRepo repository.WebhookDelivery, sender repository.WebhookSender) DeliverWebhooks {
+	return &deliverWebhooks{
+		webhookRepo:         webhookRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+		sender:              sender,
+	}
+}
+
+// Execute は now までに配信を試みる日時になった配信を送信し、その結果の件数を返す
+// 送信に失敗した配信は、失敗した回数に応じて間隔を空けて再試行し、上限に達した場合は dead にする
+func (uc *deliverWebhooks) Execute(ctx context.Context, now time.Time) (WebhookDeliveryResult, error) {
+	var result WebhookDeliveryResult
+	deliveries, err := uc.webhookDeliveryRepo.FindDue(ctx, now, webhookDeliveryBatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	webhooks := make(map[string]*model.Webhook)
+	for _, d := range deliveries {
+		webhook, ok := webhooks[d.WebhookID]
+		if !ok {
+			webhook, err = uc.webhookRepo.FindByID(ctx, d.WebhookID)
+			// 取得した後に削除されたWebhookの配信は、Webhookと共に削除されている
+			if errors.Is(err, model.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+			webhooks[d.WebhookID] = webhook
+		}
+
+		delivered, err := deliverWebhook(ctx, uc.sender, uc.webhookDeliveryRepo, *webhook, d, now)
+		if err != nil {
+			return result, err
+		}
+		switch delivered.Status {
+		case model.WebhookDeliverySucceeded:
+			result.Succeeded++
+		case model.WebhookDeliveryDead:
+			result.Dead++
+		default:
+			result.Retrying++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_deliverWebhooks_Execute(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	errTimeout := errors.New("timeout")
+	webhook := model.Webhook{ID: "w1", URL: "http://example.com", Secret: "whsec_test"}
+	deliveries := []model.WebhookDelivery{
+		{ID: "ok", WebhookID: "w1", Status: model.WebhookDeliveryPending},
+		{ID: "retry", WebhookID: "w1", Status: model.WebhookDeliveryPending, Attempts: 1},
+		{ID: "dead", WebhookID: "w1", Status: model.WebhookDeliveryPending, Attempts: model.MaxWebhookAttempts - 1},
+		{ID: "deleted", WebhookID: "w2", Status: model.WebhookDeliveryPending},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mock_repository.NewMockWebhook(ctrl)
+	// 同じWebhookは1回だけ取得する
+	mockWebhookRepo.EXPECT().FindByID(gomock.Any(), "w1").Return(&webhook, nil)
+	mockWebhookRepo.EXPECT().FindByID(gomock.Any(), "w2").Return(nil, model.ErrNotFound)
+
+	mockDeliveryRepo := mock_repository.NewMockWebhookDelivery(ctrl)
+	mockDeliveryRepo.EXPECT().
+		FindDue(gomock.Any(), now, gomock.Any()).
+		Return(deliveries, nil)
+	updated := make(map[string]model.WebhookDelivery)
+	mockDeliveryRepo.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, d model.WebhookDelivery) (*model.WebhookDelivery, error) {
+			updated[d.ID] = d
+			return &d, nil
+		}).Times(3)
+
+	mockSender := mock_repository.NewMockWebhookSender(ctrl)
+	mockSender.EXPECT().
+		Send(gomock.Any(), webhook, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ model.Webhook, d model.WebhookDelivery) (int, error) {
+			switch d.ID {
+			case "ok":
+				return 204, nil
+			case "retry":
+				return 503, nil
+			default:
+				return 0, errTimeout
+			}
+		}).Times(3)
+
+	uc := usecase.NewDeliverWebhooks(mockWebhookRepo, mockDeliveryRepo, mockSender)
+	got, err := uc.Execute(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if diff := cmp.Diff(usecase.WebhookDeliveryResult{Succeeded: 1, Retrying: 1, Dead: 1}, got); diff != "" {
+		t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+	}
+
+	// 2回目の失敗のため、最初の間隔を倍にした後に再試行する
+	retry := updated["retry"]
+	if retry.Status != model.WebhookDeliveryPending || retry.ResponseStatus != 503 || retry.NextAttemptAt == nil ||
+		!retry.NextAttemptAt.Equal(now.Add(2*model.WebhookMinBackoff)) {
+		t.Errorf("Update(retry) = status %q, response %d, next attempt %v", retry.Status, retry.ResponseStatus, retry.NextAttemptAt)
+	}
+	dead := updated["dead"]
+	if dead.Status != model.WebhookDeliveryDead || dead.LastError != errTimeout.Error() || dead.NextAttemptAt != nil {
+		t.Errorf("Update(dead) = status %q, last error %q, next attempt %v", dead.Status, dead.LastError, dead.NextAttemptAt)
+	}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// EnqueueWebhookDeliveries はイベントを購読しているWebhookへの配信を作成するユースケースを表すインターフェース
+// アウトボックスのイベントの配信先として使う
+type EnqueueWebhookDeliveries interface {
+	Execute(ctx context.Context, event model.TodoEvent) error
+}
+
+// enqueueWebhookDeliveries は usecase.EnqueueWebhookDeliveries の実装
+type enqueueWebhookDeliveries struct {
+	webhookRepo         repository.Webhook
+	webhookDeliveryRepo repository.WebhookDelivery
+}
+
+// NewEnqueueWebhookDeliveries は usecase.EnqueueWebhookDeliveries のコンストラクタ
+func NewEnqueueWebhookDeliveries(webhookRepo repository.Webhook, webhookDeliveryRepo repository.WebhookDelivery) EnqueueWebhookDeliveries {
+	return &enqueueWebhookDeliveries{
+		webhookRepo:         webhookRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+	}
+}
+
+// Execute はイベントの種類を購読している全てのWebhookに、すぐに配信を試みる配信を作成する
+// 同じイベントを再度配信された場合は、作成済みの配信を重複して作成しない
+func (uc *enqueueWebhookDeliveries) Execute(ctx context.Context, event model.TodoEvent) error {
+	webhooks, err := uc.webhookRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, w := range webhooks {
+		if !w.Matches(event.Type) {
+			continue
+		}
+		delivery, err := model.NewWebhookDelivery(w.ID, event, now)
+		if err != nil {
+			return err
+		}
+		// 作成済みの配信と、取得した後に削除されたWebhookへの配信は無視する
+		if _, err := uc.webhookDeliveryRepo.Create(ctx, *delivery); err != nil &&
+			!errors.Is(err, model.ErrConflict) && !errors.Is(err, model.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_enqueueWebhookDeliveries_Execute(t *testing.T) {
+	webhooks := []model.Webhook{
+		{ID: "all"},
+		{ID: "completed", Events: []model.TodoEventType{model.TodoEventCompleted}},
+		{ID: "created", Events: []model.TodoEventType{model.TodoEventCreated}},
+		{ID: "duplicate"},
+	}
+	event := model.TodoEvent{ID: "e1", Type: model.TodoEventCreated, TodoID: "t1"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mock_repository.NewMockWebhook(ctrl)
+	mockWebhookRepo.EXPECT().FindAll(gomock.Any()).Return(webhooks, nil)
+
+	var created []string
+	mockDeliveryRepo := mock_repository.NewMockWebhookDelivery(ctrl)
+	mockDeliveryRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, d model.WebhookDelivery) (*model.WebhookDelivery, error) {
+			if d.EventID != event.ID || d.EventType != event.Type || d.Status != model.WebhookDeliveryPending {
+				t.Errorf("Create() delivery = %+v", d)
+			}
+			// 再度配信されたイベントの配信は作成済み
+			if d.WebhookID == "duplicate" {
+				return nil, model.ErrConflict
+			}
+			created = append(created, d.WebhookID)
+			return &d, nil
+		}).Times(3)
+
+	uc := usecase.NewEnqueueWebhookDeliveries(mockWebhookRepo, mockDeliveryRepo)
+	if err := uc.Execute(context.Background(), event); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"all", "created"}, created); diff != "" {
+		t.Errorf("Create() webhooks mismatch (-want +got):\n%s", diff)
+	}
+}
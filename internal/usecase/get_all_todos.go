@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/qushot/gin-todo-api/internal/domain/model"
 	"github.com/qushot/gin-todo-api/internal/domain/repository"
@@ -25,6 +26,12 @@ func NewGetAllTodos(todoRepo repository.Todo) GetAllTodos {
 }
 
 // Execute は全てのTodoを取得する
+// 期限による絞り込みは実行した時点の日時を基準に解決する
 func (uc *getAllTodos) Execute(ctx context.Context, query model.TodoQuery) ([]model.Todo, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	query.ResolveDue(time.Now())
+
 	return uc.todoRepo.FindAll(ctx, query)
 }
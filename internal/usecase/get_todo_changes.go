@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetTodoChanges は同期トークン以降のTodoの変更の差分を取得するユースケースを表すインターフェース
+type GetTodoChanges interface {
+	Execute(ctx context.Context, token string) (*model.TodoSync, error)
+}
+
+// getTodoChanges は usecase.GetTodoChanges の実装
+type getTodoChanges struct {
+	todoRepo repository.Todo
+}
+
+// NewGetTodoChanges は usecase.GetTodoChanges のコンストラクタ
+func NewGetTodoChanges(todoRepo repository.Todo) GetTodoChanges {
+	return &getTodoChanges{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は同期トークン token より後に作成または変更したTodoと、ゴミ箱に移動または完全に削除したTodoの削除の記録を返す
+// token が空文字列の場合は最初から同期するため、ゴミ箱にないTodoだけを返し、削除の記録は返さない
+func (uc *getTodoChanges) Execute(ctx context.Context, token string) (*model.TodoSync, error) {
+	since, err := model.ParseSyncToken(token)
+	if err != nil {
+		return nil, err
+	}
+	initial := token == ""
+	changes, err := uc.todoRepo.FindChanges(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	sync := &model.TodoSync{
+		Todos:      []model.Todo{},
+		Tombstones: []model.TodoTombstone{},
+		Token:      model.FormatSyncToken(changes.Token),
+	}
+	for _, todo := range changes.Todos {
+		if todo.DeletedAt == nil {
+			sync.Todos = append(sync.Todos, todo)
+		} else if !initial {
+			sync.Tombstones = append(sync.Tombstones, model.TodoTombstone{ID: todo.ID, DeletedAt: *todo.DeletedAt})
+		}
+	}
+	if !initial {
+		sync.Tombstones = append(sync.Tombstones, changes.Purged...)
+	}
+	return sync, nil
+}
@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_getTodoChanges_Execute(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	changes := &model.TodoChanges{
+		Todos: []model.Todo{
+			{ID: "changed", Version: 2},
+			{ID: "trashed", Version: 3, DeletedAt: &deletedAt},
+		},
+		Purged: []model.TodoTombstone{{ID: "purged", DeletedAt: deletedAt, Purged: true}},
+		Token:  42,
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		since int64
+		want  *model.TodoSync
+	}{
+		{
+			name:  "initial sync returns live todos only",
+			token: "",
+			since: 0,
+			want: &model.TodoSync{
+				Todos:      []model.Todo{{ID: "changed", Version: 2}},
+				Tombstones: []model.TodoTombstone{},
+				Token:      "42",
+			},
+		},
+		{
+			name:  "delta sync returns tombstones for trashed and purged todos",
+			token: "10",
+			since: 10,
+			want: &model.TodoSync{
+				Todos: []model.Todo{{ID: "changed", Version: 2}},
+				Tombstones: []model.TodoTombstone{
+					{ID: "trashed", DeletedAt: deletedAt},
+					{ID: "purged", DeletedAt: deletedAt, Purged: true},
+				},
+				Token: "42",
+			},
+		},
+		{
+			name:  "token of an empty store is not an initial sync",
+			token: "0",
+			since: 0,
+			want: &model.TodoSync{
+				Todos: []model.Todo{{ID: "changed", Version: 2}},
+				Tombstones: []model.TodoTombstone{
+					{ID: "trashed", DeletedAt: deletedAt},
+					{ID: "purged", DeletedAt: deletedAt, Purged: true},
+				},
+				Token: "42",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindChanges(gomock.Any(), tt.since).
+				Return(changes, nil)
+
+			uc := usecase.NewGetTodoChanges(mockTodoRepo)
+			got, err := uc.Execute(context.Background(), tt.token)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_getTodoChanges_Execute_malformedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := usecase.NewGetTodoChanges(mock_repository.NewMockTodo(ctrl))
+	if _, err := uc.Execute(context.Background(), "abc"); !errors.Is(err, model.ErrInvalidArgument) {
+		t.Errorf("Execute() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetTodoListStamp はTodoの一覧が変わったかを判定するための値を取得するユースケースを表すインターフェース
+type GetTodoListStamp interface {
+	Execute(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error)
+}
+
+// getTodoListStamp は usecase.GetTodoListStamp の実装
+type getTodoListStamp struct {
+	todoRepo repository.Todo
+}
+
+// NewGetTodoListStamp は usecase.GetTodoListStamp のコンストラクタ
+func NewGetTodoListStamp(todoRepo repository.Todo) GetTodoListStamp {
+	return &getTodoListStamp{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は usecase.GetAllTodos と同じ検索クエリで一覧を取得する前に、一覧が変わったかを判定するための値を取得する
+func (uc *getTodoListStamp) Execute(ctx context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	query.ResolveDue(time.Now())
+
+	return uc.todoRepo.Stamp(ctx, query)
+}
@@ -0,0 +1,63 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_getTodoListStamp_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     model.TodoQuery
+		wantStamp bool
+		wantRange bool
+		wantErr   error
+	}{
+		{
+			name:      "stamp without due filter",
+			query:     model.TodoQuery{},
+			wantStamp: true,
+		},
+		{
+			name:      "due filter is resolved before stamping",
+			query:     model.TodoQuery{Due: model.DueFilterToday},
+			wantStamp: true,
+			wantRange: true,
+		},
+		{
+			name:    "invalid query",
+			query:   model.TodoQuery{Due: "someday"},
+			wantErr: model.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			if tt.wantStamp {
+				mockTodoRepo.EXPECT().
+					Stamp(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, query model.TodoQuery) (*model.TodoListStamp, error) {
+						if got := query.DueRange != nil; got != tt.wantRange {
+							t.Errorf("Stamp() DueRange resolved = %v, want %v", got, tt.wantRange)
+						}
+						return &model.TodoListStamp{DueRange: query.DueRange}, nil
+					})
+			}
+
+			uc := usecase.NewGetTodoListStamp(mockTodoRepo)
+			if _, err := uc.Execute(context.Background(), tt.query); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
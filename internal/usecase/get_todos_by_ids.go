@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetTodosByIDs は複数のTodoをまとめて取得するユースケースを表すインターフェース
+type GetTodosByIDs interface {
+	Execute(ctx context.Context, ids []string) (map[string]model.Todo, error)
+}
+
+// getTodosByIDs は usecase.GetTodosByIDs の実装
+type getTodosByIDs struct {
+	todoRepo repository.Todo
+}
+
+// NewGetTodosByIDs は usecase.GetTodosByIDs のコンストラクタ
+func NewGetTodosByIDs(todoRepo repository.Todo) GetTodosByIDs {
+	return &getTodosByIDs{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は ids のTodoを1回の問い合わせで取得し、IDをキーにして返す
+// 見つからないTodoは結果に含めない (呼び出し側で見つからない場合の扱いを決める)
+func (uc *getTodosByIDs) Execute(ctx context.Context, ids []string) (map[string]model.Todo, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return map[string]model.Todo{}, nil
+	}
+
+	todos, err := uc.todoRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]model.Todo, len(todos))
+	for _, todo := range todos {
+		result[todo.ID] = todo
+	}
+	return result, nil
+}
+
+// uniqueIDs は空のIDと重複を取り除いたIDの一覧を返す
+func uniqueIDs(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
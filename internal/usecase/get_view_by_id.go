@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetViewByID はIDによるビューの取得を行うユースケースを表すインターフェース
+type GetViewByID interface {
+	Execute(ctx context.Context, id string) (*model.View, error)
+}
+
+// getViewByID は usecase.GetViewByID の実装
+type getViewByID struct {
+	viewRepo repository.View
+}
+
+// NewGetViewByID は usecase.GetViewByID のコンストラクタ
+func NewGetViewByID(viewRepo repository.View) GetViewByID {
+	return &getViewByID{
+		viewRepo: viewRepo,
+	}
+}
+
+// Execute はIDによるビューの取得を行う
+func (uc *getViewByID) Execute(ctx context.Context, id string) (*model.View, error) {
+	return uc.viewRepo.FindByID(ctx, id)
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetViewTodos はビューの検索クエリに一致するTodoを取得するユースケースを表すインターフェース
+type GetViewTodos interface {
+	Execute(ctx context.Context, id string) ([]model.Todo, error)
+}
+
+// getViewTodos は usecase.GetViewTodos の実装
+type getViewTodos struct {
+	viewRepo           repository.View
+	getAllTodosUseCase GetAllTodos
+}
+
+// NewGetViewTodos は usecase.GetViewTodos のコンストラクタ
+func NewGetViewTodos(viewRepo repository.View, getAllTodosUseCase GetAllTodos) GetViewTodos {
+	return &getViewTodos{
+		viewRepo:           viewRepo,
+		getAllTodosUseCase: getAllTodosUseCase,
+	}
+}
+
+// Execute はビューの検索クエリを usecase.GetAllTodos で実行する
+// today などの期限の相対的な表現は実行した時点の日時を基準に解決される
+func (uc *getViewTodos) Execute(ctx context.Context, id string) ([]model.Todo, error) {
+	view, err := uc.viewRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.getAllTodosUseCase.Execute(ctx, view.Query)
+}
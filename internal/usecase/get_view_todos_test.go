@@ -0,0 +1,47 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_getViewTodos_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	minPriority := 3
+	view := model.View{
+		ID:    "1",
+		Name:  "overdue",
+		Query: model.TodoQuery{Due: model.DueFilterOverdue, MinPriority: &minPriority},
+	}
+
+	mockViewRepo := mock_repository.NewMockView(ctrl)
+	mockViewRepo.EXPECT().
+		FindByID(gomock.Any(), view.ID).
+		Return(&view, nil)
+	mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+	mockTodoRepo.EXPECT().
+		FindAll(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, query model.TodoQuery) ([]model.Todo, error) {
+			// 期限の相対的な表現は取得する時点で解決される
+			if query.DueRange == nil || query.DueRange.To == nil || !query.DueRange.OpenOnly {
+				t.Errorf("FindAll() DueRange = %+v, want resolved overdue range", query.DueRange)
+			}
+			if query.MinPriority == nil || *query.MinPriority != minPriority {
+				t.Errorf("FindAll() MinPriority = %v, want %d", query.MinPriority, minPriority)
+			}
+			return []model.Todo{}, nil
+		})
+
+	uc := usecase.NewGetViewTodos(mockViewRepo, usecase.NewGetAllTodos(mockTodoRepo))
+	if _, err := uc.Execute(context.Background(), view.ID); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
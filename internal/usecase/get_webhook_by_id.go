@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// GetWebhookByID はIDによるWebhookの取得を行うユースケースを表すインターフェース
+type GetWebhookByID interface {
+	Execute(ctx context.Context, id string) (*model.Webhook, error)
+}
+
+// getWebhookByID は usecase.GetWebhookByID の実装
+type getWebhookByID struct {
+	webhookRepo repository.Webhook
+}
+
+// NewGetWebhookByID は usecase.GetWebhookByID のコンストラクタ
+func NewGetWebhookByID(webhookRepo repository.Webhook) GetWebhookByID {
+	return &getWebhookByID{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Execute はIDによるWebhookの取得を秘密鍵を除いて行う
+func (uc *getWebhookByID) Execute(ctx context.Context, id string) (*model.Webhook, error) {
+	webhook, err := uc.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return withoutSecret(webhook), nil
+}
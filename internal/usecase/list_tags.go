@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTags はすべてのタグを取得するユースケースを表すインターフェース
+type ListTags interface {
+	Execute(ctx context.Context) ([]model.Tag, error)
+}
+
+// listTags は usecase.ListTags の実装
+type listTags struct {
+	tagRepo repository.Tag
+}
+
+// NewListTags は usecase.ListTags のコンストラクタ
+func NewListTags(tagRepo repository.Tag) ListTags {
+	return &listTags{
+		tagRepo: tagRepo,
+	}
+}
+
+// Execute は全てのタグを取得する
+func (uc *listTags) Execute(ctx context.Context) ([]model.Tag, error) {
+	return uc.tagRepo.FindAll(ctx)
+}
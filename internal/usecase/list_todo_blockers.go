@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTodoBlockers はTodoをブロックしているTodoを取得するユースケースを表すインターフェース
+type ListTodoBlockers interface {
+	Execute(ctx context.Context, id string) ([]model.Todo, error)
+}
+
+// listTodoBlockers は usecase.ListTodoBlockers の実装
+type listTodoBlockers struct {
+	todoRepo       repository.Todo
+	dependencyRepo repository.TodoDependency
+}
+
+// NewListTodoBlockers は usecase.ListTodoBlockers のコンストラクタ
+func NewListTodoBlockers(todoRepo repository.Todo, dependencyRepo repository.TodoDependency) ListTodoBlockers {
+	return &listTodoBlockers{
+		todoRepo:       todoRepo,
+		dependencyRepo: dependencyRepo,
+	}
+}
+
+// Execute はTodoをブロックしているTodoを取得する
+func (uc *listTodoBlockers) Execute(ctx context.Context, id string) ([]model.Todo, error) {
+	if _, err := uc.todoRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	blockerIDs, err := uc.dependencyRepo.FindBlockerIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := make([]model.Todo, 0, len(blockerIDs))
+	for _, blockerID := range blockerIDs {
+		blocker, err := uc.todoRepo.FindByID(ctx, blockerID)
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, *blocker)
+	}
+	return blockers, nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTodoChildren は子のTodoを取得するユースケースを表すインターフェース
+type ListTodoChildren interface {
+	Execute(ctx context.Context, id string) ([]model.Todo, error)
+}
+
+// listTodoChildren は usecase.ListTodoChildren の実装
+type listTodoChildren struct {
+	todoRepo repository.Todo
+}
+
+// NewListTodoChildren は usecase.ListTodoChildren のコンストラクタ
+func NewListTodoChildren(todoRepo repository.Todo) ListTodoChildren {
+	return &listTodoChildren{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は子のTodoを並び順で取得する
+func (uc *listTodoChildren) Execute(ctx context.Context, id string) ([]model.Todo, error) {
+	if _, err := uc.todoRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return uc.todoRepo.FindChildren(ctx, id)
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTodoChildrenByParentIDs は複数の親の子のTodoをまとめて取得するユースケースを表すインターフェース
+type ListTodoChildrenByParentIDs interface {
+	Execute(ctx context.Context, parentIDs []string) (map[string][]model.Todo, error)
+}
+
+// listTodoChildrenByParentIDs は usecase.ListTodoChildrenByParentIDs の実装
+type listTodoChildrenByParentIDs struct {
+	todoRepo repository.Todo
+}
+
+// NewListTodoChildrenByParentIDs は usecase.ListTodoChildrenByParentIDs のコンストラクタ
+func NewListTodoChildrenByParentIDs(todoRepo repository.Todo) ListTodoChildrenByParentIDs {
+	return &listTodoChildrenByParentIDs{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は parentIDs の各親の子のTodoを1回の問い合わせで取得し、親のIDをキーにして並び順で返す
+// 子のない親や存在しない親は結果に含めない
+func (uc *listTodoChildrenByParentIDs) Execute(ctx context.Context, parentIDs []string) (map[string][]model.Todo, error) {
+	parentIDs = uniqueIDs(parentIDs)
+	if len(parentIDs) == 0 {
+		return map[string][]model.Todo{}, nil
+	}
+
+	children, err := uc.todoRepo.FindChildrenByParentIDs(ctx, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]model.Todo, len(parentIDs))
+	for _, child := range children {
+		if child.ParentID != nil {
+			result[*child.ParentID] = append(result[*child.ParentID], child)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_listTodoChildrenByParentIDs_Execute(t *testing.T) {
+	p1, p2 := "p1", "p2"
+	tests := []struct {
+		name      string
+		parentIDs []string
+		wantQuery []string
+		children  []model.Todo
+		want      map[string][]model.Todo
+	}{
+		{
+			name:      "children are grouped by parent in one query",
+			parentIDs: []string{p1, p2, p1, ""},
+			wantQuery: []string{p1, p2},
+			children: []model.Todo{
+				{ID: "a", ParentID: &p1},
+				{ID: "b", ParentID: &p1},
+				{ID: "c", ParentID: &p2},
+			},
+			want: map[string][]model.Todo{
+				p1: {{ID: "a", ParentID: &p1}, {ID: "b", ParentID: &p1}},
+				p2: {{ID: "c", ParentID: &p2}},
+			},
+		},
+		{
+			name:      "no parents does not query the repository",
+			parentIDs: []string{""},
+			want:      map[string][]model.Todo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			if tt.wantQuery != nil {
+				mockTodoRepo.EXPECT().
+					FindChildrenByParentIDs(gomock.Any(), tt.wantQuery).
+					Return(tt.children, nil)
+			}
+
+			uc := usecase.NewListTodoChildrenByParentIDs(mockTodoRepo)
+			got, err := uc.Execute(context.Background(), tt.parentIDs)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Execute() (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
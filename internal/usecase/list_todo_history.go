@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTodoHistory はTodoの変更履歴を取得するユースケースを表すインターフェース
+type ListTodoHistory interface {
+	Execute(ctx context.Context, id string) ([]model.TodoHistory, error)
+}
+
+// listTodoHistory は usecase.ListTodoHistory の実装
+type listTodoHistory struct {
+	todoRepo    repository.Todo
+	historyRepo repository.TodoHistory
+}
+
+// NewListTodoHistory は usecase.ListTodoHistory のコンストラクタ
+func NewListTodoHistory(todoRepo repository.Todo, historyRepo repository.TodoHistory) ListTodoHistory {
+	return &listTodoHistory{
+		todoRepo:    todoRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute はTodoの変更履歴を古い順に取得する
+// 変更履歴はTodoを完全に削除した後も残るため、変更履歴がない場合のみTodoの存在を確認する
+func (uc *listTodoHistory) Execute(ctx context.Context, id string) ([]model.TodoHistory, error) {
+	histories, err := uc.historyRepo.FindByTodoID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		if _, err := uc.todoRepo.FindByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return histories, nil
+}
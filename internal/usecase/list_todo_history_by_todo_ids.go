@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTodoHistoryByTodoIDs は複数のTodoの変更履歴をまとめて取得するユースケースを表すインターフェース
+type ListTodoHistoryByTodoIDs interface {
+	Execute(ctx context.Context, todoIDs []string) (map[string][]model.TodoHistory, error)
+}
+
+// listTodoHistoryByTodoIDs は usecase.ListTodoHistoryByTodoIDs の実装
+type listTodoHistoryByTodoIDs struct {
+	historyRepo repository.TodoHistory
+}
+
+// NewListTodoHistoryByTodoIDs は usecase.ListTodoHistoryByTodoIDs のコンストラクタ
+func NewListTodoHistoryByTodoIDs(historyRepo repository.TodoHistory) ListTodoHistoryByTodoIDs {
+	return &listTodoHistoryByTodoIDs{
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute は todoIDs の各Todoの変更履歴を1回の問い合わせで取得し、TodoのIDをキーにして古い順に返す
+// 取得済みのTodoの変更履歴を読み込むためのものなので、ListTodoHistory と異なりTodoの存在は確認しない
+func (uc *listTodoHistoryByTodoIDs) Execute(ctx context.Context, todoIDs []string) (map[string][]model.TodoHistory, error) {
+	todoIDs = uniqueIDs(todoIDs)
+	if len(todoIDs) == 0 {
+		return map[string][]model.TodoHistory{}, nil
+	}
+
+	histories, err := uc.historyRepo.FindByTodoIDs(ctx, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]model.TodoHistory, len(todoIDs))
+	for _, h := range histories {
+		result[h.TodoID] = append(result[h.TodoID], h)
+	}
+	return result, nil
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListTrashedTodos はゴミ箱にあるTodoを取得するユースケースを表すインターフェース
+type ListTrashedTodos interface {
+	Execute(ctx context.Context) ([]model.Todo, error)
+}
+
+// listTrashedTodos は usecase.ListTrashedTodos の実装
+type listTrashedTodos struct {
+	todoRepo repository.Todo
+}
+
+// NewListTrashedTodos は usecase.ListTrashedTodos のコンストラクタ
+func NewListTrashedTodos(todoRepo repository.Todo) ListTrashedTodos {
+	return &listTrashedTodos{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute はゴミ箱にあるTodoを移動した日時の新しい順に取得する
+func (uc *listTrashedTodos) Execute(ctx context.Context) ([]model.Todo, error) {
+	return uc.todoRepo.FindTrash(ctx)
+}
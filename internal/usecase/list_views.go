@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListViews はビューの一覧を取得するユースケースを表すインターフェース
+type ListViews interface {
+	Execute(ctx context.Context) ([]model.View, error)
+}
+
+// listViews は usecase.ListViews の実装
+type listViews struct {
+	viewRepo repository.View
+}
+
+// NewListViews は usecase.ListViews のコンストラクタ
+func NewListViews(viewRepo repository.View) ListViews {
+	return &listViews{
+		viewRepo: viewRepo,
+	}
+}
+
+// Execute は全てのビューを名前順で取得する
+func (uc *listViews) Execute(ctx context.Context) ([]model.View, error) {
+	return uc.viewRepo.FindAll(ctx)
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// webhookDeliveryLogLimit は配信の履歴として返す配信の最大件数
+const webhookDeliveryLogLimit = 100
+
+// ListWebhookDeliveries はWebhookの配信の履歴を取得するユースケースを表すインターフェース
+type ListWebhookDeliveries interface {
+	Execute(ctx context.Context, webhookID string) ([]model.WebhookDelivery, error)
+}
+
+// listWebhookDeliveries は usecase.ListWebhookDeliveries の実装
+type listWebhookDeliveries struct {
+	webhookRepo         repository.Webhook
+	webhookDeliveryRepo repository.WebhookDelivery
+}
+
+// NewListWebhookDeliveries は usecase.ListWebhookDeliveries のコンストラクタ
+func NewListWebhookDeliveries(webhookRepo repository.Webhook, webhookDeliveryRepo repository.WebhookDelivery) ListWebhookDeliveries {
+	return &listWebhookDeliveries{
+		webhookRepo:         webhookRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+	}
+}
+
+// Execute はWebhookの配信を新しい順に webhookDeliveryLogLimit 件まで取得する
+// Webhookが存在しない場合は model.ErrNotFound を返す
+func (uc *listWebhookDeliveries) Execute(ctx context.Context, webhookID string) ([]model.WebhookDelivery, error) {
+	if _, err := uc.webhookRepo.FindByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return uc.webhookDeliveryRepo.FindByWebhookID(ctx, webhookID, webhookDeliveryLogLimit)
+}
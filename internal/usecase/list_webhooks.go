@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ListWebhooks は全てのWebhookを取得するユースケースを表すインターフェース
+type ListWebhooks interface {
+	Execute(ctx context.Context) ([]model.Webhook, error)
+}
+
+// listWebhooks は usecase.ListWebhooks の実装
+type listWebhooks struct {
+	webhookRepo repository.Webhook
+}
+
+// NewListWebhooks は usecase.ListWebhooks のコンストラクタ
+func NewListWebhooks(webhookRepo repository.Webhook) ListWebhooks {
+	return &listWebhooks{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Execute は全てのWebhookを作成した順に秘密鍵を除いて取得する
+func (uc *listWebhooks) Execute(ctx context.Context) ([]model.Webhook, error) {
+	webhooks, err := uc.webhookRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i] = *withoutSecret(&webhooks[i])
+	}
+	return webhooks, nil
+}
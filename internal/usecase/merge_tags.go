@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// MergeTags はタグを別のタグに統合するユースケースを表すインターフェース
+type MergeTags interface {
+	Execute(ctx context.Context, sourceID string, targetID string) (*model.Tag, error)
+}
+
+// mergeTags は usecase.MergeTags の実装
+type mergeTags struct {
+	txManager  repository.TxManager
+	tagRepo    repository.Tag
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
+}
+
+// NewMergeTags は usecase.MergeTags のコンストラクタ
+func NewMergeTags(txManager repository.TxManager, tagRepo repository.Tag, todoRepo repository.Todo, outboxRepo repository.Outbox) MergeTags {
+	return &mergeTags{
+		txManager:  txManager,
+		tagRepo:    tagRepo,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute は source のタグを target に統合し、統合後のタグを返す
+// source が付与されていたTodoは、同じトランザクションで更新として記録する
+func (uc *mergeTags) Execute(ctx context.Context, sourceID string, targetID string) (*model.Tag, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", model.ErrInvalidArgument)
+	}
+
+	var tag *model.Tag
+	err := changeTag(ctx, uc.txManager, uc.tagRepo, uc.todoRepo, uc.outboxRepo, sourceID, func(ctx context.Context) error {
+		var err error
+		tag, err = uc.tagRepo.Merge(ctx, sourceID, targetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
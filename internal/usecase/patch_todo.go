@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// PatchTodo はTodoを部分更新するユースケースを表すインターフェース
+type PatchTodo interface {
+	Execute(ctx context.Context, id string, patch model.TodoPatch, scope model.RecurrenceScope) (*model.Todo, error)
+}
+
+// patchTodo は usecase.PatchTodo の実装
+type patchTodo struct {
+	todoUpdater
+}
+
+// NewPatchTodo は usecase.PatchTodo のコンストラクタ
+func NewPatchTodo(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) PatchTodo {
+	return &patchTodo{
+		todoUpdater: todoUpdater{
+			txManager:      txManager,
+			todoRepo:       todoRepo,
+			dependencyRepo: dependencyRepo,
+			outboxRepo:     outboxRepo,
+		},
+	}
+}
+
+// Execute はTodoを部分更新する (scope は usecase.UpdateTodo と同様)
+func (uc *patchTodo) Execute(ctx context.Context, id string, patch model.TodoPatch, scope model.RecurrenceScope) (*model.Todo, error) {
+	return uc.updateCurrent(ctx, id, func(current *model.Todo) model.Todo { return patch.Apply(*current) }, scope)
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// PurgeExpiredIdempotencyKeys は有効期限を過ぎた冪等キーを削除するユースケースを表すインターフェース
+type PurgeExpiredIdempotencyKeys interface {
+	Execute(ctx context.Context, now time.Time) (int, error)
+}
+
+// purgeExpiredIdempotencyKeys は usecase.PurgeExpiredIdempotencyKeys の実装
+type purgeExpiredIdempotencyKeys struct {
+	idempotencyKeyRepo repository.IdempotencyKey
+}
+
+// NewPurgeExpiredIdempotencyKeys は usecase.PurgeExpiredIdempotencyKeys のコンストラクタ
+func NewPurgeExpiredIdempotencyKeys(idempotencyKeyRepo repository.IdempotencyKey) PurgeExpiredIdempotencyKeys {
+	return &purgeExpiredIdempotencyKeys{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+	}
+}
+
+// Execute は now 時点で有効期限を過ぎた冪等キーを削除し、削除した件数を返す
+func (uc *purgeExpiredIdempotencyKeys) Execute(ctx context.Context, now time.Time) (int, error) {
+	return uc.idempotencyKeyRepo.DeleteExpired(ctx, now)
+}
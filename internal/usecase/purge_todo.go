@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// PurgeTodo はゴミ箱にあるTodoを完全に削除するユースケースを表すインターフェース
+type PurgeTodo interface {
+	Execute(ctx context.Context, id string) error
+}
+
+// purgeTodo は usecase.PurgeTodo の実装
+type purgeTodo struct {
+	todoRepo repository.Todo
+}
+
+// NewPurgeTodo は usecase.PurgeTodo のコンストラクタ
+func NewPurgeTodo(todoRepo repository.Todo) PurgeTodo {
+	return &purgeTodo{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute はゴミ箱にあるTodoを子孫のTodoも含めて完全に削除する
+// ゴミ箱にないTodoは先にゴミ箱に移動する必要がある
+func (uc *purgeTodo) Execute(ctx context.Context, id string) error {
+	return uc.todoRepo.Purge(ctx, id)
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// PurgeTrash は保持期間を過ぎたゴミ箱のTodoを完全に削除するユースケースを表すインターフェース
+type PurgeTrash interface {
+	Execute(ctx context.Context, now time.Time, retention time.Duration) (int, error)
+}
+
+// purgeTrash は usecase.PurgeTrash の実装
+type purgeTrash struct {
+	todoRepo repository.Todo
+}
+
+// NewPurgeTrash は usecase.PurgeTrash のコンストラクタ
+func NewPurgeTrash(todoRepo repository.Todo) PurgeTrash {
+	return &purgeTrash{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は now 時点で retention より長くゴミ箱にあるTodoを完全に削除し、削除した件数を返す
+func (uc *purgeTrash) Execute(ctx context.Context, now time.Time, retention time.Duration) (int, error) {
+	if retention <= 0 {
+		return 0, fmt.Errorf("%w: retention must be positive", model.ErrInvalidArgument)
+	}
+
+	return uc.todoRepo.PurgeDeletedBefore(ctx, now.Add(-retention))
+}
@@ -0,0 +1,60 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_purgeTrash_Execute(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retention  time.Duration
+		wantBefore time.Time
+		want       int
+		wantErr    error
+	}{
+		{
+			name:       "purges todos deleted before the retention",
+			retention:  30 * 24 * time.Hour,
+			wantBefore: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+			want:       2,
+		},
+		{
+			name:      "non-positive retention",
+			retention: 0,
+			wantErr:   model.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			if tt.wantErr == nil {
+				mockTodoRepo.EXPECT().
+					PurgeDeletedBefore(gomock.Any(), tt.wantBefore).
+					Return(tt.want, nil)
+			}
+
+			uc := usecase.NewPurgeTrash(mockTodoRepo)
+			got, gotErr := uc.Execute(context.Background(), now, tt.retention)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// PushTodoChanges はクライアントでオフラインの間に行った変更をまとめて適用するユースケースを表すインターフェース
+type PushTodoChanges interface {
+	Execute(ctx context.Context, push model.TodoSyncPush) ([]model.TodoSyncResult, error)
+}
+
+// pushTodoChanges は usecase.PushTodoChanges の実装
+type pushTodoChanges struct {
+	todoUpdater
+	historyRepo repository.TodoHistory
+}
+
+// NewPushTodoChanges は usecase.PushTodoChanges のコンストラクタ
+func NewPushTodoChanges(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, historyRepo repository.TodoHistory, outboxRepo repository.Outbox) PushTodoChanges {
+	return &pushTodoChanges{
+		todoUpdater: todoUpdater{
+			txManager:      txManager,
+			todoRepo:       todoRepo,
+			dependencyRepo: dependencyRepo,
+			outboxRepo:     outboxRepo,
+		},
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute は変更を送信された順に1件ずつ適用し、変更ごとの結果を返す
+// 各変更は独立して適用し、競合または失敗した変更があっても後の変更は適用する
+// update と delete は元にしたバージョンが現在のバージョンと異なる場合に競合とする
+// patch は元にしたバージョンより後のサーバーの変更と変更する項目が重ならなければ、サーバーの変更とマージして適用する
+func (uc *pushTodoChanges) Execute(ctx context.Context, push model.TodoSyncPush) ([]model.TodoSyncResult, error) {
+	if err := push.Validate(); err != nil {
+		return nil, err
+	}
+
+	createdIDs := make(map[string]string)
+	results := make([]model.TodoSyncResult, len(push.Changes))
+	for i, change := range push.Changes {
+		change = resolveCreatedIDs(change, createdIDs)
+		results[i] = uc.apply(ctx, change)
+		if change.Op == model.SyncOperationCreate && change.ClientID != "" && results[i].Todo != nil {
+			createdIDs[change.ClientID] = results[i].Todo.ID
+		}
+	}
+	return results, nil
+}
+
+// resolveCreatedIDs は変更の対象と親に指定したクライアントのIDを、同じ送信で作成したTodoのIDに置き換える
+func resolveCreatedIDs(change model.TodoSyncChange, createdIDs map[string]string) model.TodoSyncChange {
+	resolve := func(id *string) *string {
+		if id == nil {
+			return nil
+		}
+		if created, ok := createdIDs[*id]; ok {
+			return &created
+		}
+		return id
+	}
+
+	if created, ok := createdIDs[change.ID]; ok {
+		change.ID = created
+	}
+	if change.Todo != nil {
+		todo := *change.Todo
+		todo.ParentID = resolve(todo.ParentID)
+		change.Todo = &todo
+	}
+	if change.Patch != nil {
+		patch := *change.Patch
+		patch.ParentID = resolve(patch.ParentID)
+		change.Patch = &patch
+	}
+	return change
+}
+
+// apply は1件の変更を適用し、結果を返す
+func (uc *pushTodoChanges) apply(ctx context.Context, change model.TodoSyncChange) model.TodoSyncResult {
+	result := model.TodoSyncResult{ClientID: change.ClientID, Op: change.Op, ID: change.ID}
+	reject := func(err error) model.TodoSyncResult {
+		result.Resolution = model.SyncResolutionRejected
+		result.Err = err
+		return result
+	}
+
+	switch change.Op {
+	case model.SyncOperationCreate:
+		if change.Todo == nil {
+			return reject(fmt.Errorf("%w: todo is required for %s", model.ErrInvalidArgument, change.Op))
+		}
+		todo, err := prepareNewTodo(ctx, uc.todoRepo, *change.Todo)
+		if err != nil {
+			return reject(err)
+		}
+		created, err := writeTodo(ctx, uc.txManager, uc.outboxRepo, model.TodoEventCreated, func(ctx context.Context) (*model.Todo, error) {
+			return uc.todoRepo.Create(ctx, todo)
+		})
+		if err != nil {
+			return reject(err)
+		}
+		result.ID = created.ID
+		result.Resolution = model.SyncResolutionApplied
+		result.Todo = created
+		return result
+	case model.SyncOperationUpdate, model.SyncOperationPatch, model.SyncOperationDelete:
+	default:
+		return reject(fmt.Errorf("%w: unknown op %q", model.ErrInvalidArgument, change.Op))
+	}
+	if change.ID == "" || change.Version < 1 {
+		return reject(fmt.Errorf("%w: id and version are required for %s", model.ErrInvalidArgument, change.Op))
+	}
+
+	if change.Op == model.SyncOperationDelete {
+		err := trashTodo(model.WithExpectedVersion(ctx, change.Version), uc.txManager, uc.todoRepo, uc.outboxRepo, change.ID, change.Cascade)
+		if errors.Is(err, model.ErrNotFound) {
+			// 既にゴミ箱に移動または完全に削除されている場合は、削除を適用したものとする
+			err = nil
+		}
+		if err != nil {
+			return uc.resolveError(ctx, result, change.Version, err)
+		}
+		result.Resolution = model.SyncResolutionApplied
+		return result
+	}
+
+	current, err := uc.todoRepo.FindByID(ctx, change.ID)
+	if err != nil {
+		return reject(err)
+	}
+	var todo model.Todo
+	expectedVersion := change.Version
+	result.Resolution = model.SyncResolutionApplied
+	switch change.Op {
+	case model.SyncOperationUpdate:
+		if change.Todo == nil {
+			return reject(fmt.Errorf("%w: todo is required for %s", model.ErrInvalidArgument, change.Op))
+		}
+		todo = *change.Todo
+	case model.SyncOperationPatch:
+		if change.Patch == nil {
+			return reject(fmt.Errorf("%w: patch is required for %s", model.ErrInvalidArgument, change.Op))
+		}
+		if current.Version != change.Version {
+			fields, err := uc.conflictingFields(ctx, current, change)
+			if err != nil {
+				return reject(err)
+			}
+			if len(fields) > 0 {
+				result.Resolution = model.SyncResolutionConflict
+				result.Todo = current
+				result.ConflictingFields = fields
+				result.Err = fmt.Errorf("%w: %v changed on the server since version %d", model.ErrConflict, fields, change.Version)
+				return result
+			}
+			expectedVersion = current.Version
+			result.Resolution = model.SyncResolutionMerged
+		}
+		todo = change.Patch.Apply(*current)
+	}
+
+	updated, err := uc.update(model.WithExpectedVersion(ctx, expectedVersion), current, todo, "")
+	if err != nil {
+		return uc.resolveError(ctx, result, expectedVersion, err)
+	}
+	result.Todo = updated
+	return result
+}
+
+// conflictingFields は change の元にしたバージョンより後にサーバーで変更した項目のうち、部分更新で変更する項目を返す
+// 元にしたバージョンの変更履歴がない場合は、部分更新で変更する全ての項目を競合した項目とする
+func (uc *pushTodoChanges) conflictingFields(ctx context.Context, current *model.Todo, change model.TodoSyncChange) ([]string, error) {
+	if change.Version > current.Version {
+		return nil, fmt.Errorf("%w: version %d is newer than the current version %d", model.ErrInvalidArgument, change.Version, current.Version)
+	}
+	base, err := uc.historyRepo.FindByVersion(ctx, current.ID, change.Version)
+	if errors.Is(err, model.ErrNotFound) {
+		return change.Patch.Fields(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return change.Patch.ConflictingFields(&base.Snapshot, current), nil
+}
+
+// resolveError は変更を適用できなかったエラーを結果に変換する
+// サーバーのTodoが期待したバージョンから変更されていた場合は競合とし、現在のTodoを返す
+func (uc *pushTodoChanges) resolveError(ctx context.Context, result model.TodoSyncResult, expectedVersion int, err error) model.TodoSyncResult {
+	result.Resolution = model.SyncResolutionRejected
+	result.Err = err
+	if !errors.Is(err, model.ErrConflict) {
+		return result
+	}
+	if current, findErr := uc.todoRepo.FindByID(ctx, result.ID); findErr == nil && current.Version != expectedVersion {
+		result.Resolution = model.SyncResolutionConflict
+		result.Todo = current
+	}
+	return result
+}
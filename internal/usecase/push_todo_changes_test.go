@@ -0,0 +1,187 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_pushTodoChanges_Execute(t *testing.T) {
+	base := model.Todo{ID: "1", Title: "base", Content: "content", Status: model.TodoStatusTodo, Tags: []string{}, Timezone: model.DefaultTimezone, Version: 1}
+	current := base
+	current.Content = "changed on the server"
+	current.Version = 2
+
+	title := "changed offline"
+	content := "changed offline"
+	tests := []struct {
+		name            string
+		change          model.TodoSyncChange
+		wantResolution  model.SyncResolution
+		wantConflicting []string
+		wantTitle       string
+		wantErr         error
+	}{
+		{
+			name:           "patch of the current version is applied",
+			change:         model.TodoSyncChange{Op: model.SyncOperationPatch, ID: "1", Version: 2, Patch: &model.TodoPatch{Title: &title}},
+			wantResolution: model.SyncResolutionApplied,
+			wantTitle:      title,
+		},
+		{
+			name:           "patch of other fields than the server changed is merged",
+			change:         model.TodoSyncChange{Op: model.SyncOperationPatch, ID: "1", Version: 1, Patch: &model.TodoPatch{Title: &title}},
+			wantResolution: model.SyncResolutionMerged,
+			wantTitle:      title,
+		},
+		{
+			name:            "patch of a field the server changed conflicts",
+			change:          model.TodoSyncChange{Op: model.SyncOperationPatch, ID: "1", Version: 1, Patch: &model.TodoPatch{Title: &title, Content: &content}},
+			wantResolution:  model.SyncResolutionConflict,
+			wantConflicting: []string{"content"},
+			wantTitle:       "base",
+			wantErr:         model.ErrConflict,
+		},
+		{
+			name:           "update of an old version conflicts",
+			change:         model.TodoSyncChange{Op: model.SyncOperationUpdate, ID: "1", Version: 1, Todo: &model.Todo{Title: title}},
+			wantResolution: model.SyncResolutionConflict,
+			wantTitle:      "base",
+			wantErr:        model.ErrConflict,
+		},
+		{
+			name:           "delete of a todo already deleted on the server is applied",
+			change:         model.TodoSyncChange{Op: model.SyncOperationDelete, ID: "gone", Version: 1, Cascade: true},
+			wantResolution: model.SyncResolutionApplied,
+		},
+		{
+			name:           "change without version is rejected",
+			change:         model.TodoSyncChange{Op: model.SyncOperationPatch, ID: "1", Patch: &model.TodoPatch{Title: &title}},
+			wantResolution: model.SyncResolutionRejected,
+			wantErr:        model.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, id string) (*model.Todo, error) {
+					if id != current.ID {
+						return nil, model.ErrNotFound
+					}
+					todo := current
+					return &todo, nil
+				}).AnyTimes()
+			mockTodoRepo.EXPECT().
+				Update(gomock.Any(), current.ID, gomock.Any()).
+				DoAndReturn(func(_ context.Context, id string, todo model.Todo) (*model.Todo, error) {
+					todo.ID = id
+					todo.Version = current.Version + 1
+					return &todo, nil
+				}).AnyTimes()
+			mockHistoryRepo := mock_repository.NewMockTodoHistory(ctrl)
+			mockHistoryRepo.EXPECT().
+				FindByVersion(gomock.Any(), base.ID, base.Version).
+				Return(&model.TodoHistory{TodoID: base.ID, Version: base.Version, Snapshot: base}, nil).AnyTimes()
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			uc := usecase.NewPushTodoChanges(mockTxManager, mockTodoRepo, mock_repository.NewMockTodoDependency(ctrl), mockHistoryRepo, mockOutboxRepo)
+			results, err := uc.Execute(context.Background(), model.TodoSyncPush{Changes: []model.TodoSyncChange{tt.change}})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			got := results[0]
+			if got.Resolution != tt.wantResolution {
+				t.Errorf("Execute() resolution = %q, want %q (err = %v)", got.Resolution, tt.wantResolution, got.Err)
+			}
+			if diff := cmp.Diff(tt.wantConflicting, got.ConflictingFields); diff != "" {
+				t.Errorf("Execute() conflicting fields mismatch (-want +got):\n%s", diff)
+			}
+			var gotTitle string
+			if got.Todo != nil {
+				gotTitle = got.Todo.Title
+			}
+			if gotTitle != tt.wantTitle {
+				t.Errorf("Execute() todo title = %q, want %q", gotTitle, tt.wantTitle)
+			}
+			if !errors.Is(got.Err, tt.wantErr) {
+				t.Errorf("Execute() result error = %v, want %v", got.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_pushTodoChanges_Execute_createdIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := map[string]model.Todo{}
+	mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+	mockTodoRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, todo model.Todo) (*model.Todo, error) {
+			todo.ID = "server-" + todo.Title
+			todo.Version = 1
+			created[todo.ID] = todo
+			return &todo, nil
+		}).Times(2)
+	mockTodoRepo.EXPECT().
+		FindByID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string) (*model.Todo, error) {
+			todo, ok := created[id]
+			if !ok {
+				return nil, model.ErrNotFound
+			}
+			return &todo, nil
+		}).AnyTimes()
+	mockTxManager := mock_repository.NewMockTxManager(ctrl)
+	mockTxManager.EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+	mockOutboxRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+
+	// 親にクライアントのIDを指定した子は、同じ送信で作成した親のIDに置き換えて作成する
+	parentID := "local-parent"
+	uc := usecase.NewPushTodoChanges(mockTxManager, mockTodoRepo, mock_repository.NewMockTodoDependency(ctrl),
+		mock_repository.NewMockTodoHistory(ctrl), mockOutboxRepo)
+	results, err := uc.Execute(context.Background(), model.TodoSyncPush{Changes: []model.TodoSyncChange{
+		{ClientID: parentID, Op: model.SyncOperationCreate, Todo: &model.Todo{Title: "parent"}},
+		{ClientID: "local-child", Op: model.SyncOperationCreate, Todo: &model.Todo{Title: "child", ParentID: &parentID}},
+	}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, result := range results {
+		if result.Resolution != model.SyncResolutionApplied {
+			t.Fatalf("Execute() result %s = %q (err = %v), want applied", result.ClientID, result.Resolution, result.Err)
+		}
+	}
+	if got := results[1].Todo.ParentID; got == nil || *got != "server-parent" {
+		t.Errorf("Execute() child parent_id = %v, want server-parent", got)
+	}
+}
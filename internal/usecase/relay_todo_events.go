@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+const (
+	// relayBatchSize は1回の実行で配信するイベントの最大件数
+	relayBatchSize = 100
+	// relayMinBackoff は配信に失敗したイベントを再試行するまでの最初の間隔
+	relayMinBackoff = time.Second
+	// relayMaxBackoff は配信に失敗したイベントを再試行するまでの間隔の上限
+	relayMaxBackoff = 5 * time.Minute
+)
+
+// RelayTodoEvents はアウトボックスに保存したイベントを配信するユースケースを表すインターフェース
+type RelayTodoEvents interface {
+	Execute(ctx context.Context, now time.Time) (int, error)
+}
+
+// relayTodoEvents は usecase.RelayTodoEvents の実装
+type relayTodoEvents struct {
+	outboxRepo repository.Outbox
+	publisher  repository.EventPublisher
+}
+
+// NewRelayTodoEvents は usecase.RelayTodoEvents のコンストラクタ
+func NewRelayTodoEvents(outboxRepo repository.Outbox, publisher repository.EventPublisher) RelayTodoEvents {
+	return &relayTodoEvents{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+	}
+}
+
+// Execute は now までに配信を試みる日時になったイベントを保存した順に配信し、配信した件数を返す
+// 配信したイベントはアウトボックスから削除するため、削除する前に失敗した場合は再度配信する (at-least-once)
+// 配信に失敗したイベントは失敗した回数に応じて間隔を空けて再試行し、それまで同じTodoの以降のイベントは配信しない
+// 配信に失敗したイベントがある場合は、再試行を予約した上でそれらのエラーを返す
+func (uc *relayTodoEvents) Execute(ctx context.Context, now time.Time) (int, error) {
+	messages, err := uc.outboxRepo.FindPending(ctx, now, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	var errs []error
+	for _, m := range messages {
+		if blocked[m.Event.TodoID] {
+			continue
+		}
+		if err := uc.publisher.Publish(ctx, m.Event); err != nil {
+			blocked[m.Event.TodoID] = true
+			errs = append(errs, fmt.Errorf("event %s (%s): %w", m.Event.ID, m.Event.Type, err))
+			if err := uc.outboxRepo.MarkFailed(ctx, m.Seq, err.Error(), now.Add(relayBackoff(m.Attempts+1))); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := uc.outboxRepo.Delete(ctx, m.Seq); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, errors.Join(errs...)
+}
+
+// relayBackoff は attempts 回目の失敗の後に再試行するまでの間隔を返す (失敗するたびに倍にし、relayMaxBackoff を上限とする)
+func relayBackoff(attempts int) time.Duration {
+	d := relayMinBackoff
+	for range attempts - 1 {
+		d *= 2
+		if d >= relayMaxBackoff {
+			return relayMaxBackoff
+		}
+	}
+	return d
+}
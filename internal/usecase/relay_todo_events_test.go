@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_relayTodoEvents_Execute(t *testing.T) {
+	now := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	errUnavailable := errors.New("sink unavailable")
+	messages := []model.OutboxMessage{
+		{Seq: 1, Event: model.TodoEvent{ID: "e1", Type: model.TodoEventCreated, TodoID: "a"}},
+		{Seq: 2, Event: model.TodoEvent{ID: "e2", Type: model.TodoEventCreated, TodoID: "b"}, Attempts: 3},
+		{Seq: 3, Event: model.TodoEvent{ID: "e3", Type: model.TodoEventUpdated, TodoID: "b"}},
+		{Seq: 4, Event: model.TodoEvent{ID: "e4", Type: model.TodoEventUpdated, TodoID: "a"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+	mockOutboxRepo.EXPECT().
+		FindPending(gomock.Any(), now, gomock.Any()).
+		Return(messages, nil)
+	var deleted []int64
+	mockOutboxRepo.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, seq int64) error {
+			deleted = append(deleted, seq)
+			return nil
+		}).AnyTimes()
+	// 4回目の失敗のため、1秒の間隔を3回倍にした8秒後に再試行する
+	mockOutboxRepo.EXPECT().
+		MarkFailed(gomock.Any(), int64(2), errUnavailable.Error(), now.Add(8*time.Second)).
+		Return(nil)
+
+	var published []string
+	mockPublisher := mock_repository.NewMockEventPublisher(ctrl)
+	mockPublisher.EXPECT().
+		Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, event model.TodoEvent) error {
+			if event.ID == "e2" {
+				return errUnavailable
+			}
+			published = append(published, event.ID)
+			return nil
+		}).AnyTimes()
+
+	uc := usecase.NewRelayTodoEvents(mockOutboxRepo, mockPublisher)
+	n, err := uc.Execute(context.Background(), now)
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("Execute() error = %v, want %v", err, errUnavailable)
+	}
+	if n != 2 {
+		t.Errorf("Execute() published = %d, want 2", n)
+	}
+	// 配信に失敗したTodoの以降のイベントは配信しない
+	if diff := cmp.Diff([]string{"e1", "e4"}, published); diff != "" {
+		t.Errorf("Publish() events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{1, 4}, deleted); diff != "" {
+		t.Errorf("Delete() seqs mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// RemoveTodoDependency はTodoの依存関係を削除するユースケースを表すインターフェース
+type RemoveTodoDependency interface {
+	Execute(ctx context.Context, id string, blockerID string) error
+}
+
+// removeTodoDependency は usecase.RemoveTodoDependency の実装
+type removeTodoDependency struct {
+	txManager      repository.TxManager
+	todoRepo       repository.Todo
+	dependencyRepo repository.TodoDependency
+	outboxRepo     repository.Outbox
+}
+
+// NewRemoveTodoDependency は usecase.RemoveTodoDependency のコンストラクタ
+func NewRemoveTodoDependency(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) RemoveTodoDependency {
+	return &removeTodoDependency{
+		txManager:      txManager,
+		todoRepo:       todoRepo,
+		dependencyRepo: dependencyRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+// Execute は id のTodoが blockerID のTodoにブロックされる依存関係を削除する
+// 削除はTodoの更新として、同じトランザクションで変更履歴とイベントを記録する
+func (uc *removeTodoDependency) Execute(ctx context.Context, id string, blockerID string) error {
+	return uc.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := uc.dependencyRepo.Lock(ctx); err != nil {
+			return err
+		}
+		todo, err := uc.todoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := todo.CheckWritable(); err != nil {
+			return err
+		}
+
+		if err := uc.dependencyRepo.Remove(ctx, id, blockerID); err != nil {
+			return err
+		}
+
+		_, err = touchTodos(ctx, uc.todoRepo, uc.outboxRepo, []model.Todo{*todo})
+		return err
+	})
+}
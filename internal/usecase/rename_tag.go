@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// RenameTag はタグ名を変更するユースケースを表すインターフェース
+type RenameTag interface {
+	Execute(ctx context.Context, id string, name string) (*model.Tag, error)
+}
+
+// renameTag は usecase.RenameTag の実装
+type renameTag struct {
+	txManager  repository.TxManager
+	tagRepo    repository.Tag
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
+}
+
+// NewRenameTag は usecase.RenameTag のコンストラクタ
+func NewRenameTag(txManager repository.TxManager, tagRepo repository.Tag, todoRepo repository.Todo, outboxRepo repository.Outbox) RenameTag {
+	return &renameTag{
+		txManager:  txManager,
+		tagRepo:    tagRepo,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute はタグ名を変更する
+// 変更後の名前のタグが既に存在する場合は model.ErrConflict を返す (統合には MergeTags を使う)
+// タグが付与されたTodoは、同じトランザクションで更新として記録する
+func (uc *renameTag) Execute(ctx context.Context, id string, name string) (*model.Tag, error) {
+	name, err := model.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag *model.Tag
+	err = changeTag(ctx, uc.txManager, uc.tagRepo, uc.todoRepo, uc.outboxRepo, id, func(ctx context.Context) error {
+		var err error
+		tag, err = uc.tagRepo.Rename(ctx, id, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// ReorderTodoChildren は子のTodoを並び替えるユースケースを表すインターフェース
+type ReorderTodoChildren interface {
+	Execute(ctx context.Context, id string, childIDs []string) ([]model.Todo, error)
+}
+
+// reorderTodoChildren は usecase.ReorderTodoChildren の実装
+type reorderTodoChildren struct {
+	todoRepo repository.Todo
+}
+
+// NewReorderTodoChildren は usecase.ReorderTodoChildren のコンストラクタ
+func NewReorderTodoChildren(todoRepo repository.Todo) ReorderTodoChildren {
+	return &reorderTodoChildren{
+		todoRepo: todoRepo,
+	}
+}
+
+// Execute は子のTodoを childIDs の順に並び替え、並び替え後の子のTodoを返す
+// childIDs には全ての子のIDを過不足なく指定する必要がある
+func (uc *reorderTodoChildren) Execute(ctx context.Context, id string, childIDs []string) ([]model.Todo, error) {
+	parent, err := uc.todoRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := parent.CheckWritable(); err != nil {
+		return nil, err
+	}
+	children, err := uc.todoRepo.FindChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentIDs := make([]string, 0, len(children))
+	for _, child := range children {
+		currentIDs = append(currentIDs, child.ID)
+	}
+	sortedIDs := slices.Sorted(slices.Values(childIDs))
+	slices.Sort(currentIDs)
+	if !slices.Equal(currentIDs, sortedIDs) {
+		return nil, fmt.Errorf("%w: ids must contain every child exactly once", model.ErrInvalidArgument)
+	}
+
+	if err := uc.todoRepo.ReorderChildren(ctx, id, childIDs); err != nil {
+		return nil, err
+	}
+
+	return uc.todoRepo.FindChildren(ctx, id)
+}
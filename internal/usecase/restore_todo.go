@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// RestoreTodo はゴミ箱にあるTodoを元に戻すユースケースを表すインターフェース
+type RestoreTodo interface {
+	Execute(ctx context.Context, id string) (*model.Todo, error)
+}
+
+// restoreTodo は usecase.RestoreTodo の実装
+type restoreTodo struct {
+	txManager      repository.TxManager
+	todoRepo       repository.Todo
+	dependencyRepo repository.TodoDependency
+	outboxRepo     repository.Outbox
+}
+
+// NewRestoreTodo は usecase.RestoreTodo のコンストラクタ
+func NewRestoreTodo(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) RestoreTodo {
+	return &restoreTodo{
+		txManager:      txManager,
+		todoRepo:       todoRepo,
+		dependencyRepo: dependencyRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+// Execute はゴミ箱にあるTodoを、一緒にゴミ箱に移動した子孫のTodoも含めて元に戻す
+// 親のTodoがゴミ箱にある場合は先に親を元に戻す必要がある
+// 元に戻すことで依存関係の循環が有効になる場合は model.ErrConflict を返す (検証は依存関係のロックを取得して行う)
+func (uc *restoreTodo) Execute(ctx context.Context, id string) (*model.Todo, error) {
+	return writeTodo(ctx, uc.txManager, uc.outboxRepo, model.TodoEventUpdated, func(ctx context.Context) (*model.Todo, error) {
+		if err := uc.dependencyRepo.Lock(ctx); err != nil {
+			return nil, err
+		}
+		if err := checkNoDependencyCycleOnRestore(ctx, uc.dependencyRepo, id); err != nil {
+			return nil, err
+		}
+		return uc.todoRepo.Restore(ctx, id)
+	})
+}
@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_restoreTodo_Execute(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies map[string][]string
+		wantRestore  bool
+		wantErr      error
+	}{
+		{
+			name:         "no cycle",
+			dependencies: map[string][]string{"1": {"2"}, "2": {"3"}},
+			wantRestore:  true,
+		},
+		{
+			// 1 (ゴミ箱) は 2 に、2 は 3 に、3 は 1 にブロックされているため、1 を元に戻すと循環が有効になる
+			name:         "cycle through trashed todo",
+			dependencies: map[string][]string{"1": {"2"}, "2": {"3"}, "3": {"1"}},
+			wantErr:      model.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			if tt.wantRestore {
+				mockTodoRepo.EXPECT().Restore(gomock.Any(), "1").Return(&model.Todo{ID: "1"}, nil)
+			}
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockDependencyRepo.EXPECT().Lock(gomock.Any()).Return(nil)
+			mockDependencyRepo.EXPECT().
+				FindAllBlockerIDs(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string) ([]string, error) {
+					return tt.dependencies[id], nil
+				}).AnyTimes()
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			if tt.wantRestore {
+				mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Len(1)).Return(nil)
+			}
+
+			uc := usecase.NewRestoreTodo(mockTxManager, mockTodoRepo, mockDependencyRepo, mockOutboxRepo)
+			_, gotErr := uc.Execute(context.Background(), "1")
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// RevertTodo はTodoを以前のバージョンの内容に戻すユースケースを表すインターフェース
+type RevertTodo interface {
+	Execute(ctx context.Context, id string, version int) (*model.Todo, error)
+}
+
+// revertTodo は usecase.RevertTodo の実装
+type revertTodo struct {
+	todoUpdater
+	historyRepo repository.TodoHistory
+}
+
+// NewRevertTodo は usecase.RevertTodo のコンストラクタ
+func NewRevertTodo(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, historyRepo repository.TodoHistory, outboxRepo repository.Outbox) RevertTodo {
+	return &revertTodo{
+		todoUpdater: todoUpdater{
+			txManager:      txManager,
+			todoRepo:       todoRepo,
+			dependencyRepo: dependencyRepo,
+			outboxRepo:     outboxRepo,
+		},
+		historyRepo: historyRepo,
+	}
+}
+
+// Execute はTodoを version の変更後の内容に戻す
+// 通常の更新と同じ検証 (ステータス遷移、ブロック、親子関係など) を行い、戻した結果も新しいバージョンとして記録される
+func (uc *revertTodo) Execute(ctx context.Context, id string, version int) (*model.Todo, error) {
+	current, err := uc.todoRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version >= current.Version {
+		return nil, fmt.Errorf("%w: version must be older than the current version %d", model.ErrInvalidArgument, current.Version)
+	}
+	history, err := uc.historyRepo.FindByVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := history.Snapshot
+	// parent_id は nil の場合に現在の親を維持するため、ルートに戻す場合は空文字を指定する
+	parentID := snapshot.ParentID
+	if parentID == nil {
+		parentID = new(string)
+	}
+	todo := model.Todo{
+		Title:        snapshot.Title,
+		Content:      snapshot.Content,
+		Status:       snapshot.Status,
+		Priority:     snapshot.Priority,
+		Tags:         snapshot.Tags,
+		ParentID:     parentID,
+		AutoComplete: snapshot.AutoComplete,
+		DueAt:        snapshot.DueAt,
+		Recurrence:   snapshot.Recurrence,
+		Timezone:     snapshot.Timezone,
+	}
+
+	return uc.update(ctx, current, todo, model.RecurrenceScopeThisAndFuture)
+}
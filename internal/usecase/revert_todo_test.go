@@ -0,0 +1,120 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_revertTodo_Execute(t *testing.T) {
+	parentID := "parent"
+	current := model.Todo{
+		ID:       "1",
+		Title:    "after",
+		Status:   model.TodoStatusInProgress,
+		Priority: 2,
+		Tags:     []string{"tag"},
+		ParentID: &parentID,
+		Timezone: model.DefaultTimezone,
+		Version:  3,
+	}
+	snapshot := model.Todo{
+		ID:       "1",
+		Title:    "before",
+		Content:  "content",
+		Status:   model.TodoStatusTodo,
+		Priority: 1,
+		Tags:     []string{},
+		Timezone: model.DefaultTimezone,
+		Version:  1,
+	}
+
+	tests := []struct {
+		name       string
+		version    int
+		history    *model.TodoHistory
+		historyErr error
+		wantUpdate *model.Todo
+		wantErr    error
+	}{
+		{
+			name:    "reverts to snapshot",
+			version: 1,
+			history: &model.TodoHistory{TodoID: "1", Version: 1, Snapshot: snapshot},
+			wantUpdate: &model.Todo{
+				Title:    "before",
+				Content:  "content",
+				Status:   model.TodoStatusTodo,
+				Priority: 1,
+				Tags:     []string{},
+				ParentID: nil,
+				Timezone: model.DefaultTimezone,
+				Version:  3,
+			},
+		},
+		{
+			name:    "current version",
+			version: 3,
+			wantErr: model.ErrInvalidArgument,
+		},
+		{
+			name:       "version without history",
+			version:    2,
+			historyErr: model.ErrNotFound,
+			wantErr:    model.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockHistoryRepo := mock_repository.NewMockTodoHistory(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), current.ID).
+				Return(&current, nil)
+			if tt.history != nil || tt.historyErr != nil {
+				mockHistoryRepo.EXPECT().
+					FindByVersion(gomock.Any(), current.ID, tt.version).
+					Return(tt.history, tt.historyErr)
+			}
+			if tt.wantUpdate != nil {
+				mockTodoRepo.EXPECT().
+					Update(gomock.Any(), current.ID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, id string, todo model.Todo) (*model.Todo, error) {
+						if diff := cmp.Diff(*tt.wantUpdate, todo); diff != "" {
+							t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+						}
+						todo.ID = id
+						return &todo, nil
+					})
+			}
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			uc := usecase.NewRevertTodo(mockTxManager, mockTodoRepo, mockDependencyRepo, mockHistoryRepo, mockOutboxRepo)
+			_, gotErr := uc.Execute(context.Background(), current.ID, tt.version)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// SendTestWebhook はWebhookにテストイベントを送信するユースケースを表すインターフェース
+type SendTestWebhook interface {
+	Execute(ctx context.Context, id string) (*model.WebhookDelivery, error)
+}
+
+// sendTestWebhook は usecase.SendTestWebhook の実装
+type sendTestWebhook struct {
+	webhookRepo         repository.Webhook
+	webhookDeliveryRepo repository.WebhookDelivery
+	sender              repository.WebhookSender
+}
+
+// NewSendTestWebhook は usecase.SendTestWebhook のコンストラクタ
+func NewSendTestWebhook(webhookRepo repository.Webhook, webhookDeliveryRepo repository.WebhookDelivery, sender repository.WebhookSender) SendTestWebhook {
+	return &sendTestWebhook{
+		webhookRepo:         webhookRepo,
+		webhookDeliveryRepo: webhookDeliveryRepo,
+		sender:              sender,
+	}
+}
+
+// Execute は model.WebhookEventTest のイベントの配信を作成してすぐに送信し、その結果の配信を返す
+// 送信に失敗した場合は、通常の配信と同様に再試行する
+func (uc *sendTestWebhook) Execute(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	webhook, err := uc.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	info := model.AuditInfoFromContext(ctx)
+	event := model.TodoEvent{
+		ID:         uuid.New().String(),
+		Type:       model.WebhookEventTest,
+		Actor:      info.Actor,
+		TraceID:    info.TraceID,
+		OccurredAt: now,
+	}
+	delivery, err := model.NewWebhookDelivery(webhook.ID, event, now)
+	if err != nil {
+		return nil, err
+	}
+	created, err := uc.webhookDeliveryRepo.Create(ctx, *delivery)
+	if err != nil {
+		return nil, err
+	}
+	return deliverWebhook(ctx, uc.sender, uc.webhookDeliveryRepo, *webhook, *created, now)
+}
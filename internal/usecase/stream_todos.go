@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// streamBatchSize は検索クエリに一致するTodoを取得し直す前にまとめて受け取るイベントの最大件数
+const streamBatchSize = 100
+
+// StreamTodos はTodoの変更を購読するユースケースを表すインターフェース
+type StreamTodos interface {
+	Execute(ctx context.Context, query model.TodoQuery, lastEventID string) (*TodoStream, error)
+}
+
+// TodoStream はTodoの変更の購読を表す
+type TodoStream struct {
+	// Events は購読者に配信するイベント (購読が終了すると閉じる)
+	Events <-chan model.TodoStreamEvent
+	err    error
+}
+
+// Err は購読がエラーで終了した場合にそのエラーを返す (Events が閉じた後に呼び出す)
+func (s *TodoStream) Err() error {
+	return s.err
+}
+
+// streamTodos は usecase.StreamTodos の実装
+type streamTodos struct {
+	todoRepo repository.Todo
+	stream   repository.TodoEventStream
+}
+
+// NewStreamTodos は usecase.StreamTodos のコンストラクタ
+func NewStreamTodos(todoRepo repository.Todo, stream repository.TodoEventStream) StreamTodos {
+	return &streamTodos{
+		todoRepo: todoRepo,
+		stream:   stream,
+	}
+}
+
+// Execute は ctx がキャンセルされるまでTodoの変更を購読する
+// イベントが発生した後に検索クエリに一致するTodoのイベントと、一致しなくなったTodoのイベントを配信する (後者は Match を false にする)
+// lastEventID を指定した場合はそのイベントより後のイベントから配信し、再開できない場合は最初に model.TodoEventStreamReset を配信する
+// 受け取りが遅れて購読が終了した場合は Events を閉じる (受信側は最後に受け取ったイベントのIDを指定して再開する)
+func (uc *streamTodos) Execute(ctx context.Context, query model.TodoQuery, lastEventID string) (*TodoStream, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	reset := false
+	backlog, events, err := uc.stream.Subscribe(ctx, lastEventID)
+	if errors.Is(err, model.ErrEventExpired) {
+		reset = true
+		backlog, events, err = uc.stream.Subscribe(ctx, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 購読を開始した後に取得するため、取得した結果に反映されていない変更は必ずイベントとして受け取る
+	matched, err := uc.findMatched(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan model.TodoStreamEvent)
+	s := &TodoStream{Events: out}
+	go func() {
+		defer close(out)
+		if reset && !sendStreamEvent(ctx, out, model.TodoStreamEvent{TodoEvent: model.TodoEvent{Type: model.TodoEventStreamReset}}) {
+			return
+		}
+		// 再開するまでに発生したイベントは、その時点で一覧に含まれていたかが分からないため全て配信する
+		for _, e := range backlog {
+			if !sendStreamEvent(ctx, out, model.TodoStreamEvent{TodoEvent: e, Match: matched[e.TodoID]}) {
+				return
+			}
+		}
+		for {
+			batch, ok := receiveEvents(ctx, events)
+			if !ok {
+				return
+			}
+			next, err := uc.findMatched(ctx, query)
+			if err != nil {
+				s.err = err
+				return
+			}
+			for _, e := range batch {
+				if !next[e.TodoID] && !matched[e.TodoID] {
+					continue
+				}
+				if !sendStreamEvent(ctx, out, model.TodoStreamEvent{TodoEvent: e, Match: next[e.TodoID]}) {
+					return
+				}
+			}
+			matched = next
+		}
+	}()
+	return s, nil
+}
+
+// findMatched は検索クエリに一致するTodoのIDの集合を返す (期限による絞り込みは実行した時点の日時を基準に解決する)
+func (uc *streamTodos) findMatched(ctx context.Context, query model.TodoQuery) (map[string]bool, error) {
+	query.ResolveDue(time.Now())
+	todos, err := uc.todoRepo.FindAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool, len(todos))
+	for _, t := range todos {
+		matched[t.ID] = true
+	}
+	return matched, nil
+}
+
+// receiveEvents はイベントを1件受け取るまで待ち、続けて受け取れるイベントを最大 streamBatchSize 件まで返す
+// 購読が終了した場合は false を返す
+func receiveEvents(ctx context.Context, events <-chan model.TodoEvent) ([]model.TodoEvent, bool) {
+	var batch []model.TodoEvent
+	select {
+	case e, ok := <-events:
+		if !ok {
+			return nil, false
+		}
+		batch = append(batch, e)
+	case <-ctx.Done():
+		return nil, false
+	}
+	for len(batch) < streamBatchSize {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				// 受け取ったイベントを配信してから終了するため、次の呼び出しで false を返す
+				return batch, true
+			}
+			batch = append(batch, e)
+		default:
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+// sendStreamEvent はイベントを out に送る (ctx がキャンセルされた場合は false を返す)
+func sendStreamEvent(ctx context.Context, out chan<- model.TodoStreamEvent, event model.TodoStreamEvent) bool {
+	select {
+	case out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
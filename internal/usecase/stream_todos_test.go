@@ -0,0 +1,168 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+// streamEvent はイベントの種類、TodoのID、検索クエリに一致するかの組
+type streamEvent struct {
+	Type   model.TodoEventType
+	TodoID string
+	Match  bool
+}
+
+// closedEvents は events を送った後に閉じたチャンネルを返す
+func closedEvents(events ...model.TodoEvent) <-chan model.TodoEvent {
+	ch := make(chan model.TodoEvent, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
+// todosWithIDs はIDのみを設定したTodoの一覧を返す
+func todosWithIDs(ids ...string) []model.Todo {
+	var ts []model.Todo
+	for _, id := range ids {
+		ts = append(ts, model.Todo{ID: id})
+	}
+	return ts
+}
+
+// collectStreamEvents は購読が終了するまでイベントを受け取る
+func collectStreamEvents(s *usecase.TodoStream) []streamEvent {
+	got := []streamEvent{}
+	for e := range s.Events {
+		got = append(got, streamEvent{e.Type, e.TodoID, e.Match})
+	}
+	return got
+}
+
+func Test_streamTodos_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		setup       func(stream *mock_repository.MockTodoEventStream, todoRepo *mock_repository.MockTodo)
+		want        []streamEvent
+		wantErr     error
+	}{
+		{
+			name: "delivers todos entering and leaving the query",
+			setup: func(stream *mock_repository.MockTodoEventStream, todoRepo *mock_repository.MockTodo) {
+				stream.EXPECT().Subscribe(gomock.Any(), "").Return(nil, closedEvents(
+					model.TodoEvent{Type: model.TodoEventUpdated, TodoID: "left"},
+					model.TodoEvent{Type: model.TodoEventUpdated, TodoID: "unrelated"},
+					model.TodoEvent{Type: model.TodoEventCreated, TodoID: "entered"},
+					model.TodoEvent{Type: model.TodoEventUpdated, TodoID: "kept"},
+				), nil)
+				gomock.InOrder(
+					todoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(todosWithIDs("left", "kept"), nil),
+					todoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(todosWithIDs("entered", "kept"), nil),
+				)
+			},
+			want: []streamEvent{
+				{model.TodoEventUpdated, "left", false},
+				{model.TodoEventCreated, "entered", true},
+				{model.TodoEventUpdated, "kept", true},
+			},
+		},
+		{
+			name:        "resumes with every retained event",
+			lastEventID: "e1",
+			setup: func(stream *mock_repository.MockTodoEventStream, todoRepo *mock_repository.MockTodo) {
+				stream.EXPECT().Subscribe(gomock.Any(), "e1").Return([]model.TodoEvent{
+					{Type: model.TodoEventDeleted, TodoID: "deleted"},
+					{Type: model.TodoEventUpdated, TodoID: "kept"},
+				}, closedEvents(), nil)
+				todoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(todosWithIDs("kept"), nil)
+			},
+			want: []streamEvent{
+				{model.TodoEventDeleted, "deleted", false},
+				{model.TodoEventUpdated, "kept", true},
+			},
+		},
+		{
+			name:        "resets when the last event has expired",
+			lastEventID: "expired",
+			setup: func(stream *mock_repository.MockTodoEventStream, todoRepo *mock_repository.MockTodo) {
+				gomock.InOrder(
+					stream.EXPECT().Subscribe(gomock.Any(), "expired").Return(nil, nil, model.ErrEventExpired),
+					stream.EXPECT().Subscribe(gomock.Any(), "").Return(nil, closedEvents(), nil),
+				)
+				todoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			want: []streamEvent{
+				{Type: model.TodoEventStreamReset},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStream := mock_repository.NewMockTodoEventStream(ctrl)
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			tt.setup(mockStream, mockTodoRepo)
+
+			uc := usecase.NewStreamTodos(mockTodoRepo, mockStream)
+			s, err := uc.Execute(context.Background(), model.TodoQuery{}, tt.lastEventID)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, collectStreamEvents(s)); diff != "" {
+				t.Errorf("events mismatch (-want +got):\n%s", diff)
+			}
+			if err := s.Err(); err != nil {
+				t.Errorf("Err() = %v", err)
+			}
+		})
+	}
+}
+
+func Test_streamTodos_Execute_findError(t *testing.T) {
+	errFind := errors.New("find failed")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStream := mock_repository.NewMockTodoEventStream(ctrl)
+	mockStream.EXPECT().Subscribe(gomock.Any(), "").Return(nil, closedEvents(model.TodoEvent{Type: model.TodoEventCreated, TodoID: "t1"}), nil)
+	mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+	gomock.InOrder(
+		mockTodoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mockTodoRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, errFind),
+	)
+
+	uc := usecase.NewStreamTodos(mockTodoRepo, mockStream)
+	s, err := uc.Execute(context.Background(), model.TodoQuery{}, "")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := collectStreamEvents(s); len(got) != 0 {
+		t.Errorf("events = %v, want none", got)
+	}
+	if err := s.Err(); !errors.Is(err, errFind) {
+		t.Errorf("Err() = %v, want %v", err, errFind)
+	}
+}
+
+func Test_streamTodos_Execute_invalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := usecase.NewStreamTodos(mock_repository.NewMockTodo(ctrl), mock_repository.NewMockTodoEventStream(ctrl))
+	if _, err := uc.Execute(context.Background(), model.TodoQuery{Status: "unknown"}, ""); !errors.Is(err, model.ErrInvalidArgument) {
+		t.Errorf("Execute() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// changeTag は id のタグが付与されたTodoを取得してから change でタグを変更し、それらのTodoの更新として変更履歴とイベントを記録する
+// タグ名はTodoの内容に含まれるため、タグの変更でもTodoのバージョンと更新日時を更新して ETag や差分同期に反映する
+func changeTag(ctx context.Context, txManager repository.TxManager, tagRepo repository.Tag, todoRepo repository.Todo, outboxRepo repository.Outbox, id string, change func(ctx context.Context) error) error {
+	return txManager.Do(ctx, func(ctx context.Context) error {
+		tag, err := tagRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		todos, err := todoRepo.FindAll(ctx, model.TodoQuery{
+			Tags:     []string{tag.Name},
+			TagMatch: model.TagMatchAny,
+			Archived: model.ArchivedFilterAny,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		_, err = touchTodos(ctx, todoRepo, outboxRepo, todos)
+		return err
+	})
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// findOpenBlockerIDs は id のTodoをブロックしているTodoのうち、完了または中止になっていないもののIDを返す
+func findOpenBlockerIDs(ctx context.Context, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, id string) ([]string, error) {
+	blockerIDs, err := dependencyRepo.FindBlockerIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var openIDs []string
+	for _, blockerID := range blockerIDs {
+		blocker, err := todoRepo.FindByID(ctx, blockerID)
+		if err != nil {
+			return nil, err
+		}
+		if !blocker.Status.IsClosed() {
+			openIDs = append(openIDs, blockerID)
+		}
+	}
+	return openIDs, nil
+}
+
+// checkNotBlocked は id のTodoをブロックしているTodoが全て完了または中止になっているかを検証する
+func checkNotBlocked(ctx context.Context, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, id string) error {
+	openIDs, err := findOpenBlockerIDs(ctx, todoRepo, dependencyRepo, id)
+	if err != nil {
+		return err
+	}
+	if len(openIDs) > 0 {
+		return fmt.Errorf("%w: todo is blocked by open todos %v", model.ErrConflict, openIDs)
+	}
+	return nil
+}
+
+// checkNoDependencyCycle は todoID が blockerID にブロックされる依存関係を追加しても循環しないかを検証する
+// blockerID から blocked-by を辿って todoID に到達する場合は循環となる
+// ゴミ箱にあるTodoも元に戻すと依存関係が有効になるため、ゴミ箱にあるTodoを経由する依存関係も辿る
+func checkNoDependencyCycle(ctx context.Context, dependencyRepo repository.TodoDependency, todoID, blockerID string) error {
+	cyclic, err := reachesDependency(ctx, dependencyRepo, []string{blockerID}, todoID)
+	if err != nil {
+		return err
+	}
+	if cyclic {
+		return fmt.Errorf("%w: dependency on %s would create a cycle", model.ErrInvalidArgument, blockerID)
+	}
+	return nil
+}
+
+// checkNoDependencyCycleOnRestore はゴミ箱にある id のTodoを元に戻しても、依存関係が循環しないかを検証する
+// (循環を検証せずに追加された依存関係が残っている場合に、元に戻すことで循環が有効になることを防ぐ)
+func checkNoDependencyCycleOnRestore(ctx context.Context, dependencyRepo repository.TodoDependency, id string) error {
+	blockerIDs, err := dependencyRepo.FindAllBlockerIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	cyclic, err := reachesDependency(ctx, dependencyRepo, blockerIDs, id)
+	if err != nil {
+		return err
+	}
+	if cyclic {
+		return fmt.Errorf("%w: restoring todo %s would activate a dependency cycle", model.ErrConflict, id)
+	}
+	return nil
+}
+
+// reachesDependency は fromIDs のTodoから blocked-by を辿って targetID のTodoに到達するかを返す (ゴミ箱にあるTodoも辿る)
+func reachesDependency(ctx context.Context, dependencyRepo repository.TodoDependency, fromIDs []string, targetID string) (bool, error) {
+	visited := map[string]bool{}
+	queue := slices.Clone(fromIDs)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == targetID {
+			return true, nil
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		next, err := dependencyRepo.FindAllBlockerIDs(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		queue = append(queue, next...)
+	}
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// recordEvents はイベントを ctx のトランザクションでアウトボックスに保存する
+func recordEvents(ctx context.Context, outboxRepo repository.Outbox, events ...model.TodoEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return outboxRepo.Add(ctx, events)
+}
+
+// writeTodo は write で書き込んだTodoの eventType のイベントを、書き込みと同じトランザクションでアウトボックスに保存する
+func writeTodo(ctx context.Context, txManager repository.TxManager, outboxRepo repository.Outbox, eventType model.TodoEventType, write func(ctx context.Context) (*model.Todo, error)) (*model.Todo, error) {
+	var todo *model.Todo
+	err := txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if todo, err = write(ctx); err != nil {
+			return err
+		}
+		return recordEvents(ctx, outboxRepo, model.NewTodoEvent(ctx, eventType, todo))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// touchTodos はタグや依存関係の変更で内容が変わったTodoのバージョンと更新日時を更新し、更新のイベントをアウトボックスに保存する
+// befores には変更する前に ctx のトランザクションで取得したTodoを指定し、更新後のTodoを返す
+func touchTodos(ctx context.Context, todoRepo repository.Todo, outboxRepo repository.Outbox, befores []model.Todo) ([]model.Todo, error) {
+	if len(befores) == 0 {
+		return []model.Todo{}, nil
+	}
+	todos, err := todoRepo.Touch(ctx, befores)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeByID := make(map[string]*model.Todo, len(befores))
+	for i := range befores {
+		beforeByID[befores[i].ID] = &befores[i]
+	}
+	var events []model.TodoEvent
+	for i := range todos {
+		events = append(events, model.TodoUpdateEvents(ctx, beforeByID[todos[i].ID], &todos[i])...)
+	}
+	if err := recordEvents(ctx, outboxRepo, events...); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// resolveParentID はリクエストの parent_id から適用する親のIDを決定する
+// nil の場合は current を維持し、空文字の場合はルートに移動する
+func resolveParentID(current, parentID *string) *string {
+	if parentID == nil {
+		return current
+	}
+	if *parentID == "" {
+		return nil
+	}
+	return parentID
+}
+
+// equalID はnilを考慮してIDが等しいかを返す
+func equalID(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// checkParent は id のTodoを parentID の子にできるか (親がアーカイブされていないか、循環しないか、深さの上限を超えないか) を検証する
+// 新規作成の場合は id に空文字を指定する
+func checkParent(ctx context.Context, todoRepo repository.Todo, id string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	// 親から祖先を辿り、自身が含まれていれば循環となる
+	depth := 0
+	for ancestorID := parentID; ancestorID != nil; depth++ {
+		if *ancestorID == id {
+			return fmt.Errorf("%w: parent_id must not be the todo itself or its descendant", model.ErrInvalidArgument)
+		}
+		if depth >= model.MaxTodoDepth {
+			return fmt.Errorf("%w: todos cannot be nested deeper than %d", model.ErrInvalidArgument, model.MaxTodoDepth)
+		}
+		ancestor, err := todoRepo.FindByID(ctx, *ancestorID)
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("%w: parent todo %s does not exist", model.ErrInvalidArgument, *ancestorID)
+		} else if err != nil {
+			return err
+		}
+		if depth == 0 {
+			if err := ancestor.CheckWritable(); err != nil {
+				return err
+			}
+		}
+		ancestorID = ancestor.ParentID
+	}
+
+	height := 1
+	if id != "" {
+		var err error
+		if height, err = subtreeHeight(ctx, todoRepo, id); err != nil {
+			return err
+		}
+	}
+	if depth+height > model.MaxTodoDepth {
+		return fmt.Errorf("%w: todos cannot be nested deeper than %d", model.ErrInvalidArgument, model.MaxTodoDepth)
+	}
+	return nil
+}
+
+// subtreeHeight は id のTodoを根とする部分木の高さを返す (子がない場合は1)
+func subtreeHeight(ctx context.Context, todoRepo repository.Todo, id string) (int, error) {
+	children, err := todoRepo.FindChildren(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	height := 1
+	for _, child := range children {
+		h, err := subtreeHeight(ctx, todoRepo, child.ID)
+		if err != nil {
+			return 0, err
+		}
+		height = max(height, h+1)
+	}
+	return height, nil
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// startRecurrence は新規作成するTodoが繰り返しの場合に系列を開始する
+func startRecurrence(todo *model.Todo) {
+	todo.SeriesID = nil
+	todo.OccurrenceAt = nil
+	if !todo.IsRecurring() {
+		return
+	}
+	seriesID := uuid.New().String()
+	todo.SeriesID = &seriesID
+	todo.OccurrenceAt = todo.DueAt
+}
+
+// applyRecurrence は current から繰り返しの系列を引き継ぎ、scope に応じて todo の発生日時を決定する
+// 繰り返しのルールの変更は以降の発生にも影響するため scope が this_and_future の場合のみ許可する
+func applyRecurrence(current, todo *model.Todo, scope model.RecurrenceScope) error {
+	ruleChanged := todo.Recurrence != current.Recurrence || todo.Timezone != current.Timezone
+	if ruleChanged && (current.IsRecurring() || todo.IsRecurring()) && scope != model.RecurrenceScopeThisAndFuture {
+		return fmt.Errorf("%w: recurrence can only be changed with scope %q", model.ErrInvalidArgument, model.RecurrenceScopeThisAndFuture)
+	}
+
+	todo.SeriesID = current.SeriesID
+	if !todo.IsRecurring() {
+		todo.OccurrenceAt = nil
+		return nil
+	}
+	if todo.SeriesID == nil {
+		seriesID := uuid.New().String()
+		todo.SeriesID = &seriesID
+	}
+	if scope == model.RecurrenceScopeThisAndFuture || current.OccurrenceAt == nil {
+		// 以降の発生は新しい期限を起点に計算する
+		todo.OccurrenceAt = todo.DueAt
+	} else {
+		todo.OccurrenceAt = current.OccurrenceAt
+	}
+	return nil
+}
+
+// findLaterOccurrences は todo と同じ系列で、todo より後に発生するTodoを返す
+func findLaterOccurrences(ctx context.Context, todoRepo repository.Todo, todo *model.Todo) ([]model.Todo, error) {
+	if todo.SeriesID == nil || todo.OccurrenceAt == nil {
+		return nil, nil
+	}
+	occurrences, err := todoRepo.FindAll(ctx, model.TodoQuery{SeriesID: *todo.SeriesID, Archived: model.ArchivedFilterAny})
+	if err != nil {
+		return nil, err
+	}
+
+	var later []model.Todo
+	for _, o := range occurrences {
+		if o.ID != todo.ID && o.OccurrenceAt != nil && o.OccurrenceAt.After(*todo.OccurrenceAt) {
+			later = append(later, o)
+		}
+	}
+	return later, nil
+}
+
+// propagateRecurrence は todo の繰り返しのルールを、同じ系列の以降の未完了の発生に反映する
+// COUNT は次の発生を作成するたびに減らすため、各発生には todo からの発生の回数に応じて残りの COUNT を計算して反映する
+func propagateRecurrence(ctx context.Context, todoRepo repository.Todo, outboxRepo repository.Outbox, todo *model.Todo) error {
+	later, err := findLaterOccurrences(ctx, todoRepo, todo)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(later, func(a, b model.Todo) int {
+		return a.OccurrenceAt.Compare(*b.OccurrenceAt)
+	})
+	for i, o := range later {
+		if o.Status.IsClosed() {
+			continue
+		}
+		recurrence, err := model.AdvanceRecurrence(todo.Recurrence, i+1)
+		if err != nil {
+			return err
+		}
+		before := o
+		o.Recurrence = recurrence
+		o.Timezone = todo.Timezone
+		updated, err := todoRepo.Update(ctx, o.ID, o)
+		if err != nil {
+			return err
+		}
+		if err := recordEvents(ctx, outboxRepo, model.TodoUpdateEvents(ctx, &before, updated)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spawnNextOccurrence は完了または中止になった繰り返しのTodoの次の発生を作成する
+// 既に次の発生が作成されている場合や、ルール上次の発生がない場合は何もしない
+func spawnNextOccurrence(ctx context.Context, todoRepo repository.Todo, outboxRepo repository.Outbox, todo *model.Todo) error {
+	if !todo.IsRecurring() || todo.OccurrenceAt == nil {
+		return nil
+	}
+	later, err := findLaterOccurrences(ctx, todoRepo, todo)
+	if err != nil {
+		return err
+	}
+	if len(later) > 0 {
+		return nil
+	}
+
+	next, nextRule, ok, err := model.NextOccurrence(todo.Recurrence, todo.Timezone, *todo.OccurrenceAt)
+	if err != nil || !ok {
+		return err
+	}
+	created, err := todoRepo.Create(ctx, model.Todo{
+		Title:        todo.Title,
+		Content:      todo.Content,
+		Status:       model.TodoStatusTodo,
+		Priority:     todo.Priority,
+		Tags:         todo.Tags,
+		ParentID:     todo.ParentID,
+		AutoComplete: todo.AutoComplete,
+		DueAt:        &next,
+		Recurrence:   nextRule,
+		Timezone:     todo.Timezone,
+		SeriesID:     todo.SeriesID,
+		OccurrenceAt: &next,
+	})
+	if err != nil {
+		return err
+	}
+	return recordEvents(ctx, outboxRepo, model.NewTodoEvent(ctx, model.TodoEventCreated, created))
+}
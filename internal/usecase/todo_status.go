@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+)
+
+// resolveStatus はリクエストの status と done から適用するステータスを決定する
+// status が省略された場合は後方互換のため done から導出する (current は新規作成時は空文字)
+func resolveStatus(current model.TodoStatus, todo model.Todo) model.TodoStatus {
+	switch {
+	case todo.Status != "":
+		return todo.Status
+	case todo.Done:
+		return model.TodoStatusDone
+	case current == "" || current == model.TodoStatusDone:
+		return model.TodoStatusTodo
+	default:
+		return current
+	}
+}
+
+// checkStatusTransition は current から next へのステータス遷移が許可されているかを検証する
+func checkStatusTransition(current, next model.TodoStatus) error {
+	if !current.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", model.ErrInvalidStatusTransition, current, next)
+	}
+	return nil
+}
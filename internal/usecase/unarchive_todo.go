@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// UnarchiveTodo はTodoのアーカイブを解除するユースケースを表すインターフェース
+type UnarchiveTodo interface {
+	Execute(ctx context.Context, id string) (*model.Todo, error)
+}
+
+// unarchiveTodo は usecase.UnarchiveTodo の実装
+type unarchiveTodo struct {
+	txManager  repository.TxManager
+	todoRepo   repository.Todo
+	outboxRepo repository.Outbox
+}
+
+// NewUnarchiveTodo は usecase.UnarchiveTodo のコンストラクタ
+func NewUnarchiveTodo(txManager repository.TxManager, todoRepo repository.Todo, outboxRepo repository.Outbox) UnarchiveTodo {
+	return &unarchiveTodo{
+		txManager:  txManager,
+		todoRepo:   todoRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Execute はTodoのアーカイブを解除する
+func (uc *unarchiveTodo) Execute(ctx context.Context, id string) (*model.Todo, error) {
+	return writeTodo(ctx, uc.txManager, uc.outboxRepo, model.TodoEventUpdated, func(ctx context.Context) (*model.Todo, error) {
+		return uc.todoRepo.Unarchive(ctx, id)
+	})
+}
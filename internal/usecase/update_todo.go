@@ -9,22 +9,185 @@ import (
 
 // UpdateTodo はTodoを更新するユースケースを表すインターフェース
 type UpdateTodo interface {
-	Execute(ctx context.Context, id string, todo model.Todo) (*model.Todo, error)
+	Execute(ctx context.Context, id string, todo model.Todo, scope model.RecurrenceScope) (*model.Todo, error)
 }
 
 // updateTodo は usecase.UpdateTodo の実装
 type updateTodo struct {
-	todoRepo repository.Todo
+	todoUpdater
 }
 
 // NewUpdateTodo は usecase.UpdateTodo のコンストラクタ
-func NewUpdateTodo(todoRepo repository.Todo) UpdateTodo {
+func NewUpdateTodo(txManager repository.TxManager, todoRepo repository.Todo, dependencyRepo repository.TodoDependency, outboxRepo repository.Outbox) UpdateTodo {
 	return &updateTodo{
-		todoRepo: todoRepo,
+		todoUpdater: todoUpdater{
+			txManager:      txManager,
+			todoRepo:       todoRepo,
+			dependencyRepo: dependencyRepo,
+			outboxRepo:     outboxRepo,
+		},
 	}
 }
 
 // Execute はTodoを更新する
-func (uc *updateTodo) Execute(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
-	return uc.todoRepo.Update(ctx, id, todo)
+// 繰り返しのTodoの場合、scope で変更をこの発生のみに適用するか以降の発生にも適用するかを指定する
+// 完了または中止にした場合は次の発生を作成する
+func (uc *updateTodo) Execute(ctx context.Context, id string, todo model.Todo, scope model.RecurrenceScope) (*model.Todo, error) {
+	return uc.updateCurrent(ctx, id, func(*model.Todo) model.Todo { return todo }, scope)
+}
+
+// todoUpdater は UpdateTodo と PatchTodo で共通の更新処理を提供する
+type todoUpdater struct {
+	txManager      repository.TxManager
+	todoRepo       repository.Todo
+	dependencyRepo repository.TodoDependency
+	outboxRepo     repository.Outbox
+}
+
+// updateCurrent は id のTodoをトランザクションの中で取得し、build で現在のTodoから作成した内容で更新する
+// トランザクションの中ではキャッシュを使わないため、古いTodoを元にして更新することはない
+func (u *todoUpdater) updateCurrent(ctx context.Context, id string, build func(current *model.Todo) model.Todo, scope model.RecurrenceScope) (*model.Todo, error) {
+	var updated *model.Todo
+	err := u.txManager.Do(ctx, func(ctx context.Context) error {
+		current, err := u.todoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		updated, err = u.update(ctx, current, build(current), scope)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// update は current を todo の内容で更新する
+// 更新とそれに続く処理 (以降の発生への反映、次の発生の作成、親の自動完了)、イベントの記録は1つのトランザクションで行う
+// リポジトリは current のバージョンを元にした更新として保存するため、取得した後に他で更新されていた場合は model.ErrConflict を返す
+func (u *todoUpdater) update(ctx context.Context, current *model.Todo, todo model.Todo, scope model.RecurrenceScope) (*model.Todo, error) {
+	var updated *model.Todo
+	err := u.txManager.Do(ctx, func(ctx context.Context) error {
+		todo, scope, err := u.prepare(ctx, current, todo, scope)
+		if err != nil {
+			return err
+		}
+
+		updated, err = u.todoRepo.Update(ctx, current.ID, todo)
+		if err != nil {
+			return err
+		}
+		if err := recordEvents(ctx, u.outboxRepo, model.TodoUpdateEvents(ctx, current, updated)...); err != nil {
+			return err
+		}
+		return u.afterUpdate(ctx, current, updated, scope)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// prepare は current を todo の内容で更新できるかを検証し、保存する内容と検証済みの scope を返す
+func (u *todoUpdater) prepare(ctx context.Context, current *model.Todo, todo model.Todo, scope model.RecurrenceScope) (model.Todo, model.RecurrenceScope, error) {
+	if err := model.CheckExpectedVersion(ctx, current); err != nil {
+		return todo, scope, err
+	}
+	if err := current.CheckWritable(); err != nil {
+		return todo, scope, err
+	}
+	scope, err := model.ValidateRecurrenceScope(scope)
+	if err != nil {
+		return todo, scope, err
+	}
+
+	todo.Version = current.Version
+	todo.Status = resolveStatus(current.Status, todo)
+	todo.Done = todo.Status == model.TodoStatusDone
+	// タグが省略された場合は現在のタグを維持する
+	if todo.Tags == nil {
+		todo.Tags = current.Tags
+	}
+	todo.ParentID = resolveParentID(current.ParentID, todo.ParentID)
+	if err := todo.Validate(); err != nil {
+		return todo, scope, err
+	}
+	if err := checkStatusTransition(current.Status, todo.Status); err != nil {
+		return todo, scope, err
+	}
+	if err := applyRecurrence(current, &todo, scope); err != nil {
+		return todo, scope, err
+	}
+	if todo.Status == model.TodoStatusDone && current.Status != model.TodoStatusDone {
+		if err := checkNotBlocked(ctx, u.todoRepo, u.dependencyRepo, current.ID); err != nil {
+			return todo, scope, err
+		}
+	}
+	if !equalID(current.ParentID, todo.ParentID) {
+		if err := checkParent(ctx, u.todoRepo, current.ID, todo.ParentID); err != nil {
+			return todo, scope, err
+		}
+	}
+
+	return todo, scope, nil
+}
+
+// afterUpdate は current から updated への更新に続く処理として、繰り返しのルールの以降の発生への反映、
+// 次の発生の作成、親の自動完了を行う
+func (u *todoUpdater) afterUpdate(ctx context.Context, current, updated *model.Todo, scope model.RecurrenceScope) error {
+	if scope == model.RecurrenceScopeThisAndFuture && (updated.Recurrence != current.Recurrence || updated.Timezone != current.Timezone) {
+		if err := propagateRecurrence(ctx, u.todoRepo, u.outboxRepo, updated); err != nil {
+			return err
+		}
+	}
+	if !current.Status.IsClosed() && updated.Status.IsClosed() {
+		if err := spawnNextOccurrence(ctx, u.todoRepo, u.outboxRepo, updated); err != nil {
+			return err
+		}
+	}
+	return u.autoCompleteParents(ctx, updated)
+}
+
+// autoCompleteParents は todo の完了により子が全て完了または中止になった親を、
+// AutoComplete が有効でブロックされていない場合に完了にする (祖先に向かって繰り返す)
+func (u *todoUpdater) autoCompleteParents(ctx context.Context, todo *model.Todo) error {
+	for todo.ParentID != nil && todo.Status.IsClosed() {
+		parent, err := u.todoRepo.FindByID(ctx, *todo.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.IsArchived() || !parent.AutoComplete || parent.Status.IsClosed() || !parent.Status.CanTransitionTo(model.TodoStatusDone) {
+			return nil
+		}
+
+		children, err := u.todoRepo.FindChildren(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if !child.Status.IsClosed() {
+				return nil
+			}
+		}
+		openBlockerIDs, err := findOpenBlockerIDs(ctx, u.todoRepo, u.dependencyRepo, parent.ID)
+		if err != nil {
+			return err
+		}
+		if len(openBlockerIDs) > 0 {
+			return nil
+		}
+
+		before := *parent
+		parent.Status = model.TodoStatusDone
+		parent.Done = true
+		if todo, err = u.todoRepo.Update(ctx, parent.ID, *parent); err != nil {
+			return err
+		}
+		if err := recordEvents(ctx, u.outboxRepo, model.TodoUpdateEvents(ctx, &before, todo)...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
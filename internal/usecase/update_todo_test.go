@@ -0,0 +1,318 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	mock_repository "github.com/qushot/gin-todo-api/internal/mocks/repository"
+	"github.com/qushot/gin-todo-api/internal/usecase"
+)
+
+func Test_updateTodo_Execute(t *testing.T) {
+	archivedAt := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	version := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		current  model.Todo
+		blockers []model.Todo
+		todo     model.Todo
+		// expectedVersion を指定した場合は期待するバージョンとして context に格納する
+		expectedVersion *int
+		want            *model.Todo
+		wantErr         error
+	}{
+		{
+			name:    "todo to in_progress",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo},
+			todo:    model.Todo{Title: "Test Todo", Status: model.TodoStatusInProgress},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress, Timezone: model.DefaultTimezone},
+		},
+		{
+			name:    "done without status completes the todo",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress},
+			todo:    model.Todo{Title: "Test Todo", Done: true},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusDone, Timezone: model.DefaultTimezone, Done: true},
+		},
+		{
+			name:     "done is rejected while blockers are open",
+			current:  model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress},
+			blockers: []model.Todo{{ID: "2", Status: model.TodoStatusDone}, {ID: "3", Status: model.TodoStatusBlocked}},
+			todo:     model.Todo{Title: "Test Todo", Status: model.TodoStatusDone},
+			wantErr:  model.ErrConflict,
+		},
+		{
+			name:     "done is allowed when blockers are closed",
+			current:  model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusInProgress},
+			blockers: []model.Todo{{ID: "2", Status: model.TodoStatusDone}, {ID: "3", Status: model.TodoStatusCancelled}},
+			todo:     model.Todo{Title: "Test Todo", Status: model.TodoStatusDone},
+			want:     &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusDone, Timezone: model.DefaultTimezone, Done: true},
+		},
+		{
+			name:    "not done without status keeps the current status",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusBlocked},
+			todo:    model.Todo{Title: "Updated"},
+			want:    &model.Todo{ID: "1", Title: "Updated", Status: model.TodoStatusBlocked, Timezone: model.DefaultTimezone},
+		},
+		{
+			name:    "not done without status reopens a done todo",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusDone, Done: true},
+			todo:    model.Todo{Title: "Test Todo"},
+			want:    &model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo, Timezone: model.DefaultTimezone},
+		},
+		{
+			name:    "blocked cannot be done directly",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusBlocked},
+			todo:    model.Todo{Title: "Test Todo", Status: model.TodoStatusDone},
+			wantErr: model.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "cancelled cannot be in_progress directly",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusCancelled},
+			todo:    model.Todo{Title: "Test Todo", Status: model.TodoStatusInProgress},
+			wantErr: model.ErrInvalidStatusTransition,
+		},
+		{
+			name:    "archived todo is read-only",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusDone, ArchivedAt: &archivedAt, Done: true},
+			todo:    model.Todo{Title: "Updated", Status: model.TodoStatusDone},
+			wantErr: model.ErrConflict,
+		},
+		{
+			name:    "unknown status",
+			current: model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo},
+			todo:    model.Todo{Title: "Test Todo", Status: "unknown"},
+			wantErr: model.ErrInvalidArgument,
+		},
+		{
+			name:            "expected version matches",
+			current:         model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo, Version: 3},
+			todo:            model.Todo{Title: "Updated"},
+			expectedVersion: version(3),
+			want:            &model.Todo{ID: "1", Title: "Updated", Status: model.TodoStatusTodo, Timezone: model.DefaultTimezone, Version: 3},
+		},
+		{
+			name:            "stale expected version",
+			current:         model.Todo{ID: "1", Title: "Test Todo", Status: model.TodoStatusTodo, Version: 3},
+			todo:            model.Todo{Title: "Updated"},
+			expectedVersion: version(2),
+			wantErr:         model.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), tt.current.ID).
+				Return(&tt.current, nil)
+			blockerIDs := make([]string, 0, len(tt.blockers))
+			for _, blocker := range tt.blockers {
+				mockTodoRepo.EXPECT().
+					FindByID(gomock.Any(), blocker.ID).
+					Return(&blocker, nil)
+				blockerIDs = append(blockerIDs, blocker.ID)
+			}
+			mockTodoRepo.EXPECT().
+				Update(gomock.Any(), tt.current.ID, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+					todo.ID = id
+					return &todo, nil
+				}).AnyTimes()
+
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockDependencyRepo.EXPECT().
+				FindBlockerIDs(gomock.Any(), tt.current.ID).
+				Return(blockerIDs, nil).AnyTimes()
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			var gotEvents []model.TodoEventType
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, events []model.TodoEvent) error {
+					for _, e := range events {
+						gotEvents = append(gotEvents, e.Type)
+					}
+					return nil
+				}).AnyTimes()
+
+			ctx := context.Background()
+			if tt.expectedVersion != nil {
+				ctx = model.WithExpectedVersion(ctx, *tt.expectedVersion)
+			}
+			uc := usecase.NewUpdateTodo(mockTxManager, mockTodoRepo, mockDependencyRepo, mockOutboxRepo)
+			got, gotErr := uc.Execute(ctx, tt.current.ID, tt.todo, "")
+			if gotErr != nil {
+				if !errors.Is(gotErr, tt.wantErr) {
+					t.Errorf("Execute() failed: %v", gotErr)
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				t.Fatal("Execute() succeeded unexpectedly")
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Execute() mismatch (-want +got):\n%s", diff)
+			}
+
+			wantEvents := []model.TodoEventType{model.TodoEventUpdated}
+			if tt.current.Status != model.TodoStatusDone && tt.want.Status == model.TodoStatusDone {
+				wantEvents = append(wantEvents, model.TodoEventCompleted)
+			}
+			if diff := cmp.Diff(wantEvents, gotEvents); diff != "" {
+				t.Errorf("Add() events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_updateTodo_Execute_recurrence(t *testing.T) {
+	seriesID := "series-1"
+	occurrenceAt := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	nextAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	laterAt := time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)
+	current := model.Todo{
+		ID:           "1",
+		Title:        "ゴミ出し",
+		Status:       model.TodoStatusTodo,
+		Tags:         []string{"home"},
+		DueAt:        &occurrenceAt,
+		Recurrence:   "FREQ=WEEKLY;COUNT=3",
+		Timezone:     model.DefaultTimezone,
+		SeriesID:     &seriesID,
+		OccurrenceAt: &occurrenceAt,
+	}
+
+	tests := []struct {
+		name       string
+		todo       model.Todo
+		scope      model.RecurrenceScope
+		occurrence []model.Todo
+		wantNext   *model.Todo
+		// wantRules は以降の発生に反映する繰り返しのルール (TodoのIDごと)
+		wantRules map[string]string
+		wantErr   error
+	}{
+		{
+			name:  "completing spawns the next occurrence",
+			todo:  model.Todo{Title: "ゴミ出し", Status: model.TodoStatusDone, DueAt: &occurrenceAt, Recurrence: "FREQ=WEEKLY;COUNT=3"},
+			scope: model.RecurrenceScopeThis,
+			wantNext: &model.Todo{
+				Title:        "ゴミ出し",
+				Status:       model.TodoStatusTodo,
+				Tags:         []string{"home"},
+				DueAt:        &nextAt,
+				Recurrence:   "FREQ=WEEKLY;COUNT=2",
+				Timezone:     model.DefaultTimezone,
+				SeriesID:     &seriesID,
+				OccurrenceAt: &nextAt,
+			},
+		},
+		{
+			name:       "completing does not spawn twice",
+			todo:       model.Todo{Title: "ゴミ出し", Status: model.TodoStatusDone, DueAt: &occurrenceAt, Recurrence: "FREQ=WEEKLY;COUNT=3"},
+			occurrence: []model.Todo{{ID: "2", Status: model.TodoStatusTodo, SeriesID: &seriesID, OccurrenceAt: &nextAt}},
+		},
+		{
+			// COUNT は発生ごとに減らすため、以降の発生には残りの COUNT を反映する
+			name:  "changing recurrence propagates the remaining count",
+			todo:  model.Todo{Title: "ゴミ出し", DueAt: &occurrenceAt, Recurrence: "FREQ=WEEKLY;COUNT=5"},
+			scope: model.RecurrenceScopeThisAndFuture,
+			occurrence: []model.Todo{
+				{ID: "3", Status: model.TodoStatusTodo, Recurrence: "FREQ=WEEKLY;COUNT=1", SeriesID: &seriesID, OccurrenceAt: &laterAt},
+				{ID: "2", Status: model.TodoStatusTodo, Recurrence: "FREQ=WEEKLY;COUNT=2", SeriesID: &seriesID, OccurrenceAt: &nextAt},
+			},
+			wantRules: map[string]string{"2": "FREQ=WEEKLY;COUNT=4", "3": "FREQ=WEEKLY;COUNT=3"},
+		},
+		{
+			name:    "recurrence cannot be changed for this occurrence only",
+			todo:    model.Todo{Title: "ゴミ出し", DueAt: &occurrenceAt, Recurrence: "FREQ=DAILY"},
+			scope:   model.RecurrenceScopeThis,
+			wantErr: model.ErrInvalidArgument,
+		},
+		{
+			name:    "unknown scope",
+			todo:    model.Todo{Title: "ゴミ出し", DueAt: &occurrenceAt, Recurrence: "FREQ=WEEKLY;COUNT=3"},
+			scope:   "unknown",
+			wantErr: model.ErrInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTodoRepo := mock_repository.NewMockTodo(ctrl)
+			mockTodoRepo.EXPECT().
+				FindByID(gomock.Any(), current.ID).
+				Return(&current, nil)
+			gotRules := map[string]string{}
+			mockTodoRepo.EXPECT().
+				Update(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id string, todo model.Todo) (*model.Todo, error) {
+					if id != current.ID {
+						gotRules[id] = todo.Recurrence
+					}
+					todo.ID = id
+					return &todo, nil
+				}).AnyTimes()
+			mockTodoRepo.EXPECT().
+				FindAll(gomock.Any(), model.TodoQuery{SeriesID: seriesID, Archived: model.ArchivedFilterAny}).
+				Return(append([]model.Todo{current}, tt.occurrence...), nil).AnyTimes()
+			if tt.wantNext != nil {
+				mockTodoRepo.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, todo model.Todo) (*model.Todo, error) {
+						if diff := cmp.Diff(*tt.wantNext, todo); diff != "" {
+							t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+						}
+						return &todo, nil
+					})
+			}
+
+			mockDependencyRepo := mock_repository.NewMockTodoDependency(ctrl)
+			mockDependencyRepo.EXPECT().
+				FindBlockerIDs(gomock.Any(), current.ID).
+				Return(nil, nil).AnyTimes()
+
+			mockTxManager := mock_repository.NewMockTxManager(ctrl)
+			mockTxManager.EXPECT().
+				Do(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).AnyTimes()
+			mockOutboxRepo := mock_repository.NewMockOutbox(ctrl)
+			mockOutboxRepo.EXPECT().
+				Add(gomock.Any(), gomock.Any()).
+				Return(nil).AnyTimes()
+
+			uc := usecase.NewUpdateTodo(mockTxManager, mockTodoRepo, mockDependencyRepo, mockOutboxRepo)
+			_, gotErr := uc.Execute(context.Background(), current.ID, tt.todo, tt.scope)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if tt.wantRules != nil {
+				if diff := cmp.Diff(tt.wantRules, gotRules); diff != "" {
+					t.Errorf("propagated recurrences mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
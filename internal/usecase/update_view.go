@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// UpdateView はビューを更新するユースケースを表すインターフェース
+type UpdateView interface {
+	Execute(ctx context.Context, id string, view model.View) (*model.View, error)
+}
+
+// updateView は usecase.UpdateView の実装
+type updateView struct {
+	viewRepo repository.View
+}
+
+// NewUpdateView は usecase.UpdateView のコンストラクタ
+func NewUpdateView(viewRepo repository.View) UpdateView {
+	return &updateView{
+		viewRepo: viewRepo,
+	}
+}
+
+// Execute は検索クエリを検証してビューの名前と検索クエリを更新する
+// 同じ名前のビューが既に存在する場合は model.ErrConflict を返す
+func (uc *updateView) Execute(ctx context.Context, id string, view model.View) (*model.View, error) {
+	if err := view.Validate(); err != nil {
+		return nil, err
+	}
+
+	return uc.viewRepo.Update(ctx, id, view)
+}
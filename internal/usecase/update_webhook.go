@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// UpdateWebhook はWebhookを更新するユースケースを表すインターフェース
+type UpdateWebhook interface {
+	Execute(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error)
+}
+
+// updateWebhook は usecase.UpdateWebhook の実装
+type updateWebhook struct {
+	webhookRepo repository.Webhook
+}
+
+// NewUpdateWebhook は usecase.UpdateWebhook のコンストラクタ
+func NewUpdateWebhook(webhookRepo repository.Webhook) UpdateWebhook {
+	return &updateWebhook{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Execute はWebhookを検証して URL とイベントの種類を更新し、秘密鍵を除いて返す
+// 秘密鍵を指定した場合のみ秘密鍵を更新する
+func (uc *updateWebhook) Execute(ctx context.Context, id string, webhook model.Webhook) (*model.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		current, err := uc.webhookRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = current.Secret
+	}
+
+	updated, err := uc.webhookRepo.Update(ctx, id, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return withoutSecret(updated), nil
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/qushot/gin-todo-api/internal/domain/model"
+	"github.com/qushot/gin-todo-api/internal/domain/repository"
+)
+
+// withoutSecret は秘密鍵を除いたWebhookを返す (秘密鍵は作成した時のみ返す)
+func withoutSecret(webhook *model.Webhook) *model.Webhook {
+	w := *webhook
+	w.Secret = ""
+	return &w
+}
+
+// deliverWebhook は配信をWebhookに送信し、その結果を保存した配信を返す
+// 送信に失敗した場合は、失敗した回数に応じて再試行を予約する (上限に達した場合は dead にする)
+func deliverWebhook(ctx context.Context, sender repository.WebhookSender, deliveryRepo repository.WebhookDelivery, webhook model.Webhook, delivery model.WebhookDelivery, now time.Time) (*model.WebhookDelivery, error) {
+	status, err := sender.Send(ctx, webhook, delivery)
+	delivery.RecordAttempt(now, status, err)
+	return deliveryRepo.Update(ctx, delivery)
+}
@@ -0,0 +1,79 @@
+-- ToDo のステータス・優先度・階層・期限・繰り返し・アーカイブ・ゴミ箱・全文検索のカラムを追加する
+-- 01_setup.sql で作成した todo テーブルを変更するため、既存のデータベースにもこのファイルを適用できる
+-- 以降のマイグレーション (トリガーやビューなど) はこのファイルで追加するカラムを参照するため、01_setup.sql の直後に適用する
+
+-- 日本語のように単語を空白で区切らない言語でも部分一致で検索できるように、単語を 2-gram に分割する
+-- 文書 (for_query = FALSE) では各単語の末尾の 1 文字も加え、1 文字の検索語を前方一致で検索できるようにする
+CREATE OR REPLACE FUNCTION todo_search_ngram(target TEXT, for_query BOOLEAN DEFAULT FALSE) RETURNS TEXT AS -- noqa: CP03
+$$
+    SELECT COALESCE(STRING_AGG(SUBSTR(words.word, i, 2), ' ' ORDER BY words.word_no, i), '')
+    FROM REGEXP_SPLIT_TO_TABLE(LOWER(target), '[[:space:][:punct:]]+') WITH ORDINALITY AS words (word, word_no)
+    CROSS JOIN LATERAL GENERATE_SERIES(
+        1, CASE WHEN for_query THEN GREATEST(LENGTH(words.word) - 1, 1) ELSE LENGTH(words.word) END
+    ) AS i
+    WHERE words.word <> ''
+$$ LANGUAGE sql IMMUTABLE;
+
+-- 検索語の一覧から、全ての検索語を含む ToDo に一致する tsquery を作成する
+-- 2 文字以上の検索語は 2-gram の連続 (フレーズ) に、1 文字の検索語は前方一致に変換する
+CREATE OR REPLACE FUNCTION todo_search_query(terms TEXT []) RETURNS TSQUERY AS -- noqa: CP03
+$$
+    SELECT STRING_AGG(
+        '(' || CASE
+            WHEN LENGTH(term) = 1 THEN TO_TSQUERY('simple', term || ':*')
+            ELSE PHRASETO_TSQUERY('simple', todo_search_ngram(term, TRUE))
+        END::TEXT || ')', ' & '
+    )::TSQUERY
+    FROM UNNEST(terms) AS term
+$$ LANGUAGE sql IMMUTABLE;
+
+ALTER TABLE todo
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'todo'
+, ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0
+, ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES todo (id) ON DELETE CASCADE
+, ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0
+, ADD COLUMN IF NOT EXISTS auto_complete BOOL NOT NULL DEFAULT FALSE
+, ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ
+, ADD COLUMN IF NOT EXISTS recurrence TEXT NOT NULL DEFAULT ''
+, ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC'
+, ADD COLUMN IF NOT EXISTS series_id UUID
+, ADD COLUMN IF NOT EXISTS occurrence_at TIMESTAMPTZ
+, ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ
+, ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ
+, ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ
+, ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+  SETWEIGHT(TO_TSVECTOR('simple', todo_search_ngram(title)), 'A')
+  || SETWEIGHT(TO_TSVECTOR('simple', todo_search_ngram(COALESCE(content, ''))), 'B')
+) STORED
+, ADD CONSTRAINT todo_status_check CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled'))
+, ADD CONSTRAINT todo_priority_check CHECK (priority BETWEEN 0 AND 4)
+, ADD CONSTRAINT todo_recurrence_check CHECK (recurrence = '' OR due_at IS NOT NULL);
+
+-- 完了フラグは status から導出するため、既存の完了フラグを status に移してから生成列に置き換える
+-- (完了した日時は不明のため、最後に更新した日時を completed_at とする)
+UPDATE todo SET status = 'done', completed_at = updated_at WHERE done;
+ALTER TABLE todo DROP COLUMN done;
+ALTER TABLE todo ADD COLUMN done BOOL GENERATED ALWAYS AS (status = 'done') STORED;
+
+COMMENT ON COLUMN todo.status IS 'ステータス';
+COMMENT ON COLUMN todo.priority IS '優先度 (0-4)';
+COMMENT ON COLUMN todo.done IS '完了フラグ (status から導出)';
+COMMENT ON COLUMN todo.parent_id IS '親 ToDo ID';
+COMMENT ON COLUMN todo.position IS '兄弟間での並び順';
+COMMENT ON COLUMN todo.auto_complete IS '子が全て完了したら自動で完了にするか';
+COMMENT ON COLUMN todo.due_at IS '期限';
+COMMENT ON COLUMN todo.recurrence IS '繰り返しのルール (RFC 5545 の RRULE、空の場合は繰り返さない)';
+COMMENT ON COLUMN todo.timezone IS '繰り返しの計算に使うタイムゾーン (IANA)';
+COMMENT ON COLUMN todo.series_id IS '繰り返しの系列 ID';
+COMMENT ON COLUMN todo.occurrence_at IS '繰り返しの系列内での発生日時';
+COMMENT ON COLUMN todo.completed_at IS 'done になった日時 (status から導出)';
+COMMENT ON COLUMN todo.archived_at IS 'アーカイブした日時 (NULL の場合はアーカイブされていない)';
+COMMENT ON COLUMN todo.deleted_at IS 'ゴミ箱に移動した日時 (NULL の場合はゴミ箱にない)';
+COMMENT ON COLUMN todo.search_vector IS '全文検索用の 2-gram (タイトルの重みを A、内容の重みを B とする)';
+
+CREATE INDEX IF NOT EXISTS idx_todo_parent_id ON todo (parent_id, position);
+CREATE INDEX IF NOT EXISTS idx_todo_series_id ON todo (series_id, occurrence_at);
+CREATE INDEX IF NOT EXISTS idx_todo_completed_at ON todo (completed_at) WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_todo_deleted_at ON todo (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_todo_search_vector ON todo USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_todo_updated_at ON todo (updated_at);
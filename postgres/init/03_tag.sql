@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS tag (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid() -- noqa: CP03
+  , name TEXT NOT NULL UNIQUE
+  , created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+COMMENT ON TABLE tag IS 'タグ';
+COMMENT ON COLUMN tag.id IS 'ID';
+COMMENT ON COLUMN tag.name IS 'タグ名';
+COMMENT ON COLUMN tag.created_at IS '作成日時';
+
+CREATE TABLE IF NOT EXISTS todo_tag (
+  todo_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE
+  , tag_id UUID NOT NULL REFERENCES tag (id) ON DELETE CASCADE
+  , PRIMARY KEY (todo_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_tag_tag_id ON todo_tag (tag_id);
+COMMENT ON TABLE todo_tag IS 'ToDo とタグの紐付け';
+COMMENT ON COLUMN todo_tag.todo_id IS 'ToDo ID';
+COMMENT ON COLUMN todo_tag.tag_id IS 'タグ ID';
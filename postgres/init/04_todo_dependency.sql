@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS todo_dependency (
+  todo_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE
+  , blocker_id UUID NOT NULL REFERENCES todo (id) ON DELETE CASCADE
+  , created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , PRIMARY KEY (todo_id, blocker_id)
+  , CONSTRAINT todo_dependency_self_check CHECK (todo_id <> blocker_id)
+);
+CREATE INDEX IF NOT EXISTS idx_todo_dependency_blocker_id ON todo_dependency (blocker_id);
+COMMENT ON TABLE todo_dependency IS 'ToDo の依存関係 (todo_id は blocker_id が完了するまで完了できない)';
+COMMENT ON COLUMN todo_dependency.todo_id IS 'ブロックされている ToDo ID';
+COMMENT ON COLUMN todo_dependency.blocker_id IS 'ブロックしている ToDo ID';
+COMMENT ON COLUMN todo_dependency.created_at IS '作成日時';
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS todo_history (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid() -- noqa: CP03
+  , todo_id UUID NOT NULL
+  , version INT NOT NULL
+  , action TEXT NOT NULL
+  , actor TEXT NOT NULL
+  , trace_id TEXT NOT NULL DEFAULT ''
+  , changes JSONB NOT NULL
+  , snapshot JSONB NOT NULL
+  , created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , CONSTRAINT todo_history_action_check CHECK (
+    action IN ('create', 'update', 'delete', 'restore', 'archive', 'unarchive')
+  )
+);
+CREATE INDEX IF NOT EXISTS idx_todo_history_todo_id ON todo_history (todo_id, version);
+COMMENT ON TABLE todo_history IS 'ToDo の変更履歴 (追記のみ。ToDo を完全に削除しても残す)';
+COMMENT ON COLUMN todo_history.id IS 'ID';
+COMMENT ON COLUMN todo_history.todo_id IS 'ToDo ID';
+COMMENT ON COLUMN todo_history.version IS '変更後の ToDo のバージョン';
+COMMENT ON COLUMN todo_history.action IS '操作の種類';
+COMMENT ON COLUMN todo_history.actor IS '操作者';
+COMMENT ON COLUMN todo_history.trace_id IS 'トレース ID';
+COMMENT ON COLUMN todo_history.changes IS '項目ごとの変更前後の値';
+COMMENT ON COLUMN todo_history.snapshot IS '変更後の ToDo 全体';
+COMMENT ON COLUMN todo_history.created_at IS '作成日時';
+
+CREATE FUNCTION forbid_todo_history_modification() RETURNS TRIGGER AS -- noqa: CP03
+$$
+BEGIN
+    RAISE EXCEPTION 'todo_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_todo_history_append_only
+BEFORE UPDATE OR DELETE ON todo_history FOR EACH ROW EXECUTE PROCEDURE forbid_todo_history_modification(); -- noqa: CP03
+COMMENT ON TRIGGER trg_todo_history_append_only ON todo_history IS '変更履歴の更新と削除を禁止するトリガー';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS todo_view (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid() -- noqa: CP03
+  , name TEXT NOT NULL UNIQUE
+  , query JSONB NOT NULL DEFAULT '{}'
+  , created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+COMMENT ON TABLE todo_view IS 'ビュー (名前を付けて保存した ToDo の検索クエリ)';
+COMMENT ON COLUMN todo_view.id IS 'ID';
+COMMENT ON COLUMN todo_view.name IS 'ビュー名';
+COMMENT ON COLUMN todo_view.query IS '検索クエリ (期限の相対的な表現は取得する時点で解決する)';
+COMMENT ON COLUMN todo_view.created_at IS '作成日時';
+COMMENT ON COLUMN todo_view.updated_at IS '更新日時';
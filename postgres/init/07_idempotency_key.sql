@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+  request_key TEXT PRIMARY KEY
+  , fingerprint TEXT NOT NULL
+  , status_code INT
+  , content_type TEXT NOT NULL DEFAULT ''
+  , body BYTEA
+  , created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires_at ON idempotency_key (expires_at);
+COMMENT ON TABLE idempotency_key IS '冪等キー (Idempotency-Key ヘッダーで指定されたリクエストのレスポンス)';
+COMMENT ON COLUMN idempotency_key.request_key IS '操作者ごとに区別した冪等キー';
+COMMENT ON COLUMN idempotency_key.fingerprint IS 'リクエストのメソッド、パス、ボディのハッシュ';
+COMMENT ON COLUMN idempotency_key.status_code IS 'レスポンスのステータスコード (処理中の場合は NULL)';
+COMMENT ON COLUMN idempotency_key.content_type IS 'レスポンスの Content-Type';
+COMMENT ON COLUMN idempotency_key.body IS 'レスポンスのボディ';
+COMMENT ON COLUMN idempotency_key.created_at IS '作成日時';
+COMMENT ON COLUMN idempotency_key.expires_at IS '有効期限';
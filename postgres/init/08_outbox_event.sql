@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS outbox_event (
+  seq BIGSERIAL PRIMARY KEY
+  , id UUID NOT NULL DEFAULT gen_random_uuid() -- noqa: CP03
+  , event_type TEXT NOT NULL
+  , todo_id UUID NOT NULL
+  , actor TEXT NOT NULL
+  , trace_id TEXT NOT NULL DEFAULT ''
+  , todo JSONB NOT NULL
+  , occurred_at TIMESTAMPTZ NOT NULL
+  , attempts INT NOT NULL DEFAULT 0
+  , last_error TEXT NOT NULL DEFAULT ''
+  , available_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_event_todo_id ON outbox_event (todo_id, seq);
+COMMENT ON TABLE outbox_event IS '配信待ちの ToDo のドメインイベント (配信したら削除する)';
+COMMENT ON COLUMN outbox_event.seq IS '保存した順序';
+COMMENT ON COLUMN outbox_event.id IS 'イベント ID';
+COMMENT ON COLUMN outbox_event.event_type IS 'イベントの種類';
+COMMENT ON COLUMN outbox_event.todo_id IS 'ToDo ID';
+COMMENT ON COLUMN outbox_event.actor IS '操作者';
+COMMENT ON COLUMN outbox_event.trace_id IS 'トレース ID';
+COMMENT ON COLUMN outbox_event.todo IS 'イベントが発生した後の ToDo 全体';
+COMMENT ON COLUMN outbox_event.occurred_at IS 'イベントが発生した日時';
+COMMENT ON COLUMN outbox_event.attempts IS '配信に失敗した回数';
+COMMENT ON COLUMN outbox_event.last_error IS '最後に配信に失敗した理由';
+COMMENT ON COLUMN outbox_event.available_at IS '次に配信を試みる日時';
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS webhook (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid() -- noqa: CP03
+  , url TEXT NOT NULL
+  , events TEXT [] NOT NULL DEFAULT '{}'
+  , secret TEXT NOT NULL
+  , created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+COMMENT ON TABLE webhook IS 'Webhook の購読';
+COMMENT ON COLUMN webhook.id IS 'ID';
+COMMENT ON COLUMN webhook.url IS '配信先の URL';
+COMMENT ON COLUMN webhook.events IS '配信するイベントの種類 (空の場合は全ての種類)';
+COMMENT ON COLUMN webhook.secret IS '署名に使う秘密鍵';
+COMMENT ON COLUMN webhook.created_at IS '作成日時';
+COMMENT ON COLUMN webhook.updated_at IS '更新日時';
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid() -- noqa: CP03
+  , webhook_id UUID NOT NULL REFERENCES webhook (id) ON DELETE CASCADE
+  , event_id TEXT NOT NULL
+  , event_type TEXT NOT NULL
+  , payload JSONB NOT NULL
+  , status TEXT NOT NULL
+  , attempts INT NOT NULL DEFAULT 0
+  , response_status INT NOT NULL DEFAULT 0
+  , last_error TEXT NOT NULL DEFAULT ''
+  , next_attempt_at TIMESTAMPTZ
+  , created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+  , UNIQUE (webhook_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_next_attempt_at ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook_id ON webhook_delivery (webhook_id, created_at);
+COMMENT ON TABLE webhook_delivery IS 'Webhook の配信とその結果';
+COMMENT ON COLUMN webhook_delivery.id IS 'ID (再試行しても変わらない)';
+COMMENT ON COLUMN webhook_delivery.webhook_id IS 'Webhook ID';
+COMMENT ON COLUMN webhook_delivery.event_id IS 'イベント ID';
+COMMENT ON COLUMN webhook_delivery.event_type IS 'イベントの種類';
+COMMENT ON COLUMN webhook_delivery.payload IS '配信するリクエストの本文';
+COMMENT ON COLUMN webhook_delivery.status IS '状態 (pending: 配信待ち, succeeded: 成功, dead: 配信を諦めた)';
+COMMENT ON COLUMN webhook_delivery.attempts IS '配信を試みた回数';
+COMMENT ON COLUMN webhook_delivery.response_status IS '最後の応答のステータスコード (応答がなかった場合は 0)';
+COMMENT ON COLUMN webhook_delivery.last_error IS '最後に配信に失敗した理由';
+COMMENT ON COLUMN webhook_delivery.next_attempt_at IS '次に配信を試みる日時 (配信待ちでない場合は NULL)';
+COMMENT ON COLUMN webhook_delivery.created_at IS '作成日時';
+COMMENT ON COLUMN webhook_delivery.updated_at IS '更新日時';
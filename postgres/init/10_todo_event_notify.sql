@@ -0,0 +1,26 @@
+-- ToDo の変更を LISTEN/NOTIFY で通知する (他の API のレプリカでの変更もリアルタイムの購読者に配信するため)
+-- 通知はコミットした順に届き、ペイロードはイベント ID、イベントの種類、ToDo ID の JSON
+CREATE FUNCTION notify_todo_event() RETURNS TRIGGER AS -- noqa: CP03
+$$
+DECLARE
+    event_type TEXT;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        event_type := 'todo.created';
+    ELSIF (NEW.deleted_at IS NOT NULL AND OLD.deleted_at IS NULL) THEN
+        event_type := 'todo.deleted';
+    ELSIF (NEW.status = 'done' AND OLD.status <> 'done') THEN
+        event_type := 'todo.completed';
+    ELSE
+        event_type := 'todo.updated';
+    END IF;
+    PERFORM pg_notify('todo_events', json_build_object(
+        'id', gen_random_uuid(), 'type', event_type, 'todo_id', NEW.id, 'occurred_at', NOW()
+    )::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_todo_notify_event
+AFTER INSERT OR UPDATE ON todo FOR EACH ROW EXECUTE PROCEDURE notify_todo_event(); -- noqa: CP03
+COMMENT ON TRIGGER trg_todo_notify_event ON todo IS 'ToDo の変更を todo_events チャンネルに通知するトリガー';
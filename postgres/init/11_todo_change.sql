@@ -0,0 +1,29 @@
+-- ToDo の最後の変更を差分同期のために記録する (ToDo を完全に削除しても残し、削除を同期する)
+-- 同期トークンはトランザクション ID で、同期した時点のスナップショットの xmin を次回の同期トークンとする
+-- (それ以降にコミットする変更のトランザクション ID は必ず xmin 以上のため、コミットの順序が前後しても取りこぼさない)
+CREATE TABLE IF NOT EXISTS todo_change (
+  todo_id UUID PRIMARY KEY
+  , change_xid XID8 NOT NULL DEFAULT PG_CURRENT_XACT_ID()
+  , changed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_todo_change_xid ON todo_change (change_xid);
+COMMENT ON TABLE todo_change IS 'ToDo の最後の変更 (差分同期のため。ToDo を完全に削除しても残す)';
+COMMENT ON COLUMN todo_change.todo_id IS 'ToDo ID';
+COMMENT ON COLUMN todo_change.change_xid IS '変更したトランザクション ID';
+COMMENT ON COLUMN todo_change.changed_at IS '変更日時';
+
+INSERT INTO todo_change (todo_id) SELECT id FROM todo ON CONFLICT DO NOTHING;
+
+CREATE FUNCTION record_todo_change() RETURNS TRIGGER AS -- noqa: CP03
+$$
+BEGIN
+    INSERT INTO todo_change (todo_id, change_xid, changed_at)
+    VALUES (CASE WHEN TG_OP = 'DELETE' THEN OLD.id ELSE NEW.id END, PG_CURRENT_XACT_ID(), NOW())
+    ON CONFLICT (todo_id) DO UPDATE SET change_xid = EXCLUDED.change_xid, changed_at = EXCLUDED.changed_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_todo_record_change
+AFTER INSERT OR UPDATE OR DELETE ON todo FOR EACH ROW EXECUTE PROCEDURE record_todo_change(); -- noqa: CP03
+COMMENT ON TRIGGER trg_todo_record_change ON todo IS 'ToDo の変更を todo_change に記録するトリガー';